@@ -26,6 +26,7 @@ import (
 	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
 	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/addons"
 	"k8s.io/minikube/pkg/minikube/assets"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/exit"
@@ -41,6 +42,7 @@ var addonListOutput string
 type AddonListTemplate struct {
 	AddonName   string
 	AddonStatus string
+	AddonSource string
 }
 
 var addonsListCmd = &cobra.Command{
@@ -53,6 +55,7 @@ var addonsListCmd = &cobra.Command{
 		}
 
 		_, cc := mustload.Partial(ClusterFlagValue())
+		addons.LoadExternal()
 		switch strings.ToLower(addonListOutput) {
 		case "list":
 			printAddonsList(cc)
@@ -98,7 +101,7 @@ var printAddonsList = func(cc *config.ClusterConfig) {
 
 	var tData [][]string
 	table := tablewriter.NewWriter(os.Stdout)
-	table.SetHeader([]string{"Addon Name", "Profile", "Status"})
+	table.SetHeader([]string{"Addon Name", "Profile", "Status", "Source"})
 	table.SetAutoFormatHeaders(true)
 	table.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
 	table.SetCenterSeparator("|")
@@ -106,7 +109,7 @@ var printAddonsList = func(cc *config.ClusterConfig) {
 	for _, addonName := range addonNames {
 		addonBundle := assets.Addons[addonName]
 		enabled := addonBundle.IsEnabled(cc)
-		tData = append(tData, []string{addonName, cc.Name, fmt.Sprintf("%s %s", stringFromStatus(enabled), iconFromStatus(enabled))})
+		tData = append(tData, []string{addonName, cc.Name, fmt.Sprintf("%s %s", stringFromStatus(enabled), iconFromStatus(enabled)), addonBundle.Source()})
 	}
 
 	table.AppendBulk(tData)
@@ -137,6 +140,7 @@ var printAddonsJSON = func(cc *config.ClusterConfig) {
 		addonsMap[addonName] = map[string]interface{}{
 			"Status":  stringFromStatus(enabled),
 			"Profile": cc.Name,
+			"Source":  addonBundle.Source(),
 		}
 	}
 	jsonString, _ := json.Marshal(addonsMap)
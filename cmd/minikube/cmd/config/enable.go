@@ -18,6 +18,7 @@ package config
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"k8s.io/minikube/pkg/addons"
@@ -29,14 +30,23 @@ import (
 )
 
 var addonsEnableCmd = &cobra.Command{
-	Use:   "enable ADDON_NAME",
+	Use:   "enable ADDON_NAME | URL",
 	Short: "Enables the addon w/ADDON_NAME within minikube (example: minikube addons enable dashboard). For a list of available addons use: minikube addons list ",
-	Long:  "Enables the addon w/ADDON_NAME within minikube (example: minikube addons enable dashboard). For a list of available addons use: minikube addons list ",
+	Long: `Enables the addon w/ADDON_NAME within minikube (example: minikube addons enable dashboard). For a list of available addons use: minikube addons list
+
+External addons are loaded from $MINIKUBE_HOME/addons/ADDON_NAME/addon.yaml, or installed there from an https or file URL pointing at an addon.yaml manifest (example: minikube addons enable https://example.com/myaddon/addon.yaml).`,
 	Run: func(cmd *cobra.Command, args []string) {
 		if len(args) != 1 {
 			exit.Message(reason.Usage, "usage: minikube addons enable ADDON_NAME")
 		}
 		addon := args[0]
+		if strings.Contains(addon, "://") {
+			name, err := addons.InstallExternal(addon)
+			if err != nil {
+				exit.Error(reason.AddonInstall, "Failed to install external addon", err)
+			}
+			addon = name
+		}
 		// replace heapster as metrics-server because heapster is deprecated
 		if addon == "heapster" {
 			out.Step(style.Waiting, "enable metrics-server addon instead of heapster addon because heapster is deprecated")
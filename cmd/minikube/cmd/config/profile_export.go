@@ -0,0 +1,60 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+)
+
+var profileExportCmd = &cobra.Command{
+	Use:     "export [MINIKUBE_PROFILE_NAME]",
+	Short:   "Exports a profile as a cluster spec file.",
+	Long:    "Prints the cluster spec describing a profile, defaulting to the current one. The cluster can be recreated from it with: minikube start -f <FILE>",
+	Example: "minikube profile export > cluster.yaml",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) > 1 {
+			exit.Message(reason.Usage, "usage: minikube profile export [MINIKUBE_PROFILE_NAME]")
+		}
+		profile := ClusterFlagValue()
+		if len(args) == 1 {
+			profile = args[0]
+		}
+
+		cc, err := config.Load(profile)
+		if err != nil {
+			if config.IsNotExist(err) {
+				exit.Message(reason.Usage, `Profile "{{.name}}" not found. Run "minikube profile list" to view all profiles.`, out.V{"name": profile})
+			}
+			exit.Error(reason.HostConfigLoad, "Error loading profile config", err)
+		}
+
+		b, err := yaml.Marshal(config.ClusterSpecFromConfig(*cc))
+		if err != nil {
+			exit.Error(reason.InternalYamlMarshal, "Failed to marshal cluster spec", err)
+		}
+		out.String("%s", string(b))
+	},
+}
+
+func init() {
+	ProfileCmd.AddCommand(profileExportCmd)
+}
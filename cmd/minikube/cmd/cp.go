@@ -0,0 +1,99 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/node"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+)
+
+var cpNode string
+
+// cpCmd represents the cp command
+var cpCmd = &cobra.Command{
+	Use:   "cp <source> <target>",
+	Short: "Copy files between the host and a node",
+	Long: `Copy a file or directory from the host into a node, or from a node onto the host.
+
+Paths on a node may be prefixed with the node name, e.g. minikube-m02:/home/docker/file.txt.
+An unprefixed target is copied to the node given by --node, defaulting to the primary control plane.`,
+	Example: `minikube cp a.txt /home/docker/b.txt
+minikube cp ./data minikube-m02:/home/docker/data
+minikube cp minikube-m02:/var/log/messages messages.log`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 2 {
+			exit.Message(reason.Usage, `Please specify a source and a target: "minikube cp <source> <target>"`)
+		}
+		co := mustload.Running(ClusterFlagValue())
+
+		srcNode, src := splitNodePath(co.Config, args[0])
+		dstNode, dst := splitNodePath(co.Config, args[1])
+		if srcNode != nil && dstNode != nil {
+			exit.Message(reason.Usage, "Copying between two nodes is not supported")
+		}
+
+		if srcNode != nil {
+			if err := machine.CopyFromNode(nodeRunner(co, srcNode), src, dst); err != nil {
+				exit.Error(reason.GuestCopy, "Failed to copy file", err)
+			}
+			return
+		}
+
+		if dstNode == nil {
+			dstNode = co.CP.Node
+			if cpNode != "" {
+				n, _, err := node.Retrieve(*co.Config, cpNode)
+				if err != nil {
+					exit.Message(reason.GuestNodeRetrieve, "Node {{.nodeName}} does not exist.", out.V{"nodeName": cpNode})
+				}
+				dstNode = n
+			}
+		}
+		if err := machine.CopyToNode(nodeRunner(co, dstNode), src, dst); err != nil {
+			exit.Error(reason.GuestCopy, "Failed to copy file", err)
+		}
+	},
+}
+
+// splitNodePath splits a "node:path" argument, returning a nil node if the prefix does not name a node in the cluster
+func splitNodePath(cc *config.ClusterConfig, arg string) (*config.Node, string) {
+	i := strings.Index(arg, ":")
+	if i <= 0 {
+		return nil, arg
+	}
+	prefix := arg[:i]
+	for _, n := range cc.Nodes {
+		if driver.MachineName(*cc, n) == prefix || (n.Name != "" && n.Name == prefix) {
+			n := n
+			return &n, arg[i+1:]
+		}
+	}
+	return nil, arg
+}
+
+func init() {
+	cpCmd.Flags().StringVarP(&cpNode, "node", "n", "", "The node to copy to, when the target has no node prefix. Defaults to the primary control plane.")
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+func TestSplitNodePath(t *testing.T) {
+	cc := &config.ClusterConfig{
+		Name:  "minikube",
+		Nodes: []config.Node{{Name: "", ControlPlane: true}, {Name: "m02"}},
+	}
+	// wantNode is "-" when the argument does not name a node
+	tests := []struct {
+		arg      string
+		wantNode string
+		wantPath string
+	}{
+		{"/home/docker/a.txt", "-", "/home/docker/a.txt"},
+		{"minikube:/home/docker/a.txt", "", "/home/docker/a.txt"},
+		{"minikube-m02:/home/docker/a.txt", "m02", "/home/docker/a.txt"},
+		{"m02:/home/docker/a.txt", "m02", "/home/docker/a.txt"},
+		{`C:\Users\docker\a.txt`, "-", `C:\Users\docker\a.txt`},
+		{"other:/a.txt", "-", "other:/a.txt"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.arg, func(t *testing.T) {
+			n, p := splitNodePath(cc, tc.arg)
+			gotNode := "-"
+			if n != nil {
+				gotNode = n.Name
+			}
+			if gotNode != tc.wantNode || p != tc.wantPath {
+				t.Errorf("splitNodePath(%q) = (%q, %q), want (%q, %q)", tc.arg, gotNode, p, tc.wantNode, tc.wantPath)
+			}
+		})
+	}
+}
@@ -324,6 +324,9 @@ func deleteProfile(profile *config.Profile) error {
 	if err := killMountProcess(); err != nil {
 		out.FailureT("Failed to kill mount process: {{.error}}", out.V{"error": err})
 	}
+	if cc != nil {
+		stopPersistentMounts(cc)
+	}
 
 	deleteHosts(api, cc)
 
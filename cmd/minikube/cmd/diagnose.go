@@ -0,0 +1,75 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"runtime"
+
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+var diagnoseOS string
+
+// diagnoseCmd represents the diagnose command
+var diagnoseCmd = &cobra.Command{
+	Use:   "diagnose <log file>",
+	Short: "Find known issues within a saved log",
+	Long: `Find known issues within a saved log, such as the output of minikube logs or minikube start --alsologtostderr, without access to the cluster. Use - to read the log from stdin.
+
+Besides the known issues built into minikube, those defined within the YAML files of $MINIKUBE_HOME/.minikube/known_issues.d are matched.`,
+	Example: `minikube logs --file=logs.txt && minikube diagnose logs.txt
+minikube logs | minikube diagnose -`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			exit.Message(reason.Usage, "Usage: minikube diagnose <log file>")
+		}
+		path := args[0]
+
+		var data []byte
+		var err error
+		if path == "-" {
+			data, err = ioutil.ReadAll(os.Stdin)
+		} else {
+			data, err = ioutil.ReadFile(path)
+		}
+		if err != nil {
+			exit.Error(reason.HostPathStat, "Unable to read the log", err)
+		}
+
+		exit.LoadKnownIssues()
+		ds := reason.Diagnose(string(data), diagnoseOS)
+		if len(ds) == 0 {
+			out.Step(style.Happy, "No known issues found")
+			return
+		}
+
+		out.Step(style.Issues, "Found {{.count}} known issues:", out.V{"count": len(ds)})
+		for _, d := range ds {
+			out.WarnReason(d.Kind, "{{.id}}: {{.match}}", out.V{"id": d.ID, "match": d.Match})
+		}
+	},
+}
+
+func init() {
+	diagnoseCmd.Flags().StringVar(&diagnoseOS, "os", runtime.GOOS, "The operating system the log was produced on, for issues specific to it (ex: linux, darwin, windows)")
+}
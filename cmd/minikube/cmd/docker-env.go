@@ -98,6 +98,8 @@ var (
 	sshAdd               bool
 	dockerUnset          bool
 	defaultNoProxyGetter NoProxyGetter
+	// envOutput is a machine readable format to write the environment in, instead of a shell script
+	envOutput string
 )
 
 // NoProxyGetter gets the no_proxy variable
@@ -134,25 +136,28 @@ func dockerShellCfgSet(ec DockerEnvConfig, envMap map[string]string) *DockerShel
 	s.MinikubeDockerdProfile = envMap[constants.MinikubeActiveDockerdEnv]
 
 	if ec.noProxy {
-		noProxyVar, noProxyValue := defaultNoProxyGetter.GetNoProxyVar()
-
-		// add the docker host to the no_proxy list idempotently
-		switch {
-		case noProxyValue == "":
-			noProxyValue = ec.hostIP
-		case strings.Contains(noProxyValue, ec.hostIP):
-		// ip already in no_proxy list, nothing to do
-		default:
-			noProxyValue = fmt.Sprintf("%s,%s", noProxyValue, ec.hostIP)
-		}
-
-		s.NoProxyVar = noProxyVar
-		s.NoProxyValue = noProxyValue
+		s.NoProxyVar, s.NoProxyValue = dockerNoProxy(ec)
 	}
 
 	return s
 }
 
+// dockerNoProxy returns the no_proxy variable, with the docker host added to it
+func dockerNoProxy(ec DockerEnvConfig) (string, string) {
+	noProxyVar, noProxyValue := defaultNoProxyGetter.GetNoProxyVar()
+
+	// add the docker host to the no_proxy list idempotently
+	switch {
+	case noProxyValue == "":
+		noProxyValue = ec.hostIP
+	case strings.Contains(noProxyValue, ec.hostIP):
+	// ip already in no_proxy list, nothing to do
+	default:
+		noProxyValue = fmt.Sprintf("%s,%s", noProxyValue, ec.hostIP)
+	}
+	return noProxyVar, noProxyValue
+}
+
 // GetNoProxyVar gets the no_proxy var
 func (EnvNoProxyGetter) GetNoProxyVar() (string, string) {
 	// first check for an existing lower case no_proxy var
@@ -206,6 +211,7 @@ var dockerEnvCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		var err error
 
+		validateEnvOutput()
 		shl := shell.ForceShell
 		if shl == "" {
 			shl, err = shell.Detect()
@@ -295,7 +301,16 @@ var dockerEnvCmd = &cobra.Command{
 			}
 		}
 
-		if err := dockerSetScript(ec, os.Stdout); err != nil {
+		if envOutput != "" {
+			vars := dockerEnvVars(ec)
+			if ec.noProxy {
+				k, v := dockerNoProxy(ec)
+				vars[k] = v
+			}
+			if err := shell.SetOutput(os.Stdout, envOutput, vars); err != nil {
+				exit.Error(reason.InternalDockerScript, "Error generating set output", err)
+			}
+		} else if err := dockerSetScript(ec, os.Stdout); err != nil {
 			exit.Error(reason.InternalDockerScript, "Error generating set output", err)
 		}
 
@@ -348,9 +363,19 @@ func dockerSetScript(ec DockerEnvConfig, w io.Writer) error {
 // dockerSetScript writes out a shell-compatible 'docker-env unset' script
 func dockerUnsetScript(ec DockerEnvConfig, w io.Writer) error {
 	vars := dockerEnvNames(ec)
+	if envOutput != "" {
+		return shell.UnsetOutput(w, envOutput, vars)
+	}
 	return shell.UnsetScript(ec.EnvConfig, w, vars)
 }
 
+// validateEnvOutput exits if --output is not a supported format
+func validateEnvOutput() {
+	if envOutput != "" && !shell.ValidOutput(envOutput) {
+		exit.Message(reason.Usage, "Invalid output format: {{.output}}. Valid values: {{.outputs}}", out.V{"output": envOutput, "outputs": strings.Join(shell.Outputs, ", ")})
+	}
+}
+
 // dockerURL returns a the docker endpoint URL for an ip/port pair.
 func dockerURL(ip string, port int) string {
 	return fmt.Sprintf("tcp://%s", net.JoinHostPort(ip, strconv.Itoa(port)))
@@ -433,6 +458,7 @@ func init() {
 	dockerEnvCmd.Flags().BoolVar(&noProxy, "no-proxy", false, "Add machine IP to NO_PROXY environment variable")
 	dockerEnvCmd.Flags().BoolVar(&sshHost, "ssh-host", false, "Use SSH connection instead of HTTPS (port 2376)")
 	dockerEnvCmd.Flags().BoolVar(&sshAdd, "ssh-add", false, "Add SSH identity key to SSH authentication agent")
-	dockerEnvCmd.Flags().StringVar(&shell.ForceShell, "shell", "", "Force environment to be configured for a specified shell: [fish, cmd, powershell, tcsh, bash, zsh], default is auto-detect")
+	dockerEnvCmd.Flags().StringVar(&shell.ForceShell, "shell", "", "Force environment to be configured for a specified shell: [fish, cmd, powershell, tcsh, bash, zsh, nushell, xonsh, elvish], default is auto-detect")
+	dockerEnvCmd.Flags().StringVarP(&envOutput, "output", "o", "", fmt.Sprintf("Write the environment in a machine readable format instead of a shell script: [%s]", strings.Join(shell.Outputs, ", ")))
 	dockerEnvCmd.Flags().BoolVarP(&dockerUnset, "unset", "u", false, "Unset variables instead of setting them")
 }
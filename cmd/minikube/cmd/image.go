@@ -0,0 +1,226 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/node"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+)
+
+var (
+	imageNode    string
+	buildOptions cruntime.BuildOptions
+)
+
+// imageCmd represents the image command
+var imageCmd = &cobra.Command{
+	Use:   "image",
+	Short: "Manage images in the cluster",
+	Long:  "Load, build, list, remove, pull, push and save images in the container runtime of every node in the cluster",
+}
+
+// loadImageCmd represents the image load command
+var loadImageCmd = &cobra.Command{
+	Use:     "load IMAGE | ARCHIVE",
+	Short:   "Load an image into the cluster",
+	Long:    "Load an image from the local daemon, a remote registry or a tarball into every node in the cluster",
+	Example: "minikube image load myapp:latest\nminikube image load myapp.tar",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			exit.Message(reason.Usage, "Please provide an image or archive to load: minikube image load <IMAGE|ARCHIVE>")
+		}
+		co := mustload.Running(ClusterFlagValue())
+
+		var images, tarballs []string
+		for _, a := range args {
+			if fi, err := os.Stat(a); err == nil && !fi.IsDir() {
+				tarballs = append(tarballs, a)
+				continue
+			}
+			images = append(images, a)
+		}
+
+		if len(tarballs) > 0 {
+			if err := machine.LoadTarballsIntoCluster(co.API, co.Config, tarballs); err != nil {
+				exit.Error(reason.GuestImageLoad, "Failed to load image", err)
+			}
+		}
+		if len(images) > 0 {
+			if err := machine.LoadImagesIntoCluster(co.API, co.Config, images); err != nil {
+				exit.Error(reason.GuestImageLoad, "Failed to load image", err)
+			}
+		}
+	},
+}
+
+// buildImageCmd represents the image build command
+var buildImageCmd = &cobra.Command{
+	Use:     "build PATH",
+	Short:   "Build an image in the cluster",
+	Long:    "Build an image from a local context directory inside every node in the cluster, using the builder of the active container runtime (docker, buildkit or podman)",
+	Example: "minikube image build -t myapp:latest .",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			exit.Message(reason.Usage, "Please provide a path to the build context: minikube image build <PATH>")
+		}
+		co := mustload.Running(ClusterFlagValue())
+		if err := machine.BuildImage(co.API, co.Config, args[0], buildOptions); err != nil {
+			exit.Error(reason.GuestImageBuild, "Failed to build image", err)
+		}
+	},
+}
+
+// listImageCmd represents the image ls command
+var listImageCmd = &cobra.Command{
+	Use:     "ls",
+	Aliases: []string{"list"},
+	Short:   "List images in the cluster",
+	Long:    "List the images present in the container runtime of any node in the cluster",
+	Run: func(cmd *cobra.Command, args []string) {
+		co := mustload.Running(ClusterFlagValue())
+		images, err := machine.ListImages(co.API, co.Config)
+		if err != nil {
+			exit.Error(reason.GuestImageList, "Failed to list images", err)
+		}
+		for _, img := range images {
+			out.Ln("%s", img)
+		}
+	},
+}
+
+// removeImageCmd represents the image rm command
+var removeImageCmd = &cobra.Command{
+	Use:     "rm IMAGE [IMAGE...]",
+	Aliases: []string{"remove"},
+	Short:   "Remove images from the cluster",
+	Long:    "Remove images from the container runtime of every node in the cluster",
+	Example: "minikube image rm myapp:latest",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			exit.Message(reason.Usage, "Please provide an image to remove: minikube image rm <IMAGE>")
+		}
+		co := mustload.Running(ClusterFlagValue())
+		if err := machine.RemoveImages(co.API, co.Config, args); err != nil {
+			exit.Error(reason.GuestImageRemove, "Failed to remove image", err)
+		}
+	},
+}
+
+// pullImageCmd represents the image pull command
+var pullImageCmd = &cobra.Command{
+	Use:     "pull IMAGE [IMAGE...]",
+	Short:   "Pull images into the cluster",
+	Long:    "Pull images from their registries into the container runtime of every node in the cluster",
+	Example: "minikube image pull busybox:latest",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			exit.Message(reason.Usage, "Please provide an image to pull: minikube image pull <IMAGE>")
+		}
+		co := mustload.Running(ClusterFlagValue())
+		if err := machine.PullImages(co.API, co.Config, args); err != nil {
+			exit.Error(reason.GuestImagePull, "Failed to pull image", err)
+		}
+	},
+}
+
+// pushImageCmd represents the image push command
+var pushImageCmd = &cobra.Command{
+	Use:     "push IMAGE [IMAGE...]",
+	Short:   "Push images from the cluster",
+	Long:    "Push images from the container runtime of a node to their registries",
+	Example: "minikube image push localhost:5000/myapp:latest",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			exit.Message(reason.Usage, "Please provide an image to push: minikube image push <IMAGE>")
+		}
+		co := mustload.Running(ClusterFlagValue())
+		if err := machine.PushImages(co.Config, imageNodeRunner(co), args); err != nil {
+			exit.Error(reason.GuestImagePush, "Failed to push image", err)
+		}
+	},
+}
+
+// saveImageCmd represents the image save command
+var saveImageCmd = &cobra.Command{
+	Use:     "save IMAGE ARCHIVE",
+	Short:   "Save an image from the cluster",
+	Long:    "Save an image from the container runtime of a node into a tarball on the host",
+	Example: "minikube image save myapp:latest myapp.tar",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 2 {
+			exit.Message(reason.Usage, "Please provide an image and a destination archive: minikube image save <IMAGE> <ARCHIVE>")
+		}
+		co := mustload.Running(ClusterFlagValue())
+		if err := machine.SaveImage(co.Config, imageNodeRunner(co), args[0], args[1]); err != nil {
+			exit.Error(reason.GuestImageSave, "Failed to save image", err)
+		}
+	},
+}
+
+// imageNodeRunner returns a command runner for the node selected by --node, defaulting to the primary control plane
+func imageNodeRunner(co mustload.ClusterController) command.Runner {
+	if imageNode == "" {
+		return co.CP.Runner
+	}
+	n, _, err := node.Retrieve(*co.Config, imageNode)
+	if err != nil {
+		exit.Message(reason.GuestNodeRetrieve, "Node {{.nodeName}} does not exist.", out.V{"nodeName": imageNode})
+	}
+	return nodeRunner(co, n)
+}
+
+// nodeRunner returns a command runner for a node of a running cluster
+func nodeRunner(co mustload.ClusterController, n *config.Node) command.Runner {
+	h, err := machine.LoadHost(co.API, driver.MachineName(*co.Config, *n))
+	if err != nil {
+		exit.Error(reason.GuestLoadHost, "Unable to load host", err)
+	}
+	r, err := machine.CommandRunner(h)
+	if err != nil {
+		exit.Error(reason.InternalCommandRunner, "Unable to get command runner", err)
+	}
+	return r
+}
+
+func init() {
+	buildImageCmd.Flags().StringVarP(&buildOptions.Tag, "tag", "t", "", "Tag to apply to the new image (optional)")
+	buildImageCmd.Flags().StringVarP(&buildOptions.File, "file", "f", "", "Path to the Dockerfile, relative to the build context (optional)")
+	buildImageCmd.Flags().BoolVar(&buildOptions.Push, "push", false, "Push the new image from the primary control plane (requires tag)")
+	buildImageCmd.Flags().StringArrayVar(&buildOptions.Env, "build-env", nil, "Environment variables to pass to the build. (format: key=value)")
+	buildImageCmd.Flags().StringArrayVar(&buildOptions.Opts, "build-opt", nil, "Additional options to pass to the builder. (format: key=value)")
+	pushImageCmd.Flags().StringVarP(&imageNode, "node", "n", "", "The node to push from. Defaults to the primary control plane.")
+	saveImageCmd.Flags().StringVarP(&imageNode, "node", "n", "", "The node to save from. Defaults to the primary control plane.")
+
+	imageCmd.AddCommand(loadImageCmd)
+	imageCmd.AddCommand(buildImageCmd)
+	imageCmd.AddCommand(listImageCmd)
+	imageCmd.AddCommand(removeImageCmd)
+	imageCmd.AddCommand(pullImageCmd)
+	imageCmd.AddCommand(pushImageCmd)
+	imageCmd.AddCommand(saveImageCmd)
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/kubeconfig"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/shell"
+)
+
+var kubectlEnvTmpl = fmt.Sprintf(
+	"{{ .Prefix }}%s{{ .Delimiter }}{{ .Kubeconfig }}{{ .Suffix }}"+
+		"{{ if .ExistingKubeconfig }}"+
+		"{{ .Prefix }}%s{{ .Delimiter }}{{ .ExistingKubeconfig }}{{ .Suffix }}"+
+		"{{ end }}"+
+		"{{ .Prefix }}%s{{ .Delimiter }}{{ .MinikubeKubeconfigProfile }}{{ .Suffix }}"+
+		"{{ .UsageHint }}",
+	constants.KubeconfigEnvVar,
+	constants.ExistingKubeconfigEnv,
+	constants.MinikubeActiveKubeconfigEnv)
+
+// KubectlShellConfig represents the shell config for kubectl
+type KubectlShellConfig struct {
+	shell.Config
+	Kubeconfig                string
+	MinikubeKubeconfigProfile string
+
+	ExistingKubeconfig string
+}
+
+// KubectlEnvConfig encapsulates all external inputs into shell generation for kubectl
+type KubectlEnvConfig struct {
+	shell.EnvConfig
+	profile    string
+	kubeconfig string
+}
+
+var kubectlUnset bool
+
+// kubectlEnvCmd represents the kubectl-env command
+var kubectlEnvCmd = &cobra.Command{
+	Use:   "kubectl-env",
+	Short: "Configure environment to use the kubeconfig of a minikube cluster",
+	Long: `Sets up the KUBECONFIG env variable to point to a kubeconfig holding only the context of the cluster, so that kubectl in this shell uses the cluster regardless of the current context.
+
+The kubeconfig is written to the profile directory when the command runs. Run it again after the cluster restarts, as the address of its API server may change.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		var err error
+
+		validateEnvOutput()
+		shl := shell.ForceShell
+		if shl == "" {
+			shl, err = shell.Detect()
+			if err != nil {
+				exit.Error(reason.InternalShellDetect, "Error detecting shell", err)
+			}
+		}
+		sh := shell.EnvConfig{
+			Shell: shl,
+		}
+
+		if kubectlUnset {
+			if err := kubectlUnsetScript(KubectlEnvConfig{EnvConfig: sh}, os.Stdout); err != nil {
+				exit.Error(reason.InternalEnvScript, "Error generating unset output", err)
+			}
+			return
+		}
+
+		cname := ClusterFlagValue()
+		co := mustload.Running(cname)
+		ec := KubectlEnvConfig{
+			EnvConfig:  sh,
+			profile:    cname,
+			kubeconfig: localpath.Kubeconfig(cname),
+		}
+		if err := kubeconfig.ExtractContext(co.Config.Name, ec.kubeconfig, kubectlSourceConfig()); err != nil {
+			exit.Error(reason.HostKubeconfigUpdate, "Failed to write the kubeconfig of the cluster", err)
+		}
+
+		if envOutput != "" {
+			err = kubectlSetOutput(ec, os.Stdout)
+		} else {
+			err = kubectlSetScript(ec, os.Stdout)
+		}
+		if err != nil {
+			exit.Error(reason.InternalEnvScript, "Error generating set output", err)
+		}
+	},
+}
+
+// kubectlShellCfgSet generates context variables for "kubectl-env"
+func kubectlShellCfgSet(ec KubectlEnvConfig, envMap map[string]string) *KubectlShellConfig {
+	const usgPlz = "To point your shell to the kubeconfig of the cluster, run:"
+	usgCmd := fmt.Sprintf("minikube -p %s kubectl-env", ec.profile)
+	return &KubectlShellConfig{
+		Config:                    *shell.CfgSet(ec.EnvConfig, usgPlz, usgCmd),
+		Kubeconfig:                envMap[constants.KubeconfigEnvVar],
+		MinikubeKubeconfigProfile: envMap[constants.MinikubeActiveKubeconfigEnv],
+		ExistingKubeconfig:        envMap[constants.ExistingKubeconfigEnv],
+	}
+}
+
+// kubectlSetScript writes out a shell-compatible 'kubectl-env' script
+func kubectlSetScript(ec KubectlEnvConfig, w io.Writer) error {
+	return shell.SetScript(ec.EnvConfig, w, kubectlEnvTmpl, kubectlShellCfgSet(ec, kubectlEnvVars(ec)))
+}
+
+// kubectlSetOutput writes out the 'kubectl-env' variables in the format of --output
+func kubectlSetOutput(ec KubectlEnvConfig, w io.Writer) error {
+	return shell.SetOutput(w, envOutput, kubectlEnvVars(ec))
+}
+
+// kubectlUnsetScript writes out a shell-compatible 'kubectl-env unset' script
+func kubectlUnsetScript(ec KubectlEnvConfig, w io.Writer) error {
+	vars := []string{constants.KubeconfigEnvVar, constants.MinikubeActiveKubeconfigEnv}
+	if envOutput != "" {
+		return shell.UnsetOutput(w, envOutput, vars)
+	}
+	return shell.UnsetScript(ec.EnvConfig, w, vars)
+}
+
+// kubectlEnvVars gets the env variables pointing kubectl to the kubeconfig of the cluster
+func kubectlEnvVars(ec KubectlEnvConfig) map[string]string {
+	env := map[string]string{
+		constants.KubeconfigEnvVar:            ec.kubeconfig,
+		constants.MinikubeActiveKubeconfigEnv: ec.profile,
+	}
+	// save the kubeconfig the shell used before, so that it is restored by --unset
+	if os.Getenv(constants.MinikubeActiveKubeconfigEnv) == "" {
+		if v := os.Getenv(constants.KubeconfigEnvVar); v != "" {
+			env[constants.ExistingKubeconfigEnv] = v
+		}
+	}
+	return env
+}
+
+// kubectlSourceConfig returns the kubeconfig to extract the context of the cluster from: the one the shell used before kubectl-env
+func kubectlSourceConfig() string {
+	if os.Getenv(constants.MinikubeActiveKubeconfigEnv) == "" {
+		return kubeconfig.PathFromEnv()
+	}
+	if v := os.Getenv(constants.ExistingKubeconfigEnv); v != "" {
+		return filepath.SplitList(v)[0]
+	}
+	return clientcmd.RecommendedHomeFile
+}
+
+func init() {
+	kubectlEnvCmd.Flags().StringVar(&shell.ForceShell, "shell", "", "Force environment to be configured for a specified shell: [fish, cmd, powershell, tcsh, bash, zsh, nushell, xonsh, elvish], default is auto-detect")
+	kubectlEnvCmd.Flags().StringVarP(&envOutput, "output", "o", "", fmt.Sprintf("Write the environment in a machine readable format instead of a shell script: [%s]", strings.Join(shell.Outputs, ", ")))
+	kubectlEnvCmd.Flags().BoolVarP(&kubectlUnset, "unset", "u", false, "Unset variables instead of setting them")
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+func TestGenerateKubectlScripts(t *testing.T) {
+	var tests = []struct {
+		shell     string
+		output    string
+		existing  string
+		config    KubectlEnvConfig
+		wantSet   string
+		wantUnset string
+	}{
+		{
+			"bash",
+			"",
+			"",
+			KubectlEnvConfig{profile: "bash", kubeconfig: "/home/user/.minikube/profiles/bash/kubeconfig"},
+			`export KUBECONFIG="/home/user/.minikube/profiles/bash/kubeconfig"
+export MINIKUBE_ACTIVE_KUBECONFIG="bash"
+
+# To point your shell to the kubeconfig of the cluster, run:
+# eval $(minikube -p bash kubectl-env)
+`,
+			`unset KUBECONFIG;
+unset MINIKUBE_ACTIVE_KUBECONFIG;
+`,
+		},
+		{
+			"elvish",
+			"",
+			"/home/user/.kube/work",
+			KubectlEnvConfig{profile: "elvish", kubeconfig: "/home/user/.minikube/profiles/elvish/kubeconfig"},
+			`set-env KUBECONFIG "/home/user/.minikube/profiles/elvish/kubeconfig"
+set-env MINIKUBE_EXISTING_KUBECONFIG "/home/user/.kube/work"
+set-env MINIKUBE_ACTIVE_KUBECONFIG "elvish"
+
+# To point your shell to the kubeconfig of the cluster, run:
+# eval (minikube -p elvish kubectl-env --shell elvish | slurp)
+`,
+			`unset-env KUBECONFIG
+unset-env MINIKUBE_ACTIVE_KUBECONFIG
+`,
+		},
+		{
+			"bash",
+			"json",
+			"",
+			KubectlEnvConfig{profile: "json", kubeconfig: "/home/user/.minikube/profiles/json/kubeconfig"},
+			`{
+  "KUBECONFIG": "/home/user/.minikube/profiles/json/kubeconfig",
+  "MINIKUBE_ACTIVE_KUBECONFIG": "json"
+}
+`,
+			`{
+  "KUBECONFIG": null,
+  "MINIKUBE_ACTIVE_KUBECONFIG": null
+}
+`,
+		},
+	}
+	defer os.Setenv(constants.KubeconfigEnvVar, os.Getenv(constants.KubeconfigEnvVar))
+	defer os.Setenv(constants.MinikubeActiveKubeconfigEnv, os.Getenv(constants.MinikubeActiveKubeconfigEnv))
+	defer func() {
+		envOutput = ""
+	}()
+	os.Unsetenv(constants.MinikubeActiveKubeconfigEnv)
+
+	for _, tc := range tests {
+		t.Run(tc.config.profile, func(t *testing.T) {
+			tc.config.EnvConfig.Shell = tc.shell
+			envOutput = tc.output
+			os.Setenv(constants.KubeconfigEnvVar, tc.existing)
+			var b []byte
+			buf := bytes.NewBuffer(b)
+			if tc.output != "" {
+				if err := kubectlSetOutput(tc.config, buf); err != nil {
+					t.Errorf("setOutput(%+v) error: %v", tc.config, err)
+				}
+			} else if err := kubectlSetScript(tc.config, buf); err != nil {
+				t.Errorf("setScript(%+v) error: %v", tc.config, err)
+			}
+			got := buf.String()
+			if diff := cmp.Diff(tc.wantSet, got); diff != "" {
+				t.Errorf("setScript(%+v) mismatch (-want +got):\n%s\n\nraw output:\n%s\nquoted: %q", tc.config, diff, got, got)
+			}
+
+			buf = bytes.NewBuffer(b)
+			if err := kubectlUnsetScript(tc.config, buf); err != nil {
+				t.Errorf("unsetScript(%+v) error: %v", tc.config, err)
+			}
+			got = buf.String()
+			if diff := cmp.Diff(tc.wantUnset, got); diff != "" {
+				t.Errorf("unsetScript(%+v) mismatch (-want +got):\n%s\n\nraw output:\n%s\nquoted: %q", tc.config, diff, got, got)
+			}
+		})
+	}
+}
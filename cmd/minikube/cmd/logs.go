@@ -18,17 +18,22 @@ package cmd
 
 import (
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
 	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/driver"
 	"k8s.io/minikube/pkg/minikube/exit"
 	"k8s.io/minikube/pkg/minikube/logs"
 	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/node"
 	"k8s.io/minikube/pkg/minikube/out"
 	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
 )
 
 const (
@@ -44,6 +49,14 @@ var (
 	numberOfLines int
 	// showProblems only shows lines that match known issues
 	showProblems bool
+	// bundleFile is the file to write a support bundle to
+	bundleFile string
+	// allNodes gets the logs of every node
+	allNodes bool
+	// logsComponents restricts the logs to these components
+	logsComponents []string
+	// logsSince is how far back to get logs from
+	logsSince time.Duration
 )
 
 // logsCmd represents the logs command
@@ -54,40 +67,101 @@ var logsCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		co := mustload.Running(ClusterFlagValue())
 
-		bs, err := cluster.Bootstrapper(co.API, viper.GetString(cmdcfg.Bootstrapper), *co.Config, co.CP.Runner)
-		if err != nil {
-			exit.Error(reason.InternalBootstrapper, "Error getting cluster bootstrapper", err)
+		if bundleFile != "" {
+			out.Step(style.Waiting, "Collecting the logs of {{.cluster}} into {{.file}} ...", out.V{"cluster": co.Config.Name, "file": bundleFile})
+			if err := logs.Bundle(bundleFile, *co.Config, logSources(co, co.Config.Nodes), numberOfLines); err != nil {
+				exit.Error(reason.HostLogsBundle, "Failed to write the support bundle", err)
+			}
+			out.Step(style.Documentation, "Please attach {{.file}} to the GitHub issue", out.V{"file": bundleFile})
+			return
 		}
 
-		cr, err := cruntime.New(cruntime.Config{Type: co.Config.KubernetesConfig.ContainerRuntime, Runner: co.CP.Runner})
-		if err != nil {
-			exit.Error(reason.InternalNewRuntime, "Unable to get runtime", err)
+		sources := logSources(co, logNodes(co))
+		o := logs.Options{Lines: numberOfLines, Since: logsSince, Components: logsComponents}
+		// --since alone includes every line since then
+		if logsSince > 0 && !cmd.Flags().Changed("length") {
+			o.Lines = 0
 		}
 		if followLogs {
-			err := logs.Follow(cr, bs, *co.Config, co.CP.Runner)
+			err := logs.Follow(sources, *co.Config, o)
 			if err != nil {
 				exit.Error(reason.InternalLogFollow, "Follow", err)
 			}
 			return
 		}
 		if showProblems {
-			problems := logs.FindProblems(cr, bs, *co.Config, co.CP.Runner)
-			logs.OutputProblems(problems, numberOfProblems)
+			for _, s := range sources {
+				problems := logs.FindProblems(s.Runtime, s.Bootstrapper, *co.Config, s.Runner)
+				logs.OutputProblems(problems, numberOfProblems)
+			}
 			return
 		}
-		err = logs.Output(cr, bs, *co.Config, co.CP.Runner, numberOfLines)
-		if err != nil {
-			out.Ln("")
-			// Avoid exit.Error, since it outputs the issue URL
-			out.WarningT("{{.error}}", out.V{"error": err})
+
+		failed := false
+		for i, s := range sources {
+			if len(sources) > 1 {
+				if i > 0 {
+					out.Step(style.Empty, "")
+				}
+				out.Step(style.Empty, "==> node {{.name}} <==", out.V{"name": driver.MachineName(*co.Config, s.Node)})
+			}
+			if err := logs.Output(s.Runtime, s.Bootstrapper, *co.Config, s.Runner, o); err != nil {
+				out.Ln("")
+				// Avoid exit.Error, since it outputs the issue URL
+				out.WarningT("{{.error}}", out.V{"error": err})
+				failed = true
+			}
+		}
+		if failed {
 			os.Exit(reason.ExSvcError)
 		}
 	},
 }
 
+// logNodes returns the nodes to get logs from: every node with --all-nodes, the node chosen with --node, or the primary control plane
+func logNodes(co mustload.ClusterController) []config.Node {
+	if allNodes {
+		if nodeName != "" {
+			exit.Message(reason.Usage, "The --node and --all-nodes flags cannot be used together")
+		}
+		return co.Config.Nodes
+	}
+	if nodeName == "" {
+		return []config.Node{*co.CP.Node}
+	}
+	n, _, err := node.Retrieve(*co.Config, nodeName)
+	if err != nil {
+		exit.Message(reason.GuestNodeRetrieve, "Node {{.nodeName}} does not exist.", out.V{"nodeName": nodeName})
+	}
+	return []config.Node{*n}
+}
+
+// logSources returns the log sources of nodes of a cluster
+func logSources(co mustload.ClusterController, nodes []config.Node) []logs.Source {
+	sources := []logs.Source{}
+	for _, n := range nodes {
+		n := n
+		r := nodeRunner(co, &n)
+		bs, err := cluster.Bootstrapper(co.API, viper.GetString(cmdcfg.Bootstrapper), *co.Config, r)
+		if err != nil {
+			exit.Error(reason.InternalBootstrapper, "Error getting cluster bootstrapper", err)
+		}
+		cr, err := cruntime.New(cruntime.Config{Type: co.Config.KubernetesConfig.ContainerRuntime, Runner: r})
+		if err != nil {
+			exit.Error(reason.InternalNewRuntime, "Unable to get runtime", err)
+		}
+		sources = append(sources, logs.Source{Node: n, Primary: n.Name == co.CP.Node.Name, Runtime: cr, Bootstrapper: bs, Runner: r})
+	}
+	return sources
+}
+
 func init() {
 	logsCmd.Flags().BoolVarP(&followLogs, "follow", "f", false, "Show only the most recent journal entries, and continuously print new entries as they are appended to the journal.")
 	logsCmd.Flags().BoolVar(&showProblems, "problems", false, "Show only log entries which point to known problems")
 	logsCmd.Flags().IntVarP(&numberOfLines, "length", "n", 60, "Number of lines back to go within the log")
+	logsCmd.Flags().StringVar(&bundleFile, "bundle", "", "Write the logs of every node, the redacted cluster configuration, the events of the last start and an index of the problems found to a support bundle (ex: minikube-logs.tar.gz)")
 	logsCmd.Flags().StringVar(&nodeName, "node", "", "The node to get logs from. Defaults to the primary control plane.")
+	logsCmd.Flags().BoolVar(&allNodes, "all-nodes", false, "Get the logs of every node. When following the logs, each line is prefixed with the name of its node.")
+	logsCmd.Flags().StringSliceVar(&logsComponents, "component", nil, "Only get the logs of these components: kubelet, dmesg, the container runtime (docker, containerd or crio), apiserver, controller-manager, scheduler, proxy, or the name of any container, such as etcd or coredns")
+	logsCmd.Flags().DurationVar(&logsSince, "since", 0, "Only get the logs newer than a relative duration (ex: 10m, 1h)")
 }
@@ -0,0 +1,46 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/metrics"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+// metricsAddr is the address on which long-running commands serve Prometheus metrics
+var metricsAddr string
+
+// addMetricsFlag adds the --metrics-addr flag to a long-running command
+func addMetricsFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&metricsAddr, "metrics-addr", "", "If set, serve Prometheus metrics on this address (ex: 127.0.0.1:9100), at /metrics")
+}
+
+// serveMetrics serves Prometheus metrics in the background, if --metrics-addr was set
+func serveMetrics() {
+	if metricsAddr == "" {
+		return
+	}
+	addr, err := metrics.Listen(metricsAddr)
+	if err != nil {
+		exit.Error(reason.HostMetricsListen, "Failed to serve metrics", err)
+	}
+	out.Step(style.URL, "Serving metrics on http://{{.addr}}/metrics", out.V{"addr": addr})
+}
@@ -17,26 +17,42 @@ limitations under the License.
 package cmd
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"net"
 	"os"
+	"os/exec"
 	"os/signal"
+	"path/filepath"
 	"runtime"
 	"strconv"
 	"strings"
 	"sync"
 	"syscall"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
 	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/drivers/kic/oci"
 	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/minikube/driver"
 	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/metrics"
+	"k8s.io/minikube/pkg/minikube/mount"
 	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/nfs"
 	"k8s.io/minikube/pkg/minikube/out"
 	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/sshutil"
 	"k8s.io/minikube/pkg/minikube/style"
+	"k8s.io/minikube/third_party/go9p"
 	"k8s.io/minikube/third_party/go9p/ufs"
 )
 
@@ -45,6 +61,9 @@ const (
 	nineP               = "9p"
 	defaultMountVersion = "9p2000.L"
 	defaultMsize        = 262144
+	defaultSyncInterval = 2 * time.Second
+	// nodeIPsInterval is how often the 9p server looks for nodes added to or removed from the cluster
+	nodeIPsInterval = 10 * time.Second
 )
 
 // placeholders for flag values
@@ -58,16 +77,34 @@ var (
 	mSize        int
 	options      []string
 	mode         uint
+	syncDir      string
+	syncInterval time.Duration
+	syncIgnore   []string
+	notifyMount  bool
+	persistMount bool
+	readOnly     bool
+	allowPaths   []string
+	umask        uint
+	authMount    bool
+	supervise    string
 )
 
 // supportedFilesystems is a map of filesystem types to not warn against.
-var supportedFilesystems = map[string]bool{nineP: true}
+var supportedFilesystems = map[string]bool{nineP: true, cluster.MountNFS: true, cluster.MountSSHFS: true, cluster.MountSync: true}
 
 // mountCmd represents the mount command
 var mountCmd = &cobra.Command{
 	Use:   "mount [flags] <source directory>:<target directory>",
 	Short: "Mounts the specified directory into minikube",
-	Long:  `Mounts the specified directory into minikube.`,
+	Long: `Mounts the specified directory into minikube.
+
+--type selects how the directory is shared:
+  9p: a 9p filesystem, served by a userspace server on the host
+  nfs: an NFSv3 filesystem, served by a userspace server on the host, which performs better with large directories
+  sshfs: an sshfs filesystem, served over the SSH connection to the node, so that no port of the host needs to be reachable
+  sync: a copy of the directory, kept up to date as files change, which is as fast as local files and notifies file watchers
+
+The file watchers of the node, such as nodemon or webpack in watch mode, are not notified of the changes made on the host to 9p, nfs and sshfs mounts. With --notify, the changed files are touched on the node as they change on the host, which notifies them.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		if isKill {
 			if err := killMountProcess(); err != nil {
@@ -75,6 +112,10 @@ var mountCmd = &cobra.Command{
 			}
 			os.Exit(0)
 		}
+		if supervise != "" {
+			superviseMount(ClusterFlagValue(), supervise)
+			return
+		}
 
 		if len(args) != 1 {
 			exit.Message(reason.Usage, `Please specify the directory to be mounted: 
@@ -107,33 +148,38 @@ var mountCmd = &cobra.Command{
 			exit.Message(reason.Usage, `'none' driver does not support 'minikube mount' command`)
 		}
 
-		var ip net.IP
-		var err error
-		if mountIP == "" {
-			ip, err = cluster.HostIP(co.CP.Host, co.Config.Name)
-			if err != nil {
-				exit.Error(reason.IfHostIP, "Error getting the host IP address to use from within the VM", err)
-			}
-		} else {
-			ip = net.ParseIP(mountIP)
-			if ip == nil {
-				exit.Message(reason.IfMountIP, "error parsing the input ip address for mount")
-			}
+		cfg := &cluster.MountConfig{
+			Type:          mountType,
+			UID:           uid,
+			GID:           gid,
+			Version:       mountVersion,
+			MSize:         mSize,
+			Mode:          os.FileMode(mode),
+			Options:       map[string]string{},
+			SyncDirection: syncDir,
+			SyncInterval:  syncInterval,
+			SyncIgnore:    syncIgnore,
+			Notify:        notifyMount,
+			ReadOnly:      readOnly,
 		}
-		port, err := getPort()
-		if err != nil {
-			exit.Error(reason.IfMountPort, "Error finding port for mount", err)
+		if syncDir != cluster.SyncOneWay && syncDir != cluster.SyncTwoWay {
+			exit.Message(reason.Usage, "--sync-direction must be {{.oneway}} or {{.twoway}}", out.V{"oneway": cluster.SyncOneWay, "twoway": cluster.SyncTwoWay})
+		}
+		if syncInterval <= 0 {
+			exit.Message(reason.Usage, "--sync-interval must be positive")
 		}
 
-		cfg := &cluster.MountConfig{
-			Type:    mountType,
-			UID:     uid,
-			GID:     gid,
-			Version: mountVersion,
-			MSize:   mSize,
-			Port:    port,
-			Mode:    os.FileMode(mode),
-			Options: map[string]string{},
+		if cfg.Type != nineP && (readOnly || len(allowPaths) > 0 || umask != 0 || authMount) {
+			exit.Message(reason.Usage, "--read-only, --allow, --umask and --9p-auth are only supported by 9p mounts")
+		}
+		for _, a := range allowPaths {
+			c := filepath.ToSlash(filepath.Clean(a))
+			if filepath.IsAbs(a) || c == ".." || strings.HasPrefix(c, "../") {
+				exit.Message(reason.Usage, "--allow path {{.path}} must be relative to the host directory, and within it", out.V{"path": a})
+			}
+		}
+		if umask > 0o777 {
+			exit.Message(reason.Usage, "--umask must be an octal permission mask, such as 022")
 		}
 
 		for _, o := range options {
@@ -145,64 +191,474 @@ var mountCmd = &cobra.Command{
 			cfg.Options[parts[0]] = parts[1]
 		}
 
-		// An escape valve to allow future hackers to try NFS, VirtFS, or other FS types.
+		// An escape valve to allow future hackers to try VirtFS, or other FS types.
 		if !supportedFilesystems[cfg.Type] {
 			out.WarningT("{{.type}} is not yet a supported filesystem. We will try anyways!", out.V{"type": cfg.Type})
 		}
 
-		bindIP := ip.String() // the ip to listen on the user's host machine
-		if driver.IsKIC(co.CP.Host.Driver.DriverName()) && runtime.GOOS != "linux" {
-			bindIP = "127.0.0.1"
+		if persistMount {
+			abs, err := filepath.Abs(hostPath)
+			if err != nil {
+				exit.Error(reason.HostPathStat, "Unable to get the absolute path of the host directory", err)
+			}
+			startPersistentMount(co.Config, newMount(abs, vmPath))
+			return
 		}
+
 		out.Step(style.Mounting, "Mounting host path {{.sourcePath}} into VM as {{.destinationPath}} ...", out.V{"sourcePath": hostPath, "destinationPath": vmPath})
 		out.Infof("Mount type:   {{.name}}", out.V{"type": cfg.Type})
 		out.Infof("User ID:      {{.userID}}", out.V{"userID": cfg.UID})
 		out.Infof("Group ID:     {{.groupID}}", out.V{"groupID": cfg.GID})
-		out.Infof("Version:      {{.version}}", out.V{"version": cfg.Version})
-		out.Infof("Message Size: {{.size}}", out.V{"size": cfg.MSize})
+		if cfg.Type == nineP {
+			out.Infof("Version:      {{.version}}", out.V{"version": cfg.Version})
+			out.Infof("Message Size: {{.size}}", out.V{"size": cfg.MSize})
+			if readOnly {
+				out.Infof("Read only:    {{.readOnly}}", out.V{"readOnly": readOnly})
+			}
+			if len(allowPaths) > 0 {
+				out.Infof("Allowed:      {{.paths}}", out.V{"paths": strings.Join(allowPaths, ", ")})
+			}
+			if umask != 0 {
+				out.Infof("Umask:        {{.umask}}", out.V{"umask": fmt.Sprintf("%03o", umask)})
+			}
+		}
 		out.Infof("Permissions:  {{.octalMode}} ({{.writtenMode}})", out.V{"octalMode": fmt.Sprintf("%o", cfg.Mode), "writtenMode": cfg.Mode})
 		out.Infof("Options:      {{.options}}", out.V{"options": cfg.Options})
+		if cfg.Notify && cfg.Type == cluster.MountSync {
+			out.WarningT("--notify is not needed with --type=sync, whose changes notify file watchers")
+			cfg.Notify = false
+		}
+
+		// sshfs and syncs go through the SSH connection to the node, rather than a server on the host
+		switch cfg.Type {
+		case cluster.MountSSHFS:
+			handleUnmount(co.CP.Runner, vmPath)
+			mountSSHFS(co, hostPath, vmPath, cfg)
+			return
+		case cluster.MountSync:
+			syncMount(co, hostPath, vmPath, cfg)
+			return
+		}
+
+		var ip net.IP
+		var err error
+		if mountIP == "" {
+			ip, err = cluster.HostIP(co.CP.Host, driver.NetworkName(*co.Config))
+			if err != nil {
+				exit.Error(reason.IfHostIP, "Error getting the host IP address to use from within the VM", err)
+			}
+		} else {
+			ip = net.ParseIP(mountIP)
+			if ip == nil {
+				exit.Message(reason.IfMountIP, "error parsing the input ip address for mount")
+			}
+		}
+		port, err := getPort()
+		if err != nil {
+			exit.Error(reason.IfMountPort, "Error finding port for mount", err)
+		}
+		cfg.Port = port
+
+		bindIP := ip.String() // the ip to listen on the user's host machine
+		if driver.IsKIC(co.CP.Host.Driver.DriverName()) && runtime.GOOS != "linux" {
+			bindIP = "127.0.0.1"
+		}
 		out.Infof("Bind Address: {{.Address}}", out.V{"Address": net.JoinHostPort(bindIP, fmt.Sprint(port))})
+		// the 9p and nfs servers only accept connections from the nodes, unless they listen on the loopback address of
+		// the host, which only the host and the nodes can connect to
+		var allowedHosts []net.IP
+		var hostUpdates <-chan []net.IP
+		if !net.ParseIP(bindIP).IsLoopback() {
+			allowedHosts = nodeIPs(co.Config, net.ParseIP(bindIP))
+			if len(allowedHosts) == 0 {
+				exit.Message(reason.IfMountIP, "None of the nodes has an address in the IP family of {{.ip}}, set the address to bind to with --ip", out.V{"ip": bindIP})
+			}
+			out.Infof("Allowed IPs:  {{.hosts}}", out.V{"hosts": allowedHosts})
+			hostUpdates = watchNodeIPs(co.Config.Name, net.ParseIP(bindIP), allowedHosts)
+		}
+		if authMount {
+			cfg.Token, err = mountToken()
+			if err != nil {
+				exit.Error(reason.GuestMount, "Error generating the mount token", err)
+			}
+		}
+
+		serveMetrics()
+		var observer go9p.StatsObserver
+		if metricsAddr != "" {
+			observer = metrics.NinePObserver{}
+		}
 
 		var wg sync.WaitGroup
 		if cfg.Type == nineP {
+			// the 9p server reports the mount user and group as owners of every file, as --uid and --gid only
+			// set the owner of the files whose owner the guest does not know
+			nuid, ngid, err := cluster.MountIDs(co.CP.Runner, cfg)
+			if err != nil {
+				exit.Error(reason.GuestMount, "mount failed", err)
+			}
+			opts := ufs.Options{
+				ReadOnly: readOnly,
+				Allow:    allowPaths,
+				Owner:    &go9p.Owner{Uid: uint32(nuid), Gid: uint32(ngid)},
+				Umask:    uint32(umask),
+				// connections from other hosts are refused as they are accepted
+				AllowedHosts: allowedHosts,
+				HostUpdates:  hostUpdates,
+				Token:        cfg.Token,
+			}
 			wg.Add(1)
 			go func() {
 				out.Step(style.Fileserver, "Userspace file server: ")
-				ufs.StartServer(net.JoinHostPort(bindIP, strconv.Itoa(port)), debugVal, hostPath)
+				ufs.StartServer(net.JoinHostPort(bindIP, strconv.Itoa(port)), debugVal, hostPath, observer, opts)
 				out.Step(style.Stopped, "Userspace file server is shutdown")
 				wg.Done()
 			}()
 		}
-
-		// Unmount if Ctrl-C or kill request is received.
-		c := make(chan os.Signal, 1)
-		signal.Notify(c, os.Interrupt, syscall.SIGTERM)
-		go func() {
-			for sig := range c {
-				out.Step(style.Unmount, "Unmounting {{.path}} ...", out.V{"path": vmPath})
-				err := cluster.Unmount(co.CP.Runner, vmPath)
-				if err != nil {
-					out.FailureT("Failed unmount: {{.error}}", out.V{"error": err})
-				}
-				exit.Message(reason.Interrupted, "Received {{.name}} signal", out.V{"name": sig})
+		if cfg.Type == cluster.MountNFS {
+			// the NFS server reports the mount user and group as owners of every file
+			nuid, ngid, err := cluster.MountIDs(co.CP.Runner, cfg)
+			if err != nil {
+				exit.Error(reason.GuestMount, "mount failed", err)
 			}
-		}()
+			l, err := net.Listen("tcp", net.JoinHostPort(bindIP, strconv.Itoa(port)))
+			if err != nil {
+				exit.Error(reason.IfMountPort, "Error listening on port for mount", err)
+			}
+			srv := nfs.NewServer(hostPath, uint32(nuid), uint32(ngid))
+			srv.SetAllowedHosts(allowedHosts)
+			if hostUpdates != nil {
+				go func() {
+					for hosts := range hostUpdates {
+						srv.SetAllowedHosts(hosts)
+					}
+				}()
+			}
+			wg.Add(1)
+			go func() {
+				out.Step(style.Fileserver, "Userspace NFS server: ")
+				if err := srv.Serve(l); err != nil {
+					out.FailureT("NFS server failed: {{.error}}", out.V{"error": err})
+				}
+				out.Step(style.Stopped, "Userspace NFS server is shutdown")
+				wg.Done()
+			}()
+		}
+
+		handleUnmount(co.CP.Runner, vmPath)
 
 		err = cluster.Mount(co.CP.Runner, ip.String(), vmPath, cfg)
 		if err != nil {
 			exit.Error(reason.GuestMount, "mount failed", err)
 		}
 		out.Step(style.Success, "Successfully mounted {{.sourcePath}} to {{.destinationPath}}", out.V{"sourcePath": hostPath, "destinationPath": vmPath})
+		if cfg.Notify {
+			forwardChanges(co.CP.Runner, hostPath, vmPath)
+		}
 		out.Ln("")
 		out.Step(style.Notice, "NOTE: This process must stay alive for the mount to be accessible ...")
 		wg.Wait()
 	},
 }
 
+// handleUnmount unmounts target when Ctrl-C or a kill request is received
+func handleUnmount(r command.Runner, target string) {
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		for sig := range c {
+			out.Step(style.Unmount, "Unmounting {{.path}} ...", out.V{"path": target})
+			err := cluster.Unmount(r, target)
+			if err != nil {
+				out.FailureT("Failed unmount: {{.error}}", out.V{"error": err})
+			}
+			exit.Message(reason.Interrupted, "Received {{.name}} signal", out.V{"name": sig})
+		}
+	}()
+}
+
+// mountSSHFS mounts hostPath on vmPath with sshfs, until the SSH session ends
+func mountSSHFS(co mustload.ClusterController, hostPath string, vmPath string, cfg *cluster.MountConfig) {
+	client, err := sshutil.NewSSHClient(co.CP.Host.Driver)
+	if err != nil {
+		exit.Error(reason.GuestMount, "mount failed", err)
+	}
+	defer client.Close()
+
+	done, err := mount.SSHFS(client, co.CP.Runner, hostPath, vmPath, cfg)
+	if err != nil {
+		exit.Error(reason.GuestMount, "mount failed", err)
+	}
+	out.Step(style.Success, "Successfully mounted {{.sourcePath}} to {{.destinationPath}}", out.V{"sourcePath": hostPath, "destinationPath": vmPath})
+	if cfg.Notify {
+		forwardChanges(co.CP.Runner, hostPath, vmPath)
+	}
+	out.Ln("")
+	out.Step(style.Notice, "NOTE: This process must stay alive for the mount to be accessible ...")
+	if err := <-done; err != nil {
+		exit.Error(reason.GuestMount, "sshfs exited", err)
+	}
+	out.Step(style.Stopped, "{{.path}} was unmounted", out.V{"path": vmPath})
+}
+
+// forwardChanges touches the files of vmPath as the files of hostPath change, in the background, so that the file watchers of the node are notified
+func forwardChanges(r command.Runner, hostPath string, vmPath string) {
+	n, err := mount.NewNotifier(r, hostPath, vmPath)
+	if err != nil {
+		out.WarningT("Unable to forward the changes of {{.path}}: {{.error}}", out.V{"path": hostPath, "error": err})
+		return
+	}
+	go func() {
+		if err := n.Run(context.Background()); err != nil {
+			klog.Warningf("forwarding the changes of %s: %v", hostPath, err)
+		}
+	}()
+	out.Step(style.Notice, "Changes of {{.sourcePath}} are forwarded to the file watchers of {{.destinationPath}}", out.V{"sourcePath": hostPath, "destinationPath": vmPath})
+}
+
+// syncMount copies hostPath to vmPath, and then keeps copying the changes
+func syncMount(co mustload.ClusterController, hostPath string, vmPath string, cfg *cluster.MountConfig) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		sig := <-c
+		cancel()
+		exit.Message(reason.Interrupted, "Received {{.name}} signal", out.V{"name": sig})
+	}()
+
+	out.Step(style.Copying, "Copying {{.sourcePath}} to {{.destinationPath}} ...", out.V{"sourcePath": hostPath, "destinationPath": vmPath})
+	s, err := mount.NewSyncer(co.CP.Runner, hostPath, vmPath, cfg)
+	if err != nil {
+		exit.Error(reason.GuestMount, "sync failed", err)
+	}
+	out.Step(style.Success, "Successfully synced {{.sourcePath}} to {{.destinationPath}}", out.V{"sourcePath": hostPath, "destinationPath": vmPath})
+	out.Ln("")
+	out.Step(style.Notice, "NOTE: This process must stay alive for the changes to be synced ...")
+	if err := s.Run(ctx); err != nil {
+		exit.Error(reason.GuestMount, "sync failed", err)
+	}
+}
+
+// nodeIPs returns the addresses of the nodes of a cluster in the IP family of bindIP, which the 9p server accepts
+// connections from. The nodes of IPv6 clusters record their IPv6 address, yet connect to an IPv4 server from their IPv4 one.
+func nodeIPs(cc *config.ClusterConfig, bindIP net.IP) []net.IP {
+	ips := []net.IP{}
+	for _, n := range cc.Nodes {
+		addrs := []string{n.IP}
+		if driver.IsKIC(cc.Driver) {
+			ip4, ip6, err := oci.ContainerIPs(cc.Driver, driver.MachineName(*cc, n))
+			if err != nil {
+				klog.Warningf("unable to get the addresses of node %q: %v", n.Name, err)
+			}
+			addrs = append(addrs, ip4, ip6)
+		}
+		if ip := sameFamily(addrs, bindIP); ip != nil {
+			ips = append(ips, ip)
+		}
+	}
+	return ips
+}
+
+// sameFamily returns the first of addrs in the IP family of ip, or nil
+func sameFamily(addrs []string, ip net.IP) net.IP {
+	for _, a := range addrs {
+		if addr := net.ParseIP(a); addr != nil && (addr.To4() == nil) == (ip.To4() == nil) {
+			return addr
+		}
+	}
+	return nil
+}
+
+// watchNodeIPs sends the addresses of the nodes of a cluster, as nodeIPs returns them, whenever nodes are added or removed
+func watchNodeIPs(profile string, bindIP net.IP, ips []net.IP) <-chan []net.IP {
+	updates := make(chan []net.IP)
+	go func() {
+		for range time.Tick(nodeIPsInterval) {
+			cc, err := config.Load(profile)
+			if err != nil {
+				klog.Warningf("unable to load the config of %q: %v", profile, err)
+				continue
+			}
+			// an empty list would accept connections from any host
+			next := nodeIPs(cc, bindIP)
+			if len(next) == 0 || fmt.Sprint(next) == fmt.Sprint(ips) {
+				continue
+			}
+			klog.Infof("the nodes of %q changed, allowing connections from %v", profile, next)
+			ips = next
+			updates <- ips
+		}
+	}()
+	return updates
+}
+
+// mountToken returns a random token, which the 9p server requires the mount command of the node to pass
+func mountToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// newMount returns a persistent mount of hostPath at guestPath, with the options of the mount flags
+func newMount(hostPath string, guestPath string) config.Mount {
+	return config.Mount{
+		HostPath:      hostPath,
+		GuestPath:     guestPath,
+		Type:          mountType,
+		IP:            mountIP,
+		UID:           uid,
+		GID:           gid,
+		Mode:          mode,
+		MSize:         mSize,
+		Version:       mountVersion,
+		Options:       options,
+		Notify:        notifyMount,
+		ReadOnly:      readOnly,
+		Allow:         allowPaths,
+		Umask:         umask,
+		Auth:          authMount,
+		SyncDirection: syncDir,
+		SyncInterval:  syncInterval,
+		SyncIgnore:    syncIgnore,
+	}
+}
+
+// startPersistentMount records a persistent mount in the config of a cluster, replacing any mount of the same guest path,
+// and starts its supervisor in the background
+func startPersistentMount(cc *config.ClusterConfig, m config.Mount) {
+	mounts := []config.Mount{}
+	for _, o := range cc.Mounts {
+		if o.GuestPath != m.GuestPath {
+			mounts = append(mounts, o)
+			continue
+		}
+		// the supervisor of the replaced mount unmounts it, and exits
+		if _, err := mount.StopSupervisor(cc.Name, o.GuestPath); err != nil {
+			exit.Error(reason.HostKillMountProc, "Error stopping mount process", err)
+		}
+	}
+	cc.Mounts = append(mounts, m)
+	if err := config.SaveProfile(cc.Name, cc); err != nil {
+		exit.Error(reason.HostSaveProfile, "failed to save config", err)
+	}
+
+	pid, err := mount.StartSupervisor(cc.Name, m.GuestPath)
+	if err != nil {
+		exit.Error(reason.GuestMount, "Error starting mount", err)
+	}
+	profileArg := ""
+	if cc.Name != constants.DefaultClusterName {
+		profileArg = fmt.Sprintf(" -p %s", cc.Name)
+	}
+	out.Step(style.Mounting, "Mounting {{.sourcePath}} into VM as {{.destinationPath}} in the background with PID {{.pid}}, logging to {{.log}}", out.V{"sourcePath": m.HostPath, "destinationPath": m.GuestPath, "pid": pid, "log": localpath.MountLog(cc.Name, m.GuestPath)})
+	out.Step(style.Tip, "After the host reboots, the mount is restored by the next minikube start. To list the mounts, run: minikube mount list{{.profile}}", out.V{"profile": profileArg})
+}
+
+// startPersistentMounts starts the supervisors of the persistent mounts of a cluster which are not running
+func startPersistentMounts(cc *config.ClusterConfig) {
+	for _, m := range cc.Mounts {
+		if _, ok := mount.SupervisorPID(cc.Name, m.GuestPath); ok {
+			continue
+		}
+		out.Step(style.Mounting, "Mounting {{.sourcePath}} into VM as {{.destinationPath}} in the background ...", out.V{"sourcePath": m.HostPath, "destinationPath": m.GuestPath})
+		if _, err := mount.StartSupervisor(cc.Name, m.GuestPath); err != nil {
+			out.FailureT("Unable to mount {{.path}}: {{.error}}", out.V{"path": m.GuestPath, "error": err})
+		}
+	}
+}
+
+// stopPersistentMounts stops the supervisors of the persistent mounts of a cluster, which stay recorded
+func stopPersistentMounts(cc *config.ClusterConfig) {
+	for _, m := range cc.Mounts {
+		if _, err := mount.StopSupervisor(cc.Name, m.GuestPath); err != nil {
+			out.WarningT("Unable to stop the mount process of {{.path}}: {{.error}}", out.V{"path": m.GuestPath, "error": err})
+		}
+	}
+}
+
+// superviseMount runs minikube mount for a persistent mount of a cluster, and runs it again whenever it exits, until
+// the mount is no longer recorded or the supervisor is interrupted
+func superviseMount(profile string, guestPath string) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-c
+		cancel()
+	}()
+
+	next := func() (*exec.Cmd, error) {
+		cc, err := config.Load(profile)
+		if config.IsNotExist(err) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range cc.Mounts {
+			if m.GuestPath == guestPath {
+				cmd := exec.Command(os.Args[0], mountArgs(profile, m)...)
+				cmd.Env = append(os.Environ(), constants.IsMinikubeChildProcess+"=true")
+				cmd.Stdout = os.Stdout
+				cmd.Stderr = os.Stderr
+				return cmd, nil
+			}
+		}
+		return nil, nil
+	}
+	if err := mount.Supervise(ctx, localpath.MountPID(profile, guestPath), next); err != nil {
+		exit.Error(reason.GuestMount, "mount supervisor failed", err)
+	}
+}
+
+// mountArgs returns the arguments of the minikube mount command of a persistent mount
+func mountArgs(profile string, m config.Mount) []string {
+	args := []string{
+		"mount", "-p", profile,
+		"--type=" + m.Type,
+		"--uid=" + m.UID,
+		"--gid=" + m.GID,
+		fmt.Sprintf("--mode=0%o", m.Mode),
+		fmt.Sprintf("--msize=%d", m.MSize),
+		"--9p-version=" + m.Version,
+		"--sync-direction=" + m.SyncDirection,
+		"--sync-interval=" + m.SyncInterval.String(),
+	}
+	if m.IP != "" {
+		args = append(args, "--ip="+m.IP)
+	}
+	if len(m.Options) > 0 {
+		args = append(args, "--options="+strings.Join(m.Options, ","))
+	}
+	if m.Notify {
+		args = append(args, "--notify")
+	}
+	if m.ReadOnly {
+		args = append(args, "--read-only")
+	}
+	if len(m.Allow) > 0 {
+		args = append(args, "--allow="+strings.Join(m.Allow, ","))
+	}
+	if m.Umask != 0 {
+		args = append(args, fmt.Sprintf("--umask=0%o", m.Umask))
+	}
+	if m.Auth {
+		args = append(args, "--9p-auth")
+	}
+	if len(m.SyncIgnore) > 0 {
+		args = append(args, "--sync-ignore="+strings.Join(m.SyncIgnore, ","))
+	}
+	return append(args, m.HostPath+":"+m.GuestPath)
+}
+
 func init() {
 	mountCmd.Flags().StringVar(&mountIP, "ip", "", "Specify the ip that the mount should be setup on")
-	mountCmd.Flags().StringVar(&mountType, "type", nineP, "Specify the mount filesystem type (supported types: 9p)")
+	mountCmd.Flags().StringVar(&mountType, "type", nineP, "Specify the mount filesystem type (supported types: 9p, nfs, sshfs, sync)")
 	mountCmd.Flags().StringVar(&mountVersion, "9p-version", defaultMountVersion, "Specify the 9p version that the mount should use")
 	mountCmd.Flags().BoolVar(&isKill, "kill", false, "Kill the mount process spawned by minikube start")
 	mountCmd.Flags().StringVar(&uid, "uid", "docker", "Default user id used for the mount")
@@ -210,6 +666,20 @@ func init() {
 	mountCmd.Flags().UintVar(&mode, "mode", 0o755, "File permissions used for the mount")
 	mountCmd.Flags().StringSliceVar(&options, "options", []string{}, "Additional mount options, such as cache=fscache")
 	mountCmd.Flags().IntVar(&mSize, "msize", defaultMsize, "The number of bytes to use for 9p packet payload")
+	mountCmd.Flags().StringVar(&syncDir, "sync-direction", cluster.SyncOneWay, "The direction in which --type=sync copies changes: one-way copies the changes of the host directory, two-way also copies the files changed on the node back to the host")
+	mountCmd.Flags().DurationVar(&syncInterval, "sync-interval", defaultSyncInterval, "How often --type=sync --sync-direction=two-way looks for the files changed on the node")
+	mountCmd.Flags().StringSliceVar(&syncIgnore, "sync-ignore", []string{}, "Glob patterns of the files and directories which --type=sync does not copy, such as .git or node_modules")
+	mountCmd.Flags().BoolVar(&notifyMount, "notify", false, "Touch the changed files on the node as they change on the host, so that the file watchers of the node are notified of the changes (9p, nfs and sshfs mounts)")
+	mountCmd.Flags().BoolVar(&persistMount, "persist", false, "Record the mount in the cluster config, and keep it mounted by a process in the background, which is restarted by minikube start, including after the host reboots. Use 'minikube mount list' to list the mounts, and 'minikube mount unmount' to remove them.")
+	mountCmd.Flags().BoolVar(&readOnly, "read-only", false, "Export the directory read-only: the 9p server refuses any change to its files")
+	mountCmd.Flags().StringSliceVar(&allowPaths, "allow", []string{}, "Only export these paths, relative to the host directory, such as src,docs. The 9p server hides the rest of the directory.")
+	mountCmd.Flags().UintVar(&umask, "umask", 0, "Permission bits, such as 022, which the 9p server clears from the mode of the files it reports, creates and chmods")
+	mountCmd.Flags().BoolVar(&authMount, "9p-auth", false, "Require a random token, generated for each mount, to connect to the 9p server. The mount command of the node passes it as the aname.")
+	mountCmd.Flags().StringVar(&supervise, "supervise", "", "Run the mount of the cluster config with this guest path, and restart it whenever it exits")
+	if err := mountCmd.Flags().MarkHidden("supervise"); err != nil {
+		klog.Info("unable to mark --supervise flag as hidden")
+	}
+	addMetricsFlag(mountCmd)
 }
 
 // getPort asks the kernel for a free open port that is ready to use
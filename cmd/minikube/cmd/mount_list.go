@@ -0,0 +1,111 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/mount"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+var mountOutput string
+
+// mountInfo is the status of a persistent mount
+type mountInfo struct {
+	HostPath  string `json:"hostPath"`
+	GuestPath string `json:"guestPath"`
+	Type      string `json:"type"`
+	State     string `json:"state"`
+	PID       int    `json:"pid,omitempty"`
+}
+
+// mountListCmd represents the mount list command
+var mountListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the persistent mounts of a cluster",
+	Long:  "List the persistent mounts of a cluster, added with 'minikube mount --persist', with the state and PID of the process which keeps each of them mounted.",
+	Run: func(cmd *cobra.Command, args []string) {
+		if mountOutput != "table" && mountOutput != "json" {
+			exit.Message(reason.Usage, "Invalid output format: {{.output}}. Valid values: 'table', 'json'", out.V{"output": mountOutput})
+		}
+		api, cc := mustload.Partial(ClusterFlagValue())
+		api.Close()
+
+		if mountOutput == "table" && len(cc.Mounts) == 0 {
+			out.Step(style.Empty, "{{.cluster}} has no persistent mounts. To add one, run: minikube mount --persist <source directory>:<target directory>", out.V{"cluster": cc.Name})
+			return
+		}
+		if err := printMounts(os.Stdout, mountInfos(cc)); err != nil {
+			exit.Error(reason.InternalListConfig, "error printing mounts", err)
+		}
+	},
+}
+
+// mountInfos returns the status of the persistent mounts of a cluster
+func mountInfos(cc *config.ClusterConfig) []mountInfo {
+	infos := []mountInfo{}
+	for _, m := range cc.Mounts {
+		info := mountInfo{HostPath: m.HostPath, GuestPath: m.GuestPath, Type: m.Type, State: "Stopped"}
+		if pid, ok := mount.SupervisorPID(cc.Name, m.GuestPath); ok {
+			info.State = "Running"
+			info.PID = pid
+		}
+		infos = append(infos, info)
+	}
+	return infos
+}
+
+// printMounts prints mounts to w in the format of --output
+func printMounts(w io.Writer, infos []mountInfo) error {
+	if mountOutput == "json" {
+		enc := json.NewEncoder(w)
+		enc.SetEscapeHTML(false)
+		return enc.Encode(infos)
+	}
+
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Host Path", "Guest Path", "Type", "State", "PID"})
+	table.SetAutoFormatHeaders(false)
+	table.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+	table.SetCenterSeparator("|")
+	for _, i := range infos {
+		pid := ""
+		if i.PID != 0 {
+			pid = strconv.Itoa(i.PID)
+		}
+		table.Append([]string{i.HostPath, i.GuestPath, i.Type, i.State, pid})
+	}
+	table.Render()
+	return nil
+}
+
+func init() {
+	mountListCmd.Flags().StringVarP(&mountOutput, "output", "o", "table", "The output format. One of 'json', 'table'")
+	mountCmd.AddCommand(mountListCmd)
+}
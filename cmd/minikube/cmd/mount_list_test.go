@@ -0,0 +1,74 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+func TestMountArgs(t *testing.T) {
+	m := config.Mount{
+		HostPath:      "/home/user/src",
+		GuestPath:     "/src",
+		Type:          "nfs",
+		UID:           "docker",
+		GID:           "docker",
+		Mode:          0o755,
+		MSize:         262144,
+		Version:       "9p2000.L",
+		Options:       []string{"cache=fscache"},
+		Notify:        true,
+		SyncDirection: "one-way",
+		SyncInterval:  2 * time.Second,
+	}
+	want := []string{"mount", "-p", "p1", "--type=nfs", "--uid=docker", "--gid=docker", "--mode=0755", "--msize=262144", "--9p-version=9p2000.L",
+		"--sync-direction=one-way", "--sync-interval=2s", "--options=cache=fscache", "--notify", "/home/user/src:/src"}
+	if got := mountArgs("p1", m); !reflect.DeepEqual(got, want) {
+		t.Errorf("mountArgs() = %v, want %v", got, want)
+	}
+
+	m.Type, m.Notify, m.Options = "9p", false, nil
+	m.ReadOnly, m.Allow, m.Umask, m.Auth = true, []string{"src", "docs"}, 0o22, true
+	want = []string{"mount", "-p", "p1", "--type=9p", "--uid=docker", "--gid=docker", "--mode=0755", "--msize=262144", "--9p-version=9p2000.L",
+		"--sync-direction=one-way", "--sync-interval=2s", "--read-only", "--allow=src,docs", "--umask=022", "--9p-auth", "/home/user/src:/src"}
+	if got := mountArgs("p1", m); !reflect.DeepEqual(got, want) {
+		t.Errorf("mountArgs() = %v, want %v", got, want)
+	}
+}
+
+func TestPrintMounts(t *testing.T) {
+	infos := []mountInfo{
+		{HostPath: "/home/user/src", GuestPath: "/src", Type: "9p", State: "Running", PID: 1234},
+		{HostPath: "/home/user/app", GuestPath: "/app", Type: "sync", State: "Stopped"},
+	}
+
+	defer func() { mountOutput = "table" }()
+	mountOutput = "json"
+	var b bytes.Buffer
+	if err := printMounts(&b, infos); err != nil {
+		t.Fatalf("printMounts: %v", err)
+	}
+	want := `[{"hostPath":"/home/user/src","guestPath":"/src","type":"9p","state":"Running","pid":1234},{"hostPath":"/home/user/app","guestPath":"/app","type":"sync","state":"Stopped"}]` + "\n"
+	if b.String() != want {
+		t.Errorf("printMounts() = %s, want %s", b.String(), want)
+	}
+}
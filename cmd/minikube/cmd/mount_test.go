@@ -0,0 +1,59 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"net"
+	"reflect"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+func TestNodeIPs(t *testing.T) {
+	cc := &config.ClusterConfig{
+		Driver: "kvm2",
+		Nodes:  []config.Node{{Name: "", IP: "192.168.39.2"}, {Name: "m02", IP: "192.168.39.3"}},
+	}
+	want := []net.IP{net.ParseIP("192.168.39.2"), net.ParseIP("192.168.39.3")}
+	if got := nodeIPs(cc, net.ParseIP("192.168.39.1")); !reflect.DeepEqual(got, want) {
+		t.Errorf("nodeIPs() = %v, want %v", got, want)
+	}
+	if got := nodeIPs(cc, net.ParseIP("fd00::1")); len(got) != 0 {
+		t.Errorf("nodeIPs() = %v, want no IPv6 address", got)
+	}
+}
+
+func TestSameFamily(t *testing.T) {
+	// the nodes of IPv6 clusters record their IPv6 address
+	addrs := []string{"fd00:192:168:49::2", "", "192.168.49.2"}
+	tests := []struct {
+		ip   string
+		want string
+	}{
+		{"192.168.49.1", "192.168.49.2"},
+		{"fd00:192:168:49::1", "fd00:192:168:49::2"},
+	}
+	for _, tc := range tests {
+		if got := sameFamily(addrs, net.ParseIP(tc.ip)); !got.Equal(net.ParseIP(tc.want)) {
+			t.Errorf("sameFamily(%v, %s) = %v, want %s", addrs, tc.ip, got, tc.want)
+		}
+	}
+	if got := sameFamily([]string{"192.168.49.2"}, net.ParseIP("fd00::1")); got != nil {
+		t.Errorf("sameFamily() = %v, want nil", got)
+	}
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/spf13/cobra"
+	"k8s.io/klog/v2"
+
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/mount"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+// mountUnmountCmd represents the mount unmount command
+var mountUnmountCmd = &cobra.Command{
+	Use:     "unmount <target directory> ...",
+	Short:   "Remove persistent mounts of a cluster",
+	Long:    "Remove persistent mounts of a cluster, added with 'minikube mount --persist': their processes are stopped, their target directories are unmounted, and they are no longer restored by minikube start.",
+	Example: "minikube mount unmount /src",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			exit.Message(reason.Usage, "Please specify the target directories of the mounts to remove: minikube mount unmount <target directory>")
+		}
+		api, cc := mustload.Partial(ClusterFlagValue())
+		defer api.Close()
+		profileArg := ""
+		if cc.Name != constants.DefaultClusterName {
+			profileArg = fmt.Sprintf(" -p %s", cc.Name)
+		}
+
+		recorded := map[string]bool{}
+		for _, m := range cc.Mounts {
+			recorded[m.GuestPath] = true
+		}
+		remove := map[string]bool{}
+		for _, p := range args {
+			if !recorded[p] {
+				exit.Message(reason.Usage, "{{.path}} is not a persistent mount of {{.cluster}}. To list them, run: minikube mount list{{.profile}}", out.V{"path": p, "cluster": cc.Name, "profile": profileArg})
+			}
+			remove[p] = true
+		}
+
+		// the mount processes unmount as they are interrupted, which processes killed on Windows do not
+		r := controlPlaneRunner(api, cc)
+		for _, p := range args {
+			if _, err := mount.StopSupervisor(cc.Name, p); err != nil {
+				exit.Error(reason.HostKillMountProc, "Error stopping mount process", err)
+			}
+			if r != nil {
+				if err := cluster.Unmount(r, p); err != nil {
+					out.WarningT("Unable to unmount {{.path}}: {{.error}}", out.V{"path": p, "error": err})
+				}
+			}
+		}
+		mounts := []config.Mount{}
+		for _, m := range cc.Mounts {
+			if !remove[m.GuestPath] {
+				mounts = append(mounts, m)
+			}
+		}
+		cc.Mounts = mounts
+		if err := config.SaveProfile(cc.Name, cc); err != nil {
+			exit.Error(reason.HostSaveProfile, "failed to save config", err)
+		}
+		for _, p := range args {
+			out.Step(style.Unmount, "Removed the persistent mount of {{.path}}", out.V{"path": p})
+		}
+	},
+}
+
+// controlPlaneRunner returns a command runner for the primary control plane of a cluster, or nil if it is not running
+func controlPlaneRunner(api libmachine.API, cc *config.ClusterConfig) command.Runner {
+	cp, err := config.PrimaryControlPlane(cc)
+	if err != nil {
+		klog.Warningf("unable to find control plane: %v", err)
+		return nil
+	}
+	name := driver.MachineName(*cc, cp)
+	if st, err := machine.Status(api, name); err != nil || st != state.Running.String() {
+		return nil
+	}
+	h, err := machine.LoadHost(api, name)
+	if err != nil {
+		klog.Warningf("unable to load host %s: %v", name, err)
+		return nil
+	}
+	r, err := machine.CommandRunner(h)
+	if err != nil {
+		klog.Warningf("unable to get command runner of %s: %v", name, err)
+		return nil
+	}
+	return r
+}
+
+func init() {
+	mountCmd.AddCommand(mountUnmountCmd)
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package cmd
 
 import (
+	"github.com/blang/semver"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"k8s.io/minikube/pkg/minikube/cni"
@@ -28,11 +29,19 @@ import (
 	"k8s.io/minikube/pkg/minikube/out"
 	"k8s.io/minikube/pkg/minikube/reason"
 	"k8s.io/minikube/pkg/minikube/style"
+	"k8s.io/minikube/pkg/util"
+	"k8s.io/minikube/pkg/version"
 )
 
 var (
-	cp     bool
-	worker bool
+	cp             bool
+	worker         bool
+	nodeCPUs       int
+	nodeMemory     string
+	nodeDiskSize   string
+	nodeLabels     []string
+	nodeTaints     []string
+	nodeK8sVersion string
 )
 
 var nodeAddCmd = &cobra.Command{
@@ -47,17 +56,26 @@ var nodeAddCmd = &cobra.Command{
 			out.FailureT("none driver does not support multi-node clusters")
 		}
 
+		// additional control planes need the virtual IP and stacked etcd which only HA clusters are set up with
+		if cp && !cc.HA {
+			exit.Message(reason.Usage, "Control plane nodes can only be added to highly available clusters, created with: minikube start --ha")
+		}
+
 		name := node.Name(len(cc.Nodes) + 1)
 
 		out.Step(style.Happy, "Adding node {{.name}} to cluster {{.cluster}}", out.V{"name": name, "cluster": cc.Name})
 
-		// TODO: Deal with parameters better. Ideally we should be able to acceot any node-specific minikube start params here.
 		n := config.Node{
 			Name:              name,
 			Worker:            worker,
 			ControlPlane:      cp,
 			KubernetesVersion: cc.KubernetesConfig.KubernetesVersion,
 		}
+		// the API servers of the control planes listen on the port the virtual IP forwards to
+		if cp {
+			n.Port = cc.KubernetesConfig.NodePort
+		}
+		nodeAddFlags(cmd, cc, &n)
 
 		// Make sure to decrease the default amount of memory we use per VM if this is the first worker node
 		if len(cc.Nodes) == 1 {
@@ -87,10 +105,84 @@ var nodeAddCmd = &cobra.Command{
 }
 
 func init() {
-	// TODO(https://github.com/kubernetes/minikube/issues/7366): We should figure out which minikube start flags to actually import
 	nodeAddCmd.Flags().BoolVar(&cp, "control-plane", false, "If true, the node added will also be a control plane in addition to a worker.")
 	nodeAddCmd.Flags().BoolVar(&worker, "worker", true, "If true, the added node will be marked for work. Defaults to true.")
 	nodeAddCmd.Flags().Bool(deleteOnFailure, false, "If set, delete the current cluster if start fails and try again. Defaults to false.")
+	nodeAddCmd.Flags().IntVar(&nodeCPUs, cpus, 0, "Number of CPUs allocated to the node. Defaults to the CPUs of the cluster.")
+	nodeAddCmd.Flags().StringVar(&nodeMemory, memory, "", "Amount of RAM to allocate to the node (format: <number>[<unit>], where unit = b, k, m or g). Defaults to the memory of the cluster.")
+	nodeAddCmd.Flags().StringVar(&nodeDiskSize, humanReadableDiskSize, "", "Disk size allocated to the node (format: <number>[<unit>], where unit = b, k, m or g). Defaults to the disk size of the cluster.")
+	nodeAddCmd.Flags().StringSliceVar(&nodeLabels, "labels", nil, "Labels the kubelet registers the node with, in the form key=value. Keys within kubernetes.io and k8s.io are restricted to the kubelet.kubernetes.io and node.kubernetes.io namespaces.")
+	nodeAddCmd.Flags().StringSliceVar(&nodeTaints, "taints", nil, "Taints the kubelet registers the node with, in the form key[=value]:effect, where effect = NoSchedule, PreferNoSchedule or NoExecute.")
+	nodeAddCmd.Flags().StringVar(&nodeK8sVersion, kubernetesVersion, "", "The Kubernetes version of the kubelet on the node, which may be up to 2 minor versions older than the control plane. Defaults to the version of the cluster.")
 
 	nodeCmd.AddCommand(nodeAddCmd)
 }
+
+// nodeAddFlags validates the node specific flags of node add, and applies them to n
+func nodeAddFlags(cmd *cobra.Command, cc *config.ClusterConfig, n *config.Node) {
+	if cmd.Flags().Changed(cpus) {
+		if !driver.HasResourceLimits(cc.Driver) {
+			out.WarningT("The '{{.name}}' driver does not respect the --cpus flag", out.V{"name": cc.Driver})
+		}
+		if nodeCPUs < minimumCPUS {
+			exitIfNotForced(reason.RsrcInsufficientCores, "Requested cpu count {{.requested_cpus}} is less than the minimum allowed of {{.minimum_cpus}}", out.V{"requested_cpus": nodeCPUs, "minimum_cpus": minimumCPUS})
+		}
+		n.CPUs = nodeCPUs
+	}
+
+	if cmd.Flags().Changed(memory) {
+		if !driver.HasResourceLimits(cc.Driver) {
+			out.WarningT("The '{{.name}}' driver does not respect the --memory flag", out.V{"name": cc.Driver})
+		}
+		req, err := util.CalculateSizeInMB(nodeMemory)
+		if err != nil {
+			exit.Message(reason.Usage, "Unable to parse memory '{{.memory}}': {{.error}}", out.V{"memory": nodeMemory, "error": err})
+		}
+		validateRequestedMemorySize(req, cc.Driver)
+		n.Memory = req
+	}
+
+	if cmd.Flags().Changed(humanReadableDiskSize) {
+		if driver.IsKIC(cc.Driver) {
+			out.WarningT("The '{{.name}}' driver does not respect the --disk-size flag", out.V{"name": cc.Driver})
+		}
+		diskSizeMB, err := util.CalculateSizeInMB(nodeDiskSize)
+		if err != nil {
+			exit.Message(reason.Usage, "Validation unable to parse disk size '{{.diskSize}}': {{.error}}", out.V{"diskSize": nodeDiskSize, "error": err})
+		}
+		if diskSizeMB < minimumDiskSize {
+			exitIfNotForced(reason.RsrcInsufficientStorage, "Requested disk size {{.requested_size}} is less than minimum of {{.minimum_size}}", out.V{"requested_size": diskSizeMB, "minimum_size": minimumDiskSize})
+		}
+		n.DiskSize = diskSizeMB
+	}
+
+	if len(nodeLabels) > 0 {
+		labels, err := config.ParseNodeLabels(nodeLabels)
+		if err != nil {
+			exit.Message(reason.Usage, "Invalid --labels: {{.error}}", out.V{"error": err})
+		}
+		n.Labels = labels
+	}
+
+	for _, t := range nodeTaints {
+		if err := config.ValidateNodeTaint(t); err != nil {
+			exit.Message(reason.Usage, "Invalid --taints: {{.error}}", out.V{"error": err})
+		}
+	}
+	n.Taints = nodeTaints
+
+	if cmd.Flags().Changed(kubernetesVersion) {
+		v, err := semver.ParseTolerant(nodeK8sVersion)
+		if err != nil {
+			exit.Message(reason.Usage, "Unable to parse Kubernetes version '{{.version}}': {{.error}}", out.V{"version": nodeK8sVersion, "error": err})
+		}
+		nv := version.VersionPrefix + v.String()
+		if cp && nv != cc.KubernetesConfig.KubernetesVersion {
+			exit.Message(reason.Usage, "Control plane nodes must run the Kubernetes version of the cluster, {{.version}}", out.V{"version": cc.KubernetesConfig.KubernetesVersion})
+		}
+		if err := config.ValidateNodeVersion(nv, cc.KubernetesConfig.KubernetesVersion); err != nil {
+			exit.Message(reason.Usage, "Unable to add a node running Kubernetes {{.version}}: {{.error}}", out.V{"version": nv, "error": err})
+		}
+		n.KubernetesVersion = nv
+	}
+}
@@ -142,6 +142,7 @@ var podmanEnvCmd = &cobra.Command{
 	Short: "Configure environment to use minikube's Podman service",
 	Long:  `Sets up podman env variables; similar to '$(podman-machine env)'.`,
 	Run: func(cmd *cobra.Command, args []string) {
+		validateEnvOutput()
 		sh := shell.EnvConfig{
 			Shell: shell.ForceShell,
 		}
@@ -207,7 +208,11 @@ var podmanEnvCmd = &cobra.Command{
 			}
 		}
 
-		if err := podmanSetScript(ec, os.Stdout); err != nil {
+		if envOutput != "" {
+			if err := shell.SetOutput(os.Stdout, envOutput, podmanEnvVars(ec)); err != nil {
+				exit.Error(reason.InternalEnvScript, "Error generating set output", err)
+			}
+		} else if err := podmanSetScript(ec, os.Stdout); err != nil {
 			exit.Error(reason.InternalEnvScript, "Error generating set output", err)
 		}
 	},
@@ -241,6 +246,9 @@ func podmanSetScript(ec PodmanEnvConfig, w io.Writer) error {
 // podmanUnsetScript writes out a shell-compatible 'podman-env unset' script
 func podmanUnsetScript(ec PodmanEnvConfig, w io.Writer) error {
 	vars := podmanEnvNames(ec)
+	if envOutput != "" {
+		return shell.UnsetOutput(w, envOutput, vars)
+	}
 	return shell.UnsetScript(ec.EnvConfig, w, vars)
 }
 
@@ -327,6 +335,7 @@ func podmanEnvNames(ec PodmanEnvConfig) []string {
 }
 
 func init() {
-	podmanEnvCmd.Flags().StringVar(&shell.ForceShell, "shell", "", "Force environment to be configured for a specified shell: [fish, cmd, powershell, tcsh, bash, zsh], default is auto-detect")
+	podmanEnvCmd.Flags().StringVar(&shell.ForceShell, "shell", "", "Force environment to be configured for a specified shell: [fish, cmd, powershell, tcsh, bash, zsh, nushell, xonsh, elvish], default is auto-detect")
+	podmanEnvCmd.Flags().StringVarP(&envOutput, "output", "o", "", fmt.Sprintf("Write the environment in a machine readable format instead of a shell script: [%s]", strings.Join(shell.Outputs, ", ")))
 	podmanEnvCmd.Flags().BoolVarP(&podmanUnset, "unset", "u", false, "Unset variables instead of setting them")
 }
@@ -0,0 +1,81 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"strconv"
+
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/portforward"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+// registryCmd represents the registry command
+var registryCmd = &cobra.Command{
+	Use:   "registry",
+	Short: "Expose the local registry of the cluster on the host",
+	Long: `Expose the local registry of a cluster started with --registry on the host, so that images pushed to localhost:<registry-port> can be run from registry.minikube:5000.
+
+The docker and podman drivers publish the registry port when the cluster is created, and the none driver runs it on the host, so this command only needs to keep running for the VM drivers.`,
+	Example: `minikube start --registry
+minikube registry
+docker push localhost:5000/app`,
+	Run: func(cmd *cobra.Command, args []string) {
+		co := mustload.Running(ClusterFlagValue())
+		cc := co.Config
+		if !cc.Registry {
+			profileArg := ""
+			if cc.Name != constants.DefaultClusterName {
+				profileArg = fmt.Sprintf(" -p %s", cc.Name)
+			}
+			exit.Message(reason.Usage, "The cluster {{.cluster}} was not started with a local registry. To create one, run: minikube delete{{.profile}} && minikube start{{.profile}} --registry", out.V{"cluster": cc.Name, "profile": profileArg})
+		}
+
+		addr := net.JoinHostPort("localhost", strconv.Itoa(cc.RegistryHostPort))
+		alias := fmt.Sprintf("%s:%d", constants.RegistryAlias, constants.RegistryAddonPort)
+		if !driver.IsVM(cc.Driver) {
+			out.Step(style.Ready, "The local registry is available on the host at {{.addr}}, and within the cluster at {{.alias}}", out.V{"addr": addr, "alias": alias})
+			return
+		}
+
+		ctrlC := make(chan os.Signal, 1)
+		signal.Notify(ctrlC, os.Interrupt)
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			<-ctrlC
+			cancel()
+		}()
+
+		// the registry proxy of the registry addon listens on the registry port of every node
+		target := net.JoinHostPort(co.CP.IP.String(), strconv.Itoa(constants.RegistryAddonPort))
+		out.Step(style.Running, "Forwarding {{.addr}} to the local registry, which is available within the cluster at {{.alias}}. Press Ctrl-C to stop.", out.V{"addr": addr, "alias": alias})
+		if err := portforward.Forward(ctx, net.JoinHostPort("127.0.0.1", strconv.Itoa(cc.RegistryHostPort)), target); err != nil {
+			exit.Error(reason.HostRegistryForward, "Failed to forward the local registry", err)
+		}
+	},
+}
@@ -189,7 +189,10 @@ func init() {
 			Commands: []*cobra.Command{
 				dockerEnvCmd,
 				podmanEnvCmd,
+				kubectlEnvCmd,
 				cacheCmd,
+				imageCmd,
+				registryCmd,
 			},
 		},
 		{
@@ -199,6 +202,7 @@ func init() {
 				configCmd.ConfigCmd,
 				configCmd.ProfileCmd,
 				updateContextCmd,
+				upgradeCmd,
 			},
 		},
 		{
@@ -213,8 +217,10 @@ func init() {
 			Commands: []*cobra.Command{
 				mountCmd,
 				sshCmd,
+				cpCmd,
 				kubectlCmd,
 				nodeCmd,
+				snapshotCmd,
 			},
 		},
 		{
@@ -223,6 +229,7 @@ func init() {
 				sshKeyCmd,
 				ipCmd,
 				logsCmd,
+				diagnoseCmd,
 				updateCheckCmd,
 				versionCmd,
 				optionsCmd,
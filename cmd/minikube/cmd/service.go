@@ -17,6 +17,7 @@ limitations under the License.
 package cmd
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/url"
@@ -56,13 +57,17 @@ var (
 	serviceURLTemplate *template.Template
 	wait               int
 	interval           int
+	serviceForward     bool
+	serviceLocalPort   int
 )
 
 // serviceCmd represents the service command
 var serviceCmd = &cobra.Command{
 	Use:   "service [flags] SERVICE",
 	Short: "Returns a URL to connect to a service",
-	Long:  `Returns the Kubernetes URL for a service in your local cluster. In the case of multiple URLs they will be printed one at a time.`,
+	Long: `Returns the Kubernetes URL for a service in your local cluster. In the case of multiple URLs they will be printed one at a time.
+
+With --forward, the docker and podman drivers forward one or more services to stable ports on the host instead, until Ctrl-C is pressed. The forwards are reconnected when they drop, or when the endpoints of a service change.`,
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
 		t, err := template.New("serviceURL").Parse(serviceURLFormat)
 		if err != nil {
@@ -73,6 +78,13 @@ var serviceCmd = &cobra.Command{
 		RootCmd.PersistentPreRun(cmd, args)
 	},
 	Run: func(cmd *cobra.Command, args []string) {
+		if serviceForward {
+			forwardServices(args)
+			return
+		}
+		if serviceLocalPort != 0 {
+			exit.Message(reason.Usage, "--local-port requires --forward")
+		}
 		if len(args) == 0 || len(args) > 1 {
 			exit.Message(reason.Usage, "You must specify a service name")
 		}
@@ -107,6 +119,8 @@ func init() {
 	serviceCmd.Flags().BoolVar(&https, "https", false, "Open the service URL with https instead of http (defaults to \"false\")")
 	serviceCmd.Flags().IntVar(&wait, "wait", service.DefaultWait, "Amount of time to wait for a service in seconds")
 	serviceCmd.Flags().IntVar(&interval, "interval", service.DefaultInterval, "The initial time interval for each check that wait performs in seconds")
+	serviceCmd.Flags().BoolVar(&serviceForward, "forward", false, "Forward the services to stable ports on the host until Ctrl-C is pressed, reconnecting when needed (docker and podman drivers only)")
+	serviceCmd.Flags().IntVar(&serviceLocalPort, "local-port", 0, "With --forward, the host port to forward the first service port to. Further ports are forwarded to the next ports. Defaults to free ports.")
 
 	serviceCmd.PersistentFlags().StringVar(&serviceURLFormat, "format", defaultServiceFormatTemplate, "Format to output service URL in. This format will be applied to each url individually and they will be printed one at a time.")
 }
@@ -150,6 +164,50 @@ func startKicServiceTunnel(svc, configName string) {
 	}
 }
 
+// forwardServices forwards services to stable ports on the host until Ctrl-C is pressed
+func forwardServices(svcs []string) {
+	if len(svcs) == 0 {
+		exit.Message(reason.Usage, "You must specify a service name")
+	}
+
+	cname := ClusterFlagValue()
+	co := mustload.Healthy(cname)
+	if !driver.IsKIC(co.Config.Driver) {
+		exit.Message(reason.Usage, "--forward is only supported by the docker and podman drivers. Use 'minikube service' without --forward or 'minikube tunnel' instead.")
+	}
+	for _, svc := range svcs {
+		if err := service.CheckService(cname, namespace, svc); err != nil {
+			exit.Error(reason.SvcNotFound, fmt.Sprintf("Unable to forward service %s", svc), err)
+		}
+	}
+
+	clientset, err := kapi.Client(cname)
+	if err != nil {
+		exit.Error(reason.InternalKubernetesClient, "error creating clientset", err)
+	}
+	port, err := oci.ForwardedPort(co.Config.Driver, cname, 22)
+	if err != nil {
+		exit.Error(reason.DrvPortForward, "error getting ssh port", err)
+	}
+	sshKey := filepath.Join(localpath.MiniPath(), "machines", cname, "id_rsa")
+
+	ctrlC := make(chan os.Signal, 1)
+	signal.Notify(ctrlC, os.Interrupt)
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-ctrlC
+		cancel()
+	}()
+
+	out.Step(style.Running, "Forwarding {{.services}}. Press Ctrl-C to stop.", out.V{"services": strings.Join(svcs, ", ")})
+	fwd := kic.NewServiceForwarder(strconv.Itoa(port), sshKey, clientset.CoreV1(), namespace, svcs, serviceLocalPort, func(rows [][]string) {
+		service.PrintForwardedServiceList(os.Stdout, rows)
+	})
+	if err := fwd.Start(ctx); err != nil {
+		exit.Error(reason.SvcTunnelStart, "error forwarding services", err)
+	}
+}
+
 func openURLs(svc string, urls []string) {
 	for _, u := range urls {
 		_, err := url.Parse(u)
@@ -0,0 +1,137 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	units "github.com/docker/go-units"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/snapshot"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+// snapshotCmd represents the snapshot command
+var snapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Save and restore snapshots of a cluster",
+	Long:  "Save the etcd data, container images and persistent volumes of every node in the cluster, and restore them later",
+}
+
+// snapshotSaveCmd represents the snapshot save command
+var snapshotSaveCmd = &cobra.Command{
+	Use:     "save NAME",
+	Short:   "Save a snapshot of the cluster",
+	Long:    "Save a snapshot of the cluster. Workloads are briefly stopped while the snapshot is taken.",
+	Example: "minikube snapshot save clean-install",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			exit.Message(reason.Usage, "Please provide a snapshot name: minikube snapshot save <NAME>")
+		}
+		co := mustload.Running(ClusterFlagValue())
+
+		out.Step(style.Waiting, "Saving snapshot {{.name}} of {{.cluster}} ...", out.V{"name": args[0], "cluster": co.Config.Name})
+		s, err := snapshot.Save(co.API, *co.Config, args[0])
+		if err != nil {
+			exit.Error(reason.GuestSnapshotSave, "Failed to save snapshot", err)
+		}
+		out.Step(style.Ready, "Saved snapshot {{.name}} ({{.size}})", out.V{"name": s.Name, "size": units.HumanSize(float64(snapshot.Size(co.Config.Name, s)))})
+	},
+}
+
+// snapshotRestoreCmd represents the snapshot restore command
+var snapshotRestoreCmd = &cobra.Command{
+	Use:     "restore NAME",
+	Short:   "Restore a snapshot of the cluster",
+	Long:    "Restore a snapshot into the cluster it was saved from, replacing its etcd data, container images and persistent volumes. The cluster must be running, with the same driver, container runtime, Kubernetes version and nodes as when the snapshot was saved.",
+	Example: "minikube snapshot restore clean-install",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			exit.Message(reason.Usage, "Please provide a snapshot name: minikube snapshot restore <NAME>")
+		}
+		co := mustload.Running(ClusterFlagValue())
+
+		out.Step(style.Waiting, "Restoring snapshot {{.name}} into {{.cluster}} ...", out.V{"name": args[0], "cluster": co.Config.Name})
+		if err := snapshot.Restore(co.API, co.Config, args[0]); err != nil {
+			exit.Error(reason.GuestSnapshotRestore, "Failed to restore snapshot", err)
+		}
+		out.Step(style.Ready, "Restored snapshot {{.name}}", out.V{"name": args[0]})
+	},
+}
+
+// snapshotListCmd represents the snapshot list command
+var snapshotListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the snapshots of the cluster",
+	Run: func(cmd *cobra.Command, args []string) {
+		profile := ClusterFlagValue()
+		snaps, err := snapshot.List(profile)
+		if err != nil {
+			exit.Error(reason.HostSnapshotList, "Failed to list snapshots", err)
+		}
+		if len(snaps) == 0 {
+			out.Step(style.Empty, "No snapshots of {{.cluster}} were found. To save one, run: minikube snapshot save <NAME>", out.V{"cluster": profile})
+			return
+		}
+
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Name", "Created", "Version", "Runtime", "Nodes", "Size"})
+		table.SetAutoFormatHeaders(false)
+		table.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+		table.SetCenterSeparator("|")
+		for _, s := range snaps {
+			table.Append([]string{
+				s.Name,
+				s.Created.Format(time.RFC3339),
+				s.Config.KubernetesConfig.KubernetesVersion,
+				s.Config.KubernetesConfig.ContainerRuntime,
+				fmt.Sprint(len(s.Config.Nodes)),
+				units.HumanSize(float64(snapshot.Size(profile, s))),
+			})
+		}
+		table.Render()
+	},
+}
+
+// snapshotDeleteCmd represents the snapshot delete command
+var snapshotDeleteCmd = &cobra.Command{
+	Use:   "delete NAME",
+	Short: "Delete a snapshot of the cluster",
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) != 1 {
+			exit.Message(reason.Usage, "Please provide a snapshot name: minikube snapshot delete <NAME>")
+		}
+		if err := snapshot.Delete(ClusterFlagValue(), args[0]); err != nil {
+			exit.Error(reason.HostSnapshotDelete, "Failed to delete snapshot", err)
+		}
+		out.Step(style.Deleted, "Deleted snapshot {{.name}}", out.V{"name": args[0]})
+	},
+}
+
+func init() {
+	snapshotCmd.AddCommand(snapshotSaveCmd)
+	snapshotCmd.AddCommand(snapshotRestoreCmd)
+	snapshotCmd.AddCommand(snapshotListCmd)
+	snapshotCmd.AddCommand(snapshotDeleteCmd)
+}
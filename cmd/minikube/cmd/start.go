@@ -17,6 +17,7 @@ limitations under the License.
 package cmd
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"math"
@@ -127,6 +128,11 @@ func platform() string {
 
 // runStart handles the executes the flow of "minikube start"
 func runStart(cmd *cobra.Command, args []string) {
+	// the spec may name the profile, so must be loaded before anything uses it
+	if f := viper.GetString(specFile); f != "" {
+		loadClusterSpec(cmd, f)
+	}
+
 	register.SetEventLogPath(localpath.EventLog(ClusterFlagValue()))
 
 	out.SetJSON(outputFormat == "json")
@@ -266,6 +272,7 @@ func runStart(cmd *cobra.Command, args []string) {
 		node.ExitIfFatal(err)
 		exit.Error(reason.GuestStart, "failed to start node", err)
 	}
+	startPersistentMounts(starter.Cfg)
 
 	if err := showKubectlInfo(kubeconfig, starter.Node.KubernetesVersion, starter.Cfg.Name); err != nil {
 		klog.Errorf("kubectl info: %v", err)
@@ -282,6 +289,9 @@ func provisionWithDriver(cmd *cobra.Command, ds registry.DriverState, existing *
 	}
 
 	validateFlags(cmd, driverName)
+	if existing == nil {
+		validateNetwork(driverName)
+	}
 	validateUser(driverName)
 	if driverName == oci.Docker {
 		validateDockerStorageDriver(driverName)
@@ -319,6 +329,9 @@ func provisionWithDriver(cmd *cobra.Command, ds registry.DriverState, existing *
 			existingAddons = existing.Addons
 		}
 	}
+	if cc.Registry && existing == nil {
+		config.AddonList = append(config.AddonList, "registry")
+	}
 
 	if viper.GetBool(nativeSSH) {
 		ssh.SetDefaultClient(ssh.Native)
@@ -343,6 +356,16 @@ func provisionWithDriver(cmd *cobra.Command, ds registry.DriverState, existing *
 }
 
 func startWithDriver(cmd *cobra.Command, starter node.Starter, existing *config.ClusterConfig) (*kubeconfig.Settings, error) {
+	// etcd on the primary control plane cannot become healthy until a quorum of its members is running again
+	if existing != nil && existing.HA {
+		for _, n := range config.ControlPlanes(*existing)[1:] {
+			n := n
+			if _, _, _, _, err := node.Provision(starter.Cfg, &n, false, viper.GetBool(deleteOnFailure)); err != nil {
+				return nil, errors.Wrapf(err, "provisioning control plane %s", n.Name)
+			}
+		}
+	}
+
 	kubeconfig, err := node.Start(starter, true)
 	if err != nil {
 		kubeconfig, err = maybeDeleteAndRetry(cmd, *starter.Cfg, *starter.Node, starter.ExistingAddons, err)
@@ -352,6 +375,9 @@ func startWithDriver(cmd *cobra.Command, starter node.Starter, existing *config.
 	}
 
 	numNodes := viper.GetInt(nodes)
+	if starter.Cfg.HA && numNodes < haControlPlanes {
+		numNodes = haControlPlanes
+	}
 	if existing != nil {
 		if numNodes > 1 {
 			// We ignore the --nodes parameter if we're restarting an existing cluster
@@ -373,9 +399,14 @@ func startWithDriver(cmd *cobra.Command, starter node.Starter, existing *config.
 					n := config.Node{
 						Name:              nodeName,
 						Worker:            true,
-						ControlPlane:      false,
+						ControlPlane:      starter.Cfg.HA && i < haControlPlanes,
 						KubernetesVersion: starter.Cfg.KubernetesConfig.KubernetesVersion,
 					}
+					// the API servers of the control planes listen on the port the virtual IP forwards to
+					if n.ControlPlane {
+						n.Port = starter.Cfg.KubernetesConfig.NodePort
+					}
+					clusterSpec.ApplyNode(i, &n)
 					out.Ln("") // extra newline for clarity on the command line
 					err := node.Add(starter.Cfg, n, viper.GetBool(deleteOnFailure))
 					if err != nil {
@@ -383,8 +414,12 @@ func startWithDriver(cmd *cobra.Command, starter node.Starter, existing *config.
 					}
 				}
 			} else {
+				cp, err := config.PrimaryControlPlane(existing)
+				if err != nil {
+					return nil, errors.Wrap(err, "getting primary control plane")
+				}
 				for _, n := range existing.Nodes {
-					if !n.ControlPlane {
+					if n.Name != cp.Name {
 						err := node.Add(starter.Cfg, n, viper.GetBool(deleteOnFailure))
 						if err != nil {
 							return nil, errors.Wrap(err, "adding node")
@@ -1021,6 +1056,24 @@ func validateFlags(cmd *cobra.Command, drvName string) {
 
 	validateCPUCount(drvName)
 
+	if viper.GetBool(ha) && driver.BareMetal(drvName) {
+		exit.Message(reason.DrvUnsupportedMulti, "The none driver is not compatible with highly available clusters.")
+	}
+
+	validateIPFamily(cmd, drvName)
+	validateNetworkFlags(drvName)
+
+	if viper.GetBool(registryMode) {
+		port := viper.GetInt(registryHostPort)
+		if port < 1 || port > 65535 {
+			exit.Message(reason.Usage, "Sorry, the --registry-port {{.port}} is not a valid port", out.V{"port": port})
+		}
+		// the none driver runs the registry proxy directly on the host, on the port of the registry addon
+		if driver.BareMetal(drvName) && port != constants.RegistryAddonPort {
+			exit.Message(reason.Usage, "The none driver always exposes the local registry on port {{.port}}", out.V{"port": constants.RegistryAddonPort})
+		}
+	}
+
 	if cmd.Flags().Changed(memory) {
 		if !driver.HasResourceLimits(drvName) {
 			out.WarningT("The '{{.name}}' driver does not respect the --memory flag", out.V{"name": drvName})
@@ -1103,6 +1156,167 @@ func validateFlags(cmd *cobra.Command, drvName string) {
 	validateRegistryMirror()
 }
 
+// validateNetworkFlags validates the --network, --subnet and --static-ip flags of the docker and podman drivers
+func validateNetworkFlags(drvName string) {
+	for _, flag := range []string{network, subnet, staticIP} {
+		if viper.GetString(flag) != "" && !driver.IsKIC(drvName) {
+			exit.Message(reason.Usage, "The --{{.flag}} flag is only supported by the docker and podman drivers", out.V{"flag": flag})
+		}
+	}
+
+	if s := viper.GetString(subnet); s != "" {
+		ipNet, err := oci.ParseSubnet(s)
+		if err != nil {
+			exit.Message(reason.Usage, "Sorry, the --subnet {{.subnet}} is not valid: {{.error}}", out.V{"subnet": s, "error": err})
+		}
+		if !isPrivateIPv4(ipNet.IP) {
+			exit.Message(reason.Usage, "Sorry, the --subnet {{.subnet}} is not a private IPv4 subnet", out.V{"subnet": s})
+		}
+	}
+
+	if s := viper.GetString(staticIP); s != "" {
+		ip := net.ParseIP(s).To4()
+		if ip == nil || !isPrivateIPv4(ip) {
+			exit.Message(reason.Usage, "Sorry, the --static-ip {{.ip}} is not a private IPv4 address", out.V{"ip": s})
+		}
+	}
+
+	if viper.GetString(subnet) != "" || viper.GetString(staticIP) != "" {
+		numNodes := viper.GetInt(nodes)
+		if viper.GetBool(ha) && numNodes < haControlPlanes {
+			numNodes = haControlPlanes
+		}
+		if err := validateNodeRange(viper.GetString(subnet), viper.GetString(staticIP), numNodes, viper.GetBool(ha)); err != nil {
+			exit.Message(reason.Usage, "Sorry, the nodes do not fit within the network: {{.error}}", out.V{"error": err})
+		}
+	}
+}
+
+// validateNodeRange checks that the addresses of the nodes of a cluster, which follow the static IP of the primary node
+// or the gateway, fit within the subnet of its network, or the /24 subnet of the static IP. The first address of the
+// subnet goes to its gateway, the last to its broadcast address, and HA clusters keep the one before for their virtual IP.
+func validateNodeRange(subnet string, staticIP string, numNodes int, ha bool) error {
+	if subnet == "" {
+		subnet = staticIP
+	}
+	ipNet, err := oci.ParseSubnet(subnet)
+	if err != nil {
+		return err
+	}
+	first, last := oci.UsableRange(ipNet)
+	start := first
+	if staticIP != "" {
+		start = net.ParseIP(staticIP).To4()
+		if !ipNet.Contains(start) || bytes.Compare(start, first) < 0 || bytes.Compare(start, last) > 0 {
+			return fmt.Errorf("the static IP %s is not within %s to %s, the addresses of the subnet %s left to nodes", start, first, last, ipNet)
+		}
+	}
+	if ha {
+		last = oci.AddToIP(last, -1)
+	}
+	if end := oci.AddToIP(start, numNodes-1); bytes.Compare(end, last) > 0 {
+		if ha {
+			return fmt.Errorf("%d nodes from %s go past %s, the last address of the subnet %s before the virtual IP of the control planes", numNodes, start, last, ipNet)
+		}
+		return fmt.Errorf("%d nodes from %s go past %s, the last address of the subnet %s left to nodes", numNodes, start, last, ipNet)
+	}
+	return nil
+}
+
+// isPrivateIPv4 returns true if an IP is within the private IPv4 ranges of RFC 1918
+func isPrivateIPv4(ip net.IP) bool {
+	for _, cidr := range []string{"10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16"} {
+		_, private, _ := net.ParseCIDR(cidr)
+		if private.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateNetwork checks the network of a new docker or podman cluster against the existing networks
+func validateNetwork(drvName string) {
+	if !driver.IsKIC(drvName) {
+		return
+	}
+	name := viper.GetString(network)
+	if name == "" {
+		name = ClusterFlagValue()
+	}
+	if viper.GetString(network) == "" && viper.GetString(subnet) == "" && viper.GetString(staticIP) == "" {
+		return
+	}
+	if err := oci.ValidateNetwork(drvName, name, viper.GetString(subnet), viper.GetString(staticIP)); err != nil {
+		exit.Message(reason.Usage, "Unable to use network {{.network}}: {{.error}}", out.V{"network": name, "error": err})
+	}
+}
+
+// validateIPFamily validates --ip-family, and that the service CIDR matches it
+func validateIPFamily(cmd *cobra.Command, drvName string) {
+	family := viper.GetString(ipFamily)
+	switch family {
+	case constants.IPv4Family, constants.DualStackFamily:
+	case constants.IPv6Family:
+		if !driver.IsDocker(drvName) {
+			exit.Message(reason.Usage, "The {{.driver}} driver does not support IPv6 only clusters, use --driver=docker or --ip-family=dual", out.V{"driver": drvName})
+		}
+	default:
+		exit.Message(reason.Usage, "Sorry, the --ip-family {{.family}} is not valid, valid options are: ipv4, ipv6, dual", out.V{"family": family})
+	}
+
+	if cmd.Flags().Changed(serviceCIDR) {
+		if err := validateServiceCIDR(family, viper.GetString(serviceCIDR)); err != nil {
+			exit.Message(reason.Usage, "Sorry, the --service-cluster-ip-range is not valid for --ip-family={{.family}}: {{.error}}", out.V{"family": family, "error": err})
+		}
+	}
+
+	if family == constants.IPv4Family {
+		return
+	}
+	// dual-stack networking was introduced as an alpha feature of Kubernetes v1.16
+	version, err := util.ParseKubernetesVersion(getKubernetesVersion(nil))
+	if err == nil && version.LT(semver.MustParse("1.16.0")) {
+		exit.Message(reason.Usage, "Sorry, --ip-family={{.family}} requires Kubernetes v1.16.0 or newer", out.V{"family": family})
+	}
+	if viper.GetInt(nodes) > 1 || viper.GetBool(ha) {
+		if c := viper.GetString(cniFlag); c == "" || c == "auto" || c == "kindnet" {
+			out.WarningT("Multi-node clusters use the kindnet CNI by default, which only assigns IPv4 addresses to pods. Pass --cni to use a CNI configured for --ip-family={{.family}}", out.V{"family": family})
+		}
+	}
+}
+
+// validateServiceCIDR checks that a comma separated service CIDR has a CIDR of each IP family of a cluster
+func validateServiceCIDR(family string, serviceCIDR string) error {
+	v4, v6 := 0, 0
+	for _, c := range strings.Split(serviceCIDR, ",") {
+		ip, _, err := net.ParseCIDR(strings.TrimSpace(c))
+		if err != nil {
+			return err
+		}
+		if ip.To4() != nil {
+			v4++
+		} else {
+			v6++
+		}
+	}
+
+	switch family {
+	case constants.IPv6Family:
+		if v4 > 0 || v6 != 1 {
+			return fmt.Errorf("expected an IPv6 CIDR, got %q", serviceCIDR)
+		}
+	case constants.DualStackFamily:
+		if v4 != 1 || v6 != 1 {
+			return fmt.Errorf("expected an IPv4 and an IPv6 CIDR, comma separated, got %q", serviceCIDR)
+		}
+	default:
+		if v6 > 0 || v4 != 1 {
+			return fmt.Errorf("expected an IPv4 CIDR, got %q", serviceCIDR)
+		}
+	}
+	return nil
+}
+
 // This function validates if the --registry-mirror
 // args match the format of http://localhost
 func validateRegistryMirror() {
@@ -1131,9 +1345,13 @@ func createNode(cc config.ClusterConfig, kubeNodeName string, existing *config.C
 
 		// Make sure that existing nodes honor if KubernetesVersion gets specified on restart
 		// KubernetesVersion is the only attribute that the user can override in the Node object
+		// Workers added with their own --kubernetes-version keep it, for as long as the control plane supports it
 		nodes := []config.Node{}
 		for _, n := range existing.Nodes {
-			n.KubernetesVersion = getKubernetesVersion(&cc)
+			pinned := !n.ControlPlane && n.KubernetesVersion != existing.KubernetesConfig.KubernetesVersion
+			if !pinned || config.ValidateNodeVersion(n.KubernetesVersion, getKubernetesVersion(&cc)) != nil {
+				n.KubernetesVersion = getKubernetesVersion(&cc)
+			}
 			nodes = append(nodes, n)
 		}
 		cc.Nodes = nodes
@@ -1148,6 +1366,7 @@ func createNode(cc config.ClusterConfig, kubeNodeName string, existing *config.C
 		ControlPlane:      true,
 		Worker:            true,
 	}
+	clusterSpec.ApplyNode(0, &cp)
 	cc.Nodes = []config.Node{cp}
 	return cc, cp, nil
 }
@@ -1230,7 +1449,11 @@ func validateKubernetesVersion(old *config.ClusterConfig) {
 
 	}
 	if defaultVersion.GT(nvs) {
-		out.Step(style.New, "Kubernetes {{.new}} is now available. If you would like to upgrade, specify: --kubernetes-version={{.prefix}}{{.new}}", out.V{"prefix": version.VersionPrefix, "new": defaultVersion})
+		profileArg := ""
+		if old.Name != constants.DefaultClusterName {
+			profileArg = fmt.Sprintf(" -p %s", old.Name)
+		}
+		out.Step(style.New, "Kubernetes {{.new}} is now available. If you would like to upgrade, run: minikube upgrade{{.profile}} --kubernetes-version={{.prefix}}{{.new}}", out.V{"prefix": version.VersionPrefix, "new": defaultVersion, "profile": profileArg})
 	}
 }
 
@@ -75,6 +75,7 @@ const (
 	apiServerPort           = "apiserver-port"
 	dnsDomain               = "dns-domain"
 	serviceCIDR             = "service-cluster-ip-range"
+	ipFamily                = "ip-family"
 	imageRepository         = "image-repository"
 	imageMirrorCountry      = "image-mirror-country"
 	mountString             = "mount-string"
@@ -102,11 +103,19 @@ const (
 	hostOnlyNicType         = "host-only-nic-type"
 	natNicType              = "nat-nic-type"
 	nodes                   = "nodes"
+	ha                      = "ha"
+	specFile                = "file"
+	registryMode            = "registry"
+	registryHostPort        = "registry-port"
+	haControlPlanes         = 3 // the smallest number of stacked etcd members which tolerates a failure
 	preload                 = "preload"
 	deleteOnFailure         = "delete-on-failure"
 	forceSystemd            = "force-systemd"
 	kicBaseImage            = "base-image"
 	ports                   = "ports"
+	network                 = "network"
+	subnet                  = "subnet"
+	staticIP                = "static-ip"
 	startNamespace          = "namespace"
 	trace                   = "trace"
 )
@@ -149,6 +158,10 @@ func initMinikubeFlags() {
 	startCmd.Flags().Bool(autoUpdate, true, "If set, automatically updates drivers to the latest version. Defaults to true.")
 	startCmd.Flags().Bool(installAddons, true, "If set, install addons. Defaults to true.")
 	startCmd.Flags().IntP(nodes, "n", 1, "The number of nodes to spin up. Defaults to 1.")
+	startCmd.Flags().StringP(specFile, "f", "", "Path to a cluster spec file describing the cluster to create. Flags set on the command line take precedence over the file.")
+	startCmd.Flags().Bool(ha, false, "Create a highly available cluster with at least three control plane nodes fronted by a virtual IP. Not supported by the none driver.")
+	startCmd.Flags().Bool(registryMode, false, fmt.Sprintf("Run a local image registry, which every node trusts and resolves as %s:%d. It is exposed on the host at localhost:<registry-port>, through 'minikube registry' for VM drivers.", constants.RegistryAlias, constants.RegistryAddonPort))
+	startCmd.Flags().Int(registryHostPort, constants.RegistryAddonPort, "The host port the local registry is exposed on, when started with --registry.")
 	startCmd.Flags().Bool(preload, true, "If set, download tarball of preloaded images if available to improve start time. Defaults to true.")
 	startCmd.Flags().Bool(deleteOnFailure, false, "If set, delete the current cluster if start fails and try again. Defaults to false.")
 	startCmd.Flags().Bool(forceSystemd, false, "If set, force the container runtime to use sytemd as cgroup manager. Currently available for docker and crio. Defaults to false.")
@@ -208,6 +221,9 @@ func initDriverFlags() {
 
 	// docker & podman
 	startCmd.Flags().StringSlice(ports, []string{}, "List of ports that should be exposed (docker and podman driver only)")
+	startCmd.Flags().String(network, "", "Existing network to attach the nodes to, which is created if it does not exist. Defaults to a network named after the cluster (docker and podman driver only)")
+	startCmd.Flags().String(subnet, "", "Subnet of the network created for the cluster, such as 192.168.60.0/24. Defaults to the first free subnet from 192.168.49.0/24 (docker and podman driver only)")
+	startCmd.Flags().String(staticIP, "", "Static private IPv4 address of the primary node, such as 192.168.60.10. The other nodes get the following addresses (docker and podman driver only)")
 }
 
 // initNetworkingFlags inits the commandline flags for connectivity related flags for start
@@ -216,11 +232,34 @@ func initNetworkingFlags() {
 	startCmd.Flags().StringSliceVar(&registryMirror, "registry-mirror", nil, "Registry mirrors to pass to the Docker daemon")
 	startCmd.Flags().String(imageRepository, "", "Alternative image repository to pull docker images from. This can be used when you have limited access to gcr.io. Set it to \"auto\" to let minikube decide one for you. For Chinese mainland users, you may use local gcr.io mirrors such as registry.cn-hangzhou.aliyuncs.com/google_containers")
 	startCmd.Flags().String(imageMirrorCountry, "", "Country code of the image mirror to be used. Leave empty to use the global one. For Chinese mainland users, set it to cn.")
-	startCmd.Flags().String(serviceCIDR, constants.DefaultServiceCIDR, "The CIDR to be used for service cluster IPs.")
+	startCmd.Flags().String(serviceCIDR, constants.DefaultServiceCIDR, fmt.Sprintf("The CIDR to be used for service cluster IPs. Defaults to %s for --ip-family=ipv6, and to both CIDRs, comma separated, for --ip-family=dual.", constants.DefaultServiceCIDRv6))
+	startCmd.Flags().String(ipFamily, constants.IPv4Family, "The IP family of the pod and service networks: ipv4, ipv6 or dual. IPv6 only clusters require the docker driver.")
 	startCmd.Flags().StringArrayVar(&config.DockerEnv, "docker-env", nil, "Environment variables to pass to the Docker daemon. (format: key=value)")
 	startCmd.Flags().StringArrayVar(&config.DockerOpt, "docker-opt", nil, "Specify arbitrary flags to pass to the Docker daemon. (format: key=value)")
 }
 
+// serviceClusterIPRange returns the service CIDR of a new cluster, which defaults to the CIDRs of its IP family
+func serviceClusterIPRange(cmd *cobra.Command) string {
+	if cmd.Flags().Changed(serviceCIDR) {
+		return viper.GetString(serviceCIDR)
+	}
+	switch viper.GetString(ipFamily) {
+	case constants.IPv6Family:
+		return constants.DefaultServiceCIDRv6
+	case constants.DualStackFamily:
+		return constants.DefaultServiceCIDR + "," + constants.DefaultServiceCIDRv6
+	}
+	return viper.GetString(serviceCIDR)
+}
+
+// clusterIPFamily returns the IP family of a cluster, which profiles created before --ip-family leave empty
+func clusterIPFamily(cc config.ClusterConfig) string {
+	if cc.KubernetesConfig.IPFamily == "" {
+		return constants.IPv4Family
+	}
+	return cc.KubernetesConfig.IPFamily
+}
+
 // ClusterFlagValue returns the current cluster name based on flags
 func ClusterFlagValue() string {
 	return viper.GetString(config.ProfileName)
@@ -328,6 +367,9 @@ func generateClusterConfig(cmd *cobra.Command, existing *config.ClusterConfig, k
 			NatNicType:              viper.GetString(natNicType),
 			StartHostTimeout:        viper.GetDuration(waitTimeout),
 			ExposedPorts:            viper.GetStringSlice(ports),
+			Network:                 viper.GetString(network),
+			Subnet:                  viper.GetString(subnet),
+			StaticIP:                viper.GetString(staticIP),
 			KubernetesConfig: config.KubernetesConfig{
 				KubernetesVersion:      k8sVersion,
 				ClusterName:            ClusterFlagValue(),
@@ -340,16 +382,27 @@ func generateClusterConfig(cmd *cobra.Command, existing *config.ClusterConfig, k
 				ContainerRuntime:       viper.GetString(containerRuntime),
 				CRISocket:              viper.GetString(criSocket),
 				NetworkPlugin:          chosenNetworkPlugin,
-				ServiceCIDR:            viper.GetString(serviceCIDR),
+				ServiceCIDR:            serviceClusterIPRange(cmd),
+				IPFamily:               viper.GetString(ipFamily),
 				ImageRepository:        repository,
 				ExtraOptions:           config.ExtraOptions,
 				ShouldLoadCachedImages: viper.GetBool(cacheImages),
 				CNI:                    chosenCNI,
 				NodePort:               viper.GetInt(apiServerPort),
 			},
-			MultiNodeRequested: viper.GetInt(nodes) > 1,
+			MultiNodeRequested: viper.GetInt(nodes) > 1 || viper.GetBool(ha),
+			HA:                 viper.GetBool(ha),
+			Mount:              viper.GetBool(createMount),
+			MountString:        viper.GetString(mountString),
+			Registry:           viper.GetBool(registryMode),
+			RegistryHostPort:   viper.GetInt(registryHostPort),
+		}
+		if cc.Registry {
+			cc.InsecureRegistry = append(cc.InsecureRegistry, fmt.Sprintf("%s:%d", constants.RegistryAlias, constants.RegistryAddonPort))
 		}
 		cc.VerifyComponents = interpretWaitFlag(*cmd)
+		clusterSpec.ApplyAddonConfig(&cc.KubernetesConfig)
+		cc.Mounts = specMounts(clusterSpec)
 		if viper.GetBool(createMount) && driver.IsKIC(drvName) {
 			cc.ContainerVolumeMounts = []string{viper.GetString(mountString)}
 		}
@@ -463,6 +516,38 @@ func updateExistingConfigFromFlags(cmd *cobra.Command, existing *config.ClusterC
 		}
 	}
 
+	if cmd.Flags().Changed(createMount) {
+		cc.Mount = viper.GetBool(createMount)
+	}
+
+	if cmd.Flags().Changed(mountString) {
+		cc.MountString = viper.GetString(mountString)
+	}
+
+	if cmd.Flags().Changed(ha) {
+		if viper.GetBool(ha) != cc.HA {
+			out.WarningT("You cannot change the high availability of an existing minikube cluster. Please first delete the cluster.")
+		}
+	}
+
+	if cmd.Flags().Changed(network) || cmd.Flags().Changed(subnet) || cmd.Flags().Changed(staticIP) {
+		if viper.GetString(network) != cc.Network || viper.GetString(subnet) != cc.Subnet || viper.GetString(staticIP) != cc.StaticIP {
+			out.WarningT("You cannot change the network of an existing minikube cluster. Please first delete the cluster.")
+		}
+	}
+
+	if cmd.Flags().Changed(ipFamily) {
+		if viper.GetString(ipFamily) != clusterIPFamily(cc) {
+			out.WarningT("You cannot change the IP family of an existing minikube cluster. Please first delete the cluster.")
+		}
+	}
+
+	if cmd.Flags().Changed(registryMode) || cmd.Flags().Changed(registryHostPort) {
+		if viper.GetBool(registryMode) != cc.Registry || viper.GetInt(registryHostPort) != cc.RegistryHostPort {
+			out.WarningT("You cannot change the local registry of an existing minikube cluster. Please first delete the cluster.")
+		}
+	}
+
 	if cmd.Flags().Changed(humanReadableDiskSize) {
 		memInMB, err := pkgutil.CalculateSizeInMB(viper.GetString(humanReadableDiskSize))
 		if err != nil {
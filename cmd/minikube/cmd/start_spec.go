@@ -0,0 +1,176 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/addons"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+// clusterSpec is the cluster spec passed to start with --file, if any
+var clusterSpec *config.ClusterSpec
+
+// loadClusterSpec loads the cluster spec passed with --file, and applies it to the start flags which were not set on the command line
+func loadClusterSpec(cmd *cobra.Command, path string) {
+	s, err := config.LoadClusterSpec(path)
+	if err != nil {
+		exit.Message(reason.Usage, "Invalid cluster spec {{.file}}: {{.error}}", out.V{"file": path, "error": err})
+	}
+	if err := validateClusterSpec(s); err != nil {
+		exit.Message(reason.Usage, "Invalid cluster spec {{.file}}: {{.error}}", out.V{"file": path, "error": err})
+	}
+	// relative host paths are relative to the spec file
+	for i, m := range s.Mounts {
+		if !filepath.IsAbs(m.HostPath) {
+			abs, err := filepath.Abs(filepath.Join(filepath.Dir(path), m.HostPath))
+			if err != nil {
+				exit.Error(reason.HostPathStat, "Unable to get the absolute path of the host directory", err)
+			}
+			s.Mounts[i].HostPath = abs
+		}
+	}
+
+	if s.Name != "" {
+		setSpecFlag(cmd, config.ProfileName, s.Name)
+	}
+	if config.ProfileExists(ClusterFlagValue()) {
+		out.Step(style.Notice, "The cluster {{.name}} already exists, so only the name within {{.file}} is used. To recreate it from the file, first run: minikube delete -p {{.name}}", out.V{"name": ClusterFlagValue(), "file": path})
+		return
+	}
+
+	for flag, value := range specFlags(s) {
+		setSpecFlag(cmd, flag, value)
+	}
+	if len(s.ExtraConfig) > 0 && !cmd.Flags().Changed("extra-config") {
+		for _, e := range s.ExtraConfig {
+			if err := cmd.Flags().Set("extra-config", e); err != nil {
+				exit.Message(reason.Usage, "Invalid cluster spec {{.file}}: {{.error}}", out.V{"file": path, "error": err})
+			}
+		}
+	}
+	clusterSpec = s
+}
+
+// validateClusterSpec checks the parts of a cluster spec which depend upon the drivers, runtimes and addons known to minikube
+func validateClusterSpec(s *config.ClusterSpec) error {
+	if s.Driver != "" && !driver.Supported(s.Driver) {
+		return fmt.Errorf("driver: %q is not supported on %s, valid drivers: %s", s.Driver, runtime.GOOS, driver.DisplaySupportedDrivers())
+	}
+	if s.ContainerRuntime != "" {
+		if _, err := cruntime.New(cruntime.Config{Type: s.ContainerRuntime}); err != nil {
+			return fmt.Errorf("containerRuntime: %q is not supported, valid runtimes: %s", s.ContainerRuntime, strings.Join(cruntime.ValidRuntimes(), ", "))
+		}
+	}
+	if len(s.Ports) > 0 && s.Driver != "" && !driver.IsKIC(s.Driver) {
+		return fmt.Errorf("ports: only supported by the docker and podman drivers")
+	}
+
+	for i, m := range s.Mounts {
+		if m.Type != "" && !supportedFilesystems[m.Type] {
+			return fmt.Errorf("mounts[%d].type: %q is not supported, valid types: %s, %s, %s, %s", i, m.Type, nineP, cluster.MountNFS, cluster.MountSSHFS, cluster.MountSync)
+		}
+	}
+
+	addons.LoadExternal()
+	for i, a := range s.Addons {
+		if _, ok := assets.Addons[a.Name]; !ok {
+			return fmt.Errorf("addons[%d].name: %q is not a known addon, see: minikube addons list", i, a.Name)
+		}
+	}
+	return nil
+}
+
+// specFlags returns the start flags which correspond to the fields of a cluster spec
+func specFlags(s *config.ClusterSpec) map[string]string {
+	flags := map[string]string{}
+	set := func(flag string, value string) {
+		if value != "" {
+			flags[flag] = value
+		}
+	}
+
+	set("driver", s.Driver)
+	set(containerRuntime, s.ContainerRuntime)
+	set(kubernetesVersion, s.KubernetesVersion)
+	set(memory, s.Memory)
+	set(humanReadableDiskSize, s.DiskSize)
+	set(cniFlag, s.CNI)
+	set(ipFamily, s.IPFamily)
+	set(featureGates, s.FeatureGates)
+	set("registry-mirror", strings.Join(s.RegistryMirrors, ","))
+	set("insecure-registry", strings.Join(s.InsecureRegistries, ","))
+	set(ports, strings.Join(s.Ports, ","))
+	if s.CPUs > 0 {
+		set(cpus, fmt.Sprint(s.CPUs))
+	}
+	if s.HA {
+		set(ha, "true")
+	}
+	if len(s.Nodes) > 0 {
+		set(nodes, fmt.Sprint(len(s.Nodes)))
+	}
+
+	names := []string{}
+	for _, a := range s.Addons {
+		names = append(names, a.Name)
+	}
+	set("addons", strings.Join(names, ","))
+	return flags
+}
+
+// specMounts returns the persistent mounts of a cluster spec, with the default options of minikube mount
+func specMounts(s *config.ClusterSpec) []config.Mount {
+	if s == nil {
+		return nil
+	}
+	mounts := []config.Mount{}
+	for _, ms := range s.Mounts {
+		m := newMount(ms.HostPath, ms.GuestPath)
+		if ms.Type != "" {
+			m.Type = ms.Type
+		}
+		m.ReadOnly = ms.ReadOnly
+		mounts = append(mounts, m)
+	}
+	return mounts
+}
+
+// setSpecFlag sets a start flag from the cluster spec, unless it was set on the command line, which takes precedence
+func setSpecFlag(cmd *cobra.Command, flag string, value string) {
+	if cmd.Flags().Changed(flag) {
+		klog.Infof("--%s overrides the cluster spec", flag)
+		return
+	}
+	if err := cmd.Flags().Set(flag, value); err != nil {
+		exit.Message(reason.Usage, "Invalid value {{.value}} for {{.flag}} within the cluster spec: {{.error}}", out.V{"value": value, "flag": flag, "error": err})
+	}
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package cmd
 
 import (
+	"net"
 	"os"
 	"strings"
 	"testing"
@@ -310,3 +311,106 @@ func TestBaseImageFlagDriverCombo(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateServiceCIDR(t *testing.T) {
+	tests := []struct {
+		family      string
+		serviceCIDR string
+		shouldErr   bool
+	}{
+		{constants.IPv4Family, "10.96.0.0/12", false},
+		{constants.IPv4Family, "fd00:10:96::/112", true},
+		{constants.IPv6Family, "fd00:10:96::/112", false},
+		{constants.IPv6Family, "10.96.0.0/12,fd00:10:96::/112", true},
+		{constants.DualStackFamily, "10.96.0.0/12,fd00:10:96::/112", false},
+		{constants.DualStackFamily, "fd00:10:96::/112, 10.96.0.0/12", false},
+		{constants.DualStackFamily, "10.96.0.0/12", true},
+		{constants.DualStackFamily, "10.96.0.0/12,10.112.0.0/12", true},
+		{constants.DualStackFamily, "10.96.0.0/12,nope", true},
+	}
+	for _, tc := range tests {
+		err := validateServiceCIDR(tc.family, tc.serviceCIDR)
+		if err != nil && !tc.shouldErr {
+			t.Errorf("validateServiceCIDR(%s, %q) returned unexpected error: %v", tc.family, tc.serviceCIDR, err)
+		}
+		if err == nil && tc.shouldErr {
+			t.Errorf("validateServiceCIDR(%s, %q) should have returned an error", tc.family, tc.serviceCIDR)
+		}
+	}
+}
+
+func TestIsPrivateIPv4(t *testing.T) {
+	tests := []struct {
+		ip       string
+		expected bool
+	}{
+		{"192.168.60.10", true},
+		{"172.20.0.2", true},
+		{"10.1.2.3", true},
+		{"172.32.0.2", false},
+		{"8.8.8.8", false},
+	}
+	for _, tc := range tests {
+		if got := isPrivateIPv4(net.ParseIP(tc.ip)); got != tc.expected {
+			t.Errorf("isPrivateIPv4(%s) = %t, expected %t", tc.ip, got, tc.expected)
+		}
+	}
+}
+
+func TestValidateNodeRange(t *testing.T) {
+	tests := []struct {
+		description string
+		subnet      string
+		staticIP    string
+		nodes       int
+		ha          bool
+		shouldErr   bool
+	}{
+		{description: "static IP", staticIP: "192.168.50.10", nodes: 3},
+		{description: "last address", staticIP: "192.168.50.254", nodes: 1},
+		{description: "past the last address", staticIP: "192.168.50.253", nodes: 3, shouldErr: true},
+		{description: "gateway", staticIP: "192.168.50.1", nodes: 1, shouldErr: true},
+		{description: "network address", staticIP: "192.168.50.0", nodes: 1, shouldErr: true},
+		{description: "ha before the virtual IP", staticIP: "192.168.50.251", nodes: 3, ha: true},
+		{description: "ha on the virtual IP", staticIP: "192.168.50.252", nodes: 3, ha: true, shouldErr: true},
+		{description: "small subnet", subnet: "192.168.50.0/28", nodes: 3, ha: true},
+		{description: "small subnet static IP", subnet: "192.168.50.0/28", staticIP: "192.168.50.12", nodes: 3, ha: true, shouldErr: true},
+		{description: "small subnet outside", subnet: "192.168.50.0/28", staticIP: "192.168.50.20", nodes: 1, shouldErr: true},
+		{description: "small subnet full", subnet: "192.168.50.0/29", nodes: 6, shouldErr: true},
+		{description: "small subnet fits", subnet: "192.168.50.0/29", nodes: 5},
+	}
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			err := validateNodeRange(tc.subnet, tc.staticIP, tc.nodes, tc.ha)
+			if err != nil && !tc.shouldErr {
+				t.Errorf("validateNodeRange(%q, %q, %d, %t) returned unexpected error: %v", tc.subnet, tc.staticIP, tc.nodes, tc.ha, err)
+			}
+			if err == nil && tc.shouldErr {
+				t.Errorf("validateNodeRange(%q, %q, %d, %t) should have returned an error", tc.subnet, tc.staticIP, tc.nodes, tc.ha)
+			}
+		})
+	}
+}
+
+func TestSpecMounts(t *testing.T) {
+	if got := specMounts(nil); len(got) != 0 {
+		t.Errorf("specMounts(nil) = %v, want none", got)
+	}
+	s := &cfg.ClusterSpec{Mounts: []cfg.MountSpec{
+		{HostPath: "/home/dev/src", GuestPath: "/src"},
+		{HostPath: "/home/dev/docs", GuestPath: "/docs", Type: "sync", ReadOnly: true},
+	}}
+	got := specMounts(s)
+	if len(got) != 2 {
+		t.Fatalf("specMounts() = %v, want 2 mounts", got)
+	}
+	if got[0].HostPath != "/home/dev/src" || got[0].GuestPath != "/src" || got[0].Type != nineP || got[0].ReadOnly {
+		t.Errorf("specMounts()[0] = %+v, want a read-write 9p mount of /home/dev/src at /src", got[0])
+	}
+	if got[0].UID != "docker" || got[0].Mode != 0o755 || got[0].MSize != defaultMsize {
+		t.Errorf("specMounts()[0] = %+v, want the defaults of minikube mount", got[0])
+	}
+	if got[1].Type != "sync" || !got[1].ReadOnly {
+		t.Errorf("specMounts()[1] = %+v, want a read-only sync mount", got[1])
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 	"text/template"
@@ -33,9 +34,14 @@ import (
 	"github.com/docker/machine/libmachine/state"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/kapi"
 	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil/kverify"
 	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/cni"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/driver"
 	"k8s.io/minikube/pkg/minikube/exit"
@@ -125,8 +131,33 @@ var (
 	codeDetails = map[int]string{
 		507: "/var is almost out of disk space",
 	}
+
+	// componentPods are the label selectors of the pods of the cluster components probed by --layout=cluster
+	componentPods = map[string]string{
+		"etcd":               "component=etcd",
+		"scheduler":          "component=kube-scheduler",
+		"controller-manager": "component=kube-controller-manager",
+		"coredns":            "k8s-app=kube-dns",
+	}
+
+	// addonPods are the label selectors of the pods of addons which aren't labeled with kubernetes.io/minikube-addons
+	addonPods = map[string]string{
+		"ingress":     "app.kubernetes.io/name=ingress-nginx,app.kubernetes.io/component=controller",
+		"ingress-dns": "app=minikube-ingress-dns",
+		"metallb":     "app=metallb",
+	}
+
+	// podlessAddons are addons which deploy no pods to probe
+	podlessAddons = map[string]bool{
+		"default-storageclass": true,
+		"pod-security-policy":  true,
+		"volumesnapshots":      true,
+	}
 )
 
+// storageProvisionerPods is the label selector of the storage-provisioner pod
+const storageProvisionerPods = "integration-test=storage-provisioner"
+
 // Status holds string representations of component states
 type Status struct {
 	Name       string
@@ -145,6 +176,7 @@ type ClusterState struct {
 	BinaryVersion string
 	TimeToStop    string
 	Components    map[string]BaseState
+	Addons        map[string]BaseState `json:",omitempty"`
 	Nodes         []NodeState
 }
 
@@ -164,7 +196,7 @@ type BaseState struct {
 	// Name is a human-readable name for the status code
 	StatusName string
 	// StatusDetail is long human-readable string describing why this particular status code was chosen
-	StatusDetail string `json:",omitempty"` // Not yet implemented
+	StatusDetail string `json:",omitempty"`
 
 	// Step is which workflow step the object is at.
 	Step string `json:",omitempty"`
@@ -262,7 +294,7 @@ func writeStatusesAtInterval(duration time.Duration, api libmachine.API, cc *con
 		case "json":
 			// Layout is currently only supported for JSON mode
 			if layout == "cluster" {
-				if err := clusterStatusJSON(statuses, os.Stdout); err != nil {
+				if err := clusterStatusJSON(statuses, os.Stdout, cc); err != nil {
 					exit.Error(reason.InternalStatusJSON, "status json failure", err)
 				}
 			} else {
@@ -411,7 +443,7 @@ For the list accessible variables for the template, see the struct values here:
 	statusCmd.Flags().StringVarP(&output, "output", "o", "text",
 		`minikube status --output OUTPUT. json, text`)
 	statusCmd.Flags().StringVarP(&layout, "layout", "l", "nodes",
-		`output layout (EXPERIMENTAL, JSON only): 'nodes' or 'cluster'`)
+		`output layout (EXPERIMENTAL, JSON only): 'nodes' or 'cluster'. The 'cluster' layout also probes the health of the cluster components and enabled addons`)
 	statusCmd.Flags().StringVarP(&nodeName, "node", "n", "", "The node to check status for. Defaults to control plane. Leave blank with default format for status on all nodes.")
 	statusCmd.Flags().DurationVarP(&watch, "watch", "w", 1*time.Second, "Continuously listing/getting the status with optional interval duration.")
 	statusCmd.Flags().Lookup("watch").NoOptDefVal = "1s"
@@ -628,8 +660,9 @@ func statusCode(st string) int {
 	return Unknown
 }
 
-func clusterStatusJSON(statuses []*Status, w io.Writer) error {
+func clusterStatusJSON(statuses []*Status, w io.Writer, cc *config.ClusterConfig) error {
 	cs := clusterState(statuses)
+	probeCluster(&cs, *cc, statuses[0].APIServer)
 
 	bs, err := json.Marshal(cs)
 	if err != nil {
@@ -639,3 +672,144 @@ func clusterStatusJSON(statuses []*Status, w io.Writer) error {
 	_, err = w.Write(bs)
 	return err
 }
+
+// probeCluster probes the health of the components deployed to the cluster, through the apiserver
+func probeCluster(cs *ClusterState, cc config.ClusterConfig, apiserver string) {
+	if apiserver != state.Running.String() {
+		code := statusCode(apiserver)
+		detail := fmt.Sprintf("apiserver is %s", apiserver)
+		for name := range probedComponents(cc) {
+			cs.Components[name] = BaseState{Name: name, StatusCode: code, StatusName: codeNames[code], StatusDetail: detail}
+		}
+		return
+	}
+
+	client, err := kapi.Client(cc.Name)
+	if err != nil {
+		klog.Errorf("kubernetes client: %v", err)
+		for name := range probedComponents(cc) {
+			cs.Components[name] = BaseState{Name: name, StatusCode: Unknown, StatusName: codeNames[Unknown], StatusDetail: err.Error()}
+		}
+		return
+	}
+	probeComponents(cs, client, cc)
+}
+
+// probedComponents returns the label selectors of the pods of the cluster components to probe, by component name
+func probedComponents(cc config.ClusterConfig) map[string]string {
+	components := map[string]string{}
+	for name, selector := range componentPods {
+		components[name] = selector
+	}
+	if cc.Addons["storage-provisioner"] {
+		components["storage-provisioner"] = storageProvisionerPods
+	}
+
+	cnm, err := cni.New(cc)
+	if err != nil {
+		klog.Warningf("unable to determine the CNI: %v", err)
+	} else if selector := cni.PodSelector(cnm); selector != "" {
+		components["cni"] = selector
+	}
+	return components
+}
+
+// probeComponents probes the health of the cluster components, enabled addons and nodes
+func probeComponents(cs *ClusterState, client kubernetes.Interface, cc config.ClusterConfig) {
+	var unhealthy []string
+	for name, selector := range probedComponents(cc) {
+		bs := podsState(client, name, "kube-system", selector)
+		cs.Components[name] = bs
+		if bs.StatusCode != OK {
+			unhealthy = append(unhealthy, fmt.Sprintf("%s: %s", name, bs.StatusDetail))
+		}
+	}
+
+	for name, enabled := range cc.Addons {
+		if !enabled || name == "storage-provisioner" || podlessAddons[name] {
+			continue
+		}
+		selector, ok := addonPods[name]
+		if !ok {
+			selector = fmt.Sprintf("kubernetes.io/minikube-addons=%s", name)
+		}
+		if cs.Addons == nil {
+			cs.Addons = map[string]BaseState{}
+		}
+		cs.Addons[name] = podsState(client, name, "", selector)
+	}
+
+	nodes, err := client.CoreV1().Nodes().List(meta.ListOptions{})
+	if err != nil {
+		klog.Errorf("list nodes: %v", err)
+	} else {
+		for i, ns := range cs.Nodes {
+			for _, n := range nodes.Items {
+				if n.Name != ns.Name || ns.StatusCode != OK {
+					continue
+				}
+				if code, detail := nodeConditionState(n); code != OK {
+					cs.Nodes[i].StatusCode = code
+					cs.Nodes[i].StatusName = codeNames[code]
+					cs.Nodes[i].StatusDetail = detail
+					unhealthy = append(unhealthy, fmt.Sprintf("%s: %s", ns.Name, detail))
+				}
+			}
+		}
+	}
+
+	// the apiserver serves requests, but the cluster is degraded
+	if cs.StatusCode == OK && len(unhealthy) > 0 {
+		sort.Strings(unhealthy)
+		cs.StatusCode = Warning
+		cs.StatusName = codeNames[Warning]
+		cs.StatusDetail = strings.Join(unhealthy, "; ")
+	}
+}
+
+// podsState returns the state of a component given the health of its pods
+func podsState(client kubernetes.Interface, name string, namespace string, selector string) BaseState {
+	bs := BaseState{Name: name, StatusCode: OK}
+	err := kverify.PodsHealthy(client, namespace, selector)
+	switch e := err.(type) {
+	case nil:
+	case *kverify.ErrPodsNotFound:
+		bs.StatusCode = NotFound
+	case *kverify.ErrPodFailed:
+		bs.StatusCode = Error
+	case *kverify.ErrPodNotReady:
+		bs.StatusCode = Warning
+		if e.Phase == core.PodPending {
+			bs.StatusCode = Starting
+		}
+	default:
+		bs.StatusCode = Unknown
+	}
+	if err != nil {
+		klog.Infof("%s status: %v", name, err)
+		bs.StatusDetail = err.Error()
+	}
+	bs.StatusName = codeNames[bs.StatusCode]
+	return bs
+}
+
+// nodeConditionState returns the status code of a node given its conditions, along with the reason for it
+func nodeConditionState(n core.Node) (int, string) {
+	err := kverify.NodeConditions(n)
+	switch err.(type) {
+	case nil:
+	case *kverify.ErrDiskPressure:
+		return InsufficientStorage, err.Error()
+	case *kverify.ErrMemoryPressure, *kverify.ErrPIDPressure:
+		return Warning, err.Error()
+	default:
+		return Error, err.Error()
+	}
+
+	for _, c := range n.Status.Conditions {
+		if c.Type == core.NodeReady && c.Status != core.ConditionTrue {
+			return Error, fmt.Sprintf("node is not ready: Reason %q Message: %q", c.Reason, c.Message)
+		}
+	}
+	return OK, ""
+}
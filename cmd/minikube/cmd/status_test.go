@@ -20,6 +20,12 @@ import (
 	"bytes"
 	"encoding/json"
 	"testing"
+
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/minikube/pkg/minikube/config"
 )
 
 func TestExitCode(t *testing.T) {
@@ -105,3 +111,79 @@ func TestStatusJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestProbeComponents(t *testing.T) {
+	ready := []core.PodCondition{{Type: core.PodReady, Status: core.ConditionTrue}}
+	pod := func(name string, labels map[string]string, status core.PodStatus) *core.Pod {
+		return &core.Pod{ObjectMeta: meta.ObjectMeta{Name: name, Namespace: "kube-system", Labels: labels}, Status: status}
+	}
+	objs := []runtime.Object{
+		pod("etcd-minikube", map[string]string{"component": "etcd"}, core.PodStatus{Phase: core.PodRunning, Conditions: ready}),
+		pod("kube-scheduler-minikube", map[string]string{"component": "kube-scheduler"}, core.PodStatus{
+			Phase: core.PodRunning,
+			ContainerStatuses: []core.ContainerStatus{
+				{Name: "kube-scheduler", State: core.ContainerState{Waiting: &core.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}},
+			},
+		}),
+		pod("coredns-f9fd979d6-x8xq7", map[string]string{"k8s-app": "kube-dns"}, core.PodStatus{
+			Phase: core.PodPending,
+			ContainerStatuses: []core.ContainerStatus{
+				{Name: "coredns", State: core.ContainerState{Waiting: &core.ContainerStateWaiting{Reason: "ContainerCreating"}}},
+			},
+		}),
+		pod("storage-provisioner", map[string]string{"integration-test": "storage-provisioner"}, core.PodStatus{Phase: core.PodRunning, Conditions: ready}),
+		pod("metrics-server-d9b576748-6tq8w", map[string]string{"kubernetes.io/minikube-addons": "metrics-server"}, core.PodStatus{Phase: core.PodRunning}),
+		&core.Node{ObjectMeta: meta.ObjectMeta{Name: "minikube"}, Status: core.NodeStatus{Conditions: []core.NodeCondition{
+			{Type: core.NodeDiskPressure, Status: core.ConditionTrue, Reason: "KubeletHasDiskPressure"},
+			{Type: core.NodeReady, Status: core.ConditionTrue},
+		}}},
+		&core.Node{ObjectMeta: meta.ObjectMeta{Name: "minikube-m02"}, Status: core.NodeStatus{Conditions: []core.NodeCondition{
+			{Type: core.NodeReady, Status: core.ConditionTrue},
+		}}},
+	}
+
+	cc := config.ClusterConfig{
+		Name:             "minikube",
+		KubernetesConfig: config.KubernetesConfig{NetworkPlugin: "kubenet"},
+		Addons:           map[string]bool{"storage-provisioner": true, "default-storageclass": true, "metrics-server": true, "dashboard": false},
+	}
+	cs := ClusterState{
+		BaseState:  BaseState{Name: "minikube", StatusCode: OK, StatusName: "OK"},
+		Components: map[string]BaseState{},
+		Nodes: []NodeState{
+			{BaseState: BaseState{Name: "minikube", StatusCode: OK}},
+			{BaseState: BaseState{Name: "minikube-m02", StatusCode: OK}},
+		},
+	}
+	probeComponents(&cs, fake.NewSimpleClientset(objs...), cc)
+
+	want := map[string]int{
+		"etcd":                OK,
+		"scheduler":           Error,
+		"controller-manager":  NotFound,
+		"coredns":             Starting,
+		"storage-provisioner": OK,
+	}
+	if len(cs.Components) != len(want) {
+		t.Errorf("got components %v, want %v", cs.Components, want)
+	}
+	for name, code := range want {
+		got := cs.Components[name]
+		if got.StatusCode != code {
+			t.Errorf("%s: got status %d (%s), want %d", name, got.StatusCode, got.StatusDetail, code)
+		}
+		if code != OK && got.StatusDetail == "" {
+			t.Errorf("%s: no status detail for status %d", name, got.StatusCode)
+		}
+	}
+
+	if len(cs.Addons) != 1 || cs.Addons["metrics-server"].StatusCode != Warning {
+		t.Errorf("got addons %+v, want metrics-server with status %d", cs.Addons, Warning)
+	}
+	if cs.Nodes[0].StatusCode != InsufficientStorage || cs.Nodes[1].StatusCode != OK {
+		t.Errorf("got nodes %+v, want statuses %d and %d", cs.Nodes, InsufficientStorage, OK)
+	}
+	if cs.StatusCode != Warning || cs.StatusDetail == "" {
+		t.Errorf("got cluster status %d (%q), want %d with detail", cs.StatusCode, cs.StatusDetail, Warning)
+	}
+}
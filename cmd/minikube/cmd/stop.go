@@ -33,6 +33,7 @@ import (
 	"k8s.io/minikube/pkg/minikube/kubeconfig"
 	"k8s.io/minikube/pkg/minikube/localpath"
 	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/metrics"
 	"k8s.io/minikube/pkg/minikube/mustload"
 	"k8s.io/minikube/pkg/minikube/out"
 	"k8s.io/minikube/pkg/minikube/out/register"
@@ -67,6 +68,7 @@ func init() {
 		klog.Info("unable to mark --schedule flag as hidden")
 	}
 	stopCmd.Flags().StringVarP(&outputFormat, "output", "o", "text", "Format to print stdout in. Options include: [text,json]")
+	addMetricsFlag(stopCmd)
 
 	if err := viper.GetViper().BindPFlags(stopCmd.Flags()); err != nil {
 		exit.Error(reason.InternalFlagsBind, "unable to bind flags", err)
@@ -112,8 +114,14 @@ func runStop(cmd *cobra.Command, args []string) {
 		}
 		// if OS is windows, scheduled stop is now being handled within minikube, so return
 		if runtime.GOOS == "windows" {
+			if metricsAddr != "" {
+				out.WarningT("--metrics-addr is not supported for scheduled stops on Windows, which run within the cluster")
+			}
 			return
 		}
+		// this process has daemonized, so it now serves the metrics until the stop
+		serveMetrics()
+		metrics.SetScheduledStop(time.Now().Add(scheduledStopDuration))
 		klog.Infof("sleeping %s before completing stop...", scheduledStopDuration.String())
 		time.Sleep(scheduledStopDuration)
 	}
@@ -137,6 +145,9 @@ func stopProfile(profile string) int {
 	api, cc := mustload.Partial(profile)
 	defer api.Close()
 
+	// persistent mounts are unmounted while the nodes run, and restored by minikube start
+	stopPersistentMounts(cc)
+
 	for _, n := range cc.Nodes {
 		machineName := driver.MachineName(*cc, n)
 
@@ -18,9 +18,12 @@ package cmd
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"os/exec"
 	"os/signal"
 	"path/filepath"
+	"runtime"
 	"strconv"
 
 	"github.com/spf13/cobra"
@@ -29,22 +32,31 @@ import (
 	"k8s.io/minikube/pkg/drivers/kic/oci"
 	"k8s.io/minikube/pkg/kapi"
 	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/minikube/driver"
 	"k8s.io/minikube/pkg/minikube/exit"
 	"k8s.io/minikube/pkg/minikube/localpath"
 	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/out"
 	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
 	"k8s.io/minikube/pkg/minikube/tunnel"
 	"k8s.io/minikube/pkg/minikube/tunnel/kic"
 )
 
-var cleanup bool
+var (
+	cleanup          bool
+	tunnelHosts      bool
+	tunnelBackground bool
+)
 
 // tunnelCmd represents the tunnel command
 var tunnelCmd = &cobra.Command{
 	Use:   "tunnel",
 	Short: "Connect to LoadBalancer services",
-	Long:  `tunnel creates a route to services deployed with type LoadBalancer and sets their Ingress to their ClusterIP. for a detailed example see https://minikube.sigs.k8s.io/docs/tasks/loadbalancer`,
+	Long: `tunnel creates a route to services deployed with type LoadBalancer and sets their Ingress to their ClusterIP. for a detailed example see https://minikube.sigs.k8s.io/docs/tasks/loadbalancer
+
+It also sets the address of Ingress resources to the ingress controller, and adds their hosts to the hosts file of the host, so that they resolve to the ingress controller through the tunnel.`,
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
 		RootCmd.PersistentPreRun(cmd, args)
 	},
@@ -60,6 +72,12 @@ var tunnelCmd = &cobra.Command{
 			}
 		}
 
+		if tunnelBackground && !startTunnelInBackground(manager, co.Config) {
+			return
+		}
+		// only the process which keeps running serves the metrics
+		serveMetrics()
+
 		// Tunnel uses the k8s clientset to query the API server for services in the LoadBalancerEmulator.
 		// We define the tunnel and minikube error free if the API server responds within a second.
 		// This also contributes to better UX, the tunnel status check can happen every second and
@@ -86,8 +104,21 @@ var tunnelCmd = &cobra.Command{
 			sshPort := strconv.Itoa(port)
 			sshKey := filepath.Join(localpath.MiniPath(), "machines", cname, "id_rsa")
 
-			kicSSHTunnel := kic.NewSSHTunnel(ctx, sshPort, sshKey, clientset.CoreV1())
+			// the ssh tunnel has no route, and is registered by its machine, for minikube tunnel status and stop
+			id := &tunnel.ID{MachineName: cname, Pid: os.Getpid()}
+			if err := manager.Register(id); err != nil {
+				exit.Error(reason.SvcTunnelStart, "error starting tunnel", err)
+			}
+			kicSSHTunnel := kic.NewSSHTunnel(ctx, sshPort, sshKey, clientset.CoreV1(), func(services []string) {
+				id.Services = services
+				if err := manager.Register(id); err != nil {
+					klog.Warningf("failed to update tunnel in registry: %v", err)
+				}
+			})
 			err = kicSSHTunnel.Start()
+			if uerr := manager.Unregister(id); uerr != nil {
+				klog.Warningf("failed to remove tunnel from registry: %v", uerr)
+			}
 			if err != nil {
 				exit.Error(reason.SvcTunnelStart, "error starting tunnel", err)
 			}
@@ -95,7 +126,12 @@ var tunnelCmd = &cobra.Command{
 			return
 		}
 
-		done, err := manager.StartTunnel(ctx, cname, co.API, config.DefaultLoader, clientset.CoreV1())
+		hostsFile := ""
+		if tunnelHosts {
+			hostsFile = tunnel.HostsFile()
+		}
+		ingress := tunnel.NewIngressEmulator(cname, clientset.CoreV1(), clientset.NetworkingV1beta1(), hostsFile)
+		done, err := manager.StartTunnel(ctx, cname, co.API, config.DefaultLoader, clientset.CoreV1(), ingress)
 		if err != nil {
 			exit.Error(reason.SvcTunnelStart, "error starting tunnel", err)
 		}
@@ -103,6 +139,42 @@ var tunnelCmd = &cobra.Command{
 	},
 }
 
+// startTunnelInBackground runs the tunnel of a cluster in the background. It returns true in the background process,
+// which should go on to run the tunnel, and false in the process which started it.
+func startTunnelInBackground(manager *tunnel.Manager, cc *config.ClusterConfig) bool {
+	profileArg := ""
+	if cc.Name != constants.DefaultClusterName {
+		profileArg = fmt.Sprintf(" -p %s", cc.Name)
+	}
+	tunnels, err := manager.List()
+	if err != nil {
+		exit.Error(reason.SvcTunnelStart, "error listing tunnels", err)
+	}
+	for _, t := range tunnels {
+		if t.MachineName == cc.Name && t.Pid != os.Getpid() {
+			exit.Message(reason.SvcTunnelStart, "A tunnel is already running for {{.cluster}} with PID {{.pid}}. To stop it, run: minikube tunnel stop{{.profile}}", out.V{"cluster": cc.Name, "pid": t.Pid, "profile": profileArg})
+		}
+	}
+
+	// routes are added with sudo, which cannot prompt for a password in the background
+	if runtime.GOOS != "windows" && !driver.NeedsPortForward(cc.Driver) {
+		if err := exec.Command("sudo", "-n", "true").Run(); err != nil {
+			out.WarningT("The tunnel needs to run sudo without a password to add routes in the background. See https://minikube.sigs.k8s.io/docs/handbook/accessing/#avoiding-password-prompts")
+		}
+	}
+
+	logFile := localpath.TunnelLog(cc.Name)
+	out.Step(style.Running, "Starting tunnel for {{.cluster}} in the background, logging to {{.log}}. To stop it, run: minikube tunnel stop{{.profile}}", out.V{"cluster": cc.Name, "log": logFile, "profile": profileArg})
+	background, err := tunnel.Daemonize(logFile)
+	if err != nil {
+		exit.Error(reason.SvcTunnelStart, "error starting tunnel in the background", err)
+	}
+	return background
+}
+
 func init() {
 	tunnelCmd.Flags().BoolVarP(&cleanup, "cleanup", "c", true, "call with cleanup=true to remove old tunnels")
+	tunnelCmd.Flags().BoolVar(&tunnelBackground, "background", false, "Run the tunnel in the background. Use 'minikube tunnel status' to list the running tunnels, and 'minikube tunnel stop' to stop it.")
+	tunnelCmd.Flags().BoolVar(&tunnelHosts, "hosts", true, "Add the hosts of Ingress resources to the hosts file of the host while the tunnel runs")
+	addMetricsFlag(tunnelCmd)
 }
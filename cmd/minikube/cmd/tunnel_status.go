@@ -0,0 +1,117 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+	"k8s.io/minikube/pkg/minikube/tunnel"
+)
+
+var tunnelOutput string
+
+// tunnelInfo is the status of a running tunnel
+type tunnelInfo struct {
+	Profile   string   `json:"profile"`
+	PID       int      `json:"pid"`
+	Route     string   `json:"route,omitempty"`
+	Services  []string `json:"services"`
+	Ingresses []string `json:"ingresses"`
+}
+
+// tunnelStatusCmd represents the tunnel status command
+var tunnelStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "List the running tunnels",
+	Long:  "List the running tunnels of all clusters, with their routes and the services and ingresses they patched.",
+	Run: func(cmd *cobra.Command, args []string) {
+		validateTunnelOutput()
+		tunnels, err := tunnel.NewManager().List()
+		if err != nil {
+			exit.Error(reason.SvcTunnelStatus, "error listing tunnels", err)
+		}
+		if tunnelOutput == "table" && len(tunnels) == 0 {
+			out.Step(style.Empty, "No tunnels are running. To start one in the background, run: minikube tunnel --background")
+			return
+		}
+		if err := printTunnels(os.Stdout, tunnels); err != nil {
+			exit.Error(reason.SvcTunnelStatus, "error printing tunnels", err)
+		}
+	},
+}
+
+func validateTunnelOutput() {
+	if tunnelOutput != "table" && tunnelOutput != "json" {
+		exit.Message(reason.Usage, "Invalid output format: {{.output}}. Valid values: 'table', 'json'", out.V{"output": tunnelOutput})
+	}
+}
+
+// tunnelInfos returns the status of tunnels
+func tunnelInfos(tunnels []*tunnel.ID) []tunnelInfo {
+	infos := []tunnelInfo{}
+	for _, t := range tunnels {
+		info := tunnelInfo{Profile: t.MachineName, PID: t.Pid, Services: t.Services, Ingresses: t.Ingresses}
+		if t.Route != nil {
+			info.Route = t.Route.String()
+		}
+		if info.Services == nil {
+			info.Services = []string{}
+		}
+		if info.Ingresses == nil {
+			info.Ingresses = []string{}
+		}
+		infos = append(infos, info)
+	}
+	return infos
+}
+
+// printTunnels prints tunnels to w in the format of --output
+func printTunnels(w io.Writer, tunnels []*tunnel.ID) error {
+	infos := tunnelInfos(tunnels)
+	if tunnelOutput == "json" {
+		enc := json.NewEncoder(w)
+		enc.SetEscapeHTML(false)
+		return enc.Encode(infos)
+	}
+
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Profile", "PID", "Route", "Services", "Ingresses"})
+	table.SetAutoFormatHeaders(false)
+	table.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+	table.SetCenterSeparator("|")
+	for _, i := range infos {
+		table.Append([]string{i.Profile, strconv.Itoa(i.PID), i.Route, strings.Join(i.Services, "\n"), strings.Join(i.Ingresses, "\n")})
+	}
+	table.Render()
+	return nil
+}
+
+func init() {
+	tunnelStatusCmd.Flags().StringVarP(&tunnelOutput, "output", "o", "table", "The output format. One of 'json', 'table'")
+	tunnelCmd.AddCommand(tunnelStatusCmd)
+}
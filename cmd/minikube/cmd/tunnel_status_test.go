@@ -0,0 +1,55 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"net"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/tunnel"
+)
+
+func TestPrintTunnels(t *testing.T) {
+	_, cidr, err := net.ParseCIDR("10.96.0.0/12")
+	if err != nil {
+		t.Fatalf("parse cidr: %v", err)
+	}
+	tunnels := []*tunnel.ID{
+		{Route: &tunnel.Route{Gateway: net.ParseIP("192.168.39.2"), DestCIDR: cidr}, MachineName: "minikube", Pid: 1234, Services: []string{"web"}},
+		{MachineName: "p2", Pid: 5678},
+	}
+
+	defer func() { tunnelOutput = "table" }()
+	tunnelOutput = "json"
+	var b bytes.Buffer
+	if err := printTunnels(&b, tunnels); err != nil {
+		t.Fatalf("printTunnels: %v", err)
+	}
+	want := `[{"profile":"minikube","pid":1234,"route":"10.96.0.0/12 -> 192.168.39.2","services":["web"],"ingresses":[]},{"profile":"p2","pid":5678,"services":[],"ingresses":[]}]` + "\n"
+	if b.String() != want {
+		t.Errorf("printTunnels() = %s, want %s", b.String(), want)
+	}
+
+	b.Reset()
+	if err := printTunnels(&b, nil); err != nil {
+		t.Fatalf("printTunnels: %v", err)
+	}
+	if b.String() != "[]\n" {
+		t.Errorf("printTunnels(nil) = %q, want []", b.String())
+	}
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+	"k8s.io/minikube/pkg/minikube/tunnel"
+)
+
+// tunnelStopTimeout is how long a tunnel is given to clean up its routes and services before it is killed
+const tunnelStopTimeout = 30 * time.Second
+
+// tunnelStopCmd represents the tunnel stop command
+var tunnelStopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "Stop the running tunnel of a cluster",
+	Long:  "Stop the running tunnel of a cluster, such as one started with 'minikube tunnel --background'. The tunnel is interrupted, so that it removes its routes and unpatches its services, and is killed if it does not exit within 30 seconds.",
+	Run: func(cmd *cobra.Command, args []string) {
+		validateTunnelOutput()
+		cname := ClusterFlagValue()
+		stopped, err := tunnel.NewManager().StopTunnels(cname, tunnelStopTimeout)
+		if err != nil {
+			exit.Error(reason.SvcTunnelStop, "error stopping tunnel", err)
+		}
+
+		if tunnelOutput == "json" {
+			if err := printTunnels(os.Stdout, stopped); err != nil {
+				exit.Error(reason.SvcTunnelStop, "error printing tunnels", err)
+			}
+			return
+		}
+		if len(stopped) == 0 {
+			out.Step(style.Empty, "No tunnel is running for {{.cluster}}", out.V{"cluster": cname})
+			return
+		}
+		for _, t := range stopped {
+			out.Step(style.Stopped, "Stopped the tunnel of {{.cluster}} with PID {{.pid}}", out.V{"cluster": cname, "pid": t.Pid})
+		}
+	},
+}
+
+func init() {
+	tunnelStopCmd.Flags().StringVarP(&tunnelOutput, "output", "o", "table", "The output format. One of 'json', 'table'")
+	tunnelCmd.AddCommand(tunnelStopCmd)
+}
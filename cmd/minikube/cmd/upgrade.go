@@ -0,0 +1,165 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/blang/semver"
+	"github.com/spf13/cobra"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/exit"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/mustload"
+	"k8s.io/minikube/pkg/minikube/node"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/out/register"
+	"k8s.io/minikube/pkg/minikube/reason"
+	"k8s.io/minikube/pkg/minikube/style"
+	"k8s.io/minikube/pkg/version"
+)
+
+var upgradeVersion string
+
+// upgradeCmd represents the upgrade command
+var upgradeCmd = &cobra.Command{
+	Use:   "upgrade",
+	Short: "Upgrade the Kubernetes version of a running cluster",
+	Long: `Upgrade the Kubernetes version of a running cluster, one minor version at a time.
+
+The control plane is upgraded with 'kubeadm upgrade apply' first. Each worker is then drained, upgraded and uncordoned in turn. If the upgrade fails, the profile records which nodes were upgraded, so that running the same upgrade again upgrades the rest.`,
+	Example: "minikube upgrade --kubernetes-version=v1.20.0",
+	Run: func(cmd *cobra.Command, args []string) {
+		register.SetEventLogPath(localpath.EventLog(ClusterFlagValue()))
+		register.Reg.SetStep(register.Upgrading)
+
+		co := mustload.Healthy(ClusterFlagValue())
+		cc := co.Config
+
+		if upgradeVersion == "" {
+			exit.Message(reason.Usage, "Please specify the Kubernetes version to upgrade to: minikube upgrade --kubernetes-version=<version>")
+		}
+		ovs, nvs, err := upgradeVersions(cc, upgradeVersion)
+		if err != nil {
+			if verr, ok := err.(*versionError); ok {
+				exit.Message(verr.kind, verr.format, verr.args)
+			}
+			exit.Error(reason.Usage, "Invalid Kubernetes version", err)
+		}
+		if nvs.EQ(ovs) {
+			out.Step(style.Ready, "{{.cluster}} is already running Kubernetes {{.version}}", out.V{"cluster": cc.Name, "version": cc.KubernetesConfig.KubernetesVersion})
+			return
+		}
+		nv := version.VersionPrefix + nvs.String()
+
+		// the profile is rolled back to this copy if the upgrade fails
+		orig := *cc
+		orig.Nodes = append([]config.Node{}, cc.Nodes...)
+
+		out.Step(style.Launch, "Upgrading {{.cluster}} from Kubernetes {{.old}} to {{.new}} ...", out.V{"cluster": cc.Name, "old": orig.KubernetesConfig.KubernetesVersion, "new": nv})
+		if err := node.Upgrade(co.API, cc, nv); err != nil {
+			upgraded := upgradedNodes(cc, nv)
+			if len(upgraded) == 0 {
+				if serr := config.SaveProfile(orig.Name, &orig); serr != nil {
+					klog.Errorf("unable to roll back the profile: %v", serr)
+				}
+				out.WarningT("The profile {{.cluster}} was rolled back to Kubernetes {{.old}}. To retry the upgrade, run minikube upgrade again.", out.V{"cluster": cc.Name, "old": orig.KubernetesConfig.KubernetesVersion})
+				exit.Error(reason.KubernetesUpgrade, "Failed to upgrade Kubernetes", err)
+			}
+			// the cluster stays at the old version until every node is upgraded, and each node records its own
+			cc.KubernetesConfig.KubernetesVersion = orig.KubernetesConfig.KubernetesVersion
+			if serr := config.SaveProfile(cc.Name, cc); serr != nil {
+				klog.Errorf("unable to save the upgraded nodes: %v", serr)
+			}
+			out.WarningT("{{.nodes}} of {{.cluster}} were upgraded to Kubernetes {{.new}}. To upgrade the other nodes, run minikube upgrade --kubernetes-version={{.new}} again.", out.V{"nodes": strings.Join(upgraded, ", "), "cluster": cc.Name, "new": nv})
+			exit.Error(reason.KubernetesUpgrade, "Failed to upgrade Kubernetes", err)
+		}
+		if err := config.SaveProfile(cc.Name, cc); err != nil {
+			exit.Error(reason.HostSaveProfile, "failed to save config", err)
+		}
+
+		register.Reg.SetStep(register.Done)
+		out.Step(style.Ready, "Upgraded {{.cluster}} to Kubernetes {{.version}}", out.V{"cluster": cc.Name, "version": nv})
+	},
+}
+
+// versionError is a requested Kubernetes version which a cluster cannot be upgraded to, and the reason to exit with
+type versionError struct {
+	kind   reason.Kind
+	format string
+	args   out.V
+}
+
+func (e *versionError) Error() string {
+	return out.Fmt(e.format, e.args)
+}
+
+// upgradeVersions returns the current Kubernetes version of a cluster, and the requested version, which may be "stable" or "latest", or a *versionError if the cluster cannot be upgraded to it
+func upgradeVersions(cc *config.ClusterConfig, requested string) (semver.Version, semver.Version, error) {
+	if strings.EqualFold(requested, "stable") {
+		requested = constants.DefaultKubernetesVersion
+	} else if strings.EqualFold(requested, "latest") {
+		requested = constants.NewestKubernetesVersion
+	}
+
+	nvs, err := semver.Make(strings.TrimPrefix(requested, version.VersionPrefix))
+	if err != nil {
+		return semver.Version{}, semver.Version{}, &versionError{reason.Usage, `Unable to parse "{{.kubernetes_version}}": {{.error}}`, out.V{"kubernetes_version": requested, "error": err}}
+	}
+	ovs, err := semver.Make(strings.TrimPrefix(cc.KubernetesConfig.KubernetesVersion, version.VersionPrefix))
+	if err != nil {
+		return semver.Version{}, semver.Version{}, &versionError{reason.InternalSemverParse, "Unable to parse the Kubernetes version of the cluster {{.version}}: {{.error}}", out.V{"version": cc.KubernetesConfig.KubernetesVersion, "error": err}}
+	}
+
+	if nvs.LT(ovs) {
+		profileArg := ""
+		if cc.Name != constants.DefaultClusterName {
+			profileArg = fmt.Sprintf(" -p %s", cc.Name)
+		}
+		return ovs, nvs, &versionError{reason.KubernetesDowngrade, "Unable to safely downgrade existing Kubernetes v{{.old}} cluster to v{{.new}}",
+			out.V{"prefix": version.VersionPrefix, "new": nvs, "old": ovs, "profile": profileArg, "suggestedName": cc.Name + "2"}}
+	}
+	// kubeadm only supports upgrades from one minor version to the next
+	if nvs.Major != ovs.Major || nvs.Minor > ovs.Minor+1 {
+		return ovs, nvs, &versionError{reason.Usage, "Kubernetes can only be upgraded one minor version at a time. First upgrade to the latest v{{.major}}.{{.minor}} release.", out.V{"major": ovs.Major, "minor": ovs.Minor + 1}}
+	}
+
+	newest, err := semver.Make(strings.TrimPrefix(constants.NewestKubernetesVersion, version.VersionPrefix))
+	if err == nil && nvs.GT(newest) {
+		out.WarningT("Specified Kubernetes version {{.specified}} is newer than the newest supported version: {{.newest}}", out.V{"specified": nvs, "newest": constants.NewestKubernetesVersion})
+	}
+	return ovs, nvs, nil
+}
+
+// upgradedNodes returns the names of the nodes of a cluster which a failed upgrade upgraded to version
+func upgradedNodes(cc *config.ClusterConfig, version string) []string {
+	var names []string
+	for _, n := range cc.Nodes {
+		if n.KubernetesVersion == version {
+			names = append(names, driver.MachineName(*cc, n))
+		}
+	}
+	return names
+}
+
+func init() {
+	upgradeCmd.Flags().StringVar(&upgradeVersion, kubernetesVersion, "", fmt.Sprintf("The Kubernetes version to upgrade to, at most one minor version newer than the cluster (ex: %s, 'stable' for %s, 'latest' for %s).", constants.NewestKubernetesVersion, constants.DefaultKubernetesVersion, constants.NewestKubernetesVersion))
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/reason"
+)
+
+func TestUpgradeVersions(t *testing.T) {
+	tests := []struct {
+		description string
+		current     string
+		requested   string
+		wantOld     string
+		wantNew     string
+		// wantErr is the reason of the error, if any
+		wantErr *reason.Kind
+	}{
+		{description: "next minor", current: "v1.19.4", requested: "v1.20.0", wantOld: "1.19.4", wantNew: "1.20.0"},
+		{description: "patch", current: "v1.19.4", requested: "1.19.6", wantOld: "1.19.4", wantNew: "1.19.6"},
+		{description: "same", current: "v1.19.4", requested: "v1.19.4", wantOld: "1.19.4", wantNew: "1.19.4"},
+		{description: "stable", current: "v1.19.4", requested: "stable", wantOld: "1.19.4", wantNew: "1.20.0"},
+		{description: "latest", current: "v1.19.4", requested: "Latest", wantOld: "1.19.4", wantNew: "1.20.0"},
+		{description: "downgrade", current: "v1.19.4", requested: "v1.19.2", wantErr: &reason.KubernetesDowngrade},
+		{description: "minor downgrade", current: "v1.20.0", requested: "v1.18.8", wantErr: &reason.KubernetesDowngrade},
+		{description: "two minors", current: "v1.18.8", requested: "v1.20.0", wantErr: &reason.Usage},
+		{description: "stable two minors ahead", current: "v1.18.8", requested: "stable", wantErr: &reason.Usage},
+		{description: "major", current: "v1.20.0", requested: "v2.0.0", wantErr: &reason.Usage},
+		{description: "invalid", current: "v1.20.0", requested: "v1.twenty", wantErr: &reason.Usage},
+		{description: "invalid cluster", current: "one", requested: "v1.20.0", wantErr: &reason.InternalSemverParse},
+	}
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			cc := &config.ClusterConfig{Name: "minikube", KubernetesConfig: config.KubernetesConfig{KubernetesVersion: tc.current}}
+			old, new, err := upgradeVersions(cc, tc.requested)
+			if tc.wantErr != nil {
+				verr, ok := err.(*versionError)
+				if !ok {
+					t.Fatalf("upgradeVersions(%q, %q) error = %v, want a version error", tc.current, tc.requested, err)
+				}
+				if verr.kind.ID != tc.wantErr.ID {
+					t.Errorf("upgradeVersions(%q, %q) reason = %s, want %s", tc.current, tc.requested, verr.kind.ID, tc.wantErr.ID)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("upgradeVersions(%q, %q): %v", tc.current, tc.requested, err)
+			}
+			if old.String() != tc.wantOld || new.String() != tc.wantNew {
+				t.Errorf("upgradeVersions(%q, %q) = %s, %s, want %s, %s", tc.current, tc.requested, old, new, tc.wantOld, tc.wantNew)
+			}
+		})
+	}
+}
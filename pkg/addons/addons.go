@@ -36,6 +36,7 @@ import (
 	"k8s.io/minikube/pkg/minikube/command"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/cruntime"
 	"k8s.io/minikube/pkg/minikube/driver"
 	"k8s.io/minikube/pkg/minikube/exit"
 	"k8s.io/minikube/pkg/minikube/machine"
@@ -189,7 +190,8 @@ https://github.com/kubernetes/minikube/issues/7332`, out.V{"driver_name": cc.Dri
 		return nil
 	}
 
-	if name == "registry" {
+	// clusters started with --registry publish the registry on a fixed host port instead
+	if name == "registry" && !cc.Registry {
 		if driver.NeedsPortForward(cc.Driver) {
 			port, err := oci.ForwardedPort(cc.Driver, cc.Name, constants.RegistryAddonPort)
 			if err != nil {
@@ -225,6 +227,19 @@ func isAddonAlreadySet(cc *config.ClusterConfig, addon *assets.Addon, enable boo
 func enableOrDisableAddonInternal(cc *config.ClusterConfig, addon *assets.Addon, cmd command.Runner, data interface{}, enable bool) error {
 	deployFiles := []string{}
 
+	if enable && len(addon.Images) > 0 {
+		cr, err := cruntime.New(cruntime.Config{Type: cc.KubernetesConfig.ContainerRuntime, Runner: cmd})
+		if err != nil {
+			return errors.Wrap(err, "container runtime")
+		}
+		for _, img := range addon.Images {
+			klog.Infof("pulling %s for addon %s", img, addon.Name())
+			if err := cr.PullImage(img); err != nil {
+				return errors.Wrapf(err, "pulling %s", img)
+			}
+		}
+	}
+
 	for _, addon := range addon.Assets {
 		var f assets.CopyableFile
 		var err error
@@ -373,6 +388,8 @@ func Start(wg *sync.WaitGroup, cc *config.ClusterConfig, toEnable map[string]boo
 		klog.Infof("enableAddons completed in %s", time.Since(start))
 	}()
 
+	LoadExternal()
+
 	// Get the default values of any addons not saved to our config
 	for name, a := range assets.Addons {
 		defaultVal := a.IsEnabled(cc)
@@ -0,0 +1,250 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addons
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/kapi"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/style"
+)
+
+var loadExternalOnce sync.Once
+
+// externalAddonsDir returns the directory external addons are loaded from
+func externalAddonsDir() string {
+	return localpath.MakeMiniPath("addons")
+}
+
+// LoadExternal registers the external addons defined within the minikube home directory (thread-safe)
+func LoadExternal() {
+	loadExternalOnce.Do(func() {
+		root := externalAddonsDir()
+		fis, err := ioutil.ReadDir(root)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				klog.Warningf("unable to read %s: %v", root, err)
+			}
+			return
+		}
+		for _, fi := range fis {
+			dir := filepath.Join(root, fi.Name())
+			if _, err := os.Stat(filepath.Join(dir, assets.AddonManifestFile)); err != nil {
+				continue
+			}
+			a, err := assets.LoadExternalAddon(dir)
+			if err == nil && a.Name() != fi.Name() {
+				err = fmt.Errorf("addon name %q does not match its directory", a.Name())
+			}
+			if err == nil {
+				err = registerExternal(a)
+			}
+			if err != nil {
+				out.WarningT("Skipping external addon in {{.dir}}: {{.error}}", out.V{"dir": dir, "error": err})
+			}
+		}
+	})
+}
+
+// registerExternal makes an external addon available to enable, disable and list
+func registerExternal(a *assets.Addon) error {
+	name := a.Name()
+	if existing, ok := assets.Addons[name]; ok && !existing.IsExternal() {
+		return fmt.Errorf("%s conflicts with a built-in addon", name)
+	}
+	assets.Addons[name] = a
+
+	for _, existing := range Addons {
+		if existing.name == name {
+			return nil
+		}
+	}
+	Addons = append(Addons, &Addon{
+		name:        name,
+		set:         SetBool,
+		validations: []setFn{validateExternalAddon},
+		callbacks:   []setFn{enableOrDisableAddon, verifyExternalAddon},
+	})
+	klog.Infof("registered external addon %s %s from %s", name, a.Manifest.Version, a.Source())
+	return nil
+}
+
+// InstallExternal downloads an external addon from an https or file URL into the minikube home directory and registers it, returning its name
+func InstallExternal(src string) (string, error) {
+	LoadExternal()
+
+	u, err := url.Parse(src)
+	if err != nil {
+		return "", errors.Wrap(err, "parse url")
+	}
+	if u.Scheme != "https" && u.Scheme != "file" {
+		return "", fmt.Errorf("unsupported addon url scheme %q: only https and file are supported", u.Scheme)
+	}
+	if u.Scheme == "file" {
+		if fi, err := os.Stat(filepath.FromSlash(u.Path)); err == nil && fi.IsDir() {
+			u.Path = path.Join(u.Path, assets.AddonManifestFile)
+		}
+	}
+
+	data, err := fetch(u)
+	if err != nil {
+		return "", errors.Wrap(err, "fetching manifest")
+	}
+	m, err := assets.ParseAddonManifest(data)
+	if err != nil {
+		return "", err
+	}
+	if existing, ok := assets.Addons[m.Name]; ok && !existing.IsExternal() {
+		return "", fmt.Errorf("%s conflicts with a built-in addon", m.Name)
+	}
+
+	if err := os.MkdirAll(localpath.MiniPath(), 0755); err != nil {
+		return "", err
+	}
+	tmp, err := ioutil.TempDir(localpath.MiniPath(), "addon-install")
+	if err != nil {
+		return "", errors.Wrap(err, "tempdir")
+	}
+	defer os.RemoveAll(tmp)
+
+	if err := ioutil.WriteFile(filepath.Join(tmp, assets.AddonManifestFile), data, 0644); err != nil {
+		return "", err
+	}
+	for _, f := range m.Files {
+		contents, err := fetch(u.ResolveReference(&url.URL{Path: f}))
+		if err != nil {
+			return "", errors.Wrapf(err, "fetching %s", f)
+		}
+		dst := filepath.Join(tmp, filepath.FromSlash(f))
+		if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+			return "", err
+		}
+		if err := ioutil.WriteFile(dst, contents, 0644); err != nil {
+			return "", err
+		}
+	}
+
+	dir := filepath.Join(externalAddonsDir(), m.Name)
+	if err := os.MkdirAll(externalAddonsDir(), 0755); err != nil {
+		return "", err
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return "", errors.Wrapf(err, "removing previous %s", dir)
+	}
+	if err := os.Rename(tmp, dir); err != nil {
+		return "", errors.Wrap(err, "rename")
+	}
+
+	a, err := assets.LoadExternalAddon(dir)
+	if err != nil {
+		return "", err
+	}
+	out.Step(style.AddonEnable, "Installed external addon {{.name}} {{.version}} from {{.url}}", out.V{"name": m.Name, "version": m.Version, "url": src})
+	return m.Name, registerExternal(a)
+}
+
+// fetch returns the contents of an https or file URL
+func fetch(u *url.URL) ([]byte, error) {
+	if u.Scheme == "file" {
+		return ioutil.ReadFile(filepath.FromSlash(u.Path))
+	}
+
+	client := &http.Client{Timeout: time.Minute}
+	resp, err := client.Get(u.String())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", u, resp.Status)
+	}
+	return ioutil.ReadAll(resp.Body)
+}
+
+// validateExternalAddon checks that the cluster meets the requirements declared by an external addon
+func validateExternalAddon(cc *config.ClusterConfig, name string, val string) error {
+	enable, err := strconv.ParseBool(val)
+	if err != nil {
+		return errors.Wrapf(err, "parsing bool: %s", name)
+	}
+	if !enable {
+		return nil
+	}
+
+	v := assets.Addons[name].Manifest.Validations
+	if len(v.ContainerRuntimes) > 0 && !contains(v.ContainerRuntimes, cc.KubernetesConfig.ContainerRuntime) {
+		return fmt.Errorf("%s requires one of the container runtimes: %s", name, strings.Join(v.ContainerRuntimes, ", "))
+	}
+	if len(v.Drivers) > 0 && !contains(v.Drivers, cc.Driver) {
+		return fmt.Errorf("%s requires one of the drivers: %s", name, strings.Join(v.Drivers, ", "))
+	}
+	if cc.CPUs < v.MinCPUs {
+		return fmt.Errorf("%s needs %d CPUs -- your configuration only allocates %d CPUs", name, v.MinCPUs, cc.CPUs)
+	}
+	if cc.Memory < v.MinMemory {
+		return fmt.Errorf("%s needs %dMB of memory -- your configuration only allocates %dMB", name, v.MinMemory, cc.Memory)
+	}
+	return nil
+}
+
+// verifyExternalAddon waits for the pods named by the health check of an external addon
+func verifyExternalAddon(cc *config.ClusterConfig, name string, val string) error {
+	enable, err := strconv.ParseBool(val)
+	if err != nil {
+		return errors.Wrapf(err, "parsing bool: %s", name)
+	}
+	hc := assets.Addons[name].Manifest.HealthCheck
+	if !enable || hc == nil {
+		return nil
+	}
+
+	timeout, err := hc.WaitTimeout()
+	if err != nil {
+		return err
+	}
+	ns := hc.Namespace
+	if ns == "" {
+		ns = "kube-system"
+	}
+
+	out.Step(style.HealthCheck, "Verifying {{.addon_name}} addon...", out.V{"addon_name": name})
+	client, err := kapi.Client(cc.Name)
+	if err != nil {
+		return errors.Wrapf(err, "get kube-client to validate %s addon", name)
+	}
+	if err := kapi.WaitForPods(client, ns, hc.Label, timeout); err != nil {
+		return errors.Wrapf(err, "waiting for %s pods", hc.Label)
+	}
+	return nil
+}
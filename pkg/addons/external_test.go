@@ -0,0 +1,206 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addons
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/localpath"
+)
+
+const testManifest = `name: hello
+version: 1.0.0
+files:
+  - deploy/hello-dp.yaml.tmpl
+  - hello-svc.yaml
+images:
+  - example.com/hello:1.0
+validations:
+  containerRuntimes: [containerd]
+  minCPUs: 2
+healthCheck:
+  namespace: default
+  label: app=hello
+  timeout: 2m
+`
+
+func writeTestAddon(t *testing.T, manifest string) string {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "external-addon")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	files := map[string]string{
+		assets.AddonManifestFile:    manifest,
+		"deploy/hello-dp.yaml.tmpl": "image: {{.ImageRepository}}hello\narch: {{.Arch}}\n",
+		"hello-svc.yaml":            "kind: Service\n",
+		"deploy/unreferenced.yaml":  "kind: Ignored\n",
+	}
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := ioutil.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	return dir
+}
+
+func TestParseAddonManifest(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		wantErr  bool
+	}{
+		{"valid", testManifest, false},
+		{"invalid name", "name: Hello_World\nfiles: [a.yaml]\n", true},
+		{"no files", "name: hello\n", true},
+		{"escaping file", "name: hello\nfiles: [../a.yaml]\n", true},
+		{"absolute file", "name: hello\nfiles: [/etc/a.yaml]\n", true},
+		{"unknown field", "name: hello\nfiles: [a.yaml]\nfoo: bar\n", true},
+		{"missing health check label", "name: hello\nfiles: [a.yaml]\nhealthCheck:\n  namespace: default\n", true},
+		{"invalid health check timeout", "name: hello\nfiles: [a.yaml]\nhealthCheck:\n  label: app=hello\n  timeout: soon\n", true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := assets.ParseAddonManifest([]byte(tc.manifest))
+			if (err != nil) != tc.wantErr {
+				t.Errorf("ParseAddonManifest() error = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestInstallExternal(t *testing.T) {
+	createTestProfile(t)
+	src := writeTestAddon(t, testManifest)
+
+	name, err := InstallExternal("file://" + filepath.ToSlash(src))
+	if err != nil {
+		t.Fatalf("InstallExternal: %v", err)
+	}
+	if name != "hello" {
+		t.Errorf("name = %q, want %q", name, "hello")
+	}
+
+	dir := filepath.Join(localpath.MiniPath(), "addons", "hello")
+	a, ok := assets.Addons[name]
+	if !ok {
+		t.Fatalf("%s was not registered", name)
+	}
+	if a.Source() != dir {
+		t.Errorf("source = %q, want %q", a.Source(), dir)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "deploy", "unreferenced.yaml")); err == nil {
+		t.Errorf("unreferenced file was installed")
+	}
+	if _, ok := isAddonValid(name); !ok {
+		t.Errorf("%s is not a valid addon", name)
+	}
+
+	var targets []string
+	for _, f := range a.Assets {
+		targets = append(targets, f.GetTargetName())
+	}
+	if got, want := strings.Join(targets, ","), "hello-dp.yaml,hello-svc.yaml"; got != want {
+		t.Errorf("targets = %s, want %s", got, want)
+	}
+
+	f, err := a.Assets[0].Evaluate(assets.GenerateTemplateData(config.KubernetesConfig{ImageRepository: "registry.example.com/"}))
+	if err != nil {
+		t.Fatalf("evaluate: %v", err)
+	}
+	b, err := ioutil.ReadAll(f)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if !strings.Contains(string(b), "image: registry.example.com/hello") {
+		t.Errorf("template was not rendered: %s", b)
+	}
+}
+
+func TestExternalTargetNames(t *testing.T) {
+	dir := writeTestAddon(t, "name: hello\nfiles: [hello-svc.yaml, deploy/hello-svc.yaml, deploy/hello-dp.yaml.tmpl]\n")
+	if err := ioutil.WriteFile(filepath.Join(dir, "deploy", "hello-svc.yaml"), []byte("kind: Service\n"), 0644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	a, err := assets.LoadExternalAddon(dir)
+	if err != nil {
+		t.Fatalf("LoadExternalAddon: %v", err)
+	}
+	var targets []string
+	for _, f := range a.Assets {
+		targets = append(targets, f.GetTargetName())
+	}
+	if got, want := strings.Join(targets, ","), "hello-svc.yaml,hello-2-hello-svc.yaml,hello-dp.yaml"; got != want {
+		t.Errorf("targets = %s, want %s", got, want)
+	}
+}
+
+func TestInstallExternalBuiltinConflict(t *testing.T) {
+	createTestProfile(t)
+	src := writeTestAddon(t, "name: dashboard\nfiles: [hello-svc.yaml]\n")
+
+	if _, err := InstallExternal("file://" + filepath.ToSlash(src)); err == nil {
+		t.Errorf("InstallExternal succeeded for a built-in addon name, want error")
+	}
+	if assets.Addons["dashboard"].IsExternal() {
+		t.Errorf("built-in dashboard addon was replaced")
+	}
+}
+
+func TestValidateExternalAddon(t *testing.T) {
+	src := writeTestAddon(t, testManifest)
+	a, err := assets.LoadExternalAddon(src)
+	if err != nil {
+		t.Fatalf("LoadExternalAddon: %v", err)
+	}
+	if err := registerExternal(a); err != nil {
+		t.Fatalf("registerExternal: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		cc      *config.ClusterConfig
+		val     string
+		wantErr bool
+	}{
+		{"meets requirements", &config.ClusterConfig{CPUs: 2, KubernetesConfig: config.KubernetesConfig{ContainerRuntime: "containerd"}}, "true", false},
+		{"wrong runtime", &config.ClusterConfig{CPUs: 2, KubernetesConfig: config.KubernetesConfig{ContainerRuntime: "docker"}}, "true", true},
+		{"too few cpus", &config.ClusterConfig{CPUs: 1, KubernetesConfig: config.KubernetesConfig{ContainerRuntime: "containerd"}}, "true", true},
+		{"disable", &config.ClusterConfig{CPUs: 1, KubernetesConfig: config.KubernetesConfig{ContainerRuntime: "docker"}}, "false", false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateExternalAddon(tc.cc, "hello", tc.val)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("validateExternalAddon() error = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}
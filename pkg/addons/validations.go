@@ -75,6 +75,7 @@ func IsVolumesnapshotsEnabled(cc *config.ClusterConfig, _, value string) error {
 // isAddonValid returns the addon, true if it is valid
 // otherwise returns nil, false
 func isAddonValid(name string) (*Addon, bool) {
+	LoadExternal()
 	for _, a := range Addons {
 		if a.name == name {
 			return a, true
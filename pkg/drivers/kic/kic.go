@@ -86,15 +86,44 @@ func (d *Driver) Create() error {
 		APIServerPort: d.NodeConfig.APIServerPort,
 	}
 
-	if gateway, err := oci.CreateNetwork(d.OCIBinary, d.NodeConfig.ClusterName); err != nil {
+	networkName := d.NodeConfig.Network
+	if networkName == "" {
+		networkName = d.NodeConfig.ClusterName
+	}
+	// a static IP implies its /24 subnet, unless the subnet is set
+	subnet := d.NodeConfig.Subnet
+	if subnet == "" {
+		subnet = d.NodeConfig.StaticIP
+	}
+	if gateway, err := oci.CreateNetwork(d.OCIBinary, networkName, subnet, d.NodeConfig.IPv6); err != nil {
+		if d.NodeConfig.Network != "" || subnet != "" {
+			return errors.Wrapf(err, "create network %s", networkName)
+		}
 		out.WarningT("Unable to create dedicated network, this might result in cluster IP change after restart: {{.error}}", out.V{"error": err})
 	} else {
-		params.Network = d.NodeConfig.ClusterName
+		params.Network = networkName
 		ip := gateway.To4()
 		// calculate the container IP based on guessing the machine index
-		ip[3] += byte(driver.IndexFromMachineName(d.NodeConfig.MachineName))
+		offset := driver.IndexFromMachineName(d.NodeConfig.MachineName)
+		if static := net.ParseIP(d.NodeConfig.StaticIP).To4(); static != nil {
+			ip = static
+			offset--
+		}
+		ip[3] += byte(offset)
+		// networks named by --network may be shared with other containers, which the calculated IPs of the nodes must
+		// not collide with. The static IP of the primary node was checked by start.
+		if d.NodeConfig.Network != "" && (d.NodeConfig.StaticIP == "" || offset > 0) {
+			free, err := oci.FreeIP(d.OCIBinary, networkName, ip)
+			if err != nil {
+				return errors.Wrapf(err, "free IP of network %s", networkName)
+			}
+			ip = free
+		}
 		klog.Infof("calculated static IP %q for the %q container", ip.String(), d.NodeConfig.MachineName)
 		params.IP = ip.String()
+		if d.NodeConfig.IPv6 {
+			params.IPv6 = oci.IPv6Address(ip).String()
+		}
 	}
 	drv := d.DriverName()
 	listAddr := oci.DefaultBindIPV4
@@ -352,6 +381,12 @@ func (d *Driver) Remove() error {
 	if err := oci.RemoveNetwork(d.OCIBinary, d.NodeConfig.ClusterName); err != nil {
 		klog.Warningf("failed to remove network (which might be okay) %s: %v", d.NodeConfig.ClusterName, err)
 	}
+	// networks named by --network are only removed if minikube created them, and no other container uses them
+	if n := d.NodeConfig.Network; n != "" && n != d.NodeConfig.ClusterName && oci.IsNetworkCreatedByMinikube(d.OCIBinary, n) {
+		if err := oci.RemoveNetwork(d.OCIBinary, n); err != nil {
+			klog.Warningf("failed to remove network (which might be okay) %s: %v", n, err)
+		}
+	}
 	return nil
 }
 
@@ -19,6 +19,7 @@ package oci
 import (
 	"bufio"
 	"bytes"
+	"encoding/binary"
 	"encoding/json"
 	"fmt"
 	"net"
@@ -37,14 +38,19 @@ const firstSubnetAddr = "192.168.49.0"
 // big enough for a cluster of 254 nodes
 const defaultSubnetMask = 24
 
+// the IPv6 subnet paired with the IPv4 subnet of a network, see IPv6Address
+const ipv6SubnetMask = 64
+
 // name of the default bridge network, used to lookup the MTU (see #9528)
 const dockerDefaultBridge = "bridge"
 
 // name of the default bridge network
 const podmanDefaultBridge = "podman"
 
-// CreateNetwork creates a network returns gateway and error, minikube creates one network per cluster
-func CreateNetwork(ociBin string, clusterName string) (net.IP, error) {
+// CreateNetwork creates a network returns gateway and error, minikube creates one network per cluster.
+// The network gets the first free subnet from 192.168.49.0/24, unless a subnet is given, see ParseSubnet.
+// With ipv6, the network also gets the IPv6 subnet paired with its IPv4 subnet, see IPv6Address.
+func CreateNetwork(ociBin string, clusterName string, subnet string, ipv6 bool) (net.IP, error) {
 	var defaultBridgeName string
 	if ociBin == Docker {
 		defaultBridgeName = dockerDefaultBridge
@@ -66,12 +72,22 @@ func CreateNetwork(ociBin string, clusterName string) (net.IP, error) {
 	if err != nil {
 		klog.Warningf("failed to get mtu information from the %s's default network %q: %v", ociBin, defaultBridgeName, err)
 	}
+
+	if subnet != "" {
+		ipNet, err := ParseSubnet(subnet)
+		if err != nil {
+			return nil, err
+		}
+		ones, _ := ipNet.Mask.Size()
+		return tryCreateDockerNetwork(ociBin, ipNet.IP.String(), ones, info.mtu, clusterName, ipv6)
+	}
+
 	attempts := 0
 	subnetAddr := firstSubnetAddr
 	// Rather than iterate through all of the valid subnets, give up at 20 to avoid a lengthy user delay for something that is unlikely to work.
 	// will be like 192.168.49.0/24 ,...,192.168.239.0/24
 	for attempts < 20 {
-		info.gateway, err = tryCreateDockerNetwork(ociBin, subnetAddr, defaultSubnetMask, info.mtu, clusterName)
+		info.gateway, err = tryCreateDockerNetwork(ociBin, subnetAddr, defaultSubnetMask, info.mtu, clusterName, ipv6)
 		if err == nil {
 			return info.gateway, nil
 		}
@@ -94,7 +110,7 @@ func CreateNetwork(ociBin string, clusterName string) (net.IP, error) {
 	return info.gateway, fmt.Errorf("failed to create network after 20 attempts")
 }
 
-func tryCreateDockerNetwork(ociBin string, subnetAddr string, subnetMask int, mtu int, name string) (net.IP, error) {
+func tryCreateDockerNetwork(ociBin string, subnetAddr string, subnetMask int, mtu int, name string, ipv6 bool) (net.IP, error) {
 	gateway := net.ParseIP(subnetAddr)
 	gateway.To4()[3]++ // first ip for gateway
 	klog.Infof("attempt to create network %s/%d with subnet: %s and gateway %s and MTU of %d ...", subnetAddr, subnetMask, name, gateway, mtu)
@@ -105,6 +121,9 @@ func tryCreateDockerNetwork(ociBin string, subnetAddr string, subnetMask int, mt
 		fmt.Sprintf("--subnet=%s", fmt.Sprintf("%s/%d", subnetAddr, subnetMask)),
 		fmt.Sprintf("--gateway=%s", gateway),
 	}
+	if ipv6 {
+		args = append(args, "--ipv6", fmt.Sprintf("--subnet=%s/%d", IPv6Address(net.ParseIP(subnetAddr)), ipv6SubnetMask), fmt.Sprintf("--gateway=%s", IPv6Address(gateway)))
+	}
 	if ociBin == Docker {
 		// options documentation https://docs.docker.com/engine/reference/commandline/network_create/#bridge-driver-options
 		args = append(args, "-o")
@@ -139,12 +158,130 @@ func tryCreateDockerNetwork(ociBin string, subnetAddr string, subnetMask int, mt
 	return gateway, nil
 }
 
+// ParseSubnet parses the subnet of a network, either a CIDR or an IPv4 address, which stands for its /24 network
+func ParseSubnet(subnet string) (*net.IPNet, error) {
+	if !strings.Contains(subnet, "/") {
+		subnet = fmt.Sprintf("%s/%d", subnet, defaultSubnetMask)
+	}
+	ip, ipNet, err := net.ParseCIDR(subnet)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parse subnet %q", subnet)
+	}
+	if ip.To4() == nil {
+		return nil, fmt.Errorf("subnet %s is not an IPv4 subnet", subnet)
+	}
+	// leaves room for the gateway, a node and the broadcast address
+	if ones, _ := ipNet.Mask.Size(); ones > 29 {
+		return nil, fmt.Errorf("subnet %s is too small, its mask must be at most /29", subnet)
+	}
+	return ipNet, nil
+}
+
+// UsableRange returns the first and the last addresses of an IPv4 subnet which nodes may use: the first address
+// after the gateway, and the last one before the broadcast address
+func UsableRange(subnet *net.IPNet) (net.IP, net.IP) {
+	first := AddToIP(subnet.IP.To4(), 2)
+	last := make(net.IP, net.IPv4len)
+	for i, b := range subnet.IP.To4() {
+		last[i] = b | ^subnet.Mask[len(subnet.Mask)-net.IPv4len+i]
+	}
+	return first, AddToIP(last, -1)
+}
+
+// AddToIP returns the IPv4 address n addresses after ip, or before it if n is negative
+func AddToIP(ip net.IP, n int) net.IP {
+	v := binary.BigEndian.Uint32(ip.To4())
+	out := make(net.IP, net.IPv4len)
+	binary.BigEndian.PutUint32(out, uint32(int64(v)+int64(n)))
+	return out
+}
+
+// ValidateNetwork checks that a cluster can be attached to a network, with a subnet and a static IP for its primary node, if set.
+// An existing network must contain them, while the subnet of a network yet to be created must not overlap any existing network.
+func ValidateNetwork(ociBin string, name string, subnet string, staticIP string) error {
+	ip := net.ParseIP(staticIP)
+	info, err := containerNetworkInspect(ociBin, name)
+	if err == nil {
+		if info.subnet == nil {
+			return fmt.Errorf("network %s has no IPv4 subnet", name)
+		}
+		if subnet != "" {
+			ipNet, err := ParseSubnet(subnet)
+			if err != nil {
+				return err
+			}
+			if ipNet.String() != info.subnet.String() {
+				return fmt.Errorf("network %s already exists with subnet %s, not %s", name, info.subnet, ipNet)
+			}
+		}
+		if ip != nil {
+			if !info.subnet.Contains(ip) {
+				return fmt.Errorf("static IP %s is not within the subnet %s of network %s", ip, info.subnet, name)
+			}
+			if ip.Equal(info.gateway) {
+				return fmt.Errorf("static IP %s is the gateway of network %s", ip, name)
+			}
+			for _, used := range info.containerIPs {
+				if ip.Equal(used) {
+					return fmt.Errorf("static IP %s is already used by a container of network %s", ip, name)
+				}
+			}
+		}
+		return nil
+	}
+	if !errors.Is(err, ErrNetworkNotFound) {
+		return errors.Wrapf(err, "inspect network %s", name)
+	}
+
+	if subnet == "" {
+		if ip == nil {
+			// a free subnet will be picked
+			return nil
+		}
+		subnet = staticIP
+	}
+	ipNet, err := ParseSubnet(subnet)
+	if err != nil {
+		return err
+	}
+	if ip != nil && !ipNet.Contains(ip) {
+		return fmt.Errorf("static IP %s is not within the subnet %s", ip, ipNet)
+	}
+	names, err := networkNames(ociBin)
+	if err != nil {
+		return errors.Wrap(err, "list networks")
+	}
+	for _, n := range names {
+		other, err := containerNetworkInspect(ociBin, n)
+		if err != nil || other.subnet == nil {
+			klog.Infof("skipping network %s: %v", n, err)
+			continue
+		}
+		if other.subnet.Contains(ipNet.IP) || ipNet.Contains(other.subnet.IP) {
+			return fmt.Errorf("subnet %s overlaps the subnet %s of network %s", ipNet, other.subnet, n)
+		}
+	}
+	return nil
+}
+
+// IPv6Address returns the IPv6 address paired with an IPv4 address of a network created by minikube, within the
+// fd00::/8 unique local range: 192.168.49.2 is paired with fd00:192:168:49::2
+func IPv6Address(ip net.IP) net.IP {
+	ip4 := ip.To4()
+	if ip4 == nil {
+		return nil
+	}
+	return net.ParseIP(fmt.Sprintf("fd00:%d:%d:%d::%d", ip4[0], ip4[1], ip4[2], ip4[3]))
+}
+
 // netInfo holds part of a docker or podman network information relevant to kic drivers
 type netInfo struct {
 	name    string
 	subnet  *net.IPNet
 	gateway net.IP
 	mtu     int
+	// containerIPs are the IPv4 addresses of the containers attached to the network, only known for docker
+	containerIPs []net.IP
 }
 
 func containerNetworkInspect(ociBin string, name string) (netInfo, error) {
@@ -172,7 +309,7 @@ func dockerNetworkInspect(name string) (netInfo, error) {
 	var vals networkInspect
 	var info = netInfo{name: name}
 
-	cmd := exec.Command(Docker, "network", "inspect", name, "--format", `{"Name": "{{.Name}}","Driver": "{{.Driver}}","Subnet": "{{range .IPAM.Config}}{{.Subnet}}{{end}}","Gateway": "{{range .IPAM.Config}}{{.Gateway}}{{end}}","MTU": {{(index .Options "com.docker.network.driver.mtu")}},{{$first := true}} "ContainerIPs": [{{range $k,$v := .Containers }}{{if $first}}{{$first = false}}{{else}}, {{end}}"{{$v.IPv4Address}}"{{end}}]}`)
+	cmd := exec.Command(Docker, "network", "inspect", name, "--format", `{"Name": "{{.Name}}","Driver": "{{.Driver}}","Subnet": "{{range .IPAM.Config}}{{.Subnet}},{{end}}","Gateway": "{{range .IPAM.Config}}{{.Gateway}},{{end}}","MTU": {{with (index .Options "com.docker.network.driver.mtu")}}{{.}}{{else}}0{{end}},{{$first := true}} "ContainerIPs": [{{range $k,$v := .Containers }}{{if $first}}{{$first = false}}{{else}}, {{end}}"{{$v.IPv4Address}}"{{end}}]}`)
 	rr, err := runCmd(cmd)
 	if err != nil {
 		logDockerNetworkInspect(Docker, name)
@@ -183,15 +320,21 @@ func dockerNetworkInspect(name string) (netInfo, error) {
 		return info, err
 	}
 
-	// results looks like {"Name": "bridge","Driver": "bridge","Subnet": "172.17.0.0/16","Gateway": "172.17.0.1","MTU": 1500, "ContainerIPs": ["172.17.0.3/16", "172.17.0.2/16"]}
+	// results looks like {"Name": "bridge","Driver": "bridge","Subnet": "172.17.0.0/16,","Gateway": "172.17.0.1,","MTU": 1500, "ContainerIPs": ["172.17.0.3/16", "172.17.0.2/16"]}
+	// networks with IPv6 enabled list an IPv6 subnet and gateway too, and networks created without an MTU option report 0
 	if err := json.Unmarshal(rr.Stdout.Bytes(), &vals); err != nil {
 		return info, fmt.Errorf("error parsing network inspect output: %q", rr.Stdout.String())
 	}
 
-	info.gateway = net.ParseIP(vals.Gateway)
+	info.gateway = net.ParseIP(firstIPv4(vals.Gateway))
 	info.mtu = vals.MTU
+	for _, c := range vals.ContainerIPs {
+		if ip, _, err := net.ParseCIDR(c); err == nil {
+			info.containerIPs = append(info.containerIPs, ip)
+		}
+	}
 
-	_, info.subnet, err = net.ParseCIDR(vals.Subnet)
+	_, info.subnet, err = net.ParseCIDR(firstIPv4(vals.Subnet))
 	if err != nil {
 		return info, errors.Wrapf(err, "parse subnet for %s", name)
 	}
@@ -199,6 +342,16 @@ func dockerNetworkInspect(name string) (netInfo, error) {
 	return info, nil
 }
 
+// firstIPv4 returns the first IPv4 address or CIDR of a comma separated list
+func firstIPv4(list string) string {
+	for _, v := range strings.Split(list, ",") {
+		if v != "" && !strings.Contains(v, ":") {
+			return v
+		}
+	}
+	return ""
+}
+
 func podmanNetworkInspect(name string) (netInfo, error) {
 	var info = netInfo{name: name}
 	cmd := exec.Command(Podman, "network", "inspect", name, "--format", `{{range .plugins}}{{if eq .type "bridge"}}{{(index (index .ipam.ranges 0) 0).subnet}},{{(index (index .ipam.ranges 0) 0).gateway}}{{end}}{{end}}`)
@@ -240,6 +393,59 @@ func logDockerNetworkInspect(ociBin string, name string) {
 	klog.Infof("output of %v: %v", rr.Args, rr.Output())
 }
 
+// NetworkAddresses returns the IPv4 subnet of a network, and the IPv4 addresses of the containers attached to it,
+// which are only known for docker
+func NetworkAddresses(ociBin string, name string) (*net.IPNet, []net.IP, error) {
+	info, err := containerNetworkInspect(ociBin, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	if info.subnet == nil {
+		return nil, nil, fmt.Errorf("network %s has no IPv4 subnet", name)
+	}
+	return info.subnet, info.containerIPs, nil
+}
+
+// FreeIP returns the first address from ip onwards, within the subnet of a network, which no container of the
+// network uses yet
+func FreeIP(ociBin string, name string, ip net.IP) (net.IP, error) {
+	subnet, used, err := NetworkAddresses(ociBin, name)
+	if err != nil {
+		return nil, err
+	}
+	return freeIP(subnet, used, ip)
+}
+
+// freeIP returns the first usable address of a subnet from ip onwards which is not used
+func freeIP(subnet *net.IPNet, used []net.IP, ip net.IP) (net.IP, error) {
+	_, last := UsableRange(subnet)
+	for ; subnet.Contains(ip) && bytes.Compare(ip.To4(), last) <= 0; ip = AddToIP(ip, 1) {
+		if !containsIP(used, ip) {
+			return ip, nil
+		}
+	}
+	return nil, fmt.Errorf("no free address left in the subnet %s", subnet)
+}
+
+// containsIP returns whether ips contains ip
+func containsIP(ips []net.IP, ip net.IP) bool {
+	for _, i := range ips {
+		if i.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsNetworkCreatedByMinikube returns whether a network was created by minikube, rather than by the user
+func IsNetworkCreatedByMinikube(ociBin string, name string) bool {
+	rr, err := runCmd(exec.Command(ociBin, "network", "inspect", name, "--format", "{{.Labels}}"))
+	if err != nil {
+		return false
+	}
+	return strings.Contains(rr.Stdout.String(), fmt.Sprintf("%s:true", CreatedByLabelKey))
+}
+
 // RemoveNetwork removes a network
 func RemoveNetwork(ociBin string, name string) error {
 	if !networkExists(ociBin, name) {
@@ -267,6 +473,22 @@ func networkExists(ociBin string, name string) bool {
 	return err == nil
 }
 
+// networkNames returns the names of all networks
+func networkNames(ociBin string) ([]string, error) {
+	rr, err := runCmd(exec.Command(ociBin, "network", "ls", "--format", "{{.Name}}"))
+	if err != nil {
+		return nil, err
+	}
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(rr.Stdout.Bytes()))
+	for scanner.Scan() {
+		if name := strings.TrimSpace(scanner.Text()); name != "" {
+			lines = append(lines, name)
+		}
+	}
+	return lines, nil
+}
+
 // networkNamesByLabel returns all network names created by a label
 func networkNamesByLabel(ociBin string, label string) ([]string, error) {
 	// docker network ls --filter='label=created_by.minikube.sigs.k8s.io=true' --format '{{.Name}}'
@@ -0,0 +1,132 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"net"
+	"testing"
+)
+
+func TestIPv6Address(t *testing.T) {
+	tests := []struct {
+		ip       string
+		expected string
+	}{
+		{"192.168.49.1", "fd00:192:168:49::1"},
+		{"192.168.58.254", "fd00:192:168:58::254"},
+		{"fd00::1", "<nil>"},
+	}
+	for _, tc := range tests {
+		if got := IPv6Address(net.ParseIP(tc.ip)).String(); got != tc.expected {
+			t.Errorf("IPv6Address(%s) = %s, expected %s", tc.ip, got, tc.expected)
+		}
+	}
+}
+
+func TestFirstIPv4(t *testing.T) {
+	tests := []struct {
+		list     string
+		expected string
+	}{
+		{"192.168.49.0/24,", "192.168.49.0/24"},
+		{"fd00:192:168:49::/64,192.168.49.0/24,", "192.168.49.0/24"},
+		{"fd00:192:168:49::1,", ""},
+	}
+	for _, tc := range tests {
+		if got := firstIPv4(tc.list); got != tc.expected {
+			t.Errorf("firstIPv4(%q) = %q, expected %q", tc.list, got, tc.expected)
+		}
+	}
+}
+
+func TestParseSubnet(t *testing.T) {
+	tests := []struct {
+		subnet    string
+		expected  string
+		shouldErr bool
+	}{
+		{"192.168.60.0/24", "192.168.60.0/24", false},
+		{"192.168.60.5", "192.168.60.0/24", false},
+		{"10.10.0.0/16", "10.10.0.0/16", false},
+		{"10.10.0.0/30", "", true},
+		{"fd00::/64", "", true},
+		{"192.168.300.0", "", true},
+	}
+	for _, tc := range tests {
+		got, err := ParseSubnet(tc.subnet)
+		if err != nil && !tc.shouldErr {
+			t.Errorf("ParseSubnet(%q) returned unexpected error: %v", tc.subnet, err)
+		}
+		if err == nil && tc.shouldErr {
+			t.Errorf("ParseSubnet(%q) should have returned an error", tc.subnet)
+		}
+		if err == nil && got.String() != tc.expected {
+			t.Errorf("ParseSubnet(%q) = %s, expected %s", tc.subnet, got, tc.expected)
+		}
+	}
+}
+
+func TestUsableRange(t *testing.T) {
+	tests := []struct {
+		subnet string
+		first  string
+		last   string
+	}{
+		{"192.168.49.0/24", "192.168.49.2", "192.168.49.254"},
+		{"192.168.49.16/28", "192.168.49.18", "192.168.49.30"},
+		{"10.10.0.0/16", "10.10.0.2", "10.10.255.254"},
+	}
+	for _, tc := range tests {
+		_, ipNet, err := net.ParseCIDR(tc.subnet)
+		if err != nil {
+			t.Fatalf("parse %s: %v", tc.subnet, err)
+		}
+		first, last := UsableRange(ipNet)
+		if first.String() != tc.first || last.String() != tc.last {
+			t.Errorf("UsableRange(%s) = %s, %s, expected %s, %s", tc.subnet, first, last, tc.first, tc.last)
+		}
+	}
+}
+
+func TestFreeIP(t *testing.T) {
+	_, subnet, _ := net.ParseCIDR("192.168.49.0/29")
+	used := []net.IP{net.ParseIP("192.168.49.3"), net.ParseIP("192.168.49.4")}
+	tests := []struct {
+		from      string
+		expected  string
+		shouldErr bool
+	}{
+		{"192.168.49.2", "192.168.49.2", false},
+		{"192.168.49.3", "192.168.49.5", false},
+		{"192.168.49.6", "192.168.49.6", false},
+		// the broadcast address is not usable
+		{"192.168.49.7", "", true},
+		{"192.168.50.2", "", true},
+	}
+	for _, tc := range tests {
+		got, err := freeIP(subnet, used, net.ParseIP(tc.from))
+		if err != nil && !tc.shouldErr {
+			t.Errorf("freeIP(%s) returned unexpected error: %v", tc.from, err)
+		}
+		if err == nil && tc.shouldErr {
+			t.Errorf("freeIP(%s) should have returned an error", tc.from)
+		}
+		if err == nil && got.String() != tc.expected {
+			t.Errorf("freeIP(%s) = %s, expected %s", tc.from, got, tc.expected)
+		}
+	}
+}
@@ -150,6 +150,11 @@ func CreateContainerNode(p CreateParams) error {
 	if p.Network != "" && p.IP != "" {
 		runArgs = append(runArgs, "--network", p.Network)
 		runArgs = append(runArgs, "--ip", p.IP)
+		if p.IPv6 != "" {
+			runArgs = append(runArgs, "--ip6", p.IPv6)
+			// docker disables IPv6 within containers unless the network is IPv6 enabled, and the node routes pod traffic
+			runArgs = append(runArgs, "--sysctl", "net.ipv6.conf.all.disable_ipv6=0", "--sysctl", "net.ipv6.conf.all.forwarding=1")
+		}
 	}
 
 	memcgSwap := true
@@ -59,6 +59,7 @@ type CreateParams struct {
 	OCIBinary     string            // docker or podman
 	Network       string            // network name that the container will attach to
 	IP            string            // static IP to assign for th container in the cluster network
+	IPv6          string            // static IPv6 address to assign to the container in the cluster network, if IPv6 is enabled
 }
 
 // createOpt is an option for Create
@@ -62,4 +62,8 @@ type Config struct {
 	KubernetesVersion string            // Kubernetes version to install
 	ContainerRuntime  string            // container runtime kic is running
 	ExtraArgs         []string          // a list of any extra option to pass to oci binary during creation time, for example --expose 8080...
+	IPv6              bool              // enable IPv6 on the network of the cluster, for IPv6 and dual-stack clusters
+	Network           string            // network to attach the container to, which defaults to a network named after the cluster
+	Subnet            string            // subnet of the network, if minikube creates it
+	StaticIP          string            // static IP of the primary node, the other nodes get the following IPs
 }
@@ -30,6 +30,11 @@ type Addon struct {
 	Assets    []*BinAsset
 	enabled   bool
 	addonName string
+	// Images are pulled onto the node before the addon is applied
+	Images []string
+	// Manifest is set for external addons, which are loaded at runtime
+	Manifest *AddonManifest
+	source   string
 }
 
 // NewAddon creates a new Addon
@@ -47,6 +52,19 @@ func (a *Addon) Name() string {
 	return a.addonName
 }
 
+// Source returns where the addon was loaded from
+func (a *Addon) Source() string {
+	if a.source == "" {
+		return BuiltinAddonSource
+	}
+	return a.source
+}
+
+// IsExternal returns if the addon was loaded at runtime rather than compiled into minikube
+func (a *Addon) IsExternal() bool {
+	return a.Manifest != nil
+}
+
 // IsEnabled checks if an Addon is enabled for the given profile
 func (a *Addon) IsEnabled(cc *config.ClusterConfig) bool {
 	status, ok := cc.Addons[a.Name()]
@@ -0,0 +1,157 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assets
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+
+	"k8s.io/minikube/pkg/minikube/vmpath"
+)
+
+const (
+	// AddonManifestFile is the name of the file describing an external addon
+	AddonManifestFile = "addon.yaml"
+	// BuiltinAddonSource is the source of addons compiled into minikube
+	BuiltinAddonSource = "builtin"
+)
+
+// validAddonName matches the names which may be used for external addons
+var validAddonName = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// AddonManifest describes an addon which is loaded at runtime rather than compiled into minikube
+type AddonManifest struct {
+	Name    string `yaml:"name"`
+	Version string `yaml:"version"`
+	// Files are paths relative to the manifest, rendered with GenerateTemplateData before being applied
+	Files       []string          `yaml:"files"`
+	Images      []string          `yaml:"images,omitempty"`
+	Validations AddonValidations  `yaml:"validations,omitempty"`
+	HealthCheck *AddonHealthCheck `yaml:"healthCheck,omitempty"`
+}
+
+// AddonValidations are the cluster requirements of an external addon
+type AddonValidations struct {
+	ContainerRuntimes []string `yaml:"containerRuntimes,omitempty"`
+	Drivers           []string `yaml:"drivers,omitempty"`
+	MinCPUs           int      `yaml:"minCPUs,omitempty"`
+	// MinMemory is in MB
+	MinMemory int `yaml:"minMemory,omitempty"`
+}
+
+// AddonHealthCheck describes the pods to wait for once an external addon is enabled
+type AddonHealthCheck struct {
+	Namespace string `yaml:"namespace"`
+	Label     string `yaml:"label"`
+	Timeout   string `yaml:"timeout,omitempty"`
+}
+
+// ParseAddonManifest parses and validates an external addon manifest
+func ParseAddonManifest(data []byte) (*AddonManifest, error) {
+	m := &AddonManifest{}
+	if err := yaml.UnmarshalStrict(data, m); err != nil {
+		return nil, errors.Wrap(err, "unmarshal")
+	}
+	if !validAddonName.MatchString(m.Name) {
+		return nil, fmt.Errorf("invalid addon name %q: must consist of lower case alphanumeric characters or '-'", m.Name)
+	}
+	if len(m.Files) == 0 {
+		return nil, fmt.Errorf("addon %s does not list any files", m.Name)
+	}
+	for _, f := range m.Files {
+		if c := path.Clean(f); path.IsAbs(c) || c == ".." || strings.HasPrefix(c, "../") {
+			return nil, fmt.Errorf("addon %s file %q must be relative to the manifest", m.Name, f)
+		}
+	}
+	if hc := m.HealthCheck; hc != nil {
+		if hc.Label == "" {
+			return nil, fmt.Errorf("addon %s health check requires a label", m.Name)
+		}
+		if _, err := hc.WaitTimeout(); err != nil {
+			return nil, errors.Wrapf(err, "addon %s health check timeout", m.Name)
+		}
+	}
+	return m, nil
+}
+
+// WaitTimeout returns how long to wait for the health check to pass
+func (hc *AddonHealthCheck) WaitTimeout() (time.Duration, error) {
+	if hc.Timeout == "" {
+		return 6 * time.Minute, nil
+	}
+	return time.ParseDuration(hc.Timeout)
+}
+
+// NewExternalAddon creates a disabled Addon from a manifest, reading its files relative to dir
+func NewExternalAddon(m *AddonManifest, dir string) (*Addon, error) {
+	var as []*BinAsset
+	names := map[string]bool{}
+	for _, f := range m.Files {
+		src := filepath.Join(dir, filepath.FromSlash(f))
+		contents, err := ioutil.ReadFile(src)
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading %s", f)
+		}
+		// files of different directories may share a base name, which would overwrite each other in the guest
+		name := externalTargetName(m.Name, f)
+		for n := 2; names[name]; n++ {
+			name = externalTargetName(m.Name, fmt.Sprintf("%d-%s", n, path.Base(f)))
+		}
+		names[name] = true
+		a, err := newBinAssetFromBytes(contents, src, vmpath.GuestAddonsDir, name, "0640")
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing %s", f)
+		}
+		as = append(as, a)
+	}
+
+	a := NewAddon(as, false, m.Name)
+	a.Images = m.Images
+	a.Manifest = m
+	a.source = dir
+	return a, nil
+}
+
+// LoadExternalAddon loads the external addon defined within dir
+func LoadExternalAddon(dir string) (*Addon, error) {
+	data, err := ioutil.ReadFile(filepath.Join(dir, AddonManifestFile))
+	if err != nil {
+		return nil, err
+	}
+	m, err := ParseAddonManifest(data)
+	if err != nil {
+		return nil, err
+	}
+	return NewExternalAddon(m, dir)
+}
+
+// externalTargetName returns the name an external addon file is installed as, prefixed to avoid collisions within the guest addons directory
+func externalTargetName(addon string, file string) string {
+	name := strings.TrimSuffix(path.Base(file), ".tmpl")
+	if strings.HasPrefix(name, addon+"-") {
+		return name
+	}
+	return fmt.Sprintf("%s-%s", addon, name)
+}
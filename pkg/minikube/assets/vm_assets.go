@@ -84,6 +84,7 @@ func (b *BaseAsset) GetModTime() (time.Time, error) {
 type FileAsset struct {
 	BaseAsset
 	reader io.ReadSeeker
+	file   *os.File
 }
 
 // NewMemoryAssetTarget creates a new MemoryAsset, with target
@@ -117,6 +118,7 @@ func NewFileAsset(src, targetDir, targetName, permissions string) (*FileAsset, e
 			Permissions: permissions,
 		},
 		reader: io.NewSectionReader(f, 0, info.Size()),
+		file:   f,
 	}, nil
 }
 
@@ -153,6 +155,14 @@ func (f *FileAsset) Seek(offset int64, whence int) (int64, error) {
 	return f.reader.Seek(offset, whence)
 }
 
+// Close closes the file of the asset
+func (f *FileAsset) Close() error {
+	if f.file == nil {
+		return nil
+	}
+	return f.file.Close()
+}
+
 // MemoryAsset is a memory-based asset
 type MemoryAsset struct {
 	BaseAsset
@@ -221,6 +231,20 @@ func NewBinAsset(name, targetDir, targetName, permissions string) (*BinAsset, er
 	return m, err
 }
 
+// newBinAssetFromBytes creates a new BinAsset from contents which were not compiled into minikube
+func newBinAssetFromBytes(contents []byte, name, targetDir, targetName, permissions string) (*BinAsset, error) {
+	m := &BinAsset{
+		BaseAsset: BaseAsset{
+			SourcePath:  name,
+			TargetDir:   targetDir,
+			TargetName:  targetName,
+			Permissions: permissions,
+		},
+	}
+	err := m.setData(contents)
+	return m, err
+}
+
 func defaultValue(defValue string, val interface{}) string {
 	if val == nil {
 		return defValue
@@ -237,7 +261,11 @@ func (m *BinAsset) loadData() error {
 	if err != nil {
 		return err
 	}
+	return m.setData(contents)
+}
 
+// setData parses contents as the template and data of the asset
+func (m *BinAsset) setData(contents []byte) error {
 	tpl, err := template.New(m.SourcePath).Funcs(template.FuncMap{"default": defaultValue}).Parse(string(contents))
 	if err != nil {
 		return err
@@ -31,6 +31,8 @@ type LogOptions struct {
 	Lines int
 	// Follow is whether or not to actively follow the logs, as in tail -f.
 	Follow bool
+	// Since is how far back to include logs from, if set.
+	Since time.Duration
 }
 
 // Bootstrapper contains all the methods needed to bootstrap a Kubernetes cluster
@@ -41,7 +43,8 @@ type Bootstrapper interface {
 	WaitForNode(config.ClusterConfig, config.Node, time.Duration) error
 	JoinCluster(config.ClusterConfig, config.Node, string) error
 	UpdateNode(config.ClusterConfig, config.Node, cruntime.Manager) error
-	GenerateToken(config.ClusterConfig) (string, error)
+	UpgradeNode(config.ClusterConfig, config.Node, cruntime.Manager) error
+	GenerateToken(config.ClusterConfig, config.Node) (string, error)
 	// LogCommands returns a map of log type to a command which will display that log.
 	LogCommands(config.ClusterConfig, LogOptions) map[string]string
 	SetupCerts(config.KubernetesConfig, config.Node) error
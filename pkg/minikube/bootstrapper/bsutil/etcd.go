@@ -0,0 +1,104 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bsutil
+
+import (
+	"fmt"
+	"net"
+	"os/exec"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/kapi"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/vmpath"
+)
+
+// etcdctl returns a command which runs etcdctl within the etcd pod of the control plane cp, using the runner of cp
+func etcdctl(cc config.ClusterConfig, cp config.Node, args ...string) *exec.Cmd {
+	certs := path.Join(vmpath.GuestKubernetesCertsDir, "etcd")
+	kubectl := kapi.KubectlBinaryPath(cc.KubernetesConfig.KubernetesVersion)
+	cmd := []string{
+		"KUBECONFIG=" + path.Join(vmpath.GuestPersistentDir, "kubeconfig"), kubectl,
+		"-n", "kube-system", "exec", "etcd-" + KubeNodeName(cc, cp), "--",
+		"etcdctl", "--endpoints=https://127.0.0.1:2379",
+		"--cacert=" + path.Join(certs, "ca.crt"),
+		"--cert=" + path.Join(certs, "server.crt"),
+		"--key=" + path.Join(certs, "server.key"),
+	}
+	return exec.Command("sudo", append(cmd, args...)...)
+}
+
+// RemoveEtcdMember removes the stacked etcd member of node n, if present, using the etcd of control plane cp
+func RemoveEtcdMember(r command.Runner, cc config.ClusterConfig, cp config.Node, n config.Node) error {
+	rr, err := r.RunCmd(etcdctl(cc, cp, "member", "list"))
+	if err != nil {
+		return errors.Wrap(err, "etcd member list")
+	}
+
+	name := KubeNodeName(cc, n)
+	id, ok := etcdMemberID(rr.Stdout.String(), name)
+	if !ok {
+		klog.Infof("%s is not an etcd member", name)
+		return nil
+	}
+
+	klog.Infof("removing etcd member %s (%s)", name, id)
+	if _, err := r.RunCmd(etcdctl(cc, cp, "member", "remove", id)); err != nil {
+		return errors.Wrapf(err, "etcd member remove %s", name)
+	}
+	return nil
+}
+
+// UpdateEtcdMemberPeerURL points the stacked etcd member of node n at its current IP, using the etcd of control plane cp
+func UpdateEtcdMemberPeerURL(r command.Runner, cc config.ClusterConfig, cp config.Node, n config.Node) error {
+	rr, err := r.RunCmd(etcdctl(cc, cp, "member", "list"))
+	if err != nil {
+		return errors.Wrap(err, "etcd member list")
+	}
+
+	name := KubeNodeName(cc, n)
+	id, ok := etcdMemberID(rr.Stdout.String(), name)
+	if !ok {
+		return fmt.Errorf("%s is not an etcd member", name)
+	}
+
+	peerURL := fmt.Sprintf("--peer-urls=https://%s", net.JoinHostPort(n.IP, "2380"))
+	klog.Infof("updating etcd member %s (%s): %s", name, id, peerURL)
+	if _, err := r.RunCmd(etcdctl(cc, cp, "member", "update", id, peerURL)); err != nil {
+		return errors.Wrapf(err, "etcd member update %s", name)
+	}
+	return nil
+}
+
+// etcdMemberID finds the ID of the named member within the output of `etcdctl member list`
+func etcdMemberID(out string, name string) (string, bool) {
+	// 8e9e05c52164694d, started, minikube, https://192.168.49.2:2380, https://192.168.49.2:2379, false
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Split(line, ",")
+		if len(fields) < 3 {
+			continue
+		}
+		if strings.TrimSpace(fields[2]) == name {
+			return strings.TrimSpace(fields[0]), true
+		}
+	}
+	return "", false
+}
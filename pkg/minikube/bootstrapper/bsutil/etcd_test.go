@@ -0,0 +1,43 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package bsutil
+
+import (
+	"testing"
+)
+
+func TestEtcdMemberID(t *testing.T) {
+	out := `8e9e05c52164694d, started, minikube, https://192.168.49.2:2380, https://192.168.49.2:2379, false
+2f1a7c7f8e2f0c61, started, minikube-m02, https://192.168.49.3:2380, https://192.168.49.3:2379, false
+`
+	tests := []struct {
+		name   string
+		wantID string
+		wantOK bool
+	}{
+		{"minikube", "8e9e05c52164694d", true},
+		{"minikube-m02", "2f1a7c7f8e2f0c61", true},
+		{"minikube-m03", "", false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			id, ok := etcdMemberID(out, tc.name)
+			if id != tc.wantID || ok != tc.wantOK {
+				t.Errorf("etcdMemberID() = %q, %v, want %q, %v", id, ok, tc.wantID, tc.wantOK)
+			}
+		})
+	}
+}
@@ -22,10 +22,16 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/blang/semver"
 	"github.com/pkg/errors"
 	"k8s.io/kubernetes/cmd/kubeadm/app/features"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
 )
 
+// dualStackFeatureGate enables dual-stack networking, which is enabled by default from Kubernetes v1.21
+const dualStackFeatureGate = "IPv6DualStack"
+
 // supportedFG indicates whether a feature name is supported by the bootstrapper
 func supportedFG(featureName string) bool {
 	for k := range features.InitFeatureGates {
@@ -67,3 +73,17 @@ func parseFeatureArgs(featureGates string) (map[string]bool, string, error) {
 	componentFeatureArgs = strings.TrimRight(componentFeatureArgs, ",")
 	return kubeadmFeatureArgs, componentFeatureArgs, nil
 }
+
+// needsDualStackFeatureGate returns true if a cluster is dual-stack, and its Kubernetes version requires the dual-stack feature gate to be enabled
+func needsDualStackFeatureGate(k8s config.KubernetesConfig, version semver.Version) bool {
+	if k8s.IPFamily != constants.DualStackFamily || version.GTE(semver.MustParse("1.21.0-alpha.0")) {
+		return false
+	}
+	// an explicitly set feature gate takes precedence
+	for _, s := range strings.Split(k8s.FeatureGates, ",") {
+		if strings.TrimSpace(strings.SplitN(s, "=", 2)[0]) == dualStackFeatureGate {
+			return false
+		}
+	}
+	return true
+}
@@ -20,6 +20,10 @@ package bsutil
 import (
 	"reflect"
 	"testing"
+
+	"github.com/blang/semver"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
 )
 
 func TestParseFeatureArgs(t *testing.T) {
@@ -92,3 +96,26 @@ func TestSupport(t *testing.T) {
 	}
 
 }
+
+func TestNeedsDualStackFeatureGate(t *testing.T) {
+	tests := []struct {
+		description  string
+		family       string
+		featureGates string
+		version      string
+		expected     bool
+	}{
+		{"ipv4", constants.IPv4Family, "", "1.20.0", false},
+		{"dual-stack", constants.DualStackFamily, "", "1.20.0", true},
+		{"dual-stack enabled by default", constants.DualStackFamily, "", "1.21.0", false},
+		{"explicitly set", constants.DualStackFamily, "a=b,IPv6DualStack=false", "1.20.0", false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			k8s := config.KubernetesConfig{IPFamily: tc.family, FeatureGates: tc.featureGates}
+			if got := needsDualStackFeatureGate(k8s, semver.MustParse(tc.version)); got != tc.expected {
+				t.Errorf("needsDualStackFeatureGate() = %t, expected %t", got, tc.expected)
+			}
+		})
+	}
+}
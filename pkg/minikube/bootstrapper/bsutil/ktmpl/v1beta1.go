@@ -53,7 +53,7 @@ kind: ClusterConfiguration
 {{- end}}
 {{end -}}
 {{if .FeatureArgs}}featureGates:
-{{range $i, $val := .FeatureArgs}}{{$i}}: {{$val}}
+{{range $i, $val := .FeatureArgs}}  {{$i}}: {{$val}}
 {{end -}}{{end -}}
 certificatesDir: {{.CertDir}}
 clusterName: {{.ClusterName}}
@@ -90,7 +90,7 @@ staticPodPath: {{.StaticPodPath}}
 apiVersion: kubeproxy.config.k8s.io/v1alpha1
 kind: KubeProxyConfiguration
 clusterCIDR: "{{.PodSubnet }}"
-metricsBindAddress: {{.AdvertiseAddress}}:10249
+metricsBindAddress: {{.MetricsBindAddress}}
 {{- range $i, $val := printMapInOrder .KubeProxyOptions ": " }}
 {{$val}}
 {{- end}}
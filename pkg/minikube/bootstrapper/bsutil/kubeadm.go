@@ -20,6 +20,7 @@ package bsutil
 import (
 	"bytes"
 	"fmt"
+	"net"
 	"path"
 
 	"github.com/blang/semver"
@@ -50,6 +51,10 @@ func GenerateKubeadmYAML(cc config.ClusterConfig, n config.Node, r cruntime.Mana
 	if err != nil {
 		return nil, errors.Wrap(err, "parses feature gate config for kubeadm and component")
 	}
+	// kubeadm passes the feature gate on to the control plane components and kube-proxy
+	if needsDualStackFeatureGate(k8s, version) {
+		kubeadmFeatureArgs[dualStackFeatureGate] = true
+	}
 
 	// In case of no port assigned, use default
 	cp, err := config.PrimaryControlPlane(&cc)
@@ -66,7 +71,12 @@ func GenerateKubeadmYAML(cc config.ClusterConfig, n config.Node, r cruntime.Mana
 		return nil, errors.Wrap(err, "getting cgroup driver")
 	}
 
-	componentOpts, err := createExtraComponentConfig(k8s.ExtraOptions, version, componentFeatureArgs, cp)
+	// the apiserver must also be reachable through the virtual IP of HA clusters
+	sans := []string{"127.0.0.1", "localhost", cp.IP}
+	if k8s.APIServerHAVIP != "" {
+		sans = append(sans, k8s.APIServerHAVIP)
+	}
+	componentOpts, err := createExtraComponentConfig(k8s.ExtraOptions, version, componentFeatureArgs, sans)
 	if err != nil {
 		return nil, errors.Wrap(err, "generating extra component config for kubeadm")
 	}
@@ -76,7 +86,7 @@ func GenerateKubeadmYAML(cc config.ClusterConfig, n config.Node, r cruntime.Mana
 		return nil, errors.Wrap(err, "cni")
 	}
 
-	podCIDR := cnm.CIDR()
+	podCIDR := cni.FamilyCIDR(cc, cnm.CIDR())
 	overrideCIDR := k8s.ExtraOptions.Get("pod-network-cidr", Kubeadm)
 	if overrideCIDR != "" {
 		podCIDR = overrideCIDR
@@ -101,6 +111,7 @@ func GenerateKubeadmYAML(cc config.ClusterConfig, n config.Node, r cruntime.Mana
 		FeatureArgs         map[string]bool
 		NoTaintMaster       bool
 		NodeIP              string
+		MetricsBindAddress  string
 		CgroupDriver        string
 		ClientCAFile        string
 		StaticPodPath       string
@@ -108,7 +119,7 @@ func GenerateKubeadmYAML(cc config.ClusterConfig, n config.Node, r cruntime.Mana
 		KubeProxyOptions    map[string]string
 	}{
 		CertDir:           vmpath.GuestKubernetesCertsDir,
-		ServiceCIDR:       constants.DefaultServiceCIDR,
+		ServiceCIDR:       defaultServiceCIDR(k8s.IPFamily),
 		PodSubnet:         podCIDR,
 		AdvertiseAddress:  n.IP,
 		APIServerPort:     nodePort,
@@ -125,6 +136,7 @@ func GenerateKubeadmYAML(cc config.ClusterConfig, n config.Node, r cruntime.Mana
 		NoTaintMaster:       false, // That does not work with k8s 1.12+
 		DNSDomain:           k8s.DNSDomain,
 		NodeIP:              n.IP,
+		MetricsBindAddress:  net.JoinHostPort(n.IP, "10249"),
 		CgroupDriver:        cgroupDriver,
 		ClientCAFile:        path.Join(vmpath.GuestKubernetesCertsDir, "ca.crt"),
 		StaticPodPath:       vmpath.GuestManifestsDir,
@@ -155,6 +167,17 @@ func GenerateKubeadmYAML(cc config.ClusterConfig, n config.Node, r cruntime.Mana
 	return b.Bytes(), nil
 }
 
+// defaultServiceCIDR returns the default service CIDR of an IP family
+func defaultServiceCIDR(family string) string {
+	switch family {
+	case constants.IPv6Family:
+		return constants.DefaultServiceCIDRv6
+	case constants.DualStackFamily:
+		return constants.DefaultServiceCIDR + "," + constants.DefaultServiceCIDRv6
+	}
+	return constants.DefaultServiceCIDR
+}
+
 // These are the components that can be configured
 // through the "extra-config"
 const (
@@ -237,6 +237,7 @@ func TestGenerateKubeadmYAML(t *testing.T) {
 		{"containerd-api-port", "containerd", false, config.ClusterConfig{Name: "mk", Nodes: []config.Node{{Port: 12345}}}},
 		{"containerd-pod-network-cidr", "containerd", false, config.ClusterConfig{Name: "mk", KubernetesConfig: config.KubernetesConfig{ExtraOptions: extraOptsPodCidr}}},
 		{"image-repository", "docker", false, config.ClusterConfig{Name: "mk", KubernetesConfig: config.KubernetesConfig{ImageRepository: "test/repo"}}},
+		{"dual-stack", "docker", false, config.ClusterConfig{Name: "mk", KubernetesConfig: config.KubernetesConfig{IPFamily: constants.DualStackFamily}}},
 	}
 	for _, version := range versions {
 		for _, tc := range tests {
@@ -21,6 +21,8 @@ import (
 	"bytes"
 	"os"
 	"path"
+	"sort"
+	"strings"
 
 	"github.com/pkg/errors"
 	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil/ktmpl"
@@ -63,6 +65,19 @@ func extraKubeletOpts(mc config.ClusterConfig, nc config.Node, r cruntime.Manage
 		extraOpts["hostname-override"] = nodeName
 	}
 
+	// per-node labels and taints are applied by the kubelet when it registers the node
+	if _, ok := extraOpts["node-labels"]; !ok && len(nc.Labels) > 0 {
+		labels := []string{}
+		for k, v := range nc.Labels {
+			labels = append(labels, k+"="+v)
+		}
+		sort.Strings(labels)
+		extraOpts["node-labels"] = strings.Join(labels, ",")
+	}
+	if _, ok := extraOpts["register-with-taints"]; !ok && len(nc.Taints) > 0 {
+		extraOpts["register-with-taints"] = strings.Join(nc.Taints, ",")
+	}
+
 	pauseImage := images.Pause(version, k8s.ImageRepository)
 	if _, ok := extraOpts["pod-infra-container-image"]; !ok && k8s.ImageRepository != "" && pauseImage != "" && k8s.ContainerRuntime != remoteContainerRuntime {
 		extraOpts["pod-infra-container-image"] = pauseImage
@@ -73,6 +88,10 @@ func extraKubeletOpts(mc config.ClusterConfig, nc config.Node, r cruntime.Manage
 	if err != nil {
 		return nil, errors.Wrap(err, "parses feature gate config for kubelet")
 	}
+	// unlike the control plane components, the kubelet does not get the feature gate from kubeadm
+	if needsDualStackFeatureGate(k8s, version) {
+		kubeletFeatureArgs = strings.TrimLeft(kubeletFeatureArgs+","+dualStackFeatureGate+"=true", ",")
+	}
 
 	if kubeletFeatureArgs != "" {
 		extraOpts["feature-gates"] = kubeletFeatureArgs
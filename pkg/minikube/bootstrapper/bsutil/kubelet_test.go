@@ -168,6 +168,34 @@ Wants=docker.socket
 ExecStart=
 ExecStart=/var/lib/minikube/binaries/v1.18.2/kubelet --authorization-mode=Webhook --bootstrap-kubeconfig=/etc/kubernetes/bootstrap-kubelet.conf --cgroup-driver=cgroupfs --client-ca-file=/var/lib/minikube/certs/ca.crt --cluster-domain=cluster.local --config=/var/lib/kubelet/config.yaml --container-runtime=docker --fail-swap-on=false --hostname-override=minikube --kubeconfig=/etc/kubernetes/kubelet.conf --node-ip=192.168.1.100 --pod-infra-container-image=docker-proxy-image.io/google_containers/pause:3.2 --pod-manifest-path=/etc/kubernetes/manifests
 
+[Install]
+`,
+		},
+		{
+			description: "worker with labels and taints",
+			cfg: config.ClusterConfig{
+				Name: "minikube",
+				KubernetesConfig: config.KubernetesConfig{
+					KubernetesVersion: constants.DefaultKubernetesVersion,
+					ContainerRuntime:  "docker",
+				},
+				Nodes: []config.Node{
+					{
+						IP:     "192.168.1.101",
+						Name:   "m02",
+						Worker: true,
+						Labels: map[string]string{"pool": "gpu", "node.kubernetes.io/instance-type": "large"},
+						Taints: []string{"dedicated=gpu:NoSchedule", "spot:PreferNoSchedule"},
+					},
+				},
+			},
+			expected: `[Unit]
+Wants=docker.socket
+
+[Service]
+ExecStart=
+ExecStart=/var/lib/minikube/binaries/v1.20.0/kubelet --bootstrap-kubeconfig=/etc/kubernetes/bootstrap-kubelet.conf --config=/var/lib/kubelet/config.yaml --container-runtime=docker --hostname-override=minikube-m02 --kubeconfig=/etc/kubernetes/kubelet.conf --node-ip=192.168.1.101 --node-labels=node.kubernetes.io/instance-type=large,pool=gpu --register-with-taints=dedicated=gpu:NoSchedule,spot:PreferNoSchedule
+
 [Install]
 `,
 		},
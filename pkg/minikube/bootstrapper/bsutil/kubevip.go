@@ -0,0 +1,89 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bsutil
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil/ktmpl"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/images"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/vmpath"
+)
+
+// KubeVipManifestPath is the path to the kube-vip static pod manifest
+var KubeVipManifestPath = path.Join(vmpath.GuestManifestsDir, "kube-vip.yaml")
+
+// NewKubeVipConfig generates the kube-vip static pod manifest for a control plane of an HA cluster
+func NewKubeVipConfig(cc config.ClusterConfig, n config.Node, iface string) ([]byte, error) {
+	if cc.KubernetesConfig.APIServerHAVIP == "" {
+		return nil, fmt.Errorf("cluster %s has no virtual IP", cc.Name)
+	}
+	port := n.Port
+	if port <= 0 {
+		port = constants.APIServerPort
+	}
+
+	opts := struct {
+		VIP       string
+		Port      int
+		Interface string
+		Image     string
+	}{
+		VIP:       cc.KubernetesConfig.APIServerHAVIP,
+		Port:      port,
+		Interface: iface,
+		Image:     images.KubeVip(cc.KubernetesConfig.ImageRepository),
+	}
+
+	var b bytes.Buffer
+	if err := ktmpl.KubeVipTemplate.Execute(&b, opts); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// NetworkInterface returns the name of the network interface which holds ip within the guest
+func NetworkInterface(r command.Runner, ip string) (string, error) {
+	rr, err := r.RunCmd(exec.Command("ip", "-o", "-4", "addr", "show"))
+	if err != nil {
+		return "", errors.Wrap(err, "ip addr")
+	}
+	return parseNetworkInterface(rr.Stdout.String(), ip)
+}
+
+// parseNetworkInterface finds the interface holding ip within the output of `ip -o -4 addr show`
+func parseNetworkInterface(out string, ip string) (string, error) {
+	// 2: eth0    inet 192.168.49.2/24 brd 192.168.49.255 scope global eth0\       valid_lft forever preferred_lft forever
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 4 || fields[2] != "inet" {
+			continue
+		}
+		if strings.Split(fields[3], "/")[0] == ip {
+			return strings.Split(fields[1], "@")[0], nil
+		}
+	}
+	return "", fmt.Errorf("no network interface holds %s", ip)
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package bsutil
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+func TestParseNetworkInterface(t *testing.T) {
+	out := `1: lo    inet 127.0.0.1/8 scope host lo\       valid_lft forever preferred_lft forever
+2: eth0    inet 192.168.39.12/24 brd 192.168.39.255 scope global dynamic eth0\       valid_lft 3303sec preferred_lft 3303sec
+12: eth1@if13    inet 192.168.49.2/24 brd 192.168.49.255 scope global eth1\       valid_lft forever preferred_lft forever
+`
+	tests := []struct {
+		ip      string
+		want    string
+		wantErr bool
+	}{
+		{"192.168.39.12", "eth0", false},
+		{"192.168.49.2", "eth1", false},
+		{"192.168.49.3", "", true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.ip, func(t *testing.T) {
+			got, err := parseNetworkInterface(out, tc.ip)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("parseNetworkInterface() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("parseNetworkInterface() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNewKubeVipConfig(t *testing.T) {
+	cc := config.ClusterConfig{
+		Name: "minikube",
+		KubernetesConfig: config.KubernetesConfig{
+			APIServerHAVIP: "192.168.49.254",
+		},
+	}
+	n := config.Node{Name: "m02", IP: "192.168.49.3", Port: 8443, ControlPlane: true}
+
+	b, err := NewKubeVipConfig(cc, n, "eth0")
+	if err != nil {
+		t.Fatalf("NewKubeVipConfig: %v", err)
+	}
+	for _, want := range []string{"value: 192.168.49.254", "value: eth0", `value: "8443"`, "image: ghcr.io/kube-vip/kube-vip:"} {
+		if !strings.Contains(string(b), want) {
+			t.Errorf("manifest does not contain %q:\n%s", want, b)
+		}
+	}
+
+	cc.KubernetesConfig.APIServerHAVIP = ""
+	if _, err := NewKubeVipConfig(cc, n, "eth0"); err == nil {
+		t.Errorf("NewKubeVipConfig succeeded without a virtual IP, want error")
+	}
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kverify
+
+import (
+	"fmt"
+
+	core "k8s.io/api/core/v1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// failingReasons are the reasons for which a waiting container will not start without intervention
+var failingReasons = map[string]bool{
+	"CrashLoopBackOff":           true,
+	"CreateContainerConfigError": true,
+	"CreateContainerError":       true,
+	"ErrImagePull":               true,
+	"ImagePullBackOff":           true,
+	"InvalidImageName":           true,
+	"RunContainerError":          true,
+}
+
+// ErrPodsNotFound is returned when no pod matches the selector of a component
+type ErrPodsNotFound struct {
+	Selector string
+}
+
+func (e *ErrPodsNotFound) Error() string {
+	return fmt.Sprintf("no pods found matching %q", e.Selector)
+}
+
+// ErrPodFailed is returned when a pod of a component failed, or will not start without intervention
+type ErrPodFailed struct {
+	Pod    string
+	Reason string
+}
+
+func (e *ErrPodFailed) Error() string {
+	return fmt.Sprintf("pod %q failed: %s", e.Pod, e.Reason)
+}
+
+// ErrPodNotReady is returned when a pod of a component is pending, or running but not ready
+type ErrPodNotReady struct {
+	Pod    string
+	Phase  core.PodPhase
+	Reason string
+}
+
+func (e *ErrPodNotReady) Error() string {
+	return fmt.Sprintf("pod %q is %s but not ready: %s", e.Pod, e.Phase, e.Reason)
+}
+
+// PodsHealthy verifies that the pods matching a label selector are running and ready, returning why the first one is not.
+// Pods which have run to completion are ignored. An empty namespace matches the pods of all namespaces.
+func PodsHealthy(cs kubernetes.Interface, namespace string, selector string) error {
+	pods, err := cs.CoreV1().Pods(namespace).List(meta.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return err
+	}
+
+	found := false
+	for _, pod := range pods.Items {
+		if pod.Status.Phase == core.PodSucceeded {
+			continue
+		}
+		found = true
+		if err := podHealthy(pod); err != nil {
+			return err
+		}
+	}
+	if !found {
+		return &ErrPodsNotFound{Selector: selector}
+	}
+	return nil
+}
+
+// podHealthy verifies that a pod is running and ready
+func podHealthy(pod core.Pod) error {
+	if pod.Status.Phase == core.PodFailed {
+		return &ErrPodFailed{Pod: pod.Name, Reason: podReason(pod)}
+	}
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.State.Waiting != nil && failingReasons[cs.State.Waiting.Reason] {
+			return &ErrPodFailed{Pod: pod.Name, Reason: fmt.Sprintf("container %q: %s", cs.Name, cs.State.Waiting.Reason)}
+		}
+	}
+
+	for _, c := range pod.Status.Conditions {
+		if c.Type == core.PodReady && c.Status == core.ConditionTrue {
+			return nil
+		}
+	}
+	return &ErrPodNotReady{Pod: pod.Name, Phase: pod.Status.Phase, Reason: podReason(pod)}
+}
+
+// podReason returns the most specific reason given for the status of a pod
+func podReason(pod core.Pod) string {
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.State.Waiting != nil && cs.State.Waiting.Reason != "" {
+			return fmt.Sprintf("container %q: %s", cs.Name, cs.State.Waiting.Reason)
+		}
+		if cs.State.Terminated != nil && cs.State.Terminated.Reason != "" {
+			return fmt.Sprintf("container %q: %s", cs.Name, cs.State.Terminated.Reason)
+		}
+	}
+	for _, c := range pod.Status.Conditions {
+		if c.Status != core.ConditionTrue && c.Reason != "" {
+			return fmt.Sprintf("%s: %s", c.Type, c.Reason)
+		}
+	}
+	if pod.Status.Reason != "" {
+		return pod.Status.Reason
+	}
+	return string(pod.Status.Phase)
+}
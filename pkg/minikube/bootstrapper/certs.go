@@ -201,6 +201,10 @@ func generateProfileCerts(k8s config.KubernetesConfig, n config.Node, ccs CACert
 		apiServerIPs = append(apiServerIPs, net.ParseIP(v))
 	}
 
+	if k8s.APIServerHAVIP != "" {
+		apiServerIPs = append(apiServerIPs, net.ParseIP(k8s.APIServerHAVIP))
+	}
+
 	apiServerNames := append(k8s.APIServerNames, k8s.APIServerName, constants.ControlPlaneAlias)
 	apiServerAlternateNames := append(
 		apiServerNames,
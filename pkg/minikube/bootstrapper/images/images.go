@@ -167,3 +167,11 @@ func KindNet(repo string) string {
 	}
 	return path.Join(repo, "kindnetd:0.5.4")
 }
+
+// KubeVip returns the image used for the virtual IP fronting the control planes of HA clusters
+func KubeVip(repo string) string {
+	if repo == "" {
+		repo = "ghcr.io/kube-vip"
+	}
+	return path.Join(repo, "kube-vip:v0.3.7")
+}
@@ -91,15 +91,27 @@ func (k *Bootstrapper) LogCommands(cfg config.ClusterConfig, o bootstrapper.LogO
 	if o.Lines > 0 {
 		kubelet.WriteString(fmt.Sprintf(" -n %d", o.Lines))
 	}
+	if o.Since > 0 {
+		kubelet.WriteString(fmt.Sprintf(" --since=-%ds", int(o.Since.Seconds())))
+	}
 	if o.Follow {
 		kubelet.WriteString(" -f")
 	}
 
 	var dmesg strings.Builder
-	dmesg.WriteString("sudo dmesg -PH -L=never --level warn,err,crit,alert,emerg")
+	if o.Since > 0 {
+		// dmesg of older util-linux and busybox cannot filter by time, so the messages are filtered by their raw
+		// timestamp, in seconds since boot, which /proc/uptime gives the current one of
+		dmesg.WriteString("sudo dmesg -P -L=never --level warn,err,crit,alert,emerg")
+	} else {
+		dmesg.WriteString("sudo dmesg -PH -L=never --level warn,err,crit,alert,emerg")
+	}
 	if o.Follow {
 		dmesg.WriteString(" --follow")
 	}
+	if o.Since > 0 {
+		dmesg.WriteString(fmt.Sprintf(` | awk -v since="$(awk '{print $1 - %d}' /proc/uptime)" '{t = $0; sub(/^\[ */, "", t); sub(/\].*/, "", t); if (t + 0 >= since) {print; fflush()}}'`, int(o.Since.Seconds())))
+	}
 	if o.Lines > 0 {
 		dmesg.WriteString(fmt.Sprintf(" | tail -n %d", o.Lines))
 	}
@@ -655,6 +667,16 @@ func (k *Bootstrapper) restartControlPlane(cfg config.ClusterConfig) error {
 	return nil
 }
 
+// joinCommand returns the kubeadm join command of a node, from the join command printed by the primary control plane
+func joinCommand(cc config.ClusterConfig, n config.Node, joinCmd string) string {
+	// Join the master by specifying its token
+	joinCmd = fmt.Sprintf("%s --node-name=%s", joinCmd, driver.MachineName(cc, n))
+	if n.ControlPlane {
+		joinCmd = fmt.Sprintf("%s --apiserver-advertise-address=%s --apiserver-bind-port=%d", joinCmd, n.IP, n.Port)
+	}
+	return joinCmd
+}
+
 // JoinCluster adds a node to an existing cluster
 func (k *Bootstrapper) JoinCluster(cc config.ClusterConfig, n config.Node, joinCmd string) error {
 	start := time.Now()
@@ -663,8 +685,7 @@ func (k *Bootstrapper) JoinCluster(cc config.ClusterConfig, n config.Node, joinC
 		klog.Infof("JoinCluster complete in %s", time.Since(start))
 	}()
 
-	// Join the master by specifying its token
-	joinCmd = fmt.Sprintf("%s --node-name=%s", joinCmd, driver.MachineName(cc, n))
+	joinCmd = joinCommand(cc, n, joinCmd)
 
 	join := func() error {
 		// reset first to clear any possibly existing state
@@ -687,6 +708,17 @@ func (k *Bootstrapper) JoinCluster(cc config.ClusterConfig, n config.Node, joinC
 		return errors.Wrap(err, "joining cp")
 	}
 
+	// kubeadm reset removes every static pod manifest, including the kube-vip one written by UpdateNode
+	if cc.HA && n.ControlPlane {
+		kubeVip, err := k.kubeVipAsset(cc, n)
+		if err != nil {
+			return err
+		}
+		if err := bsutil.CopyFiles(k.c, []assets.CopyableFile{kubeVip}); err != nil {
+			return errors.Wrap(err, "copy kube-vip manifest")
+		}
+	}
+
 	if _, err := k.c.RunCmd(exec.Command("/bin/bash", "-c", "sudo systemctl daemon-reload && sudo systemctl enable kubelet && sudo systemctl start kubelet")); err != nil {
 		return errors.Wrap(err, "starting kubelet")
 	}
@@ -694,8 +726,8 @@ func (k *Bootstrapper) JoinCluster(cc config.ClusterConfig, n config.Node, joinC
 	return nil
 }
 
-// GenerateToken creates a token and returns the appropriate kubeadm join command to run, or the already existing token
-func (k *Bootstrapper) GenerateToken(cc config.ClusterConfig) (string, error) {
+// GenerateToken creates a token and returns the appropriate kubeadm join command to run for the node, or the already existing token
+func (k *Bootstrapper) GenerateToken(cc config.ClusterConfig, n config.Node) (string, error) {
 	// Take that generated token and use it to get a kubeadm join command
 	tokenCmd := exec.Command("/bin/bash", "-c", fmt.Sprintf("%s token create --print-join-command --ttl=0", bsutil.InvokeKubeadm(cc.KubernetesConfig.KubernetesVersion)))
 	r, err := k.c.RunCmd(tokenCmd)
@@ -710,9 +742,41 @@ func (k *Bootstrapper) GenerateToken(cc config.ClusterConfig) (string, error) {
 		joinCmd = fmt.Sprintf("%s --cri-socket %s", joinCmd, cc.KubernetesConfig.CRISocket)
 	}
 
+	if n.ControlPlane {
+		key, err := k.uploadCerts(cc)
+		if err != nil {
+			return "", errors.Wrap(err, "uploading certs")
+		}
+		joinCmd = fmt.Sprintf("%s --control-plane --certificate-key=%s", joinCmd, key)
+	}
+
 	return joinCmd, nil
 }
 
+// uploadCerts shares the control plane certificates through the kubeadm-certs secret, returning the key to decrypt them with
+func (k *Bootstrapper) uploadCerts(cc config.ClusterConfig) (string, error) {
+	version, err := util.ParseKubernetesVersion(cc.KubernetesConfig.KubernetesVersion)
+	if err != nil {
+		return "", errors.Wrap(err, "parsing Kubernetes version")
+	}
+	if version.LT(semver.MustParse("1.15.0")) {
+		return "", fmt.Errorf("joining additional control planes requires Kubernetes v1.15.0 or newer, got %s", version)
+	}
+
+	rr, err := k.c.RunCmd(exec.Command("/bin/bash", "-c", fmt.Sprintf("%s init phase upload-certs --upload-certs --config %s", bsutil.InvokeKubeadm(cc.KubernetesConfig.KubernetesVersion), bsutil.KubeadmYamlPath)))
+	if err != nil {
+		return "", errors.Wrap(err, "kubeadm upload-certs")
+	}
+
+	// the key is printed on the last line of output
+	lines := strings.Split(strings.TrimSpace(rr.Stdout.String()), "\n")
+	key := strings.TrimSpace(lines[len(lines)-1])
+	if key == "" {
+		return "", fmt.Errorf("no certificate key in output: %s", rr.Output())
+	}
+	return key, nil
+}
+
 // DeleteCluster removes the components that were started earlier
 func (k *Bootstrapper) DeleteCluster(k8s config.KubernetesConfig) error {
 	cr, err := cruntime.New(cruntime.Config{Type: k8s.ContainerRuntime, Runner: k.c, Socket: k8s.CRISocket})
@@ -802,6 +866,19 @@ func (k *Bootstrapper) UpdateCluster(cfg config.ClusterConfig) error {
 	return nil
 }
 
+// kubeVipAsset returns the kube-vip static pod manifest for a control plane of an HA cluster
+func (k *Bootstrapper) kubeVipAsset(cfg config.ClusterConfig, n config.Node) (assets.CopyableFile, error) {
+	iface, err := bsutil.NetworkInterface(k.c, n.IP)
+	if err != nil {
+		return nil, errors.Wrap(err, "network interface")
+	}
+	kubeVipCfg, err := bsutil.NewKubeVipConfig(cfg, n, iface)
+	if err != nil {
+		return nil, errors.Wrap(err, "generating kube-vip config")
+	}
+	return assets.NewMemoryAssetTarget(kubeVipCfg, bsutil.KubeVipManifestPath, "0600"), nil
+}
+
 // UpdateNode updates a node.
 func (k *Bootstrapper) UpdateNode(cfg config.ClusterConfig, n config.Node, r cruntime.Manager) error {
 	kubeadmCfg, err := bsutil.GenerateKubeadmYAML(cfg, n, r)
@@ -809,23 +886,34 @@ func (k *Bootstrapper) UpdateNode(cfg config.ClusterConfig, n config.Node, r cru
 		return errors.Wrap(err, "generating kubeadm cfg")
 	}
 
-	kubeletCfg, err := bsutil.NewKubeletConfig(cfg, n, r)
+	// a worker may run an older kubelet than the control plane, though it is still joined using the kubeadm of the control plane
+	nodeCfg := cfg
+	if !n.ControlPlane && n.KubernetesVersion != "" {
+		nodeCfg.KubernetesConfig.KubernetesVersion = n.KubernetesVersion
+	}
+
+	kubeletCfg, err := bsutil.NewKubeletConfig(nodeCfg, n, r)
 	if err != nil {
 		return errors.Wrap(err, "generating kubelet config")
 	}
 
-	kubeletService, err := bsutil.NewKubeletService(cfg.KubernetesConfig)
+	kubeletService, err := bsutil.NewKubeletService(nodeCfg.KubernetesConfig)
 	if err != nil {
 		return errors.Wrap(err, "generating kubelet service")
 	}
 
-	klog.Infof("kubelet %s config:\n%+v", kubeletCfg, cfg.KubernetesConfig)
+	klog.Infof("kubelet %s config:\n%+v", kubeletCfg, nodeCfg.KubernetesConfig)
 
 	sm := sysinit.New(k.c)
 
 	if err := bsutil.TransferBinaries(cfg.KubernetesConfig, k.c, sm); err != nil {
 		return errors.Wrap(err, "downloading binaries")
 	}
+	if nodeCfg.KubernetesConfig.KubernetesVersion != cfg.KubernetesConfig.KubernetesVersion {
+		if err := bsutil.TransferBinaries(nodeCfg.KubernetesConfig, k.c, sm); err != nil {
+			return errors.Wrap(err, "downloading node binaries")
+		}
+	}
 
 	files := []assets.CopyableFile{
 		assets.NewMemoryAssetTarget(kubeletCfg, bsutil.KubeletSystemdConfFile, "0644"),
@@ -836,8 +924,17 @@ func (k *Bootstrapper) UpdateNode(cfg config.ClusterConfig, n config.Node, r cru
 		files = append(files, assets.NewMemoryAssetTarget(kubeadmCfg, bsutil.KubeadmYamlPath+".new", "0640"))
 	}
 
+	// every control plane of an HA cluster runs kube-vip, which advertises the virtual IP from the elected leader
+	if cfg.HA && n.ControlPlane {
+		kubeVip, err := k.kubeVipAsset(cfg, n)
+		if err != nil {
+			return err
+		}
+		files = append(files, kubeVip)
+	}
+
 	// Installs compatibility shims for non-systemd environments
-	kubeletPath := path.Join(vmpath.GuestPersistentDir, "binaries", cfg.KubernetesConfig.KubernetesVersion, "kubelet")
+	kubeletPath := path.Join(vmpath.GuestPersistentDir, "binaries", nodeCfg.KubernetesConfig.KubernetesVersion, "kubelet")
 	shims, err := sm.GenerateInitShim("kubelet", kubeletPath, bsutil.KubeletSystemdConfFile)
 	if err != nil {
 		return errors.Wrap(err, "shim")
@@ -853,13 +950,77 @@ func (k *Bootstrapper) UpdateNode(cfg config.ClusterConfig, n config.Node, r cru
 		return errors.Wrap(err, "control plane")
 	}
 
-	if err := machine.AddHostAlias(k.c, constants.ControlPlaneAlias, net.ParseIP(cp.IP)); err != nil {
+	cpIP := cp.IP
+	if cfg.KubernetesConfig.APIServerHAVIP != "" {
+		cpIP = cfg.KubernetesConfig.APIServerHAVIP
+	}
+	if err := machine.AddHostAlias(k.c, constants.ControlPlaneAlias, net.ParseIP(cpIP)); err != nil {
 		return errors.Wrap(err, "host alias")
 	}
 
 	return nil
 }
 
+// upgradePreflightIgnores are the preflight checks of kubeadm upgrade apply which may fail on minikube clusters
+var upgradePreflightIgnores = []string{
+	// Corefiles edited by users, with plugins or options which kubeadm does not know, fail the CoreDNS migration checks
+	"CoreDNSUnsupportedPlugins",
+	"CoreDNSMigration",
+}
+
+// UpgradeNode upgrades the Kubernetes components of a node to the version within cfg, using kubeadm upgrade apply on the primary control plane, and kubeadm upgrade node on every other node
+func (k *Bootstrapper) UpgradeNode(cfg config.ClusterConfig, n config.Node, r cruntime.Manager) error {
+	start := time.Now()
+	klog.Infof("UpgradeNode %s to %s", n.Name, cfg.KubernetesConfig.KubernetesVersion)
+	defer func() {
+		klog.Infof("UpgradeNode complete in %s", time.Since(start))
+	}()
+
+	sm := sysinit.New(k.c)
+	if err := bsutil.TransferBinaries(cfg.KubernetesConfig, k.c, sm); err != nil {
+		return errors.Wrap(err, "downloading binaries")
+	}
+	// transferring the binaries stops the kubelet, which kubeadm still needs to restart the static pods of a control plane
+	if err := sm.Start("kubelet"); err != nil {
+		return errors.Wrap(err, "starting kubelet")
+	}
+
+	primary, err := config.PrimaryControlPlane(&cfg)
+	if err != nil {
+		return errors.Wrap(err, "primary control plane")
+	}
+
+	kubeadm := bsutil.InvokeKubeadm(cfg.KubernetesConfig.KubernetesVersion)
+	upgrade := fmt.Sprintf("%s upgrade node", kubeadm)
+	if n.Name == primary.Name {
+		// minikube manages the certificates itself
+		upgrade = fmt.Sprintf("%s upgrade apply %s --yes --certificate-renewal=false --ignore-preflight-errors=%s", kubeadm, cfg.KubernetesConfig.KubernetesVersion, strings.Join(upgradePreflightIgnores, ","))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), initTimeoutMinutes*time.Minute)
+	defer cancel()
+	if _, err := k.c.RunCmd(exec.CommandContext(ctx, "/bin/bash", "-c", upgrade)); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return errors.Wrap(ctx.Err(), "kubeadm upgrade")
+		}
+		return errors.Wrap(err, "kubeadm upgrade")
+	}
+
+	// point the kubelet at the upgraded binaries
+	if err := k.UpdateNode(cfg, n, r); err != nil {
+		return errors.Wrap(err, "update node")
+	}
+	if n.ControlPlane {
+		if _, err := k.c.RunCmd(exec.Command("sudo", "cp", bsutil.KubeadmYamlPath+".new", bsutil.KubeadmYamlPath)); err != nil {
+			return errors.Wrap(err, "cp")
+		}
+	}
+	if err := sm.Restart("kubelet"); err != nil {
+		return errors.Wrap(err, "restarting kubelet")
+	}
+	return nil
+}
+
 // kubectlPath returns the path to the kubelet
 func kubectlPath(cfg config.ClusterConfig) string {
 	return path.Join(vmpath.GuestPersistentDir, "binaries", cfg.KubernetesConfig.KubernetesVersion, "kubectl")
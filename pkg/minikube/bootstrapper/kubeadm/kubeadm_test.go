@@ -0,0 +1,93 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeadm
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+func TestJoinCommand(t *testing.T) {
+	cc := config.ClusterConfig{Name: "minikube", KubernetesConfig: config.KubernetesConfig{NodePort: 8443}}
+	join := "kubeadm join control-plane.minikube.internal:8443 --token abc"
+	tests := []struct {
+		name string
+		n    config.Node
+		want string
+	}{
+		{
+			name: "worker",
+			n:    config.Node{Name: "m03", IP: "192.168.49.4", Worker: true},
+			want: join + " --node-name=minikube-m03",
+		},
+		{
+			name: "control plane",
+			n:    config.Node{Name: "m02", IP: "192.168.49.3", Port: 8443, ControlPlane: true, Worker: true},
+			want: join + " --node-name=minikube-m02 --apiserver-advertise-address=192.168.49.3 --apiserver-bind-port=8443",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := joinCommand(cc, tc.n, join); got != tc.want {
+				t.Errorf("joinCommand() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestLogCommandsSince(t *testing.T) {
+	k := &Bootstrapper{}
+	cmds := k.LogCommands(config.ClusterConfig{}, bootstrapper.LogOptions{Lines: 10, Since: time.Minute})
+	if want := "sudo journalctl -u kubelet -n 10 --since=-60s"; cmds["kubelet"] != want {
+		t.Errorf("kubelet command = %q, want %q", cmds["kubelet"], want)
+	}
+	dmesg := cmds["dmesg"]
+	if !strings.HasPrefix(dmesg, "sudo dmesg -P ") || !strings.HasSuffix(dmesg, " | tail -n 10") {
+		t.Fatalf("dmesg command = %q, want raw timestamps, filtered and then tailed", dmesg)
+	}
+
+	// the filter keeps the messages of the last minute, given the uptime of the host running the test
+	if runtime.GOOS != "linux" {
+		t.Skip("/proc/uptime is only known on linux")
+	}
+	b, err := ioutil.ReadFile("/proc/uptime")
+	if err != nil {
+		t.Skipf("uptime: %v", err)
+	}
+	uptime, err := strconv.ParseFloat(strings.Fields(string(b))[0], 64)
+	if err != nil || uptime < 120 {
+		t.Skipf("the host booted too recently: %v", err)
+	}
+	messages := fmt.Sprintf("printf '%%s\\n' '[    0.500000] old' '[%12.6f] recent'", uptime-1)
+	filter := dmesg[strings.Index(dmesg, " | awk"):]
+	out, err := exec.Command("/bin/bash", "-c", messages+filter).Output()
+	if err != nil {
+		t.Skipf("bash and awk are required: %v", err)
+	}
+	if got := strings.TrimSpace(string(out)); !strings.HasSuffix(got, "] recent") || strings.Contains(got, "old") {
+		t.Errorf("filtered messages = %q, want only the recent one", got)
+	}
+}
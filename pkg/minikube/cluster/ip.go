@@ -33,13 +33,13 @@ import (
 	"k8s.io/minikube/pkg/minikube/machine"
 )
 
-// HostIP gets the ip address to be used for mapping host -> VM and VM -> host
-func HostIP(host *host.Host, clusterName string) (net.IP, error) {
+// HostIP gets the ip address to be used for mapping host -> VM and VM -> host, networkName is the network of the docker and podman drivers
+func HostIP(host *host.Host, networkName string) (net.IP, error) {
 	switch host.DriverName {
 	case driver.Docker:
-		return oci.RoutableHostIPFromInside(oci.Docker, clusterName, host.Name)
+		return oci.RoutableHostIPFromInside(oci.Docker, networkName, host.Name)
 	case driver.Podman:
-		return oci.RoutableHostIPFromInside(oci.Podman, clusterName, host.Name)
+		return oci.RoutableHostIPFromInside(oci.Podman, networkName, host.Name)
 	case driver.KVM2:
 		return net.ParseIP("192.168.39.1"), nil
 	case driver.HyperV:
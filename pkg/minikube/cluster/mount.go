@@ -23,15 +23,34 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	"k8s.io/klog/v2"
 	"k8s.io/minikube/pkg/minikube/command"
 )
 
+// Mount types, besides 9p
+const (
+	// MountNFS mounts with NFSv3, served by a userspace NFS server on the host
+	MountNFS = "nfs"
+	// MountSSHFS mounts with sshfs, which talks SFTP with the host over an SSH session
+	MountSSHFS = "sshfs"
+	// MountSync copies the host directory to the node, and then its changes as they happen
+	MountSync = "sync"
+)
+
+// Directions of syncs
+const (
+	// SyncOneWay copies the changes of the host directory to the node
+	SyncOneWay = "one-way"
+	// SyncTwoWay also copies the files changed on the node back to the host
+	SyncTwoWay = "two-way"
+)
+
 // MountConfig defines the options available to the Mount command
 type MountConfig struct {
-	// Type is the filesystem type (Typically 9p)
+	// Type is the filesystem type: 9p, nfs, sshfs or sync
 	Type string
 	// UID is the User ID which this path will be mounted as
 	UID string
@@ -47,6 +66,18 @@ type MountConfig struct {
 	Mode os.FileMode
 	// Extra mount options. See https://www.kernel.org/doc/Documentation/filesystems/9p.txt
 	Options map[string]string
+	// SyncDirection is the direction in which syncs copy changes: one-way or two-way
+	SyncDirection string
+	// SyncInterval is how often two-way syncs look for the files changed on the node
+	SyncInterval time.Duration
+	// SyncIgnore lists glob patterns of the names of the files which syncs do not copy
+	SyncIgnore []string
+	// ReadOnly mounts 9p filesystems read-only, which the 9p server enforces
+	ReadOnly bool
+	// Token, unless empty, is the token the 9p server requires, which is passed as the aname
+	Token string
+	// Notify forwards the changes of the host files to the file watchers of the node, for the types other than sync
+	Notify bool
 }
 
 // mountRunner is the subset of CommandRunner used for mounting
@@ -54,8 +85,23 @@ type mountRunner interface {
 	RunCmd(*exec.Cmd) (*command.RunResult, error)
 }
 
-// Mount runs the mount command from the 9p client on the VM to the 9p server on the host
+// Mount runs the mount command from the 9p or NFS client on the VM to the server on the host
 func Mount(r mountRunner, source string, target string, c *MountConfig) error {
+	if err := PrepareMount(r, target, c); err != nil {
+		return err
+	}
+
+	rr, err := r.RunCmd(exec.Command("/bin/bash", "-c", mntCmd(source, target, c)))
+	if err != nil {
+		return errors.Wrapf(err, "mount with cmd %s ", rr.Command())
+	}
+
+	klog.Infof("mount successful: %q", rr.Output())
+	return nil
+}
+
+// PrepareMount unmounts whatever is mounted on target, and creates it
+func PrepareMount(r mountRunner, target string, c *MountConfig) error {
 	if err := Unmount(r, target); err != nil {
 		return errors.Wrap(err, "umount")
 	}
@@ -63,14 +109,33 @@ func Mount(r mountRunner, source string, target string, c *MountConfig) error {
 	if _, err := r.RunCmd(exec.Command("/bin/bash", "-c", fmt.Sprintf("sudo mkdir -m %o -p %s", c.Mode, target))); err != nil {
 		return errors.Wrap(err, "create folder pre-mount")
 	}
+	return nil
+}
 
-	rr, err := r.RunCmd(exec.Command("/bin/bash", "-c", mntCmd(source, target, c)))
+// SSHFSCmd returns the command which mounts source with sshfs in slave mode, to be run in an SSH session whose stdin and stdout talk SFTP with the host
+func SSHFSCmd(source string, target string, c *MountConfig) string {
+	return mntCmd(source, target, c)
+}
+
+// MountIDs returns the numeric user and group IDs on the node of the user and group of a mount
+func MountIDs(r mountRunner, c *MountConfig) (int, int, error) {
+	rr, err := r.RunCmd(exec.Command("/bin/bash", "-c", fmt.Sprintf("echo %s:%s", resolveUID(c.UID), resolveGID(c.GID))))
 	if err != nil {
-		return errors.Wrapf(err, "mount with cmd %s ", rr.Command())
+		return 0, 0, errors.Wrap(err, "resolve ids")
 	}
-
-	klog.Infof("mount successful: %q", rr.Output())
-	return nil
+	ids := strings.Split(strings.TrimSpace(rr.Stdout.String()), ":")
+	if len(ids) != 2 {
+		return 0, 0, errors.Errorf("unexpected ids: %q", rr.Stdout.String())
+	}
+	uid, err := strconv.Atoi(ids[0])
+	if err != nil {
+		return 0, 0, errors.Errorf("unknown user %q", c.UID)
+	}
+	gid, err := strconv.Atoi(ids[1])
+	if err != nil {
+		return 0, 0, errors.Errorf("unknown group %q", c.GID)
+	}
+	return uid, gid, nil
 }
 
 // returns either a raw UID number, or the subshell to resolve it.
@@ -102,20 +167,53 @@ func resolveGID(id string) string {
 
 // mntCmd returns a mount command based on a config.
 func mntCmd(source string, target string, c *MountConfig) string {
-	options := map[string]string{
-		"dfltgid": resolveGID(c.GID),
-		"dfltuid": resolveUID(c.UID),
-		"trans":   "tcp",
-	}
+	var options map[string]string
+	switch c.Type {
+	case MountNFS:
+		// the NFS server serves the mount protocol on its own port, so that neither the portmapper nor the lock manager are needed
+		options = map[string]string{
+			"mountproto": "tcp",
+			"nolock":     "",
+			"proto":      "tcp",
+			"vers":       "3",
+		}
+		if c.Port != 0 {
+			options["port"] = strconv.Itoa(c.Port)
+			options["mountport"] = strconv.Itoa(c.Port)
+		}
+		if strings.Contains(source, ":") {
+			source = "[" + source + "]"
+		}
+		source += ":/"
+	case MountSSHFS:
+		options = map[string]string{
+			"allow_other": "",
+			"gid":         resolveGID(c.GID),
+			"slave":       "",
+			"uid":         resolveUID(c.UID),
+		}
+	default:
+		options = map[string]string{
+			"dfltgid": resolveGID(c.GID),
+			"dfltuid": resolveUID(c.UID),
+			"trans":   "tcp",
+		}
 
-	if c.Port != 0 {
-		options["port"] = strconv.Itoa(c.Port)
-	}
-	if c.Version != "" {
-		options["version"] = c.Version
-	}
-	if c.MSize != 0 {
-		options["msize"] = strconv.Itoa(c.MSize)
+		if c.Port != 0 {
+			options["port"] = strconv.Itoa(c.Port)
+		}
+		if c.Version != "" {
+			options["version"] = c.Version
+		}
+		if c.MSize != 0 {
+			options["msize"] = strconv.Itoa(c.MSize)
+		}
+		if c.ReadOnly {
+			options["ro"] = ""
+		}
+		if c.Token != "" {
+			options["aname"] = c.Token
+		}
 	}
 
 	// Copy in all of the user-supplied keys and values
@@ -134,6 +232,10 @@ func mntCmd(source string, target string, c *MountConfig) string {
 		opts = append(opts, fmt.Sprintf("%s=%s", k, v))
 	}
 	sort.Strings(opts)
+	if c.Type == MountSSHFS {
+		// sshfs stays in the foreground, so that it exits with the SSH session
+		return fmt.Sprintf("sudo sshfs -f -o %s %s %s", strings.Join(opts, ","), source, target)
+	}
 	return fmt.Sprintf("sudo mount -t %s -o %s %s %s", c.Type, strings.Join(opts, ","), source, target)
 }
 
@@ -64,6 +64,41 @@ func TestMntCmd(t *testing.T) {
 			}},
 			want: "sudo mount -t 9p -o dfltgid=0,dfltuid=0,trans=tcp,version=9p2000.L src tgt",
 		},
+		{
+			name:   "read-only",
+			source: "src",
+			target: "target",
+			cfg:    &MountConfig{Type: "9p", Mode: os.FileMode(0700), ReadOnly: true},
+			want:   "sudo mount -t 9p -o dfltgid=0,dfltuid=0,ro,trans=tcp src target",
+		},
+		{
+			name:   "token",
+			source: "src",
+			target: "target",
+			cfg:    &MountConfig{Type: "9p", Mode: os.FileMode(0700), Token: "0123abcd"},
+			want:   "sudo mount -t 9p -o aname=0123abcd,dfltgid=0,dfltuid=0,trans=tcp src target",
+		},
+		{
+			name:   "nfs",
+			source: "192.168.49.1",
+			target: "/target",
+			cfg:    &MountConfig{Type: "nfs", Mode: os.FileMode(0755), UID: "docker", GID: "docker", Port: 2049, Options: map[string]string{"actimeo": "1"}},
+			want:   "sudo mount -t nfs -o actimeo=1,mountport=2049,mountproto=tcp,nolock,port=2049,proto=tcp,vers=3 192.168.49.1:/ /target",
+		},
+		{
+			name:   "nfs-ipv6",
+			source: "fd00::1",
+			target: "/target",
+			cfg:    &MountConfig{Type: "nfs", Mode: os.FileMode(0755), Port: 2049},
+			want:   "sudo mount -t nfs -o mountport=2049,mountproto=tcp,nolock,port=2049,proto=tcp,vers=3 [fd00::1]:/ /target",
+		},
+		{
+			name:   "sshfs",
+			source: ":/home/user",
+			target: "/target",
+			cfg:    &MountConfig{Type: "sshfs", Mode: os.FileMode(0755), UID: "docker", GID: "docker", Options: map[string]string{"cache": "yes"}},
+			want:   "sudo sshfs -f -o allow_other,cache=yes,gid=$(grep ^docker: /etc/group | cut -d: -f3),slave,uid=$(id -u docker) :/home/user /target",
+		},
 	}
 
 	for _, tc := range tests {
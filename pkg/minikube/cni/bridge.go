@@ -19,6 +19,7 @@ package cni
 import (
 	"bytes"
 	"fmt"
+	"strings"
 	"text/template"
 
 	"github.com/pkg/errors"
@@ -42,7 +43,11 @@ var bridgeConf = template.Must(template.New("bridge").Parse(`
   "hairpinMode": true,
   "ipam": {
       "type": "host-local",
+{{- if .PodCIDRs}}
+      "ranges": [{{range $i, $cidr := .PodCIDRs}}{{if $i}}, {{end}}[{"subnet": "{{$cidr}}"}]{{end}}]
+{{- else}}
       "subnet": "{{.PodCIDR}}"
+{{- end}}
   }
 }
 `))
@@ -59,6 +64,10 @@ func (c Bridge) String() string {
 
 func (c Bridge) netconf() (assets.CopyableFile, error) {
 	input := &tmplInput{PodCIDR: DefaultPodCIDR}
+	// IPv6 and dual-stack clusters allocate pod addresses from a range per IP family
+	if cidr := FamilyCIDR(c.cc, DefaultPodCIDR); cidr != DefaultPodCIDR {
+		input.PodCIDRs = strings.Split(cidr, ",")
+	}
 
 	b := bytes.Buffer{}
 	if err := bridgeConf.Execute(&b, input); err != nil {
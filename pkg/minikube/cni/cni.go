@@ -30,6 +30,7 @@ import (
 	"k8s.io/minikube/pkg/minikube/assets"
 	"k8s.io/minikube/pkg/minikube/command"
 	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/minikube/driver"
 	"k8s.io/minikube/pkg/minikube/vmpath"
 )
@@ -37,6 +38,8 @@ import (
 const (
 	// DefaultPodCIDR is the default CIDR to use in minikube CNI's.
 	DefaultPodCIDR = "10.244.0.0/16"
+	// DefaultPodCIDRv6 is the default IPv6 CIDR to use in minikube CNI's, for IPv6 and dual-stack clusters.
+	DefaultPodCIDRv6 = "fd00:10:244::/56"
 )
 
 // Runner is the subset of command.Runner this package consumes
@@ -61,6 +64,7 @@ type Manager interface {
 type tmplInput struct {
 	ImageName    string
 	PodCIDR      string
+	PodCIDRs     []string // the pod CIDRs of each IP family, only set for IPv6 and dual-stack clusters
 	DefaultRoute string
 }
 
@@ -93,6 +97,33 @@ func New(cc config.ClusterConfig) (Manager, error) {
 	}
 }
 
+// FamilyCIDR returns the pod CIDR of a cluster for its IP family, given the IPv4 pod CIDR of its CNI.
+// Dual-stack clusters get a comma separated CIDR, as kubeadm expects.
+func FamilyCIDR(cc config.ClusterConfig, cidr string) string {
+	switch cc.KubernetesConfig.IPFamily {
+	case constants.IPv6Family:
+		return DefaultPodCIDRv6
+	case constants.DualStackFamily:
+		return cidr + "," + DefaultPodCIDRv6
+	}
+	return cidr
+}
+
+// PodSelector returns the label selector of the pods of a CNI which minikube deploys, or "" if it deploys no pods
+func PodSelector(m Manager) string {
+	switch m.(type) {
+	case KindNet:
+		return "app=kindnet"
+	case Calico:
+		return "k8s-app=calico-node"
+	case Cilium:
+		return "k8s-app=cilium"
+	case Flannel:
+		return "app=flannel"
+	}
+	return ""
+}
+
 func IsDisabled(cc config.ClusterConfig) bool {
 	if cc.KubernetesConfig.NetworkPlugin != "" && cc.KubernetesConfig.NetworkPlugin != "cni" {
 		return true
@@ -115,6 +146,12 @@ func chooseDefault(cc config.ClusterConfig) Manager {
 		return Bridge{}
 	}
 
+	// the bridge CNI assigns addresses of every IP family, unlike the built-in network of the docker runtime and kindnet
+	if cc.KubernetesConfig.IPFamily != "" && cc.KubernetesConfig.IPFamily != constants.IPv4Family && len(cc.Nodes) <= 1 && !cc.MultiNodeRequested {
+		klog.Infof("%s IP family found, recommending bridge", cc.KubernetesConfig.IPFamily)
+		return Bridge{cc: cc}
+	}
+
 	if cc.KubernetesConfig.ContainerRuntime != "docker" {
 		if driver.IsKIC(cc.Driver) {
 			klog.Infof("%q driver + %s runtime found, recommending kindnet", cc.Driver, cc.KubernetesConfig.ContainerRuntime)
@@ -137,6 +137,46 @@ func (e *execRunner) Copy(f assets.CopyableFile) error {
 	return writeFile(dst, f, os.FileMode(perms))
 }
 
+// CopyFrom copies a file and its permissions
+func (e *execRunner) CopyFrom(src string, dst string) error {
+	klog.Infof("cp: %s <-- %s", dst, src)
+	fi, err := os.Stat(src)
+	if err != nil {
+		return errors.Wrapf(err, "stat %s", src)
+	}
+
+	w, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, fi.Mode().Perm())
+	if err != nil {
+		return errors.Wrap(err, "create")
+	}
+	defer w.Close()
+
+	r, err := os.Open(src)
+	switch {
+	case err == nil:
+		defer r.Close()
+		if _, err := io.Copy(w, r); err != nil {
+			return errors.Wrap(err, "copy")
+		}
+	case e.sudo && os.IsPermission(err):
+		// stream the file through sudo, rather than leaving a root-owned destination
+		var stderr bytes.Buffer
+		c := exec.Command("sudo", "cat", src)
+		c.Stdout = w
+		c.Stderr = &stderr
+		if err := c.Run(); err != nil {
+			return errors.Wrapf(err, "sudo cat %s: %s", src, stderr.String())
+		}
+	default:
+		return errors.Wrap(err, "open")
+	}
+
+	if err := w.Chmod(fi.Mode().Perm()); err != nil {
+		return errors.Wrap(err, "chmod")
+	}
+	return w.Close()
+}
+
 // Remove removes a file
 func (e *execRunner) Remove(f assets.CopyableFile) error {
 	dst := filepath.Join(f.GetTargetDir(), f.GetTargetName())
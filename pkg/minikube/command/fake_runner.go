@@ -20,6 +20,7 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os/exec"
 	"strings"
 	"time"
@@ -101,6 +102,15 @@ func (f *FakeCommandRunner) Copy(file assets.CopyableFile) error {
 	return nil
 }
 
+// CopyFrom writes the stored contents of a filename to a local path
+func (f *FakeCommandRunner) CopyFrom(src string, dst string) error {
+	contents, err := f.GetFileToContents(src)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(dst, []byte(contents), 0644)
+}
+
 // Remove removes the filename, file contents key value pair from the stored map
 func (f *FakeCommandRunner) Remove(file assets.CopyableFile) error {
 	f.fileMap.Delete(file.GetSourcePath())
@@ -236,6 +236,59 @@ func copyToDocker(src string, dest string) error {
 	return nil
 }
 
+// CopyFrom copies a file from the container, preserving its permissions
+func (k *kicRunner) CopyFrom(src string, dst string) error {
+	fullSource := fmt.Sprintf("%s:%s", k.nameOrID, src)
+	klog.Infof("%s: %s --> %s", k.ociBin, fullSource, dst)
+	if k.ociBin == oci.Podman {
+		return copyFromPodman(fullSource, dst)
+	}
+	return copyFromDocker(fullSource, dst)
+}
+
+// Podman cp command doesn't match docker and doesn't have -a
+func copyFromPodman(src string, dst string) error {
+	if runtime.GOOS == "linux" {
+		cmd := oci.PrefixCmd(exec.Command(oci.Podman, "cp", src, dst))
+		klog.Infof("Run: %v", cmd)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return errors.Wrapf(err, "podman copy %s into %s, output: %s", src, dst, string(out))
+		}
+		return nil
+	}
+
+	parts := strings.Split(src, ":")
+	container := parts[0]
+	path := parts[1]
+	out, err := exec.Command(oci.Podman, "exec", container, "stat", "-c", "%a", path).Output()
+	if err != nil {
+		return errors.Wrapf(err, "podman stat %s", src)
+	}
+	perms, err := strconv.ParseUint(strings.TrimSpace(string(out)), 8, 32)
+	if err != nil {
+		return errors.Wrapf(err, "parsing permissions %q", out)
+	}
+	file, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, os.FileMode(perms))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	cmd := exec.Command(oci.Podman, "exec", container, "cat", path)
+	cmd.Stdout = file
+	klog.Infof("Run: %v", cmd)
+	if err := cmd.Run(); err != nil {
+		return errors.Wrapf(err, "podman copy %s into %s", src, dst)
+	}
+	return file.Close()
+}
+
+func copyFromDocker(src string, dst string) error {
+	if out, err := oci.PrefixCmd(exec.Command(oci.Docker, "cp", src, dst)).CombinedOutput(); err != nil {
+		return errors.Wrapf(err, "docker copy %s into %s, output: %s", src, dst, string(out))
+	}
+	return nil
+}
+
 // Remove removes a file
 func (k *kicRunner) Remove(f assets.CopyableFile) error {
 	dst := path.Join(f.GetTargetDir(), f.GetTargetName())
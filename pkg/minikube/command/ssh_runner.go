@@ -17,11 +17,15 @@ limitations under the License.
 package command
 
 import (
+	"bufio"
 	"bytes"
 	"fmt"
 	"io"
+	"os"
 	"os/exec"
 	"path"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -272,3 +276,92 @@ func (s *SSHRunner) Copy(f assets.CopyableFile) error {
 	}
 	return g.Wait()
 }
+
+// CopyFrom copies a file from the remote over SSH, using the scp source protocol.
+func (s *SSHRunner) CopyFrom(src string, dst string) error {
+	klog.Infof("scp %s <-- %s", dst, src)
+
+	sess, err := s.session()
+	if err != nil {
+		return errors.Wrap(err, "NewSession")
+	}
+	defer func() {
+		if err := sess.Close(); err != nil {
+			if err != io.EOF {
+				klog.Errorf("session close: %v", err)
+			}
+		}
+	}()
+
+	w, err := sess.StdinPipe()
+	if err != nil {
+		return errors.Wrap(err, "StdinPipe")
+	}
+	r, err := sess.StdoutPipe()
+	if err != nil {
+		return errors.Wrap(err, "StdoutPipe")
+	}
+
+	scp := fmt.Sprintf("sudo scp -f %s", shellquote.Join(src))
+	if err := sess.Start(scp); err != nil {
+		return errors.Wrapf(err, "%s", scp)
+	}
+
+	if err := receiveSCP(r, w, dst); err != nil {
+		return errors.Wrapf(err, "%s", scp)
+	}
+	w.Close()
+	return sess.Wait()
+}
+
+// receiveSCP acts as the sink end of a single-file scp transfer, writing the file to dst
+func receiveSCP(r io.Reader, w io.Writer, dst string) error {
+	br := bufio.NewReader(r)
+
+	// Signal that we are ready to receive the file header
+	fmt.Fprint(w, "\x00")
+	header, err := br.ReadString('\n')
+	if err != nil {
+		return errors.Wrap(err, "reading header")
+	}
+	// The header looks like "C0644 1234 name"; anything else is an error message
+	if !strings.HasPrefix(header, "C") {
+		return fmt.Errorf("unexpected response: %q", strings.TrimSpace(strings.TrimLeft(header, "\x01\x02")))
+	}
+	fields := strings.SplitN(strings.TrimSpace(header[1:]), " ", 3)
+	if len(fields) != 3 {
+		return fmt.Errorf("malformed header: %q", header)
+	}
+	perms, err := strconv.ParseUint(fields[0], 8, 32)
+	if err != nil {
+		return errors.Wrapf(err, "parsing permissions %q", fields[0])
+	}
+	size, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		return errors.Wrapf(err, "parsing size %q", fields[1])
+	}
+
+	f, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, os.FileMode(perms))
+	if err != nil {
+		return errors.Wrap(err, "create")
+	}
+	defer f.Close()
+
+	fmt.Fprint(w, "\x00")
+	copied, err := io.CopyN(f, br, size)
+	if err != nil {
+		return errors.Wrap(err, "io.Copy")
+	}
+	if copied != size {
+		return fmt.Errorf("%s: expected to copy %d bytes, but copied %d instead", dst, size, copied)
+	}
+	if b, err := br.ReadByte(); err != nil || b != 0 {
+		return fmt.Errorf("%s: transfer was not terminated cleanly: %v", dst, err)
+	}
+	fmt.Fprint(w, "\x00")
+
+	if err := f.Chmod(os.FileMode(perms)); err != nil {
+		return errors.Wrap(err, "chmod")
+	}
+	return f.Close()
+}
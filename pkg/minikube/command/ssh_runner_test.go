@@ -19,6 +19,9 @@ package command
 import (
 	"bytes"
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 	"testing"
@@ -61,3 +64,57 @@ func TestTeePrefix(t *testing.T) {
 		t.Errorf("log=%q, want: %q", gotLog, wantLog)
 	}
 }
+
+func TestReceiveSCP(t *testing.T) {
+	dir, err := ioutil.TempDir("", "scp")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		name    string
+		in      string
+		want    string
+		perms   os.FileMode
+		wantErr bool
+	}{
+		{"file", "C0640 5 hello.txt\nhello\x00", "hello", 0640, false},
+		{"empty", "C0755 0 empty\n\x00", "", 0755, false},
+		{"error", "\x01scp: /missing: No such file or directory\n", "", 0, true},
+		{"truncated", "C0644 10 short\nabc", "", 0, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dst := filepath.Join(dir, tc.name)
+			var acks bytes.Buffer
+			err := receiveSCP(strings.NewReader(tc.in), &acks, dst)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("receiveSCP(%q) succeeded, want error", tc.in)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("receiveSCP(%q): %v", tc.in, err)
+			}
+			got, err := ioutil.ReadFile(dst)
+			if err != nil {
+				t.Fatalf("read: %v", err)
+			}
+			if string(got) != tc.want {
+				t.Errorf("contents=%q, want: %q", got, tc.want)
+			}
+			fi, err := os.Stat(dst)
+			if err != nil {
+				t.Fatalf("stat: %v", err)
+			}
+			if fi.Mode().Perm() != tc.perms {
+				t.Errorf("perms=%o, want: %o", fi.Mode().Perm(), tc.perms)
+			}
+			if acks.String() != "\x00\x00\x00" {
+				t.Errorf("acks=%q, want three", acks.String())
+			}
+		})
+	}
+}
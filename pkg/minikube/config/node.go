@@ -0,0 +1,134 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/blang/semver"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// maxNodeVersionSkew is the number of minor versions a kubelet may lag behind the control plane
+const maxNodeVersionSkew = 2
+
+// taintEffects are the valid effects of a node taint
+var taintEffects = []string{"NoSchedule", "PreferNoSchedule", "NoExecute"}
+
+// kubeletLabelNamespaces are the namespaces within kubernetes.io and k8s.io which a kubelet may set labels in
+var kubeletLabelNamespaces = []string{"kubelet.kubernetes.io", "node.kubernetes.io"}
+
+// ParseNodeLabels parses labels of the form key=value, as accepted by the kubelet --node-labels flag
+func ParseNodeLabels(labels []string) (map[string]string, error) {
+	m := map[string]string{}
+	for _, l := range labels {
+		kv := strings.SplitN(l, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid label %q, expected key=value", l)
+		}
+		if err := validateNodeLabel(kv[0], kv[1]); err != nil {
+			return nil, err
+		}
+		m[kv[0]] = kv[1]
+	}
+	return m, nil
+}
+
+// validateNodeLabel checks a label which the kubelet will set upon registering the node
+func validateNodeLabel(key string, value string) error {
+	if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+		return fmt.Errorf("invalid label key %q: %s", key, strings.Join(errs, "; "))
+	}
+	if errs := validation.IsValidLabelValue(value); len(errs) > 0 {
+		return fmt.Errorf("invalid label value %q: %s", value, strings.Join(errs, "; "))
+	}
+
+	if i := strings.Index(key, "/"); i > 0 {
+		ns := key[:i]
+		if !restrictedLabelNamespace(ns) {
+			return nil
+		}
+		for _, allowed := range kubeletLabelNamespaces {
+			if ns == allowed || strings.HasSuffix(ns, "."+allowed) {
+				return nil
+			}
+		}
+		return fmt.Errorf("invalid label key %q: the kubelet may only set labels within the %s namespaces of kubernetes.io", key, strings.Join(kubeletLabelNamespaces, " and "))
+	}
+	return nil
+}
+
+// restrictedLabelNamespace returns whether a label namespace is reserved by Kubernetes
+func restrictedLabelNamespace(ns string) bool {
+	for _, r := range []string{"kubernetes.io", "k8s.io"} {
+		if ns == r || strings.HasSuffix(ns, "."+r) {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateNodeTaint checks a taint of the form key[=value]:effect, as accepted by the kubelet --register-with-taints flag
+func ValidateNodeTaint(taint string) error {
+	i := strings.LastIndex(taint, ":")
+	if i < 0 {
+		return fmt.Errorf("invalid taint %q, expected key[=value]:effect", taint)
+	}
+	kv, effect := taint[:i], taint[i+1:]
+
+	valid := false
+	for _, e := range taintEffects {
+		if effect == e {
+			valid = true
+		}
+	}
+	if !valid {
+		return fmt.Errorf("invalid taint %q: effect must be one of %s", taint, strings.Join(taintEffects, ", "))
+	}
+
+	parts := strings.SplitN(kv, "=", 2)
+	if errs := validation.IsQualifiedName(parts[0]); len(errs) > 0 {
+		return fmt.Errorf("invalid taint key %q: %s", parts[0], strings.Join(errs, "; "))
+	}
+	if len(parts) == 2 {
+		if errs := validation.IsValidLabelValue(parts[1]); len(errs) > 0 {
+			return fmt.Errorf("invalid taint value %q: %s", parts[1], strings.Join(errs, "; "))
+		}
+	}
+	return nil
+}
+
+// ValidateNodeVersion checks that a node may run the given Kubernetes version alongside a control plane running clusterVersion
+func ValidateNodeVersion(nodeVersion string, clusterVersion string) error {
+	nv, err := semver.ParseTolerant(nodeVersion)
+	if err != nil {
+		return fmt.Errorf("invalid Kubernetes version %q: %v", nodeVersion, err)
+	}
+	cv, err := semver.ParseTolerant(clusterVersion)
+	if err != nil {
+		return fmt.Errorf("invalid Kubernetes version %q: %v", clusterVersion, err)
+	}
+
+	if nv.GT(cv) {
+		return fmt.Errorf("node version %s must not be newer than the control plane version %s", nodeVersion, clusterVersion)
+	}
+	if nv.Major != cv.Major || cv.Minor-nv.Minor > maxNodeVersionSkew {
+		return fmt.Errorf("node version %s must be within %d minor versions of the control plane version %s", nodeVersion, maxNodeVersionSkew, clusterVersion)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseNodeLabels(t *testing.T) {
+	tests := []struct {
+		labels  []string
+		want    map[string]string
+		wantErr bool
+	}{
+		{[]string{"pool=gpu", "tier="}, map[string]string{"pool": "gpu", "tier": ""}, false},
+		{[]string{"node.kubernetes.io/instance-type=large"}, map[string]string{"node.kubernetes.io/instance-type": "large"}, false},
+		{[]string{"example.com/team=infra"}, map[string]string{"example.com/team": "infra"}, false},
+		{[]string{"pool"}, nil, true},
+		{[]string{"-pool=gpu"}, nil, true},
+		{[]string{"pool=not valid"}, nil, true},
+		{[]string{"kubernetes.io/role=gpu"}, nil, true},
+		{[]string{"node-role.kubernetes.io/worker="}, nil, true},
+	}
+	for _, tc := range tests {
+		got, err := ParseNodeLabels(tc.labels)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("ParseNodeLabels(%v) error = %v, wantErr %v", tc.labels, err, tc.wantErr)
+			continue
+		}
+		if !tc.wantErr && !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("ParseNodeLabels(%v) = %v, want %v", tc.labels, got, tc.want)
+		}
+	}
+}
+
+func TestValidateNodeTaint(t *testing.T) {
+	tests := []struct {
+		taint   string
+		wantErr bool
+	}{
+		{"dedicated=gpu:NoSchedule", false},
+		{"spot:PreferNoSchedule", false},
+		{"example.com/maintenance=true:NoExecute", false},
+		{"dedicated=gpu", true},
+		{"dedicated=gpu:Never", true},
+		{":NoSchedule", true},
+		{"dedicated=not valid:NoSchedule", true},
+	}
+	for _, tc := range tests {
+		if err := ValidateNodeTaint(tc.taint); (err != nil) != tc.wantErr {
+			t.Errorf("ValidateNodeTaint(%q) error = %v, wantErr %v", tc.taint, err, tc.wantErr)
+		}
+	}
+}
+
+func TestValidateNodeVersion(t *testing.T) {
+	tests := []struct {
+		node    string
+		cluster string
+		wantErr bool
+	}{
+		{"v1.20.0", "v1.20.0", false},
+		{"v1.18.8", "v1.20.0", false},
+		{"1.19.4", "v1.20.0", false},
+		{"v1.17.11", "v1.20.0", true},
+		{"v1.20.1", "v1.20.0", true},
+		{"v1.21.0", "v1.20.0", true},
+		{"latest", "v1.20.0", true},
+	}
+	for _, tc := range tests {
+		if err := ValidateNodeVersion(tc.node, tc.cluster); (err != nil) != tc.wantErr {
+			t.Errorf("ValidateNodeVersion(%q, %q) error = %v, wantErr %v", tc.node, tc.cluster, err, tc.wantErr)
+		}
+	}
+}
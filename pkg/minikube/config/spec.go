@@ -0,0 +1,356 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/blang/semver"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/util"
+)
+
+const (
+	// SpecAPIVersion is the version of the cluster spec schema
+	SpecAPIVersion = "minikube.sigs.k8s.io/v1alpha1"
+	// SpecKind is the kind of a cluster spec
+	SpecKind = "Cluster"
+)
+
+// ClusterSpec is the declarative, versioned description of a cluster, as accepted by `minikube start -f`
+type ClusterSpec struct {
+	APIVersion         string      `yaml:"apiVersion"`
+	Kind               string      `yaml:"kind"`
+	Name               string      `yaml:"name,omitempty"`
+	Driver             string      `yaml:"driver,omitempty"`
+	ContainerRuntime   string      `yaml:"containerRuntime,omitempty"`
+	KubernetesVersion  string      `yaml:"kubernetesVersion,omitempty"`
+	CPUs               int         `yaml:"cpus,omitempty"`
+	Memory             string      `yaml:"memory,omitempty"`
+	DiskSize           string      `yaml:"diskSize,omitempty"`
+	HA                 bool        `yaml:"ha,omitempty"`
+	CNI                string      `yaml:"cni,omitempty"`
+	IPFamily           string      `yaml:"ipFamily,omitempty"`
+	Nodes              []NodeSpec  `yaml:"nodes,omitempty"`
+	Addons             []AddonSpec `yaml:"addons,omitempty"`
+	ExtraConfig        []string    `yaml:"extraConfig,omitempty"`
+	FeatureGates       string      `yaml:"featureGates,omitempty"`
+	Mounts             []MountSpec `yaml:"mounts,omitempty"`
+	RegistryMirrors    []string    `yaml:"registryMirrors,omitempty"`
+	InsecureRegistries []string    `yaml:"insecureRegistries,omitempty"`
+	Ports              []string    `yaml:"ports,omitempty"`
+}
+
+// NodeSpec describes a node of a cluster spec. The first node is the primary control plane.
+type NodeSpec struct {
+	CPUs     int               `yaml:"cpus,omitempty"`
+	Memory   string            `yaml:"memory,omitempty"`
+	DiskSize string            `yaml:"diskSize,omitempty"`
+	Labels   map[string]string `yaml:"labels,omitempty"`
+	Taints   []string          `yaml:"taints,omitempty"`
+}
+
+// AddonSpec describes an addon to enable, along with its configuration
+type AddonSpec struct {
+	Name   string            `yaml:"name"`
+	Config map[string]string `yaml:"config,omitempty"`
+}
+
+// MountSpec describes a host directory to mount into the cluster, which is kept mounted as minikube mount --persist does
+type MountSpec struct {
+	HostPath  string `yaml:"hostPath"`
+	GuestPath string `yaml:"guestPath"`
+	// Type is the filesystem type of the mount, 9p unless set
+	Type     string `yaml:"type,omitempty"`
+	ReadOnly bool   `yaml:"readOnly,omitempty"`
+}
+
+// addonSpecConfig are the configuration keys each addon accepts within a cluster spec
+var addonSpecConfig = map[string]map[string]func(*KubernetesConfig) *string{
+	"metallb": {
+		"loadBalancerStartIP": func(k *KubernetesConfig) *string { return &k.LoadBalancerStartIP },
+		"loadBalancerEndIP":   func(k *KubernetesConfig) *string { return &k.LoadBalancerEndIP },
+	},
+	"ingress": {
+		"customCert": func(k *KubernetesConfig) *string { return &k.CustomIngressCert },
+	},
+}
+
+// LoadClusterSpec reads and validates a cluster spec file
+func LoadClusterSpec(path string) (*ClusterSpec, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "read")
+	}
+	return ParseClusterSpec(b)
+}
+
+// ParseClusterSpec parses and validates a cluster spec
+func ParseClusterSpec(b []byte) (*ClusterSpec, error) {
+	s := &ClusterSpec{}
+	if err := yaml.UnmarshalStrict(b, s); err != nil {
+		return nil, errors.Wrap(err, "parse")
+	}
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Validate checks the fields of a cluster spec which can be checked without knowledge of drivers, runtimes or addons
+func (s *ClusterSpec) Validate() error {
+	if s.APIVersion != SpecAPIVersion {
+		return fmt.Errorf("apiVersion: unsupported version %q, expected %q", s.APIVersion, SpecAPIVersion)
+	}
+	if s.Kind != SpecKind {
+		return fmt.Errorf("kind: unsupported kind %q, expected %q", s.Kind, SpecKind)
+	}
+	if s.Name != "" && !ProfileNameValid(s.Name) {
+		return fmt.Errorf("name: invalid profile name %q", s.Name)
+	}
+	if s.KubernetesVersion != "" && s.KubernetesVersion != "stable" && s.KubernetesVersion != "latest" {
+		if _, err := semver.ParseTolerant(s.KubernetesVersion); err != nil {
+			return fmt.Errorf("kubernetesVersion: invalid version %q: %v", s.KubernetesVersion, err)
+		}
+	}
+	if s.CPUs < 0 {
+		return fmt.Errorf("cpus: must not be negative")
+	}
+	switch s.IPFamily {
+	case "", constants.IPv4Family, constants.IPv6Family, constants.DualStackFamily:
+	default:
+		return fmt.Errorf("ipFamily: invalid IP family %q, expected ipv4, ipv6 or dual", s.IPFamily)
+	}
+	if err := validateSize("memory", s.Memory); err != nil {
+		return err
+	}
+	if err := validateSize("diskSize", s.DiskSize); err != nil {
+		return err
+	}
+
+	for i, n := range s.Nodes {
+		if n.CPUs < 0 {
+			return fmt.Errorf("nodes[%d].cpus: must not be negative", i)
+		}
+		if err := validateSize(fmt.Sprintf("nodes[%d].memory", i), n.Memory); err != nil {
+			return err
+		}
+		if err := validateSize(fmt.Sprintf("nodes[%d].diskSize", i), n.DiskSize); err != nil {
+			return err
+		}
+		for k, v := range n.Labels {
+			if err := validateNodeLabel(k, v); err != nil {
+				return fmt.Errorf("nodes[%d].labels: %v", i, err)
+			}
+		}
+		for _, t := range n.Taints {
+			if err := ValidateNodeTaint(t); err != nil {
+				return fmt.Errorf("nodes[%d].taints: %v", i, err)
+			}
+		}
+	}
+
+	seen := map[string]bool{}
+	for i, a := range s.Addons {
+		if a.Name == "" {
+			return fmt.Errorf("addons[%d].name: must not be empty", i)
+		}
+		if seen[a.Name] {
+			return fmt.Errorf("addons[%d].name: %s is listed more than once", i, a.Name)
+		}
+		seen[a.Name] = true
+		for k, v := range a.Config {
+			if _, ok := addonSpecConfig[a.Name][k]; !ok {
+				return fmt.Errorf("addons[%d].config: %s does not accept %q, valid keys: %s", i, a.Name, k, strings.Join(addonSpecKeys(a.Name), ", "))
+			}
+			if strings.HasSuffix(k, "IP") && net.ParseIP(v) == nil {
+				return fmt.Errorf("addons[%d].config.%s: invalid IP %q", i, k, v)
+			}
+		}
+	}
+
+	for i, e := range s.ExtraConfig {
+		if err := (&ExtraOptionSlice{}).Set(e); err != nil {
+			return fmt.Errorf("extraConfig[%d]: %v", i, err)
+		}
+	}
+
+	guestPaths := map[string]bool{}
+	for i, m := range s.Mounts {
+		if m.HostPath == "" || m.GuestPath == "" {
+			return fmt.Errorf("mounts[%d]: hostPath and guestPath are both required", i)
+		}
+		if !strings.HasPrefix(m.GuestPath, "/") {
+			return fmt.Errorf("mounts[%d].guestPath: must be absolute", i)
+		}
+		if guestPaths[m.GuestPath] {
+			return fmt.Errorf("mounts[%d].guestPath: %s is already mounted", i, m.GuestPath)
+		}
+		guestPaths[m.GuestPath] = true
+	}
+	return nil
+}
+
+// validateSize checks a human readable size, such as 4g or 2048mb
+func validateSize(field string, size string) error {
+	if size == "" {
+		return nil
+	}
+	mb, err := util.CalculateSizeInMB(size)
+	if err != nil {
+		return fmt.Errorf("%s: invalid size %q: %v", field, size, err)
+	}
+	if mb <= 0 {
+		return fmt.Errorf("%s: must be positive", field)
+	}
+	return nil
+}
+
+// addonSpecKeys returns the configuration keys an addon accepts within a cluster spec
+func addonSpecKeys(name string) []string {
+	keys := []string{}
+	for k := range addonSpecConfig[name] {
+		keys = append(keys, k)
+	}
+	if len(keys) == 0 {
+		return []string{"none"}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ApplyAddonConfig sets the addon configuration of a cluster spec within a Kubernetes config
+func (s *ClusterSpec) ApplyAddonConfig(k *KubernetesConfig) {
+	if s == nil {
+		return
+	}
+	for _, a := range s.Addons {
+		for key, v := range a.Config {
+			*addonSpecConfig[a.Name][key](k) = v
+		}
+	}
+}
+
+// ApplyNode sets the resources, labels and taints of the i-th node of a cluster spec within n
+func (s *ClusterSpec) ApplyNode(i int, n *Node) {
+	if s == nil || i >= len(s.Nodes) {
+		return
+	}
+	ns := s.Nodes[i]
+	if ns.CPUs > 0 {
+		n.CPUs = ns.CPUs
+	}
+	if ns.Memory != "" {
+		// validated by Validate
+		n.Memory, _ = util.CalculateSizeInMB(ns.Memory)
+	}
+	if ns.DiskSize != "" {
+		n.DiskSize, _ = util.CalculateSizeInMB(ns.DiskSize)
+	}
+	n.Labels = ns.Labels
+	n.Taints = ns.Taints
+}
+
+// ClusterSpecFromConfig describes an existing cluster as a cluster spec
+func ClusterSpecFromConfig(cc ClusterConfig) *ClusterSpec {
+	s := &ClusterSpec{
+		APIVersion:         SpecAPIVersion,
+		Kind:               SpecKind,
+		Name:               cc.Name,
+		Driver:             cc.Driver,
+		ContainerRuntime:   cc.KubernetesConfig.ContainerRuntime,
+		KubernetesVersion:  cc.KubernetesConfig.KubernetesVersion,
+		CPUs:               cc.CPUs,
+		HA:                 cc.HA,
+		CNI:                cc.KubernetesConfig.CNI,
+		IPFamily:           cc.KubernetesConfig.IPFamily,
+		FeatureGates:       cc.KubernetesConfig.FeatureGates,
+		RegistryMirrors:    cc.RegistryMirror,
+		InsecureRegistries: cc.InsecureRegistry,
+		Ports:              cc.ExposedPorts,
+	}
+	if cc.Memory > 0 {
+		s.Memory = fmt.Sprintf("%dmb", cc.Memory)
+	}
+	if cc.DiskSize > 0 {
+		s.DiskSize = fmt.Sprintf("%dmb", cc.DiskSize)
+	}
+
+	for _, n := range cc.Nodes {
+		ns := NodeSpec{CPUs: n.CPUs, Labels: n.Labels, Taints: n.Taints}
+		if n.Memory > 0 {
+			ns.Memory = fmt.Sprintf("%dmb", n.Memory)
+		}
+		if n.DiskSize > 0 {
+			ns.DiskSize = fmt.Sprintf("%dmb", n.DiskSize)
+		}
+		s.Nodes = append(s.Nodes, ns)
+	}
+
+	names := []string{}
+	for name, enabled := range cc.Addons {
+		if enabled {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		a := AddonSpec{Name: name}
+		for key, field := range addonSpecConfig[name] {
+			if v := *field(&cc.KubernetesConfig); v != "" {
+				if a.Config == nil {
+					a.Config = map[string]string{}
+				}
+				a.Config[key] = v
+			}
+		}
+		s.Addons = append(s.Addons, a)
+	}
+
+	for _, e := range cc.KubernetesConfig.ExtraOptions {
+		s.ExtraConfig = append(s.ExtraConfig, e.String())
+	}
+
+	for _, m := range cc.Mounts {
+		s.Mounts = append(s.Mounts, MountSpec{HostPath: m.HostPath, GuestPath: m.GuestPath, Type: m.Type, ReadOnly: m.ReadOnly})
+	}
+	// mounts of minikube start --mount, which are exported as persistent mounts
+	if cc.Mount {
+		// the host path may itself contain a colon, such as C:\Users on Windows
+		if i := strings.LastIndex(cc.MountString, ":"); i > 0 && !mountedAt(s.Mounts, cc.MountString[i+1:]) {
+			s.Mounts = append(s.Mounts, MountSpec{HostPath: cc.MountString[:i], GuestPath: cc.MountString[i+1:]})
+		}
+	}
+	return s
+}
+
+// mountedAt tells if one of mounts mounts a directory at guestPath
+func mountedAt(mounts []MountSpec, guestPath string) bool {
+	for _, m := range mounts {
+		if m.GuestPath == guestPath {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,182 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v2"
+)
+
+const testSpec = `apiVersion: minikube.sigs.k8s.io/v1alpha1
+kind: Cluster
+name: dev
+driver: docker
+containerRuntime: containerd
+kubernetesVersion: v1.20.0
+cpus: 2
+memory: 4g
+cni: calico
+nodes:
+  - cpus: 4
+    memory: 8g
+  - diskSize: 40g
+    labels:
+      pool: gpu
+    taints:
+      - dedicated=gpu:NoSchedule
+addons:
+  - name: ingress
+  - name: metallb
+    config:
+      loadBalancerStartIP: 192.168.49.100
+      loadBalancerEndIP: 192.168.49.120
+extraConfig:
+  - kubelet.max-pods=200
+mounts:
+  - hostPath: /home/dev/src
+    guestPath: /src
+  - hostPath: /home/dev/docs
+    guestPath: /docs
+    type: sync
+    readOnly: true
+registryMirrors:
+  - https://mirror.example.com
+ports:
+  - 8080:80
+`
+
+func TestParseClusterSpec(t *testing.T) {
+	s, err := ParseClusterSpec([]byte(testSpec))
+	if err != nil {
+		t.Fatalf("ParseClusterSpec: %v", err)
+	}
+
+	n := Node{}
+	s.ApplyNode(0, &n)
+	if n.CPUs != 4 || n.Memory != 8192 {
+		t.Errorf("ApplyNode(0) = cpus %d, memory %d, want 4, 8192", n.CPUs, n.Memory)
+	}
+	n = Node{}
+	s.ApplyNode(1, &n)
+	s.ApplyNode(5, &n)
+	if n.CPUs != 0 || n.Memory != 0 {
+		t.Errorf("ApplyNode(1) = cpus %d, memory %d, want the cluster defaults", n.CPUs, n.Memory)
+	}
+	if n.DiskSize != 40960 || n.Labels["pool"] != "gpu" || len(n.Taints) != 1 {
+		t.Errorf("ApplyNode(1) = disk %d, labels %v, taints %v, want 40960, pool=gpu, dedicated=gpu:NoSchedule", n.DiskSize, n.Labels, n.Taints)
+	}
+
+	k := KubernetesConfig{}
+	s.ApplyAddonConfig(&k)
+	if k.LoadBalancerStartIP != "192.168.49.100" || k.LoadBalancerEndIP != "192.168.49.120" {
+		t.Errorf("ApplyAddonConfig() = %s-%s, want 192.168.49.100-192.168.49.120", k.LoadBalancerStartIP, k.LoadBalancerEndIP)
+	}
+}
+
+func TestParseClusterSpecErrors(t *testing.T) {
+	header := "apiVersion: minikube.sigs.k8s.io/v1alpha1\nkind: Cluster\n"
+	tests := []struct {
+		name    string
+		spec    string
+		wantErr string
+	}{
+		{"wrong version", "apiVersion: minikube.sigs.k8s.io/v2\nkind: Cluster\n", "apiVersion"},
+		{"wrong kind", "apiVersion: minikube.sigs.k8s.io/v1alpha1\nkind: Node\n", "kind"},
+		{"unknown field", header + "cpu: 2\n", "field cpu not found"},
+		{"invalid name", header + "name: -dev\n", "name"},
+		{"invalid version", header + "kubernetesVersion: one\n", "kubernetesVersion"},
+		{"invalid memory", header + "memory: lots\n", "memory"},
+		{"invalid node memory", header + "nodes:\n  - memory: 8x\n", "nodes[0].memory"},
+		{"negative node cpus", header + "nodes:\n  - {}\n  - cpus: -1\n", "nodes[1].cpus"},
+		{"restricted node label", header + "nodes:\n  - labels:\n      kubernetes.io/role: gpu\n", "nodes[0].labels"},
+		{"invalid node taint", header + "nodes:\n  - taints: [dedicated=gpu]\n", "nodes[0].taints"},
+		{"duplicate addon", header + "addons:\n  - name: ingress\n  - name: ingress\n", "addons[1].name"},
+		{"unknown addon config", header + "addons:\n  - name: ingress\n    config:\n      foo: bar\n", "customCert"},
+		{"invalid addon IP", header + "addons:\n  - name: metallb\n    config:\n      loadBalancerStartIP: nope\n", "invalid IP"},
+		{"invalid extra config", header + "extraConfig:\n  - kubelet\n", "extraConfig[0]"},
+		{"invalid IP family", header + "ipFamily: ipv5\n", "ipFamily"},
+		{"relative guest path", header + "mounts:\n  - hostPath: /src\n    guestPath: src\n", "mounts[0].guestPath"},
+		{"duplicate guest path", header + "mounts:\n  - {hostPath: /a, guestPath: /a}\n  - {hostPath: /b, guestPath: /a}\n", "mounts[1].guestPath"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseClusterSpec([]byte(tc.spec))
+			if err == nil {
+				t.Fatalf("ParseClusterSpec succeeded, want error containing %q", tc.wantErr)
+			}
+			if !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("ParseClusterSpec() error = %v, want error containing %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestClusterSpecRoundTrip(t *testing.T) {
+	s, err := ParseClusterSpec([]byte(testSpec))
+	if err != nil {
+		t.Fatalf("ParseClusterSpec: %v", err)
+	}
+
+	cc := ClusterConfig{
+		Name:   "dev",
+		Driver: "docker",
+		CPUs:   2,
+		Memory: 4096,
+		// the mount of minikube start --mount is exported too, unless it is a persistent mount already
+		Mount:       true,
+		MountString: "/home/dev/src:/src",
+		Mounts: []Mount{
+			{HostPath: "/home/dev/src", GuestPath: "/src"},
+			{HostPath: "/home/dev/docs", GuestPath: "/docs", Type: "sync", ReadOnly: true},
+		},
+		RegistryMirror:   []string{"https://mirror.example.com"},
+		ExposedPorts:     []string{"8080:80"},
+		Addons:           map[string]bool{"metallb": true, "ingress": true, "dashboard": false},
+		KubernetesConfig: KubernetesConfig{KubernetesVersion: "v1.20.0", ContainerRuntime: "containerd", CNI: "calico"},
+	}
+	for i := 0; i < len(s.Nodes); i++ {
+		n := Node{}
+		s.ApplyNode(i, &n)
+		cc.Nodes = append(cc.Nodes, n)
+	}
+	s.ApplyAddonConfig(&cc.KubernetesConfig)
+	for _, e := range s.ExtraConfig {
+		if err := cc.KubernetesConfig.ExtraOptions.Set(e); err != nil {
+			t.Fatalf("extra config: %v", err)
+		}
+	}
+
+	b, err := yaml.Marshal(ClusterSpecFromConfig(cc))
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	got, err := ParseClusterSpec(b)
+	if err != nil {
+		t.Fatalf("exported spec is invalid: %v\n%s", err, b)
+	}
+
+	// sizes are exported in megabytes
+	want := *s
+	want.Memory = "4096mb"
+	want.Nodes = []NodeSpec{{CPUs: 4, Memory: "8192mb"}, {DiskSize: "40960mb", Labels: s.Nodes[1].Labels, Taints: s.Nodes[1].Taints}}
+	if !reflect.DeepEqual(*got, want) {
+		t.Errorf("round trip mismatch:\ngot  %+v\nwant %+v", *got, want)
+	}
+}
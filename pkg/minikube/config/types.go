@@ -73,7 +73,16 @@ type ClusterConfig struct {
 	StartHostTimeout        time.Duration
 	ScheduledStop           *ScheduledStopConfig
 	ExposedPorts            []string // Only used by the docker and podman driver
+	Network                 string   // Only used by the docker and podman driver: network to attach to, defaults to a network named after the cluster
+	Subnet                  string   // Only used by the docker and podman driver: subnet of the network minikube creates
+	StaticIP                string   // Only used by the docker and podman driver: IP of the primary node
 	MultiNodeRequested      bool
+	HA                      bool // Highly available: multiple control planes fronted by KubernetesConfig.APIServerHAVIP
+	Mount                   bool
+	MountString             string
+	Mounts                  []Mount // persistent mounts, kept mounted by supervised background minikube mount processes
+	Registry                bool    // Local image registry, exposed on the host at RegistryHostPort
+	RegistryHostPort        int
 }
 
 // KubernetesConfig contains the parameters used to configure the VM Kubernetes.
@@ -84,12 +93,14 @@ type KubernetesConfig struct {
 	APIServerName       string
 	APIServerNames      []string
 	APIServerIPs        []net.IP
+	APIServerHAVIP      string // virtual IP fronting every control plane, only used by HA clusters
 	DNSDomain           string
 	ContainerRuntime    string
 	CRISocket           string
 	NetworkPlugin       string
 	FeatureGates        string // https://kubernetes.io/docs/reference/command-line-tools-reference/feature-gates/
-	ServiceCIDR         string // the subnet which Kubernetes services will be deployed to
+	ServiceCIDR         string // the subnet which Kubernetes services will be deployed to, comma separated for dual-stack clusters
+	IPFamily            string // ipv4, ipv6 or dual, empty for ipv4
 	ImageRepository     string
 	LoadBalancerStartIP string // currently only used by MetalLB addon
 	LoadBalancerEndIP   string // currently only used by MetalLB addon
@@ -115,6 +126,11 @@ type Node struct {
 	KubernetesVersion string
 	ControlPlane      bool
 	Worker            bool
+	CPUs              int // overrides ClusterConfig.CPUs when set
+	Memory            int // overrides ClusterConfig.Memory when set
+	DiskSize          int // overrides ClusterConfig.DiskSize when set
+	Labels            map[string]string
+	Taints            []string
 }
 
 // VersionedExtraOption holds information on flags to apply to a specific range
@@ -142,6 +158,28 @@ type VersionedExtraOption struct {
 	GreaterThanOrEqual semver.Version
 }
 
+// Mount is a persistent mount of a host directory, added with minikube mount --persist. Its fields are the flags of minikube mount.
+type Mount struct {
+	HostPath      string
+	GuestPath     string
+	Type          string
+	IP            string
+	UID           string
+	GID           string
+	Mode          uint
+	MSize         int
+	Version       string // 9p version
+	Options       []string
+	Notify        bool
+	ReadOnly      bool
+	Allow         []string
+	Umask         uint
+	Auth          bool
+	SyncDirection string
+	SyncInterval  time.Duration
+	SyncIgnore    []string
+}
+
 // ScheduledStopConfig contains information around scheduled stop
 // not yet used, will be used to show status of scheduled stop
 type ScheduledStopConfig struct {
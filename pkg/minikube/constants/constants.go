@@ -52,10 +52,20 @@ const (
 	ClusterDNSDomain = "cluster.local"
 	// DefaultServiceCIDR is The CIDR to be used for service cluster IPs
 	DefaultServiceCIDR = "10.96.0.0/12"
+	// DefaultServiceCIDRv6 is the CIDR to be used for IPv6 service cluster IPs
+	DefaultServiceCIDRv6 = "fd00:10:96::/112"
+	// IPv4Family is the IP family of IPv4 only clusters, which is the default
+	IPv4Family = "ipv4"
+	// IPv6Family is the IP family of IPv6 only clusters
+	IPv6Family = "ipv6"
+	// DualStackFamily is the IP family of dual-stack clusters, with both IPv4 and IPv6 pod and service networks
+	DualStackFamily = "dual"
 	// HostAlias is a DNS alias to the the container/VM host IP
 	HostAlias = "host.minikube.internal"
 	// ControlPlaneAlias is a DNS alias pointing to the apiserver frontend
 	ControlPlaneAlias = "control-plane.minikube.internal"
+	// RegistryAlias is a DNS alias to the registry addon, resolvable from every node of a cluster started with --registry
+	RegistryAlias = "registry.minikube"
 
 	// DockerHostEnv is used for docker daemon settings
 	DockerHostEnv = "DOCKER_HOST"
@@ -75,6 +85,8 @@ const (
 	// MinikubeActivePodmanEnv holds the podman service that the user's shell is pointing at
 	// value would be profile or empty if pointing to the user's host.
 	MinikubeActivePodmanEnv = "MINIKUBE_ACTIVE_PODMAN"
+	// MinikubeActiveKubeconfigEnv holds the profile whose kubeconfig the user's shell is pointing at
+	MinikubeActiveKubeconfigEnv = "MINIKUBE_ACTIVE_KUBECONFIG"
 	// MinikubeForceSystemdEnv is used to force systemd as cgroup manager for the container runtime
 	MinikubeForceSystemdEnv = "MINIKUBE_FORCE_SYSTEMD"
 	// TestDiskUsedEnv is used in integration tests for insufficient storage with 'minikube status'
@@ -96,6 +108,9 @@ const (
 
 	// ExistingContainerHostEnv is used to save original podman environment
 	ExistingContainerHostEnv = MinikubeExistingPrefix + "CONTAINER_HOST"
+
+	// ExistingKubeconfigEnv is used to save original kubectl environment
+	ExistingKubeconfigEnv = MinikubeExistingPrefix + "KUBECONFIG"
 )
 
 var (
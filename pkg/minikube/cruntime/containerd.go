@@ -98,6 +98,10 @@ oom_score = 0
       [plugins.cri.registry.mirrors]
         [plugins.cri.registry.mirrors."docker.io"]
           endpoint = ["https://registry-1.docker.io"]
+{{- range .InsecureRegistry}}
+        [plugins.cri.registry.mirrors."{{.}}"]
+          endpoint = ["http://{{.}}"]
+{{- end}}
   [plugins.diff-service]
     default = ["walking"]
   [plugins.linux]
@@ -121,6 +125,7 @@ type Containerd struct {
 	Runner            CommandRunner
 	ImageRepository   string
 	KubernetesVersion semver.Version
+	InsecureRegistry  []string
 	Init              sysinit.Manager
 }
 
@@ -172,14 +177,27 @@ func (r *Containerd) Available() error {
 }
 
 // generateContainerdConfig sets up /etc/containerd/config.toml
-func generateContainerdConfig(cr CommandRunner, imageRepository string, kv semver.Version) error {
+func generateContainerdConfig(cr CommandRunner, imageRepository string, kv semver.Version, insecureRegistry []string) error {
 	cPath := containerdConfigFile
 	t, err := template.New("containerd.config.toml").Parse(containerdConfigTemplate)
 	if err != nil {
 		return err
 	}
 	pauseImage := images.Pause(kv, imageRepository)
-	opts := struct{ PodInfraContainerImage string }{PodInfraContainerImage: pauseImage}
+	// containerd mirrors registries by host, so the insecure CIDRs which docker accepts are skipped
+	hosts := []string{}
+	for _, r := range insecureRegistry {
+		if !strings.Contains(r, "/") {
+			hosts = append(hosts, r)
+		}
+	}
+	opts := struct {
+		PodInfraContainerImage string
+		InsecureRegistry       []string
+	}{
+		PodInfraContainerImage: pauseImage,
+		InsecureRegistry:       hosts,
+	}
 	var b bytes.Buffer
 	if err := t.Execute(&b, opts); err != nil {
 		return err
@@ -201,7 +219,7 @@ func (r *Containerd) Enable(disOthers, _ bool) error {
 	if err := populateCRIConfig(r.Runner, r.SocketPath()); err != nil {
 		return err
 	}
-	if err := generateContainerdConfig(r.Runner, r.ImageRepository, r.KubernetesVersion); err != nil {
+	if err := generateContainerdConfig(r.Runner, r.ImageRepository, r.KubernetesVersion, r.InsecureRegistry); err != nil {
 		return err
 	}
 	if err := enableIPForwarding(r.Runner); err != nil {
@@ -236,6 +254,73 @@ func (r *Containerd) LoadImage(path string) error {
 	return nil
 }
 
+// ListImages returns a list of images managed by this runtime
+func (r *Containerd) ListImages() ([]string, error) {
+	return listCRIImages(r.Runner)
+}
+
+// PullImage pulls an image into this runtime
+func (r *Containerd) PullImage(name string) error {
+	return pullCRIImage(r.Runner, name)
+}
+
+// PushImage pushes an image from this runtime to its registry
+func (r *Containerd) PushImage(name string) error {
+	klog.Infof("Pushing image: %s", name)
+	c := exec.Command("sudo", "ctr", "-n=k8s.io", "images", "push", name)
+	if _, err := r.Runner.RunCmd(c); err != nil {
+		return errors.Wrapf(err, "ctr images push")
+	}
+	return nil
+}
+
+// RemoveImage removes an image from this runtime
+func (r *Containerd) RemoveImage(name string) error {
+	return removeCRIImage(r.Runner, name)
+}
+
+// SaveImage saves an image from this runtime into a tarball
+func (r *Containerd) SaveImage(name string, path string) error {
+	klog.Infof("Saving image %s: %s", name, path)
+	c := exec.Command("sudo", "ctr", "-n=k8s.io", "images", "export", path, name)
+	if _, err := r.Runner.RunCmd(c); err != nil {
+		return errors.Wrapf(err, "ctr images export")
+	}
+	return nil
+}
+
+// BuildImage builds an image into this runtime using buildkit
+func (r *Containerd) BuildImage(o BuildOptions) error {
+	klog.Infof("Building image: %s", o.Dir)
+	file := o.File
+	if file == "" {
+		file = "Dockerfile"
+	}
+	args := []string{"buildctl", "build",
+		"--frontend", "dockerfile.v0",
+		"--local", fmt.Sprintf("context=%s", o.Dir),
+		"--local", fmt.Sprintf("dockerfile=%s", path.Dir(path.Join(o.Dir, file))),
+		"--opt", fmt.Sprintf("filename=%s", path.Base(file)),
+	}
+	if o.Tag != "" {
+		// https://github.com/moby/buildkit#imageregistry
+		output := fmt.Sprintf("type=image,name=%s", o.Tag)
+		if o.Push {
+			output += ",push=true"
+		}
+		args = append(args, "--output", output)
+	}
+	// buildctl takes the options of the frontend as --opt key=value
+	for _, opt := range o.Opts {
+		args = append(args, "--opt", opt)
+	}
+	c := exec.Command("sudo", append(append([]string{"env"}, o.Env...), args...)...)
+	if rr, err := r.Runner.RunCmd(c); err != nil {
+		return errors.Wrapf(err, "buildctl build: %s", rr.Output())
+	}
+	return nil
+}
+
 // CGroupDriver returns cgroup driver ("cgroupfs" or "systemd")
 func (r *Containerd) CGroupDriver() (string, error) {
 	info, err := getCRIInfo(r.Runner)
@@ -295,13 +380,13 @@ func (r *Containerd) StopContainers(ids []string) error {
 }
 
 // ContainerLogCmd returns the command to retrieve the log for a container based on ID
-func (r *Containerd) ContainerLogCmd(id string, len int, follow bool) string {
-	return criContainerLogCmd(r.Runner, id, len, follow)
+func (r *Containerd) ContainerLogCmd(id string, o LogOptions) string {
+	return criContainerLogCmd(r.Runner, id, o)
 }
 
 // SystemLogCmd returns the command to retrieve system logs
-func (r *Containerd) SystemLogCmd(len int) string {
-	return fmt.Sprintf("sudo journalctl -u containerd -n %d", len)
+func (r *Containerd) SystemLogCmd(o LogOptions) string {
+	return journalctlCmd("containerd", o)
 }
 
 // Preload preloads the container runtime with k8s images
@@ -17,7 +17,10 @@ limitations under the License.
 package cruntime
 
 import (
+	"reflect"
 	"testing"
+
+	"k8s.io/minikube/pkg/minikube/command"
 )
 
 func TestAddRepoTagToImageName(t *testing.T) {
@@ -38,3 +41,28 @@ func TestAddRepoTagToImageName(t *testing.T) {
 		})
 	}
 }
+
+func TestContainerdBuildImageOpts(t *testing.T) {
+	r := command.NewFakeCommandRunner()
+	build := "sudo env buildctl build --frontend dockerfile.v0 --local context=/tmp/build --local dockerfile=/tmp/build --opt filename=Dockerfile --opt build-arg:VERSION=1.0 --opt target=prod"
+	r.SetCommandToOutput(map[string]string{build: ""})
+	cr := &Containerd{Runner: r}
+	if err := cr.BuildImage(BuildOptions{Dir: "/tmp/build", Opts: []string{"build-arg:VERSION=1.0", "target=prod"}}); err != nil {
+		t.Errorf("BuildImage: %v", err)
+	}
+}
+
+func TestListCRIImages(t *testing.T) {
+	r := command.NewFakeCommandRunner()
+	r.SetCommandToOutput(map[string]string{
+		"which crictl": "/usr/local/bin/crictl\n",
+		"sudo /usr/local/bin/crictl images --output json": `{"images": [{"id": "sha256:1", "repoTags": ["k8s.gcr.io/pause:3.2"]}]}`,
+	})
+	got, err := listCRIImages(r)
+	if err != nil {
+		t.Fatalf("listCRIImages: %v", err)
+	}
+	if want := []string{"k8s.gcr.io/pause:3.2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("listCRIImages() = %v, want %v", got, want)
+	}
+}
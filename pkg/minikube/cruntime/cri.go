@@ -243,16 +243,19 @@ func getCRIInfo(cr CommandRunner) (map[string]interface{}, error) {
 }
 
 // criContainerLogCmd returns the command to retrieve the log for a container based on ID
-func criContainerLogCmd(cr CommandRunner, id string, len int, follow bool) string {
+func criContainerLogCmd(cr CommandRunner, id string, o LogOptions) string {
 	crictl := getCrictlPath(cr)
 	var cmd strings.Builder
 	cmd.WriteString("sudo ")
 	cmd.WriteString(crictl)
 	cmd.WriteString(" logs ")
-	if len > 0 {
-		cmd.WriteString(fmt.Sprintf("--tail %d ", len))
+	if o.Lines > 0 {
+		cmd.WriteString(fmt.Sprintf("--tail %d ", o.Lines))
 	}
-	if follow {
+	if o.Since > 0 {
+		cmd.WriteString(fmt.Sprintf("--since %ds ", int(o.Since.Seconds())))
+	}
+	if o.Follow {
 		cmd.WriteString("--follow ")
 	}
 
@@ -271,3 +274,50 @@ func addRepoTagToImageName(imgName string) string {
 	} // else it already has repo name dont add anything
 	return imgName
 }
+
+// listCRIImages returns a list of images, as reported by crictl
+func listCRIImages(cr CommandRunner) ([]string, error) {
+	crictl := getCrictlPath(cr)
+	rr, err := cr.RunCmd(exec.Command("sudo", crictl, "images", "--output", "json"))
+	if err != nil {
+		return nil, errors.Wrap(err, "crictl images")
+	}
+	var jsonImages struct {
+		Images []struct {
+			ID       string   `json:"id"`
+			RepoTags []string `json:"repoTags"`
+		} `json:"images"`
+	}
+	if err := json.Unmarshal(rr.Stdout.Bytes(), &jsonImages); err != nil {
+		return nil, errors.Wrap(err, "unmarshal images")
+	}
+	var images []string
+	for _, img := range jsonImages.Images {
+		images = append(images, img.RepoTags...)
+	}
+	return images, nil
+}
+
+// pullCRIImage pulls an image using crictl
+func pullCRIImage(cr CommandRunner, name string) error {
+	klog.Infof("Pulling image: %s", name)
+
+	crictl := getCrictlPath(cr)
+	c := exec.Command("sudo", crictl, "pull", name)
+	if _, err := cr.RunCmd(c); err != nil {
+		return errors.Wrap(err, "crictl")
+	}
+	return nil
+}
+
+// removeCRIImage removes an image using crictl
+func removeCRIImage(cr CommandRunner, name string) error {
+	klog.Infof("Removing image: %s", name)
+
+	crictl := getCrictlPath(cr)
+	c := exec.Command("sudo", crictl, "rmi", name)
+	if _, err := cr.RunCmd(c); err != nil {
+		return errors.Wrap(err, "crictl")
+	}
+	return nil
+}
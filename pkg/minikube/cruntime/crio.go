@@ -177,6 +177,65 @@ func (r *CRIO) LoadImage(path string) error {
 	return nil
 }
 
+// ListImages returns a list of images managed by this runtime
+func (r *CRIO) ListImages() ([]string, error) {
+	return listCRIImages(r.Runner)
+}
+
+// PullImage pulls an image into this runtime
+func (r *CRIO) PullImage(name string) error {
+	return pullCRIImage(r.Runner, name)
+}
+
+// PushImage pushes an image from this runtime to its registry
+func (r *CRIO) PushImage(name string) error {
+	klog.Infof("Pushing image: %s", name)
+	c := exec.Command("sudo", "podman", "push", name)
+	if _, err := r.Runner.RunCmd(c); err != nil {
+		return errors.Wrap(err, "crio push image")
+	}
+	return nil
+}
+
+// RemoveImage removes an image from this runtime
+func (r *CRIO) RemoveImage(name string) error {
+	return removeCRIImage(r.Runner, name)
+}
+
+// SaveImage saves an image from this runtime into a tarball
+func (r *CRIO) SaveImage(name string, path string) error {
+	klog.Infof("Saving image %s: %s", name, path)
+	c := exec.Command("sudo", "podman", "save", "-o", path, name)
+	if _, err := r.Runner.RunCmd(c); err != nil {
+		return errors.Wrap(err, "crio save image")
+	}
+	return nil
+}
+
+// BuildImage builds an image into this runtime using podman (buildah)
+func (r *CRIO) BuildImage(o BuildOptions) error {
+	klog.Infof("Building image: %s", o.Dir)
+	args := []string{"podman", "build"}
+	if o.File != "" {
+		args = append(args, "-f", path.Join(o.Dir, o.File))
+	}
+	if o.Tag != "" {
+		args = append(args, "-t", o.Tag)
+	}
+	for _, opt := range o.Opts {
+		args = append(args, "--"+opt)
+	}
+	args = append(args, o.Dir)
+	c := exec.Command("sudo", append(append([]string{"env"}, o.Env...), args...)...)
+	if rr, err := r.Runner.RunCmd(c); err != nil {
+		return errors.Wrapf(err, "crio build image: %s", rr.Output())
+	}
+	if o.Tag != "" && o.Push {
+		return r.PushImage(o.Tag)
+	}
+	return nil
+}
+
 // CGroupDriver returns cgroup driver ("cgroupfs" or "systemd")
 func (r *CRIO) CGroupDriver() (string, error) {
 	c := exec.Command("crio", "config")
@@ -233,13 +292,13 @@ func (r *CRIO) StopContainers(ids []string) error {
 }
 
 // ContainerLogCmd returns the command to retrieve the log for a container based on ID
-func (r *CRIO) ContainerLogCmd(id string, len int, follow bool) string {
-	return criContainerLogCmd(r.Runner, id, len, follow)
+func (r *CRIO) ContainerLogCmd(id string, o LogOptions) string {
+	return criContainerLogCmd(r.Runner, id, o)
 }
 
 // SystemLogCmd returns the command to retrieve system logs
-func (r *CRIO) SystemLogCmd(len int) string {
-	return fmt.Sprintf("sudo journalctl -u crio -n %d", len)
+func (r *CRIO) SystemLogCmd(o LogOptions) string {
+	return journalctlCmd("crio", o)
 }
 
 // Preload preloads the container runtime with k8s images
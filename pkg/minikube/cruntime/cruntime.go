@@ -20,6 +20,8 @@ package cruntime
 import (
 	"fmt"
 	"os/exec"
+	"strings"
+	"time"
 
 	"github.com/blang/semver"
 	"k8s.io/klog/v2"
@@ -56,6 +58,8 @@ type CommandRunner interface {
 	RunCmd(cmd *exec.Cmd) (*command.RunResult, error)
 	// Copy is a convenience method that runs a command to copy a file
 	Copy(assets.CopyableFile) error
+	// CopyFrom is a convenience method that runs a command to copy a remote file to a local path
+	CopyFrom(string, string) error
 	// Remove is a convenience method that runs a command to remove a file
 	Remove(assets.CopyableFile) error
 }
@@ -89,6 +93,18 @@ type Manager interface {
 
 	// ImageExists takes image name and image sha checks if an it exists
 	ImageExists(string, string) bool
+	// ListImages returns a list of images managed by this container runtime
+	ListImages() ([]string, error)
+	// PullImage pulls an image into this container runtime
+	PullImage(string) error
+	// PushImage pushes an image from this container runtime to its registry
+	PushImage(string) error
+	// RemoveImage removes an image from this container runtime
+	RemoveImage(string) error
+	// SaveImage saves an image from this container runtime into a tarball
+	SaveImage(string, string) error
+	// BuildImage builds an image from a context directory
+	BuildImage(BuildOptions) error
 
 	// ListContainers returns a list of managed by this container runtime
 	ListContainers(ListOptions) ([]string, error)
@@ -101,9 +117,9 @@ type Manager interface {
 	// UnpauseContainers unpauses containers based on ID
 	UnpauseContainers([]string) error
 	// ContainerLogCmd returns the command to retrieve the log for a container based on ID
-	ContainerLogCmd(string, int, bool) string
+	ContainerLogCmd(string, LogOptions) string
 	// SystemLogCmd returns the command to return the system logs
-	SystemLogCmd(int) string
+	SystemLogCmd(LogOptions) string
 	// Preload preloads the container runtime with k8s images
 	Preload(config.KubernetesConfig) error
 	// ImagesPreloaded returns true if all images have been preloaded
@@ -122,6 +138,8 @@ type Config struct {
 	ImageRepository string
 	// KubernetesVersion Kubernetes version
 	KubernetesVersion semver.Version
+	// InsecureRegistry are the registries which may be pulled from over plain HTTP
+	InsecureRegistry []string
 }
 
 // ListOptions are the options to use for listing containers
@@ -134,6 +152,32 @@ type ListOptions struct {
 	Namespaces []string
 }
 
+// LogOptions are the options to use for retrieving logs
+type LogOptions struct {
+	// Lines is the number of recent log lines to include, as in tail -n.
+	Lines int
+	// Follow is whether or not to actively follow the logs, as in tail -f.
+	Follow bool
+	// Since is how far back to include logs from, if set.
+	Since time.Duration
+}
+
+// BuildOptions are the options to use for building images
+type BuildOptions struct {
+	// Dir is the build context directory, as seen by the runtime
+	Dir string
+	// File is the path to the Dockerfile, relative to Dir
+	File string
+	// Tag is the name to give the resulting image
+	Tag string
+	// Push pushes the resulting image to its registry
+	Push bool
+	// Env is a list of environment variables (KEY=VALUE) passed to the builder
+	Env []string
+	// Opts is a list of additional builder options (key=value), without leading dashes
+	Opts []string
+}
+
 // New returns an appropriately configured runtime
 func New(c Config) (Manager, error) {
 	sm := sysinit.New(c.Runner)
@@ -159,6 +203,7 @@ func New(c Config) (Manager, error) {
 			Runner:            c.Runner,
 			ImageRepository:   c.ImageRepository,
 			KubernetesVersion: c.KubernetesVersion,
+			InsecureRegistry:  c.InsecureRegistry,
 			Init:              sm,
 		}, nil
 	default:
@@ -166,6 +211,23 @@ func New(c Config) (Manager, error) {
 	}
 }
 
+// journalctlCmd returns the journalctl command to retrieve the logs of a systemd unit
+func journalctlCmd(unit string, o LogOptions) string {
+	var cmd strings.Builder
+	cmd.WriteString("sudo journalctl -u ")
+	cmd.WriteString(unit)
+	if o.Lines > 0 {
+		cmd.WriteString(fmt.Sprintf(" -n %d", o.Lines))
+	}
+	if o.Since > 0 {
+		cmd.WriteString(fmt.Sprintf(" --since=-%ds", int(o.Since.Seconds())))
+	}
+	if o.Follow {
+		cmd.WriteString(" -f")
+	}
+	return cmd.String()
+}
+
 // ContainerStatusCommand works across container runtimes with good formatting
 func ContainerStatusCommand() string {
 	// Fallback to 'docker ps' if it fails (none driver)
@@ -22,6 +22,7 @@ import (
 	"os/exec"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -157,6 +158,7 @@ type FakeRunner struct {
 	cmds       []string
 	services   map[string]serviceState
 	containers map[string]string
+	images     map[string]string
 	t          *testing.T
 }
 
@@ -167,6 +169,7 @@ func NewFakeRunner(t *testing.T) *FakeRunner {
 		cmds:       []string{},
 		t:          t,
 		containers: map[string]string{},
+		images:     map[string]string{},
 	}
 }
 
@@ -221,6 +224,10 @@ func (f *FakeRunner) Copy(assets.CopyableFile) error {
 	return nil
 }
 
+func (f *FakeRunner) CopyFrom(string, string) error {
+	return nil
+}
+
 func (f *FakeRunner) Remove(assets.CopyableFile) error {
 	return nil
 }
@@ -277,6 +284,36 @@ func (f *FakeRunner) dockerInspect(args []string) (string, error) {
 	return "", nil
 }
 
+func (f *FakeRunner) dockerImages(args []string) (string, error) {
+	// images --format {{.Repository}}:{{.Tag}}
+	names := []string{}
+	for _, name := range f.images {
+		names = append(names, name)
+	}
+	return strings.Join(names, "\n"), nil
+}
+
+func (f *FakeRunner) dockerRmi(args []string) (string, error) {
+	for _, name := range args[1:] {
+		f.t.Logf("fake docker: Removing image %q", name)
+		if !f.removeImage(name) {
+			return "", fmt.Errorf("no such image")
+		}
+	}
+	return "", nil
+}
+
+// removeImage removes an image by name from the fake image store
+func (f *FakeRunner) removeImage(name string) bool {
+	for id, iname := range f.images {
+		if iname == name {
+			delete(f.images, id)
+			return true
+		}
+	}
+	return false
+}
+
 // docker is a fake implementation of docker
 func (f *FakeRunner) docker(args []string, _ bool) (string, error) {
 	switch cmd := args[0]; cmd {
@@ -289,6 +326,12 @@ func (f *FakeRunner) docker(args []string, _ bool) (string, error) {
 	case "rm":
 		return f.dockerRm(args)
 
+	case "images":
+		return f.dockerImages(args)
+
+	case "rmi":
+		return f.dockerRmi(args)
+
 	case "version":
 
 		if args[1] == "--format" && args[2] == "{{.Server.Version}}" {
@@ -409,6 +452,20 @@ func (f *FakeRunner) crictl(args []string, _ bool) (string, error) {
 			delete(f.containers, id)
 
 		}
+	case "images":
+		// crictl images --output json
+		images := []string{}
+		for id, name := range f.images {
+			images = append(images, fmt.Sprintf(`{"id": %q, "repoTags": [%q]}`, id, name))
+		}
+		return fmt.Sprintf(`{"images": [%s]}`, strings.Join(images, ",")), nil
+	case "rmi":
+		for _, name := range args[1:] {
+			f.t.Logf("fake crictl: Removing image %q", name)
+			if !f.removeImage(name) {
+				return "", fmt.Errorf("no such image")
+			}
+		}
 
 	}
 	return "", nil
@@ -704,3 +761,82 @@ func TestContainerFunctions(t *testing.T) {
 		})
 	}
 }
+
+func TestImageFunctions(t *testing.T) {
+	var tests = []struct {
+		runtime string
+	}{
+		{"docker"},
+		{"crio"},
+		{"containerd"},
+	}
+
+	sortSlices := cmpopts.SortSlices(func(a, b string) bool { return a < b })
+	for _, tc := range tests {
+		t.Run(tc.runtime, func(t *testing.T) {
+			runner := NewFakeRunner(t)
+			runner.images = map[string]string{
+				"sha256:abc0": "docker.io/library/busybox:latest",
+				"sha256:fgh1": "k8s.gcr.io/pause:3.2",
+			}
+			cr, err := New(Config{Type: tc.runtime, Runner: runner})
+			if err != nil {
+				t.Fatalf("New(%s): %v", tc.runtime, err)
+			}
+
+			got, err := cr.ListImages()
+			if err != nil {
+				t.Fatalf("ListImages: %v", err)
+			}
+			want := []string{"docker.io/library/busybox:latest", "k8s.gcr.io/pause:3.2"}
+			if diff := cmp.Diff(got, want, sortSlices); diff != "" {
+				t.Errorf("ListImages() unexpected results, diff (-got + want): %s", diff)
+			}
+
+			// Remove an image and assert that it has disappeared
+			if err := cr.RemoveImage("k8s.gcr.io/pause:3.2"); err != nil {
+				t.Fatalf("RemoveImage: %v", err)
+			}
+			got, err = cr.ListImages()
+			if err != nil {
+				t.Fatalf("ListImages: %v", err)
+			}
+			want = []string{"docker.io/library/busybox:latest"}
+			if diff := cmp.Diff(got, want, sortSlices); diff != "" {
+				t.Errorf("ListImages() unexpected results, diff (-got + want): %s", diff)
+			}
+
+			if err := cr.RemoveImage("missing"); err == nil {
+				t.Errorf("RemoveImage(missing) succeeded, want error")
+			}
+		})
+	}
+}
+
+func TestLogCmds(t *testing.T) {
+	var tests = []struct {
+		runtime   string
+		opts      LogOptions
+		container string
+		system    string
+	}{
+		{"docker", LogOptions{Lines: 60}, "docker logs --tail 60 abc", "sudo journalctl -u docker -n 60"},
+		{"docker", LogOptions{Since: time.Hour, Follow: true}, "docker logs --since 3600s --follow abc", "sudo journalctl -u docker --since=-3600s -f"},
+		{"containerd", LogOptions{Lines: 10, Since: 5 * time.Minute}, "sudo /usr/bin/crictl logs --tail 10 --since 300s abc", "sudo journalctl -u containerd -n 10 --since=-300s"},
+		{"crio", LogOptions{Follow: true}, "sudo /usr/bin/crictl logs --follow abc", "sudo journalctl -u crio -f"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.runtime, func(t *testing.T) {
+			r, err := New(Config{Type: tc.runtime, Runner: NewFakeRunner(t)})
+			if err != nil {
+				t.Fatalf("New(%s): %v", tc.runtime, err)
+			}
+			if got := r.ContainerLogCmd("abc", tc.opts); got != tc.container {
+				t.Errorf("ContainerLogCmd(%+v) = %q, want %q", tc.opts, got, tc.container)
+			}
+			if got := r.SystemLogCmd(tc.opts); got != tc.system {
+				t.Errorf("SystemLogCmd(%+v) = %q, want %q", tc.opts, got, tc.system)
+			}
+		})
+	}
+}
@@ -157,6 +157,87 @@ func (r *Docker) LoadImage(path string) error {
 	return nil
 }
 
+// ListImages returns a list of images managed by this runtime
+func (r *Docker) ListImages() ([]string, error) {
+	c := exec.Command("docker", "images", "--format", "{{.Repository}}:{{.Tag}}")
+	rr, err := r.Runner.RunCmd(c)
+	if err != nil {
+		return nil, errors.Wrap(err, "docker images")
+	}
+	var images []string
+	for _, line := range strings.Split(rr.Stdout.String(), "\n") {
+		if line == "" || strings.Contains(line, "<none>") {
+			continue
+		}
+		images = append(images, line)
+	}
+	return images, nil
+}
+
+// PullImage pulls an image into this runtime
+func (r *Docker) PullImage(name string) error {
+	klog.Infof("Pulling image: %s", name)
+	c := exec.Command("docker", "pull", name)
+	if _, err := r.Runner.RunCmd(c); err != nil {
+		return errors.Wrap(err, "pullimage docker.")
+	}
+	return nil
+}
+
+// PushImage pushes an image from this runtime to its registry
+func (r *Docker) PushImage(name string) error {
+	klog.Infof("Pushing image: %s", name)
+	c := exec.Command("docker", "push", name)
+	if _, err := r.Runner.RunCmd(c); err != nil {
+		return errors.Wrap(err, "pushimage docker.")
+	}
+	return nil
+}
+
+// RemoveImage removes an image from this runtime
+func (r *Docker) RemoveImage(name string) error {
+	klog.Infof("Removing image: %s", name)
+	c := exec.Command("docker", "rmi", name)
+	if _, err := r.Runner.RunCmd(c); err != nil {
+		return errors.Wrap(err, "removeimage docker.")
+	}
+	return nil
+}
+
+// SaveImage saves an image from this runtime into a tarball
+func (r *Docker) SaveImage(name string, path string) error {
+	klog.Infof("Saving image %s: %s", name, path)
+	c := exec.Command("sudo", "docker", "save", "-o", path, name)
+	if _, err := r.Runner.RunCmd(c); err != nil {
+		return errors.Wrap(err, "saveimage docker.")
+	}
+	return nil
+}
+
+// BuildImage builds an image into this runtime
+func (r *Docker) BuildImage(o BuildOptions) error {
+	klog.Infof("Building image: %s", o.Dir)
+	args := []string{"docker", "build"}
+	if o.File != "" {
+		args = append(args, "-f", path.Join(o.Dir, o.File))
+	}
+	if o.Tag != "" {
+		args = append(args, "-t", o.Tag)
+	}
+	for _, opt := range o.Opts {
+		args = append(args, "--"+opt)
+	}
+	args = append(args, o.Dir)
+	c := exec.Command("env", append(o.Env, args...)...)
+	if rr, err := r.Runner.RunCmd(c); err != nil {
+		return errors.Wrapf(err, "buildimage docker: %s", rr.Output())
+	}
+	if o.Tag != "" && o.Push {
+		return r.PushImage(o.Tag)
+	}
+	return nil
+}
+
 // CGroupDriver returns cgroup driver ("cgroupfs" or "systemd")
 func (r *Docker) CGroupDriver() (string, error) {
 	// Note: the server daemon has to be running, for this call to return successfully
@@ -264,13 +345,16 @@ func (r *Docker) UnpauseContainers(ids []string) error {
 }
 
 // ContainerLogCmd returns the command to retrieve the log for a container based on ID
-func (r *Docker) ContainerLogCmd(id string, len int, follow bool) string {
+func (r *Docker) ContainerLogCmd(id string, o LogOptions) string {
 	var cmd strings.Builder
 	cmd.WriteString("docker logs ")
-	if len > 0 {
-		cmd.WriteString(fmt.Sprintf("--tail %d ", len))
+	if o.Lines > 0 {
+		cmd.WriteString(fmt.Sprintf("--tail %d ", o.Lines))
+	}
+	if o.Since > 0 {
+		cmd.WriteString(fmt.Sprintf("--since %ds ", int(o.Since.Seconds())))
 	}
-	if follow {
+	if o.Follow {
 		cmd.WriteString("--follow ")
 	}
 
@@ -279,8 +363,8 @@ func (r *Docker) ContainerLogCmd(id string, len int, follow bool) string {
 }
 
 // SystemLogCmd returns the command to retrieve system logs
-func (r *Docker) SystemLogCmd(len int) string {
-	return fmt.Sprintf("sudo journalctl -u docker -n %d", len)
+func (r *Docker) SystemLogCmd(o LogOptions) string {
+	return journalctlCmd("docker", o)
 }
 
 // ForceSystemd forces the docker daemon to use systemd as cgroup manager
@@ -305,15 +305,34 @@ func SetLibvirtURI(v string) {
 
 }
 
+// NetworkName returns the name of the network the docker and podman drivers attach the nodes of a cluster to
+func NetworkName(cc config.ClusterConfig) string {
+	if cc.Network != "" {
+		return cc.Network
+	}
+	return cc.Name
+}
+
 // MachineName returns the name of the machine, as seen by the hypervisor given the cluster and node names
 func MachineName(cc config.ClusterConfig, n config.Node) string {
 	// For single node cluster, default to back to old naming
-	if len(cc.Nodes) == 1 || n.ControlPlane {
+	if len(cc.Nodes) == 1 || n.ControlPlane && n.Name == primaryName(cc) {
 		return cc.Name
 	}
 	return fmt.Sprintf("%s-%s", cc.Name, n.Name)
 }
 
+// primaryName returns the name of the first control plane of a cluster, whose machine is named after the cluster:
+// the other control planes of highly available clusters are named after their node, like workers
+func primaryName(cc config.ClusterConfig) string {
+	for _, n := range cc.Nodes {
+		if n.ControlPlane {
+			return n.Name
+		}
+	}
+	return ""
+}
+
 // IndexFromMachineName returns the order of the container based on it is name
 func IndexFromMachineName(machineName string) int {
 	// minikube-m02
@@ -247,6 +247,30 @@ func TestMachineName(t *testing.T) {
 			},
 			Want: "p2-m2",
 		},
+
+		{
+			ClusterConfig: config.ClusterConfig{Name: "ha",
+				Nodes: []config.Node{
+					{
+						Name:              "",
+						IP:                "172.17.0.3",
+						Port:              8443,
+						KubernetesVersion: "v1.19.2",
+						ControlPlane:      true,
+						Worker:            true,
+					},
+					{
+						Name:              "m02",
+						IP:                "172.17.0.4",
+						Port:              8443,
+						KubernetesVersion: "v1.19.2",
+						ControlPlane:      true,
+						Worker:            true,
+					},
+				},
+			},
+			Want: "ha-m02",
+		},
 	}
 
 	for _, tc := range testsCases {
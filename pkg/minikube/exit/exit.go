@@ -20,8 +20,10 @@ package exit
 import (
 	"os"
 	"runtime"
+	"sync"
 
 	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/localpath"
 	"k8s.io/minikube/pkg/minikube/out"
 	"k8s.io/minikube/pkg/minikube/reason"
 	"k8s.io/minikube/pkg/minikube/style"
@@ -63,8 +65,20 @@ func Advice(r reason.Kind, msg string, advice string, a ...out.V) {
 	Message(r, msg, a...)
 }
 
+var loadKnownIssuesOnce sync.Once
+
+// LoadKnownIssues loads the known issues defined within the minikube home directory (thread-safe)
+func LoadKnownIssues() {
+	loadKnownIssuesOnce.Do(func() {
+		if err := reason.LoadKnownIssues(localpath.MakeMiniPath("known_issues.d")); err != nil {
+			klog.Warningf("unable to load known issues: %v", err)
+		}
+	})
+}
+
 // Error takes a fatal error, matches it against known issues, and outputs the best message for it
 func Error(r reason.Kind, msg string, err error) {
+	LoadKnownIssues()
 	ki := reason.MatchKnownIssue(r, err, runtime.GOOS)
 	if ki != nil {
 		Message(*ki, err.Error())
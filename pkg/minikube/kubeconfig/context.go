@@ -88,3 +88,30 @@ func DeleteContext(machineName string, configPath ...string) error {
 	}
 	return nil
 }
+
+// ExtractContext writes a kubeconfig holding only a context, along with its cluster and user, as the current context
+func ExtractContext(name string, dst string, configPath ...string) error {
+	fPath := PathFromEnv()
+	if configPath != nil {
+		fPath = configPath[0]
+	}
+	kcfg, err := readOrNew(fPath)
+	if err != nil {
+		return errors.Wrap(err, "Error getting kubeconfig status")
+	}
+	ctx, ok := kcfg.Contexts[name]
+	if !ok {
+		return errors.Errorf("context %q not found in %s", name, fPath)
+	}
+
+	ecfg := api.NewConfig()
+	ecfg.Contexts[name] = ctx
+	if c, ok := kcfg.Clusters[ctx.Cluster]; ok {
+		ecfg.Clusters[ctx.Cluster] = c
+	}
+	if u, ok := kcfg.AuthInfos[ctx.AuthInfo]; ok {
+		ecfg.AuthInfos[ctx.AuthInfo] = u
+	}
+	ecfg.CurrentContext = name
+	return writeToFile(ecfg, dst)
+}
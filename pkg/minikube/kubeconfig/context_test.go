@@ -147,3 +147,29 @@ func TestUnsetCurrentContextOnlyChangesIfProfileIsTheCurrentContext(t *testing.T
 		t.Errorf("Expected context name %s but got %s", contextName, cfg.CurrentContext)
 	}
 }
+
+func TestExtractContext(t *testing.T) {
+	// See kubeconfig_test
+	fn := tempFile(t, kubeConfigWithoutHTTPS)
+	defer os.Remove(fn)
+	dst := filepath.Join(filepath.Dir(fn), "extracted-"+filepath.Base(fn))
+	defer os.Remove(dst)
+
+	if err := ExtractContext("la-croix", dst, fn); err != nil {
+		t.Fatalf("ExtractContext: %v", err)
+	}
+	cfg, err := readOrNew(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.CurrentContext != "la-croix" {
+		t.Errorf("current context = %q, want la-croix", cfg.CurrentContext)
+	}
+	if len(cfg.Contexts) != 1 || len(cfg.Clusters) != 1 || len(cfg.AuthInfos) != 1 {
+		t.Errorf("extracted %d contexts, %d clusters and %d users, want one of each", len(cfg.Contexts), len(cfg.Clusters), len(cfg.AuthInfos))
+	}
+
+	if err := ExtractContext("nonexistent", dst, fn); err == nil {
+		t.Errorf("ExtractContext(nonexistent) returned no error")
+	}
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package localpath
 
 import (
+	"net/url"
 	"os"
 	"os/exec"
 	"path"
@@ -62,11 +63,31 @@ func Profile(name string) string {
 	return filepath.Join(MiniPath(), "profiles", name)
 }
 
+// TunnelLog returns the path to the log of a tunnel running in the background
+func TunnelLog(name string) string {
+	return filepath.Join(Profile(name), "tunnel.log")
+}
+
+// MountPID returns the path to the pid file of the supervisor of a persistent mount
+func MountPID(name string, guestPath string) string {
+	return filepath.Join(Profile(name), "mounts", url.PathEscape(guestPath)+".pid")
+}
+
+// MountLog returns the path to the log of the supervisor of a persistent mount
+func MountLog(name string, guestPath string) string {
+	return filepath.Join(Profile(name), "mounts", url.PathEscape(guestPath)+".log")
+}
+
 // EventLog returns the path to a CloudEvents log
 func EventLog(name string) string {
 	return filepath.Join(Profile(name), "events.json")
 }
 
+// Kubeconfig returns the path to a kubeconfig holding only the context of a profile, used by kubectl-env
+func Kubeconfig(name string) string {
+	return filepath.Join(Profile(name), "kubeconfig")
+}
+
 // ClientCert returns client certificate path, used by kubeconfig
 func ClientCert(name string) string {
 	new := filepath.Join(Profile(name), "client.crt")
@@ -0,0 +1,330 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logs
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/drivers/kic/oci"
+	"k8s.io/minikube/pkg/kapi"
+	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/vmpath"
+	"k8s.io/minikube/pkg/version"
+)
+
+// redacted replaces secrets in a support bundle
+const redacted = "<redacted>"
+
+// secretKeyRe matches the keys of options which may hold secrets
+var secretKeyRe = regexp.MustCompile(`(?i)pass|token|secret|key|credential|auth`)
+
+// clusterCommands are log commands which describe the whole cluster, so are only run on the primary control plane
+var clusterCommands = []string{"describe nodes"}
+
+// Source is a node to collect logs from
+type Source struct {
+	Node         config.Node
+	Primary      bool
+	Runtime      cruntime.Manager
+	Bootstrapper bootstrapper.Bootstrapper
+	Runner       command.Runner
+}
+
+// BundleIndex describes the contents of a support bundle, and is written to its index.json
+type BundleIndex struct {
+	Profile           string    `json:"profile"`
+	MinikubeVersion   string    `json:"minikubeVersion"`
+	Created           time.Time `json:"created"`
+	Driver            string    `json:"driver"`
+	KubernetesVersion string    `json:"kubernetesVersion"`
+	ContainerRuntime  string    `json:"containerRuntime"`
+	Files             []string  `json:"files"`
+	// Failed are the logs which could not be collected, and why
+	Failed map[string]string `json:"failed,omitempty"`
+	// Problems are the log entries which point to known problems, by node and log
+	Problems map[string]map[string][]string `json:"problems,omitempty"`
+}
+
+// bundle is a support bundle being written
+type bundle struct {
+	tw    *tar.Writer
+	index BundleIndex
+}
+
+// add adds a file to the bundle
+func (b *bundle) add(name string, data []byte) error {
+	hdr := &tar.Header{
+		Name:    name,
+		Mode:    0644,
+		Size:    int64(len(data)),
+		ModTime: b.index.Created,
+	}
+	if err := b.tw.WriteHeader(hdr); err != nil {
+		return errors.Wrapf(err, "header %s", name)
+	}
+	if _, err := b.tw.Write(data); err != nil {
+		return errors.Wrapf(err, "write %s", name)
+	}
+	b.index.Files = append(b.index.Files, name)
+	return nil
+}
+
+// fail records a log which could not be collected
+func (b *bundle) fail(name string, err error) {
+	klog.Warningf("unable to collect %s: %v", name, err)
+	b.index.Failed[name] = err.Error()
+}
+
+// Bundle writes a support bundle of a cluster to a gzipped tarball: the logs of each node, its redacted configuration,
+// the events of its last start, the output of its driver, and an index.json summarizing the problems found.
+func Bundle(file string, cc config.ClusterConfig, sources []Source, lines int) error {
+	// the bundle is written to a temporary file, so that a failure does not leave a partial bundle behind
+	f, err := ioutil.TempFile(filepath.Dir(file), "."+filepath.Base(file)+".*")
+	if err != nil {
+		return errors.Wrap(err, "create")
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+	if err := writeBundle(f, cc, sources, lines); err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return errors.Wrap(err, "close")
+	}
+	return os.Rename(f.Name(), file)
+}
+
+// writeBundle writes a support bundle of a cluster to w
+func writeBundle(w io.Writer, cc config.ClusterConfig, sources []Source, lines int) error {
+	gw := gzip.NewWriter(w)
+	b := &bundle{
+		tw: tar.NewWriter(gw),
+		index: BundleIndex{
+			Profile:           cc.Name,
+			MinikubeVersion:   version.GetVersion(),
+			Created:           time.Now(),
+			Driver:            cc.Driver,
+			KubernetesVersion: cc.KubernetesConfig.KubernetesVersion,
+			ContainerRuntime:  cc.KubernetesConfig.ContainerRuntime,
+			Failed:            map[string]string{},
+			Problems:          map[string]map[string][]string{},
+		},
+	}
+
+	data, err := json.MarshalIndent(Redact(cc), "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "marshal config")
+	}
+	if err := b.add("cluster/config.json", data); err != nil {
+		return err
+	}
+	if data, err := ioutil.ReadFile(localpath.EventLog(cc.Name)); err == nil {
+		if err := b.add("cluster/events.json", data); err != nil {
+			return err
+		}
+	} else {
+		b.fail("cluster/events.json", err)
+	}
+
+	for _, s := range sources {
+		name := driver.MachineName(cc, s.Node)
+		if err := b.addDriverInfo(cc, name); err != nil {
+			return err
+		}
+		if err := b.addNode(cc, s, name, lines); err != nil {
+			return err
+		}
+	}
+
+	sort.Strings(b.index.Files)
+	data, err = json.MarshalIndent(b.index, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "marshal index")
+	}
+	if err := b.add("index.json", data); err != nil {
+		return err
+	}
+	if err := b.tw.Close(); err != nil {
+		return errors.Wrap(err, "close tar")
+	}
+	if err := gw.Close(); err != nil {
+		return errors.Wrap(err, "close gzip")
+	}
+	return nil
+}
+
+// addNode adds the logs of a node to the bundle, along with the cluster wide logs if it is the primary control plane
+func (b *bundle) addNode(cc config.ClusterConfig, s Source, name string, lines int) error {
+	cmds := logCommands(s.Runtime, s.Bootstrapper, cc, Options{Lines: lines})
+	cmds["kernel"] = "uptime && uname -a && grep PRETTY /etc/os-release"
+
+	files := map[string]string{}
+	for k, c := range cmds {
+		files[path.Join("nodes", name, fileName(k))] = c
+	}
+	for _, k := range clusterCommands {
+		delete(files, path.Join("nodes", name, fileName(k)))
+		if s.Primary {
+			files[path.Join("cluster", fileName(k))] = cmds[k]
+		}
+	}
+	if s.Primary {
+		files["cluster/kubernetes-events.log"] = fmt.Sprintf("sudo %s get events -A --sort-by=.lastTimestamp --kubeconfig=%s",
+			kapi.KubectlBinaryPath(cc.KubernetesConfig.KubernetesVersion), path.Join(vmpath.GuestPersistentDir, "kubeconfig"))
+	}
+
+	names := []string{}
+	for k := range files {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	problems := map[string][]string{}
+	for _, file := range names {
+		klog.Infof("Collecting %s ...", file)
+		var out bytes.Buffer
+		c := exec.Command("/bin/bash", "-c", files[file])
+		c.Stdout = &out
+		c.Stderr = &out
+		if _, err := s.Runner.RunCmd(c); err != nil {
+			b.fail(file, err)
+			continue
+		}
+		if ps := findProblems(out.Bytes()); len(ps) > 0 {
+			problems[path.Base(file)] = ps
+		}
+		if err := b.add(file, out.Bytes()); err != nil {
+			return err
+		}
+	}
+	if len(problems) > 0 {
+		b.index.Problems[name] = problems
+	}
+	return nil
+}
+
+// addDriverInfo adds what the driver knows about a machine to the bundle, for the drivers which can describe it
+func (b *bundle) addDriverInfo(cc config.ClusterConfig, name string) error {
+	var c *exec.Cmd
+	var file string
+	switch {
+	case driver.IsKIC(cc.Driver):
+		c = oci.PrefixCmd(exec.Command(cc.Driver, "container", "inspect", name))
+		file = path.Join("nodes", name, "inspect.json")
+	case cc.Driver == driver.KVM2:
+		c = exec.Command("virsh", "-c", cc.KVMQemuURI, "dumpxml", name)
+		file = path.Join("nodes", name, "domain.xml")
+	default:
+		return nil
+	}
+
+	data, err := c.Output()
+	if err != nil {
+		b.fail(file, errors.Wrap(err, strings.Join(c.Args, " ")))
+		return nil
+	}
+	return b.add(file, data)
+}
+
+// findProblems returns the lines of a log which point to known problems
+func findProblems(log []byte) []string {
+	problems := []string{}
+	scanner := bufio.NewScanner(bytes.NewReader(log))
+	for scanner.Scan() {
+		if l := scanner.Text(); IsProblem(l) {
+			problems = append(problems, l)
+		}
+	}
+	return problems
+}
+
+// fileName returns the name of the file the output of a log command is written to
+func fileName(name string) string {
+	return strings.NewReplacer(" ", "-", "[", "", "]", "").Replace(name) + ".log"
+}
+
+// Redact returns a copy of a cluster configuration without the secrets it may hold:
+// the values of environment variables and options whose name suggests a secret, and the user info of URLs.
+func Redact(cc config.ClusterConfig) config.ClusterConfig {
+	cc.DockerEnv = redactKeyValues(cc.DockerEnv)
+	cc.DockerOpt = redactKeyValues(cc.DockerOpt)
+	cc.RegistryMirror = redactKeyValues(cc.RegistryMirror)
+	cc.InsecureRegistry = redactKeyValues(cc.InsecureRegistry)
+
+	opts := config.ExtraOptionSlice{}
+	for _, o := range cc.KubernetesConfig.ExtraOptions {
+		if secretKeyRe.MatchString(o.Key) {
+			o.Value = redacted
+		} else {
+			o.Value = redactURL(o.Value)
+		}
+		opts = append(opts, o)
+	}
+	cc.KubernetesConfig.ExtraOptions = opts
+	return cc
+}
+
+// redactKeyValues redacts a list of values, each of which may be formatted as KEY=VALUE
+func redactKeyValues(kvs []string) []string {
+	if kvs == nil {
+		return nil
+	}
+	redactedKVs := []string{}
+	for _, kv := range kvs {
+		k, v := "", kv
+		if i := strings.Index(kv, "="); i >= 0 {
+			k, v = kv[:i+1], kv[i+1:]
+		}
+		if secretKeyRe.MatchString(k) {
+			v = redacted
+		}
+		redactedKVs = append(redactedKVs, k+redactURL(v))
+	}
+	return redactedKVs
+}
+
+// redactURL redacts the user info of a URL, such as the credentials of a proxy
+func redactURL(s string) string {
+	u, err := url.Parse(s)
+	if err != nil || u.User == nil {
+		return s
+	}
+	return strings.Replace(s, u.User.String()+"@", redacted+"@", 1)
+}
@@ -21,11 +21,14 @@ import (
 	"bufio"
 	"bytes"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"regexp"
 	"sort"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 	"k8s.io/klog/v2"
@@ -33,6 +36,7 @@ import (
 	"k8s.io/minikube/pkg/minikube/command"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/driver"
 	"k8s.io/minikube/pkg/minikube/out"
 	"k8s.io/minikube/pkg/minikube/style"
 )
@@ -81,6 +85,27 @@ var importantPods = []string{
 	"kube-controller-manager",
 }
 
+// componentContainers are the names of the containers of the Kubernetes components which can be selected by a shorter name
+var componentContainers = map[string]string{
+	"apiserver":          "kube-apiserver",
+	"controller-manager": "kube-controller-manager",
+	"scheduler":          "kube-scheduler",
+	"proxy":              "kube-proxy",
+}
+
+// Options are the options to use for retrieving logs
+type Options struct {
+	// Lines is the number of recent log lines to include, as in tail -n.
+	Lines int
+	// Follow is whether or not to actively follow the logs, as in tail -f.
+	Follow bool
+	// Since is how far back to include logs from, if set.
+	Since time.Duration
+	// Components restricts the logs to these components: the logs of the bootstrapper (such as kubelet or dmesg),
+	// the container runtime (such as containerd), Kubernetes components (such as apiserver or etcd) or any container by name.
+	Components []string
+}
+
 // logRunner is the subset of CommandRunner used for logging
 type logRunner interface {
 	RunCmd(*exec.Cmd) (*command.RunResult, error)
@@ -90,21 +115,92 @@ type logRunner interface {
 // include usage messages from a failed binary, but small enough to not include irrelevant problems.
 const lookBackwardsCount = 400
 
-// Follow follows logs from multiple files in tail(1) format
-func Follow(r cruntime.Manager, bs bootstrapper.Bootstrapper, cfg config.ClusterConfig, cr logRunner) error {
-	cs := []string{}
-	for _, v := range logCommands(r, bs, cfg, 0, true) {
-		cs = append(cs, v+" &")
+// Follow follows logs from multiple files of one or more nodes in tail(1) format.
+// When following several nodes, each line is prefixed with the name of its node.
+func Follow(sources []Source, cfg config.ClusterConfig, o Options) error {
+	o.Follow = true
+	var mu sync.Mutex
+	errs := make(chan error, len(sources))
+	started := 0
+	for _, s := range sources {
+		name := driver.MachineName(cfg, s.Node)
+		cmds := logCommands(s.Runtime, s.Bootstrapper, cfg, o)
+		if len(cmds) == 0 {
+			klog.Infof("no logs to follow on %s", name)
+			continue
+		}
+		cs := []string{}
+		for _, v := range cmds {
+			cs = append(cs, v+" &")
+		}
+		cs = append(cs, "wait")
+
+		var w io.Writer = os.Stdout
+		if len(sources) > 1 {
+			w = &prefixWriter{prefix: fmt.Sprintf("[%s] ", name), w: os.Stdout, mu: &mu}
+		}
+		cmd := exec.Command("/bin/bash", "-c", strings.Join(cs, " "))
+		cmd.Stdout = w
+		cmd.Stderr = w
+		go func(r command.Runner) {
+			if _, err := r.RunCmd(cmd); err != nil {
+				errs <- errors.Wrapf(err, "log follow %s", name)
+				return
+			}
+			errs <- nil
+		}(s.Runner)
+		started++
+	}
+	if started == 0 {
+		return noLogsError(o)
 	}
-	cs = append(cs, "wait")
 
-	cmd := exec.Command("/bin/bash", "-c", strings.Join(cs, " "))
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stdout
-	if _, err := cr.RunCmd(cmd); err != nil {
-		return errors.Wrapf(err, "log follow")
+	var failed error
+	for i := 0; i < started; i++ {
+		if err := <-errs; err != nil {
+			klog.Warning(err)
+			failed = err
+		}
 	}
-	return nil
+	return failed
+}
+
+// prefixWriter writes complete lines to w, each prefixed with prefix
+type prefixWriter struct {
+	prefix string
+	w      io.Writer
+	// mu serializes the lines written to w by several prefixWriters
+	mu  *sync.Mutex
+	buf []byte
+}
+
+// Write writes the complete lines of p, and keeps the last incomplete line until the next write
+func (p *prefixWriter) Write(b []byte) (int, error) {
+	p.buf = append(p.buf, b...)
+	i := bytes.LastIndexByte(p.buf, '\n')
+	if i < 0 {
+		return len(b), nil
+	}
+	var lines bytes.Buffer
+	for _, l := range bytes.SplitAfter(p.buf[:i+1], []byte("\n")) {
+		if len(l) > 0 {
+			lines.WriteString(p.prefix)
+			lines.Write(l)
+		}
+	}
+	p.buf = append([]byte{}, p.buf[i+1:]...)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, err := p.w.Write(lines.Bytes()); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// noLogsError returns the error for components which have no logs
+func noLogsError(o Options) error {
+	return fmt.Errorf("no logs found for: %s", strings.Join(o.Components, ", "))
 }
 
 // IsProblem returns whether this line matches a known problem
@@ -115,7 +211,7 @@ func IsProblem(line string) bool {
 // FindProblems finds possible root causes among the logs
 func FindProblems(r cruntime.Manager, bs bootstrapper.Bootstrapper, cfg config.ClusterConfig, cr logRunner) map[string][]string {
 	pMap := map[string][]string{}
-	cmds := logCommands(r, bs, cfg, lookBackwardsCount, false)
+	cmds := logCommands(r, bs, cfg, Options{Lines: lookBackwardsCount})
 	for name := range cmds {
 		klog.Infof("Gathering logs for %s ...", name)
 		var b bytes.Buffer
@@ -127,14 +223,9 @@ func FindProblems(r cruntime.Manager, bs bootstrapper.Bootstrapper, cfg config.C
 			klog.Warningf("failed %s: command: %s %v output: %s", name, rr.Command(), err, rr.Output())
 			continue
 		}
-		scanner := bufio.NewScanner(&b)
-		problems := []string{}
-		for scanner.Scan() {
-			l := scanner.Text()
-			if IsProblem(l) {
-				klog.Warningf("Found %s problem: %s", name, l)
-				problems = append(problems, l)
-			}
+		problems := findProblems(b.Bytes())
+		for _, l := range problems {
+			klog.Warningf("Found %s problem: %s", name, l)
 		}
 		if len(problems) > 0 {
 			pMap[name] = problems
@@ -157,9 +248,14 @@ func OutputProblems(problems map[string][]string, maxLines int) {
 }
 
 // Output displays logs from multiple sources in tail(1) format
-func Output(r cruntime.Manager, bs bootstrapper.Bootstrapper, cfg config.ClusterConfig, runner command.Runner, lines int) error {
-	cmds := logCommands(r, bs, cfg, lines, false)
-	cmds["kernel"] = "uptime && uname -a && grep PRETTY /etc/os-release"
+func Output(r cruntime.Manager, bs bootstrapper.Bootstrapper, cfg config.ClusterConfig, runner command.Runner, o Options) error {
+	cmds := logCommands(r, bs, cfg, o)
+	if len(o.Components) == 0 {
+		cmds["kernel"] = "uptime && uname -a && grep PRETTY /etc/os-release"
+	}
+	if len(cmds) == 0 {
+		return noLogsError(o)
+	}
 
 	names := []string{}
 	for k := range cmds {
@@ -195,9 +291,29 @@ func Output(r cruntime.Manager, bs bootstrapper.Bootstrapper, cfg config.Cluster
 }
 
 // logCommands returns a list of commands that would be run to receive the anticipated logs
-func logCommands(r cruntime.Manager, bs bootstrapper.Bootstrapper, cfg config.ClusterConfig, length int, follow bool) map[string]string {
-	cmds := bs.LogCommands(cfg, bootstrapper.LogOptions{Lines: length, Follow: follow})
-	for _, pod := range importantPods {
+func logCommands(r cruntime.Manager, bs bootstrapper.Bootstrapper, cfg config.ClusterConfig, o Options) map[string]string {
+	cmds := bs.LogCommands(cfg, bootstrapper.LogOptions{Lines: o.Lines, Follow: o.Follow, Since: o.Since})
+	ro := cruntime.LogOptions{Lines: o.Lines, Follow: o.Follow, Since: o.Since}
+	pods := importantPods
+	if len(o.Components) > 0 {
+		all := cmds
+		cmds = map[string]string{}
+		pods = []string{}
+		for _, c := range o.Components {
+			switch {
+			case all[c] != "":
+				cmds[c] = all[c]
+			case c == cfg.KubernetesConfig.ContainerRuntime || strings.EqualFold(c, r.Name()):
+				cmds[r.Name()] = r.SystemLogCmd(ro)
+			case componentContainers[c] != "":
+				pods = append(pods, componentContainers[c])
+			default:
+				pods = append(pods, c)
+			}
+		}
+	}
+
+	for _, pod := range pods {
 		ids, err := r.ListContainers(cruntime.ListOptions{Name: pod})
 		if err != nil {
 			klog.Errorf("Failed to list containers for %q: %v", pod, err)
@@ -210,11 +326,13 @@ func logCommands(r cruntime.Manager, bs bootstrapper.Bootstrapper, cfg config.Cl
 		}
 		for _, i := range ids {
 			key := fmt.Sprintf("%s [%s]", pod, i)
-			cmds[key] = r.ContainerLogCmd(i, length, follow)
+			cmds[key] = r.ContainerLogCmd(i, ro)
 		}
 	}
-	cmds[r.Name()] = r.SystemLogCmd(length)
-	cmds["container status"] = cruntime.ContainerStatusCommand()
+	if len(o.Components) == 0 {
+		cmds[r.Name()] = r.SystemLogCmd(ro)
+		cmds["container status"] = cruntime.ContainerStatusCommand()
+	}
 
 	return cmds
 }
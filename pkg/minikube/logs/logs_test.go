@@ -17,6 +17,8 @@ limitations under the License.
 package logs
 
 import (
+	"bytes"
+	"sync"
 	"testing"
 )
 
@@ -61,3 +63,18 @@ func TestIsProblem(t *testing.T) {
 		})
 	}
 }
+
+func TestPrefixWriter(t *testing.T) {
+	var b bytes.Buffer
+	var mu sync.Mutex
+	w := &prefixWriter{prefix: "[m02] ", w: &b, mu: &mu}
+	for _, s := range []string{"first line\nsec", "ond line\n", "\nunfinished"} {
+		if _, err := w.Write([]byte(s)); err != nil {
+			t.Fatalf("Write(%q): %v", s, err)
+		}
+	}
+	want := "[m02] first line\n[m02] second line\n[m02] \n"
+	if got := b.String(); got != want {
+		t.Errorf("prefixWriter wrote %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/vmpath"
+)
+
+// buildRoot is where build contexts are unpacked within the guest VM
+var buildRoot = path.Join(vmpath.GuestPersistentDir, "build")
+
+// BuildImage builds an image from a local context directory on every running node of the cluster.
+// If o.Push is set, the image is only pushed from the primary control plane.
+func BuildImage(api libmachine.API, cc *config.ClusterConfig, src string, o cruntime.BuildOptions) error {
+	src, err := filepath.Abs(src)
+	if err != nil {
+		return errors.Wrap(err, "abs")
+	}
+	fi, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	if !fi.IsDir() {
+		return fmt.Errorf("build context %s is not a directory", src)
+	}
+
+	tf, err := ioutil.TempFile("", "minikube-build-*.tar")
+	if err != nil {
+		return errors.Wrap(err, "tempfile")
+	}
+	defer os.Remove(tf.Name())
+	if err := tarDir(src, tf); err != nil {
+		tf.Close()
+		return errors.Wrapf(err, "archiving %s", src)
+	}
+	if err := tf.Close(); err != nil {
+		return errors.Wrap(err, "close")
+	}
+
+	cp, err := config.PrimaryControlPlane(cc)
+	if err != nil {
+		return errors.Wrap(err, "primary control plane")
+	}
+
+	return forEachRunningNode(api, cc, func(n config.Node, cr cruntime.Manager, runner command.Runner) error {
+		no := o
+		no.Push = o.Push && n.Name == cp.Name
+		return transferAndBuildImage(runner, cr, tf.Name(), no)
+	})
+}
+
+// transferAndBuildImage transfers a build context tarball and builds an image from it
+func transferAndBuildImage(runner command.Runner, cr cruntime.Manager, tarball string, o cruntime.BuildOptions) error {
+	name := fmt.Sprintf("build.%d", time.Now().UnixNano())
+	dir := path.Join(buildRoot, name)
+	if _, err := runner.RunCmd(exec.Command("sudo", "mkdir", "-p", dir)); err != nil {
+		return errors.Wrap(err, "mkdir")
+	}
+	defer func() {
+		if _, err := runner.RunCmd(exec.Command("sudo", "rm", "-rf", dir, dir+".tar")); err != nil {
+			klog.Warningf("failed to remove %s: %v", dir, err)
+		}
+	}()
+
+	f, err := assets.NewFileAsset(tarball, buildRoot, name+".tar", "0644")
+	if err != nil {
+		return errors.Wrap(err, "creating copyable file asset")
+	}
+	if err := runner.Copy(f); err != nil {
+		return errors.Wrap(err, "transferring build context")
+	}
+	if _, err := runner.RunCmd(exec.Command("sudo", "tar", "-C", dir, "-xf", dir+".tar")); err != nil {
+		return errors.Wrap(err, "extracting build context")
+	}
+
+	o.Dir = dir
+	if err := cr.BuildImage(o); err != nil {
+		return errors.Wrapf(err, "%s build", cr.Name())
+	}
+	klog.Infof("Built %s from %s", o.Tag, tarball)
+	return nil
+}
+
+// tarDir writes the contents of a directory as a tar stream, with paths relative to the directory
+func tarDir(dir string, w io.Writer) error {
+	tw := tar.NewWriter(w)
+	err := filepath.Walk(dir, func(p string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+
+		link := ""
+		if fi.Mode()&os.ModeSymlink != 0 {
+			if link, err = os.Readlink(p); err != nil {
+				return err
+			}
+		}
+		hdr, err := tar.FileInfoHeader(fi, link)
+		if err != nil {
+			return err
+		}
+		hdr.Name = filepath.ToSlash(rel)
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if !fi.Mode().IsRegular() {
+			return nil
+		}
+
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return tw.Close()
+}
@@ -19,13 +19,16 @@ package machine
 import (
 	"fmt"
 	"os"
+	"os/exec"
 	"path"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/docker/docker/client"
+	"github.com/docker/machine/libmachine"
 	"github.com/docker/machine/libmachine/state"
 	"github.com/pkg/errors"
 	"golang.org/x/sync/errgroup"
@@ -232,13 +235,18 @@ func CacheAndLoadImages(images []string) error {
 
 // transferAndLoadImage transfers and loads a single image from the cache
 func transferAndLoadImage(cr command.Runner, k8s config.KubernetesConfig, imgName string, cacheDir string) error {
+	src := filepath.Join(cacheDir, imgName)
+	src = localpath.SanitizeCacheDir(src)
+	klog.Infof("Loading image from cache: %s", src)
+	return transferAndLoadTarball(cr, k8s, src)
+}
+
+// transferAndLoadTarball transfers and loads a single image tarball from the host
+func transferAndLoadTarball(cr command.Runner, k8s config.KubernetesConfig, src string) error {
 	r, err := cruntime.New(cruntime.Config{Type: k8s.ContainerRuntime, Runner: cr})
 	if err != nil {
 		return errors.Wrap(err, "runtime")
 	}
-	src := filepath.Join(cacheDir, imgName)
-	src = localpath.SanitizeCacheDir(src)
-	klog.Infof("Loading image from cache: %s", src)
 	filename := filepath.Base(src)
 	if _, err := os.Stat(src); err != nil {
 		return err
@@ -260,6 +268,160 @@ func transferAndLoadImage(cr command.Runner, k8s config.KubernetesConfig, imgNam
 		return errors.Wrapf(err, "%s load %s", r.Name(), dst)
 	}
 
-	klog.Infof("Transferred and loaded %s from cache", src)
+	klog.Infof("Transferred and loaded %s", src)
+	return nil
+}
+
+// forEachRunningNode calls fn with a container runtime for every running node of the cluster
+func forEachRunningNode(api libmachine.API, cc *config.ClusterConfig, fn func(n config.Node, cr cruntime.Manager, runner command.Runner) error) error {
+	var failed []string
+	for _, n := range cc.Nodes {
+		m := driver.MachineName(*cc, n)
+
+		status, err := Status(api, m)
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s: status: %v", m, err))
+			continue
+		}
+		if status != state.Running.String() {
+			klog.Infof("skipping %s: not running (state=%s)", m, status)
+			continue
+		}
+
+		h, err := api.Load(m)
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s: load: %v", m, err))
+			continue
+		}
+		runner, err := CommandRunner(h)
+		if err != nil {
+			return err
+		}
+		cr, err := cruntime.New(cruntime.Config{Type: cc.KubernetesConfig.ContainerRuntime, Runner: runner})
+		if err != nil {
+			return errors.Wrap(err, "runtime")
+		}
+		if err := fn(n, cr, runner); err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", m, err))
+		}
+	}
+	if len(failed) > 0 {
+		return errors.New(strings.Join(failed, "\n"))
+	}
+	return nil
+}
+
+// LoadImagesIntoCluster caches images from the local daemon or registry and loads them into every running node
+func LoadImagesIntoCluster(api libmachine.API, cc *config.ClusterConfig, images []string) error {
+	if err := image.SaveToDir(images, constants.ImageCacheDir); err != nil {
+		return errors.Wrap(err, "save to dir")
+	}
+	return forEachRunningNode(api, cc, func(_ config.Node, _ cruntime.Manager, runner command.Runner) error {
+		// Always transfer: the user may have rebuilt an image under an existing tag
+		for _, img := range images {
+			if err := transferAndLoadImage(runner, cc.KubernetesConfig, img, constants.ImageCacheDir); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// LoadTarballsIntoCluster loads image tarballs from the host into every running node
+func LoadTarballsIntoCluster(api libmachine.API, cc *config.ClusterConfig, tarballs []string) error {
+	return forEachRunningNode(api, cc, func(_ config.Node, _ cruntime.Manager, runner command.Runner) error {
+		for _, t := range tarballs {
+			if err := transferAndLoadTarball(runner, cc.KubernetesConfig, t); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ListImages returns the sorted union of images present on every running node
+func ListImages(api libmachine.API, cc *config.ClusterConfig) ([]string, error) {
+	seen := map[string]bool{}
+	err := forEachRunningNode(api, cc, func(_ config.Node, cr cruntime.Manager, _ command.Runner) error {
+		images, err := cr.ListImages()
+		if err != nil {
+			return err
+		}
+		for _, img := range images {
+			seen[img] = true
+		}
+		return nil
+	})
+	images := []string{}
+	for img := range seen {
+		images = append(images, img)
+	}
+	sort.Strings(images)
+	return images, err
+}
+
+// RemoveImages removes images from every running node
+func RemoveImages(api libmachine.API, cc *config.ClusterConfig, images []string) error {
+	return forEachRunningNode(api, cc, func(_ config.Node, cr cruntime.Manager, _ command.Runner) error {
+		for _, img := range images {
+			if err := cr.RemoveImage(img); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// PullImages pulls images into every running node
+func PullImages(api libmachine.API, cc *config.ClusterConfig, images []string) error {
+	return forEachRunningNode(api, cc, func(_ config.Node, cr cruntime.Manager, _ command.Runner) error {
+		for _, img := range images {
+			if err := cr.PullImage(img); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// PushImages pushes images from a node to their registries
+func PushImages(cc *config.ClusterConfig, runner command.Runner, images []string) error {
+	cr, err := cruntime.New(cruntime.Config{Type: cc.KubernetesConfig.ContainerRuntime, Runner: runner})
+	if err != nil {
+		return errors.Wrap(err, "runtime")
+	}
+	for _, img := range images {
+		if err := cr.PushImage(img); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SaveImage saves an image from a node into a tarball on the host
+func SaveImage(cc *config.ClusterConfig, runner command.Runner, img string, dst string) error {
+	cr, err := cruntime.New(cruntime.Config{Type: cc.KubernetesConfig.ContainerRuntime, Runner: runner})
+	if err != nil {
+		return errors.Wrap(err, "runtime")
+	}
+	filename := filepath.Base(localpath.SanitizeCacheDir(img)) + ".tar"
+	src := path.Join(loadRoot, filename)
+	// loadRoot only exists on nodes which images were loaded into
+	if _, err := runner.RunCmd(exec.Command("sudo", "mkdir", "-p", loadRoot)); err != nil {
+		return errors.Wrap(err, "mkdir")
+	}
+	if err := cr.SaveImage(img, src); err != nil {
+		return errors.Wrapf(err, "%s save %s", cr.Name(), img)
+	}
+	defer func() {
+		if _, err := runner.RunCmd(exec.Command("sudo", "rm", "-f", src)); err != nil {
+			klog.Warningf("failed to remove %s: %v", src, err)
+		}
+	}()
+
+	if err := runner.CopyFrom(src, dst); err != nil {
+		return errors.Wrap(err, "transferring saved image")
+	}
+	klog.Infof("Saved %s to %s", img, dst)
 	return nil
 }
@@ -0,0 +1,55 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+func TestSaveImage(t *testing.T) {
+	dir, err := ioutil.TempDir("", "saveimage")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	src := path.Join(loadRoot, "busybox_latest.tar")
+	r := command.NewFakeCommandRunner()
+	// the directory of the tarball is created before the image is saved to it
+	r.SetCommandToOutput(map[string]string{
+		"sudo mkdir -p " + loadRoot:                      "",
+		"sudo docker save -o " + src + " busybox:latest": "",
+		"sudo rm -f " + src:                              "",
+	})
+	r.SetFileToContents(map[string]string{src: "tarball"})
+
+	cc := &config.ClusterConfig{KubernetesConfig: config.KubernetesConfig{ContainerRuntime: "docker"}}
+	dst := filepath.Join(dir, "busybox.tar")
+	if err := SaveImage(cc, r, "busybox:latest", dst); err != nil {
+		t.Fatalf("SaveImage: %v", err)
+	}
+	if b, err := ioutil.ReadFile(dst); err != nil || string(b) != "tarball" {
+		t.Errorf("%s = %q, %v, want the saved image", dst, b, err)
+	}
+}
@@ -0,0 +1,228 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/command"
+)
+
+// copyRoot is where directory archives are staged within the guest VM
+const copyRoot = "/tmp"
+
+// CopyToNode copies a file or directory from the host to a path on a node.
+// Directories are copied recursively, with their contents placed at dst.
+// Files copied to an existing directory are copied into it.
+func CopyToNode(runner command.Runner, src string, dst string) error {
+	fi, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	if !path.IsAbs(dst) {
+		return fmt.Errorf("destination %q must be an absolute path", dst)
+	}
+
+	if !fi.IsDir() {
+		if _, err := runner.RunCmd(exec.Command("sudo", "test", "-d", dst)); err == nil {
+			dst = path.Join(dst, filepath.Base(src))
+		}
+		if _, err := runner.RunCmd(exec.Command("sudo", "mkdir", "-p", path.Dir(dst))); err != nil {
+			return errors.Wrap(err, "mkdir")
+		}
+		f, err := assets.NewFileAsset(src, path.Dir(dst), path.Base(dst), fmt.Sprintf("%04o", fi.Mode().Perm()))
+		if err != nil {
+			return errors.Wrapf(err, "creating copyable file asset: %s", src)
+		}
+		return runner.Copy(f)
+	}
+
+	tf, err := ioutil.TempFile("", "minikube-cp-*.tar")
+	if err != nil {
+		return errors.Wrap(err, "tempfile")
+	}
+	defer os.Remove(tf.Name())
+	if err := tarDir(src, tf); err != nil {
+		tf.Close()
+		return errors.Wrapf(err, "archiving %s", src)
+	}
+	if err := tf.Close(); err != nil {
+		return errors.Wrap(err, "close")
+	}
+
+	name := fmt.Sprintf("minikube-cp.%d.tar", time.Now().UnixNano())
+	staged := path.Join(copyRoot, name)
+	f, err := assets.NewFileAsset(tf.Name(), copyRoot, name, "0644")
+	if err != nil {
+		return errors.Wrap(err, "creating copyable file asset")
+	}
+	if err := runner.Copy(f); err != nil {
+		return errors.Wrap(err, "transferring archive")
+	}
+	defer func() {
+		if _, err := runner.RunCmd(exec.Command("sudo", "rm", "-f", staged)); err != nil {
+			klog.Warningf("failed to remove %s: %v", staged, err)
+		}
+	}()
+
+	if _, err := runner.RunCmd(exec.Command("sudo", "mkdir", "-p", dst)); err != nil {
+		return errors.Wrap(err, "mkdir")
+	}
+	if _, err := runner.RunCmd(exec.Command("sudo", "tar", "--no-same-owner", "-C", dst, "-xf", staged)); err != nil {
+		return errors.Wrap(err, "extracting archive")
+	}
+	return nil
+}
+
+// CopyFromNode copies a file or directory from a node to a path on the host.
+// Directories are copied recursively, with their contents placed at dst.
+// Files copied to an existing directory are copied into it.
+func CopyFromNode(runner command.Runner, src string, dst string) error {
+	if !path.IsAbs(src) {
+		return fmt.Errorf("source %q must be an absolute path", src)
+	}
+	if _, err := runner.RunCmd(exec.Command("sudo", "test", "-d", src)); err != nil {
+		if fi, err := os.Stat(dst); err == nil && fi.IsDir() {
+			dst = filepath.Join(dst, path.Base(src))
+		}
+		return runner.CopyFrom(src, dst)
+	}
+
+	staged := path.Join(copyRoot, fmt.Sprintf("minikube-cp.%d.tar", time.Now().UnixNano()))
+	if _, err := runner.RunCmd(exec.Command("sudo", "tar", "-C", src, "-cf", staged, ".")); err != nil {
+		return errors.Wrap(err, "archiving")
+	}
+	defer func() {
+		if _, err := runner.RunCmd(exec.Command("sudo", "rm", "-f", staged)); err != nil {
+			klog.Warningf("failed to remove %s: %v", staged, err)
+		}
+	}()
+
+	tf, err := ioutil.TempFile("", "minikube-cp-*.tar")
+	if err != nil {
+		return errors.Wrap(err, "tempfile")
+	}
+	tf.Close()
+	defer os.Remove(tf.Name())
+	if err := runner.CopyFrom(staged, tf.Name()); err != nil {
+		return errors.Wrap(err, "transferring archive")
+	}
+
+	r, err := os.Open(tf.Name())
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	return untar(r, dst)
+}
+
+// untar extracts a tar stream into a directory, refusing entries which escape it, or which would be written through
+// a symlink, as symlinks of the archive may point anywhere
+func untar(r io.Reader, dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		p := filepath.Join(dir, filepath.FromSlash(hdr.Name))
+		if p != filepath.Clean(dir) && !strings.HasPrefix(p, filepath.Clean(dir)+string(os.PathSeparator)) {
+			return fmt.Errorf("archive entry %q escapes %s", hdr.Name, dir)
+		}
+		symlink, err := throughSymlink(dir, p)
+		if err != nil {
+			return err
+		}
+		if symlink {
+			return fmt.Errorf("archive entry %q would be written through a symlink", hdr.Name)
+		}
+		mode := os.FileMode(hdr.Mode).Perm()
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(p, mode|0700); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			if err := os.Symlink(hdr.Linkname, p); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+				return err
+			}
+			f, err := os.OpenFile(p, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+			if err != nil {
+				return err
+			}
+			if _, err := io.Copy(f, tr); err != nil {
+				f.Close()
+				return err
+			}
+			if err := f.Close(); err != nil {
+				return err
+			}
+		default:
+			klog.Infof("skipping %s: unsupported type %c", hdr.Name, hdr.Typeflag)
+		}
+	}
+}
+
+// throughSymlink tells if p, or any of its parents within dir, is a symlink
+func throughSymlink(dir string, p string) (bool, error) {
+	rel, err := filepath.Rel(dir, p)
+	if err != nil {
+		return false, err
+	}
+	cur := filepath.Clean(dir)
+	for _, name := range strings.Split(rel, string(os.PathSeparator)) {
+		if name == "." {
+			continue
+		}
+		cur = filepath.Join(cur, name)
+		fi, err := os.Lstat(cur)
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		if fi.Mode()&os.ModeSymlink != 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,219 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"archive/tar"
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/command"
+)
+
+func TestUntar(t *testing.T) {
+	src, err := ioutil.TempDir("", "untar-src")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(src)
+	dst, err := ioutil.TempDir("", "untar-dst")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(dst)
+
+	if err := os.MkdirAll(filepath.Join(src, "bin"), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(src, "bin", "run.sh"), []byte("#!/bin/sh\n"), 0750); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := tarDir(src, &buf); err != nil {
+		t.Fatalf("tarDir: %v", err)
+	}
+	if err := untar(&buf, dst); err != nil {
+		t.Fatalf("untar: %v", err)
+	}
+
+	fi, err := os.Stat(filepath.Join(dst, "bin", "run.sh"))
+	if err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+	if fi.Mode().Perm() != 0750 {
+		t.Errorf("perms=%o, want: %o", fi.Mode().Perm(), 0750)
+	}
+}
+
+func TestUntarEscape(t *testing.T) {
+	dst, err := ioutil.TempDir("", "untar-dst")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(dst)
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	if err := tw.WriteHeader(&tar.Header{Name: "../escape", Mode: 0644, Typeflag: tar.TypeReg}); err != nil {
+		t.Fatalf("header: %v", err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+
+	if err := untar(&buf, dst); err == nil {
+		t.Errorf("untar succeeded for an escaping entry, want error")
+	}
+}
+
+func TestUntarThroughSymlink(t *testing.T) {
+	outside, err := ioutil.TempDir("", "untar-outside")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(outside)
+
+	tests := []struct {
+		name    string
+		entries []*tar.Header
+		wantErr bool
+	}{
+		{"file through link", []*tar.Header{
+			{Name: "link", Linkname: outside, Typeflag: tar.TypeSymlink},
+			{Name: "link/file", Mode: 0644, Typeflag: tar.TypeReg},
+		}, true},
+		// link/../file is cleaned to file, within the directory
+		{"dotdot through link", []*tar.Header{
+			{Name: "link", Linkname: filepath.Join(outside, "sub"), Typeflag: tar.TypeSymlink},
+			{Name: "link/../file", Mode: 0644, Typeflag: tar.TypeReg},
+		}, false},
+		{"file over link", []*tar.Header{
+			{Name: "link", Linkname: filepath.Join(outside, "file"), Typeflag: tar.TypeSymlink},
+			{Name: "link", Mode: 0644, Typeflag: tar.TypeReg},
+		}, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dst, err := ioutil.TempDir("", "untar-dst")
+			if err != nil {
+				t.Fatalf("tempdir: %v", err)
+			}
+			defer os.RemoveAll(dst)
+
+			var buf bytes.Buffer
+			tw := tar.NewWriter(&buf)
+			for _, h := range tc.entries {
+				if err := tw.WriteHeader(h); err != nil {
+					t.Fatalf("header: %v", err)
+				}
+			}
+			if err := tw.Close(); err != nil {
+				t.Fatalf("close: %v", err)
+			}
+
+			if err := untar(&buf, dst); (err != nil) != tc.wantErr {
+				t.Errorf("untar error = %v, want error: %v", err, tc.wantErr)
+			}
+			if _, err := os.Stat(filepath.Join(outside, "file")); err == nil {
+				t.Errorf("untar wrote %s", filepath.Join(outside, "file"))
+			}
+		})
+	}
+}
+
+// copyRecorder records the target paths of the files copied to it
+type copyRecorder struct {
+	*command.FakeCommandRunner
+	targets []string
+}
+
+func (r *copyRecorder) Copy(f assets.CopyableFile) error {
+	r.targets = append(r.targets, filepath.ToSlash(filepath.Join(f.GetTargetDir(), f.GetTargetName())))
+	return nil
+}
+
+func TestCopyToNodeDirectory(t *testing.T) {
+	src, err := ioutil.TempFile("", "app.conf")
+	if err != nil {
+		t.Fatalf("tempfile: %v", err)
+	}
+	src.Close()
+	defer os.Remove(src.Name())
+	base := filepath.Base(src.Name())
+
+	tests := []struct {
+		name string
+		dir  bool
+		dst  string
+		want string
+	}{
+		{"file", false, "/etc/app.conf", "/etc/app.conf"},
+		{"directory", true, "/etc", "/etc/" + base},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := &copyRecorder{FakeCommandRunner: command.NewFakeCommandRunner()}
+			cmds := map[string]string{"sudo mkdir -p " + filepath.ToSlash(filepath.Dir(tc.want)): ""}
+			if tc.dir {
+				cmds["sudo test -d "+tc.dst] = ""
+			}
+			r.SetCommandToOutput(cmds)
+
+			if err := CopyToNode(r, src.Name(), tc.dst); err != nil {
+				t.Fatalf("CopyToNode: %v", err)
+			}
+			if len(r.targets) != 1 || r.targets[0] != tc.want {
+				t.Errorf("copied to %v, want %s", r.targets, tc.want)
+			}
+		})
+	}
+}
+
+func TestCopyFromNodeDirectory(t *testing.T) {
+	dir, err := ioutil.TempDir("", "cp")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		name string
+		dst  string
+		want string
+	}{
+		{"file", filepath.Join(dir, "node-hosts"), filepath.Join(dir, "node-hosts")},
+		{"directory", dir, filepath.Join(dir, "hosts")},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			r := command.NewFakeCommandRunner()
+			r.SetFileToContents(map[string]string{"/etc/hosts": "127.0.0.1 localhost\n"})
+
+			if err := CopyFromNode(r, "/etc/hosts", tc.dst); err != nil {
+				t.Fatalf("CopyFromNode: %v", err)
+			}
+			if b, err := ioutil.ReadFile(tc.want); err != nil || string(b) != "127.0.0.1 localhost\n" {
+				t.Errorf("%s = %q, %v, want the file of the node", tc.want, b, err)
+			}
+		})
+	}
+}
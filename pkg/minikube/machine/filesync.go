@@ -122,6 +122,13 @@ func assetsFromDir(localRoot string, destRoot string, flatten bool) ([]assets.Co
 			return err
 		}
 		if fi.IsDir() {
+			// external addons are rendered and applied by `minikube addons enable` rather than synced as-is
+			if flatten && localPath != localRoot {
+				if _, err := os.Stat(filepath.Join(localPath, assets.AddonManifestFile)); err == nil {
+					klog.Infof("skipping external addon %s", localPath)
+					return filepath.SkipDir
+				}
+			}
 			return nil
 		}
 
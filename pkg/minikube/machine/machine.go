@@ -24,7 +24,9 @@ import (
 	libprovision "github.com/docker/machine/libmachine/provision"
 	"github.com/pkg/errors"
 	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/drivers/kic/oci"
 	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/minikube/driver"
 	"k8s.io/minikube/pkg/provision"
 )
@@ -122,6 +124,13 @@ func saveHost(api libmachine.API, h *host.Host, cfg *config.ClusterConfig, n *co
 	if err != nil {
 		return err
 	}
+	// the nodes of IPv6 clusters are addressed through the IPv6 address of their container
+	if cfg.KubernetesConfig.IPFamily == constants.IPv6Family && driver.IsDocker(h.DriverName) {
+		_, ip, err = oci.ContainerIPs(oci.Docker, h.Name)
+		if err != nil {
+			return errors.Wrap(err, "container IPv6 address")
+		}
+	}
 	n.IP = ip
 	return config.SaveNode(cfg, n)
 }
@@ -136,12 +136,14 @@ func createHost(api libmachine.API, cfg *config.ClusterConfig, n *config.Node) (
 			See https://minikube.sigs.k8s.io/docs/reference/drivers/vmware/ for more information.
 			To disable this message, run [minikube config set ShowDriverDeprecationNotification false]`)
 	}
-	showHostInfo(*cfg)
+	// drivers size machines from the cluster config, so hand them one carrying the resources of this node
+	ncfg := nodeResources(*cfg, *n)
+	showHostInfo(ncfg)
 	def := registry.Driver(cfg.Driver)
 	if def.Empty() {
 		return nil, fmt.Errorf("unsupported/missing driver: %s", cfg.Driver)
 	}
-	dd, err := def.Config(*cfg, *n)
+	dd, err := def.Config(ncfg, *n)
 	if err != nil {
 		return nil, errors.Wrap(err, "config")
 	}
@@ -320,6 +322,20 @@ func acquireMachinesLock(name string, drv string) (mutex.Releaser, error) {
 	return r, err
 }
 
+// nodeResources returns the cluster config with any resources specific to node n applied
+func nodeResources(cfg config.ClusterConfig, n config.Node) config.ClusterConfig {
+	if n.CPUs > 0 {
+		cfg.CPUs = n.CPUs
+	}
+	if n.Memory > 0 {
+		cfg.Memory = n.Memory
+	}
+	if n.DiskSize > 0 {
+		cfg.DiskSize = n.DiskSize
+	}
+	return cfg
+}
+
 // showHostInfo shows host information
 func showHostInfo(cfg config.ClusterConfig) {
 	machineType := driver.MachineType(cfg.Driver)
@@ -0,0 +1,153 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package metrics exposes Prometheus metrics for long-running minikube processes, such as tunnel, mount and scheduled stop
+package metrics
+
+import (
+	"net"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"k8s.io/klog/v2"
+)
+
+const namespace = "minikube"
+
+// registry holds the metrics of minikube, rather than the global prometheus registry
+var registry = prometheus.NewRegistry()
+
+var (
+	// TunnelRoutesAdded counts the routes added by minikube tunnel
+	TunnelRoutesAdded = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "tunnel",
+		Name:      "routes_added_total",
+		Help:      "Number of routes to the cluster added by the tunnel.",
+	})
+	// TunnelRoutesRemoved counts the routes removed by minikube tunnel
+	TunnelRoutesRemoved = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "tunnel",
+		Name:      "routes_removed_total",
+		Help:      "Number of routes to the cluster removed by the tunnel.",
+	})
+	// TunnelPatchedServices is the number of LoadBalancer services patched by minikube tunnel
+	TunnelPatchedServices = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "tunnel",
+		Name:      "patched_services",
+		Help:      "Number of LoadBalancer services whose ingress is set by the tunnel.",
+	})
+
+	mountRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "mount",
+		Name:      "9p_requests_total",
+		Help:      "Number of 9P requests served by the mount file server, by operation.",
+	}, []string{"op"})
+	mountReceivedBytes = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "mount",
+		Name:      "9p_received_bytes_total",
+		Help:      "Number of bytes of 9P requests received by the mount file server.",
+	})
+	mountSentBytes = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "mount",
+		Name:      "9p_sent_bytes_total",
+		Help:      "Number of bytes of 9P responses sent by the mount file server.",
+	})
+	mountRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "mount",
+		Name:      "9p_request_duration_seconds",
+		Help:      "Time taken by the mount file server to respond to 9P requests, by operation.",
+		// from 50µs to 13s
+		Buckets: prometheus.ExponentialBuckets(0.00005, 4, 10),
+	}, []string{"op"})
+
+	// scheduledStopDeadline is the time of the scheduled stop, in nanoseconds since the epoch, or 0 if there is none
+	scheduledStopDeadline int64
+	scheduledStopRemaining = prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "scheduled_stop",
+		Name:      "remaining_seconds",
+		Help:      "Time remaining until the scheduled stop of the cluster.",
+	}, func() float64 {
+		deadline := atomic.LoadInt64(&scheduledStopDeadline)
+		if deadline == 0 {
+			return 0
+		}
+		if remaining := time.Until(time.Unix(0, deadline)); remaining > 0 {
+			return remaining.Seconds()
+		}
+		return 0
+	})
+)
+
+func init() {
+	registry.MustRegister(
+		prometheus.NewGoCollector(),
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+		TunnelRoutesAdded,
+		TunnelRoutesRemoved,
+		TunnelPatchedServices,
+		mountRequests,
+		mountReceivedBytes,
+		mountSentBytes,
+		mountRequestDuration,
+		scheduledStopRemaining,
+	)
+}
+
+// Listen serves the metrics at http://<addr>/metrics in the background
+func Listen(addr string) (net.Addr, error) {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, errors.Wrapf(err, "listen on %s", addr)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+	go func() {
+		if err := http.Serve(l, mux); err != nil {
+			klog.Errorf("metrics server on %s: %v", l.Addr(), err)
+		}
+	}()
+	klog.Infof("serving metrics on http://%s/metrics", l.Addr())
+	return l.Addr(), nil
+}
+
+// NinePObserver records the requests served by the 9P mount file server
+type NinePObserver struct{}
+
+// ObserveRequest records a 9P request, implementing go9p.StatsObserver
+func (NinePObserver) ObserveRequest(op string, tsize, rsize uint32, latency time.Duration) {
+	mountRequests.WithLabelValues(op).Inc()
+	mountReceivedBytes.Add(float64(tsize))
+	mountSentBytes.Add(float64(rsize))
+	mountRequestDuration.WithLabelValues(op).Observe(latency.Seconds())
+}
+
+// SetScheduledStop records the time at which the cluster is scheduled to stop
+func SetScheduledStop(deadline time.Time) {
+	atomic.StoreInt64(&scheduledStopDeadline, deadline.UnixNano())
+}
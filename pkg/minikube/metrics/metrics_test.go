@@ -0,0 +1,60 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestListen(t *testing.T) {
+	addr, err := Listen("127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+
+	TunnelRoutesAdded.Inc()
+	NinePObserver{}.ObserveRequest("read", 23, 4119, 2*time.Millisecond)
+	SetScheduledStop(time.Now().Add(time.Hour))
+
+	resp, err := http.Get(fmt.Sprintf("http://%s/metrics", addr))
+	if err != nil {
+		t.Fatalf("get metrics: %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("read metrics: %v", err)
+	}
+
+	for _, want := range []string{
+		"minikube_tunnel_routes_added_total 1",
+		`minikube_mount_9p_requests_total{op="read"} 1`,
+		"minikube_mount_9p_received_bytes_total 23",
+		"minikube_mount_9p_sent_bytes_total 4119",
+		`minikube_mount_9p_request_duration_seconds_count{op="read"} 1`,
+		"minikube_scheduled_stop_remaining_seconds 35",
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("metrics do not contain %q:\n%s", want, body)
+		}
+	}
+}
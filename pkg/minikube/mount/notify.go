@@ -0,0 +1,168 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mount
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/kballard/go-shellquote"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/out"
+)
+
+// touchBatch is how many files are touched by each command
+const touchBatch = 100
+
+// Notifier forwards the changes of a host directory to the node it is mounted on, where the mount itself generates no
+// inotify events. Each changed file is touched on the node with its modification time on the host, which generates
+// an IN_MODIFY event without changing the file. The removal of a file touches its directory instead.
+type Notifier struct {
+	r      command.Runner
+	root   string
+	target string
+	w      *fsnotify.Watcher
+}
+
+// NewNotifier starts watching root, which is mounted on target
+func NewNotifier(r command.Runner, root string, target string) (*Notifier, error) {
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, errors.Wrap(err, "watcher")
+	}
+	n := &Notifier{
+		r:      r,
+		root:   filepath.Clean(root),
+		target: target,
+		w:      w,
+	}
+	if err := n.watch(n.root); err != nil {
+		w.Close()
+		return nil, err
+	}
+	return n, nil
+}
+
+// Run forwards changes until ctx is done
+func (n *Notifier) Run(ctx context.Context) error {
+	defer n.w.Close()
+
+	pending := map[string]bool{}
+	settled := time.NewTimer(settleTime)
+	settled.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case ev, ok := <-n.w.Events:
+			if !ok {
+				return nil
+			}
+			// touching a file on the node changes its attributes on the host: these changes are not forwarded back
+			if ev.Op == fsnotify.Chmod {
+				continue
+			}
+			if ev.Op&(fsnotify.Remove|fsnotify.Rename) != 0 {
+				pending[filepath.Dir(ev.Name)] = true
+			} else {
+				pending[ev.Name] = true
+			}
+			if ev.Op&fsnotify.Create != 0 {
+				if fi, err := os.Lstat(ev.Name); err == nil && fi.IsDir() {
+					if err := n.watch(ev.Name); err != nil {
+						klog.Warningf("watch %s: %v", ev.Name, err)
+					}
+				}
+			}
+			settled.Reset(settleTime)
+		case err, ok := <-n.w.Errors:
+			if !ok {
+				return nil
+			}
+			klog.Warningf("watch %s: %v", n.root, err)
+		case <-settled.C:
+			if err := n.forward(pending); err != nil {
+				out.WarningT("Unable to forward the changes of {{.path}}: {{.error}}", out.V{"path": n.root, "error": err})
+			}
+			pending = map[string]bool{}
+		}
+	}
+}
+
+// watch watches a host directory and the directories below it
+func (n *Notifier) watch(dir string) error {
+	return filepath.Walk(dir, func(p string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !fi.IsDir() {
+			return nil
+		}
+		return errors.Wrapf(n.w.Add(p), "watch %s", p)
+	})
+}
+
+// forward touches the files of the node which correspond to changed host paths
+func (n *Notifier) forward(pending map[string]bool) error {
+	paths := []string{}
+	for p := range pending {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	var touches []string
+	for _, p := range paths {
+		rel, err := filepath.Rel(n.root, p)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			continue
+		}
+		// paths removed since they changed are forwarded by the change of their directory
+		fi, err := os.Lstat(p)
+		if err != nil || !(fi.IsDir() || fi.Mode().IsRegular()) {
+			continue
+		}
+		// only the modification time is set, as setting both times generates IN_ATTRIB rather than IN_MODIFY
+		t := fi.ModTime()
+		dst := path.Join(n.target, filepath.ToSlash(rel))
+		touches = append(touches, fmt.Sprintf("touch -c -m -d @%d.%09d %s", t.Unix(), t.Nanosecond(), shellquote.Join(dst)))
+	}
+
+	for len(touches) > 0 {
+		c := len(touches)
+		if c > touchBatch {
+			c = touchBatch
+		}
+		// touch -c ignores the files which were removed from the node before they are touched
+		if _, err := n.r.RunCmd(exec.Command("sudo", "/bin/bash", "-c", strings.Join(touches[:c], " && "))); err != nil {
+			return errors.Wrap(err, "touch")
+		}
+		klog.Infof("forwarded the changes of %d paths", c)
+		touches = touches[c:]
+	}
+	return nil
+}
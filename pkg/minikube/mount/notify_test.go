@@ -0,0 +1,72 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mount
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+
+	"k8s.io/minikube/pkg/minikube/command"
+)
+
+func TestNotifyForward(t *testing.T) {
+	root, err := ioutil.TempDir("", "notify")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(root)
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		t.Fatalf("watcher: %v", err)
+	}
+	defer w.Close()
+
+	mtime := time.Unix(1600000000, 123456789)
+	for _, rel := range []string{"app.js", "src dir/main.go", "src dir"} {
+		p := filepath.Join(root, filepath.FromSlash(rel))
+		if filepath.Ext(p) != "" {
+			writeFile(t, p, "changed")
+		}
+		if err := os.Chtimes(p, mtime, mtime); err != nil {
+			t.Fatalf("chtimes: %v", err)
+		}
+	}
+
+	r := command.NewFakeCommandRunner()
+	want := exec.Command("sudo", "/bin/bash", "-c", "touch -c -m -d @1600000000.123456789 /target/app.js && "+
+		"touch -c -m -d @1600000000.123456789 '/target/src dir' && "+
+		"touch -c -m -d @1600000000.123456789 '/target/src dir/main.go'")
+	r.SetCommandToOutput(map[string]string{(&command.RunResult{Args: want.Args}).Command(): ""})
+
+	n := &Notifier{r: r, root: root, target: "/target", w: w}
+	pending := map[string]bool{
+		filepath.Join(root, "app.js"):              true,
+		filepath.Join(root, "src dir"):             true,
+		filepath.Join(root, "src dir", "main.go"):  true,
+		filepath.Join(root, "removed.txt"):         true,
+		filepath.Join(filepath.Dir(root), "other"): true,
+	}
+	if err := n.forward(pending); err != nil {
+		t.Errorf("forward: %v", err)
+	}
+}
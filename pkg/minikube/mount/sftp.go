@@ -0,0 +1,290 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mount
+
+import (
+	"encoding/binary"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/pkg/sftp"
+	"k8s.io/klog/v2"
+)
+
+// SFTP packet types, and status codes, which the confined server looks at
+const (
+	fxpOpen     = 3
+	fxpLstat    = 7
+	fxpSetstat  = 9
+	fxpOpendir  = 11
+	fxpRemove   = 13
+	fxpMkdir    = 14
+	fxpRmdir    = 15
+	fxpRealpath = 16
+	fxpStat     = 17
+	fxpRename   = 18
+	fxpReadlink = 19
+	fxpSymlink  = 20
+	fxpStatus   = 101
+	fxpName     = 104
+	fxpExtended = 200
+
+	fxPermissionDenied = 3
+	fxOpUnsupported    = 8
+
+	// maxPacket is the size of the largest packet accepted, as in pkg/sftp
+	maxPacket = 256 * 1024
+)
+
+// pathArg tells how a string argument of a request is mapped
+type pathArg int
+
+const (
+	// rawArg is passed as it is, such as the target of a symlink
+	rawArg pathArg = iota
+	// linkArg is a path whose last name may be a symlink, which is not followed
+	linkArg
+	// followArg is a path which is followed, so that its last name must not be a symlink
+	followArg
+)
+
+// pathArgs lists the string arguments of the requests which name files, which follow their id
+var pathArgs = map[byte][]pathArg{
+	fxpOpen:     {followArg},
+	fxpLstat:    {linkArg},
+	fxpSetstat:  {followArg},
+	fxpOpendir:  {followArg},
+	fxpRemove:   {linkArg},
+	fxpMkdir:    {linkArg},
+	fxpRmdir:    {linkArg},
+	fxpStat:     {followArg},
+	fxpRename:   {linkArg, linkArg},
+	fxpReadlink: {linkArg},
+	// as OpenSSH, and pkg/sftp: the target, then the symlink
+	fxpSymlink: {rawArg, linkArg},
+}
+
+// extendedArgs lists the string arguments of the extended requests which are served, which follow their name
+var extendedArgs = map[string][]pathArg{
+	"posix-rename@openssh.com": {linkArg, linkArg},
+	"hardlink@openssh.com":     {linkArg, linkArg},
+	"statvfs@openssh.com":      {followArg},
+	"fstatvfs@openssh.com":     {},
+}
+
+// ConfinedSFTPServer returns an SFTP server of root: the paths of the requests are relative to root, whatever
+// they start with, and the requests which would go through symlinks, possibly out of root, are refused.
+func ConfinedSFTPServer(rw io.ReadWriteCloser, root string) (*sftp.Server, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, errors.Wrap(err, "abs")
+	}
+	c := &confiner{root: root, w: rw}
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(c.filter(rw, pw))
+	}()
+	return sftp.NewServer(struct {
+		io.Reader
+		io.WriteCloser
+	}{pr, c})
+}
+
+// confiner filters the requests to an SFTP server, and writes the responses of the server, and its own
+type confiner struct {
+	root string
+
+	mu sync.Mutex
+	w  io.WriteCloser
+}
+
+// Write writes a packet, which pkg/sftp writes at once
+func (c *confiner) Write(b []byte) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.w.Write(b)
+}
+
+func (c *confiner) Close() error {
+	return c.w.Close()
+}
+
+// filter copies the requests read from r to w, mapping their paths, until r ends. It responds to the requests
+// which are refused, and to realpath requests, itself.
+func (c *confiner) filter(r io.Reader, w io.Writer) error {
+	var hdr [4]byte
+	for {
+		if _, err := io.ReadFull(r, hdr[:]); err != nil {
+			return err
+		}
+		n := binary.BigEndian.Uint32(hdr[:])
+		if n == 0 || n > maxPacket {
+			return errors.Errorf("invalid packet length %d", n)
+		}
+		p := make([]byte, n)
+		if _, err := io.ReadFull(r, p); err != nil {
+			return err
+		}
+
+		out, err := c.request(p)
+		if err != nil {
+			return err
+		}
+		if out == nil {
+			continue
+		}
+		binary.BigEndian.PutUint32(hdr[:], uint32(len(out)))
+		if _, err := w.Write(append(hdr[:], out...)); err != nil {
+			return err
+		}
+	}
+}
+
+// request returns the packet p, of which the paths are mapped to root, or nil if it was responded to
+func (c *confiner) request(p []byte) ([]byte, error) {
+	typ := p[0]
+	args, ok := pathArgs[typ]
+	if typ != fxpRealpath && typ != fxpExtended && !ok {
+		// requests on handles, and the init request
+		return p, nil
+	}
+
+	id, rest, err := readUint32(p[1:])
+	if err != nil {
+		return nil, err
+	}
+	out := append([]byte{typ}, p[1:5]...)
+	if typ == fxpExtended {
+		var name string
+		if name, rest, err = readString(rest); err != nil {
+			return nil, err
+		}
+		if args, ok = extendedArgs[name]; !ok {
+			return nil, c.status(id, fxOpUnsupported, "unsupported extended request "+name)
+		}
+		out = appendString(out, name)
+	}
+	if typ == fxpRealpath {
+		var rp string
+		if rp, _, err = readString(rest); err != nil {
+			return nil, err
+		}
+		return nil, c.name(id, path.Clean("/"+rp))
+	}
+
+	for _, a := range args {
+		var s string
+		if s, rest, err = readString(rest); err != nil {
+			return nil, err
+		}
+		if a != rawArg {
+			host, ok := c.hostPath(s, a == followArg)
+			if !ok {
+				klog.Warningf("sftp: refused request %d for %q, which goes through a symlink", typ, s)
+				return nil, c.status(id, fxPermissionDenied, "permission denied")
+			}
+			s = host
+		}
+		out = appendString(out, s)
+	}
+	return append(out, rest...), nil
+}
+
+// hostPath returns the path of the host which an SFTP path is mapped to, and whether it may be used: neither any
+// of its parents below root, nor the file itself if it is followed, is a symlink.
+func (c *confiner) hostPath(p string, follow bool) (string, bool) {
+	rel := strings.TrimPrefix(path.Clean("/"+p), "/")
+	if rel == "" {
+		return c.root, true
+	}
+	// on windows, names must not hold separators or drives of their own
+	if filepath.Separator != '/' && strings.ContainsAny(rel, `\:`) {
+		return "", false
+	}
+	names := strings.Split(rel, "/")
+	cur := c.root
+	for i, name := range names {
+		cur = filepath.Join(cur, name)
+		if i == len(names)-1 && !follow {
+			break
+		}
+		fi, err := os.Lstat(cur)
+		if os.IsNotExist(err) {
+			// files which do not exist are not symlinks, and neither are the files below them
+			break
+		}
+		if err != nil || fi.Mode()&os.ModeSymlink != 0 {
+			return "", false
+		}
+	}
+	return filepath.Join(c.root, filepath.FromSlash(rel)), true
+}
+
+// status responds to a request with a status
+func (c *confiner) status(id uint32, code uint32, msg string) error {
+	p := appendUint32([]byte{fxpStatus}, id)
+	p = appendUint32(p, code)
+	p = appendString(p, msg)
+	p = appendString(p, "")
+	return c.respond(p)
+}
+
+// name responds to a request with a single name, without attributes
+func (c *confiner) name(id uint32, name string) error {
+	p := appendUint32([]byte{fxpName}, id)
+	p = appendUint32(p, 1)
+	p = appendString(p, name)
+	p = appendString(p, name)
+	p = appendUint32(p, 0)
+	return c.respond(p)
+}
+
+func (c *confiner) respond(p []byte) error {
+	_, err := c.Write(append(appendUint32(nil, uint32(len(p))), p...))
+	return err
+}
+
+func readUint32(b []byte) (uint32, []byte, error) {
+	if len(b) < 4 {
+		return 0, nil, errors.New("short packet")
+	}
+	return binary.BigEndian.Uint32(b), b[4:], nil
+}
+
+func readString(b []byte) (string, []byte, error) {
+	n, b, err := readUint32(b)
+	if err != nil {
+		return "", nil, err
+	}
+	if uint32(len(b)) < n {
+		return "", nil, errors.New("short packet")
+	}
+	return string(b[:n]), b[n:], nil
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	return append(b, byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+}
+
+func appendString(b []byte, s string) []byte {
+	return append(appendUint32(b, uint32(len(s))), s...)
+}
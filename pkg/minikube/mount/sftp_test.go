@@ -0,0 +1,134 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mount
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/sftp"
+)
+
+// confinedClient serves root with a confined SFTP server, and returns a client of it
+func confinedClient(t *testing.T, root string) *sftp.Client {
+	// requests flow from the client to the server through c2s, and responses back through s2c
+	c2sr, c2sw := io.Pipe()
+	s2cr, s2cw := io.Pipe()
+	srv, err := ConfinedSFTPServer(struct {
+		io.Reader
+		io.WriteCloser
+	}{c2sr, s2cw}, root)
+	if err != nil {
+		t.Fatalf("ConfinedSFTPServer: %v", err)
+	}
+	go srv.Serve()
+
+	c, err := sftp.NewClientPipe(s2cr, c2sw)
+	if err != nil {
+		t.Fatalf("NewClientPipe: %v", err)
+	}
+	t.Cleanup(func() {
+		// closing the server ends the client's response stream, which Close waits for
+		srv.Close()
+		c.Close()
+	})
+	return c
+}
+
+func TestConfinedSFTPServer(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "sftp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+	root := filepath.Join(tmp, "root")
+	secret := filepath.Join(tmp, "secret")
+	for _, d := range []string{filepath.Join(root, "src"), secret} {
+		if err := os.MkdirAll(d, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, f := range []string{filepath.Join(root, "src", "main.go"), filepath.Join(secret, "key")} {
+		if err := ioutil.WriteFile(f, []byte("data"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Symlink(secret, filepath.Join(root, "escape")); err != nil {
+		t.Fatal(err)
+	}
+
+	c := confinedClient(t, root)
+
+	if _, err := c.Stat("/src/main.go"); err != nil {
+		t.Errorf("stat of a file of the root: %v", err)
+	}
+	// absolute paths, and .., are relative to the root
+	for _, p := range []string{"/../secret/key", "../secret/key", filepath.ToSlash(filepath.Join(secret, "key"))} {
+		if _, err := c.Stat(p); err == nil {
+			t.Errorf("stat of %s succeeded", p)
+		}
+	}
+	if got, err := c.Getwd(); err != nil || got != "/" {
+		t.Errorf("Getwd() = %q, %v, want /", got, err)
+	}
+
+	// symlinks are listed, but not followed
+	if fi, err := c.Lstat("/escape"); err != nil || fi.Mode()&os.ModeSymlink == 0 {
+		t.Errorf("lstat of the symlink = %v, %v, want a symlink", fi, err)
+	}
+	if target, err := c.ReadLink("/escape"); err != nil || target != secret {
+		t.Errorf("readlink of the symlink = %q, %v, want %q", target, err, secret)
+	}
+	for _, p := range []string{"/escape", "/escape/key"} {
+		if _, err := c.Stat(p); err == nil {
+			t.Errorf("stat of %s succeeded", p)
+		}
+	}
+	if f, err := c.OpenFile("/escape/key", os.O_WRONLY|os.O_TRUNC); err == nil {
+		f.Close()
+		t.Error("open through the symlink succeeded")
+	}
+	if err := c.Chmod("/escape", 0777); err == nil {
+		t.Error("chmod of the symlink succeeded")
+	}
+	if err := c.Rename("/src/main.go", "/escape/main.go"); err == nil {
+		t.Error("rename through the symlink succeeded")
+	}
+	if b, err := ioutil.ReadFile(filepath.Join(secret, "key")); err != nil || string(b) != "data" {
+		t.Errorf("%s = %q, %v, want %q", filepath.Join(secret, "key"), b, err, "data")
+	}
+
+	// files opened for reading and writing work as usual
+	f, err := c.OpenFile("/src/main.go", os.O_RDWR)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer f.Close()
+	if _, err := f.Write([]byte("DA")); err != nil {
+		t.Errorf("write: %v", err)
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		t.Fatalf("seek: %v", err)
+	}
+	b := make([]byte, 4)
+	if _, err := io.ReadFull(f, b); err != nil || string(b) != "DAta" {
+		t.Errorf("read = %q, %v, want %q", b, err, "DAta")
+	}
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package mount shares host directories with the nodes of a cluster over SSH: with sshfs, or by syncing them
+package mount
+
+import (
+	"bytes"
+	"io"
+	"os/exec"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
+	"k8s.io/klog/v2"
+
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/command"
+)
+
+// mountTimeout is how long to wait for sshfs to mount
+const mountTimeout = 30 * time.Second
+
+// SSHFS mounts root on target with sshfs, which runs on the node in slave mode: it talks SFTP with a server
+// of the host over the stdin and stdout of an SSH session, so that no port of the host needs to be reachable.
+// It returns once the mount is ready, with a channel which receives the result of the session once it ends.
+func SSHFS(client *ssh.Client, r command.Runner, root string, target string, c *cluster.MountConfig) (<-chan error, error) {
+	if _, err := r.RunCmd(exec.Command("/bin/bash", "-c", "command -v sshfs")); err != nil {
+		return nil, errors.New("sshfs is not installed on the node")
+	}
+	if err := cluster.PrepareMount(r, target, c); err != nil {
+		return nil, err
+	}
+
+	sess, err := client.NewSession()
+	if err != nil {
+		return nil, errors.Wrap(err, "NewSession")
+	}
+	stdin, err := sess.StdinPipe()
+	if err != nil {
+		return nil, errors.Wrap(err, "StdinPipe")
+	}
+	stdout, err := sess.StdoutPipe()
+	if err != nil {
+		return nil, errors.Wrap(err, "StdoutPipe")
+	}
+	var stderr bytes.Buffer
+	sess.Stderr = &stderr
+
+	// the server confines the requests of sshfs, which runs as root on the node, to root
+	srv, err := ConfinedSFTPServer(struct {
+		io.Reader
+		io.WriteCloser
+	}{stdout, stdin}, root)
+	if err != nil {
+		return nil, errors.Wrap(err, "sftp server")
+	}
+
+	cmd := cluster.SSHFSCmd(":/", target, c)
+	klog.Infof("sshfs: %s", cmd)
+	if err := sess.Start(cmd); err != nil {
+		return nil, errors.Wrapf(err, "start %s", cmd)
+	}
+	go func() {
+		if err := srv.Serve(); err != nil && err != io.EOF {
+			klog.Warningf("sftp server: %v", err)
+		}
+	}()
+
+	done := make(chan error, 1)
+	go func() {
+		err := sess.Wait()
+		srv.Close()
+		if err != nil {
+			err = errors.Wrapf(err, "sshfs: %s", stderr.String())
+		}
+		done <- err
+	}()
+
+	deadline := time.Now().Add(mountTimeout)
+	for {
+		if _, err := r.RunCmd(exec.Command("findmnt", target)); err == nil {
+			return done, nil
+		}
+		select {
+		case err := <-done:
+			if err == nil {
+				err = errors.New("sshfs exited")
+			}
+			return nil, err
+		case <-time.After(time.Second):
+		}
+		if time.Now().After(deadline) {
+			sess.Close()
+			return nil, errors.Errorf("%s was not mounted after %s: %v", target, mountTimeout, <-done)
+		}
+	}
+}
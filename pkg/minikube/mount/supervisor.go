@@ -0,0 +1,260 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mount
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	ps "github.com/mitchellh/go-ps"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+
+	"k8s.io/minikube/pkg/minikube/constants"
+	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/util/lock"
+)
+
+const (
+	// minRestartDelay is how long a mount process which exited is waited for before it is restarted
+	minRestartDelay = time.Second
+	// maxRestartDelay bounds the restart delay, which doubles as long as the mount process keeps exiting
+	maxRestartDelay = time.Minute
+	// healthyRun is how long a mount process must have run for the restart delay to be reset
+	healthyRun = time.Minute
+	// stopTimeout is how long a process is given to unmount before it is killed
+	stopTimeout = 30 * time.Second
+)
+
+// StartSupervisor starts the supervisor of a persistent mount of a cluster in the background, which runs minikube
+// mount with the options recorded in the cluster config. It returns the pid of the supervisor, which may already run.
+func StartSupervisor(profile string, guestPath string) (int, error) {
+	if pid, ok := SupervisorPID(profile, guestPath); ok {
+		return pid, nil
+	}
+	pidFile := localpath.MountPID(profile, guestPath)
+	if err := os.MkdirAll(filepath.Dir(pidFile), 0o755); err != nil {
+		return 0, err
+	}
+	logFile := localpath.MountLog(profile, guestPath)
+	f, err := os.OpenFile(logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return 0, errors.Wrapf(err, "opening %s", logFile)
+	}
+	defer f.Close()
+
+	cmd := exec.Command(os.Args[0], "mount", "-p", profile, "--supervise", guestPath)
+	cmd.Env = append(os.Environ(), constants.IsMinikubeChildProcess+"=true")
+	cmd.Stdout = f
+	cmd.Stderr = f
+	detach(cmd)
+	if err := cmd.Start(); err != nil {
+		return 0, errors.Wrap(err, "starting mount supervisor")
+	}
+	pid := cmd.Process.Pid
+	if err := writePIDs(pidFile, pid); err != nil {
+		return pid, err
+	}
+	return pid, cmd.Process.Release()
+}
+
+// SupervisorPID returns the pid of the supervisor of a persistent mount, and whether it is running
+func SupervisorPID(profile string, guestPath string) (int, bool) {
+	pids := readPIDs(localpath.MountPID(profile, guestPath))
+	if len(pids) == 0 || !running(pids[0]) {
+		return 0, false
+	}
+	return pids[0], true
+}
+
+// StopSupervisor stops the supervisor of a persistent mount, which interrupts its mount process so that it unmounts.
+// Processes which cannot be interrupted, as on Windows, are killed. It returns the pid of the supervisor, or 0 if it was not running.
+func StopSupervisor(profile string, guestPath string) (int, error) {
+	pidFile := localpath.MountPID(profile, guestPath)
+	pids := readPIDs(pidFile)
+	if len(pids) == 0 || !running(pids[0]) {
+		if err := os.Remove(pidFile); err != nil && !os.IsNotExist(err) {
+			return 0, err
+		}
+		return 0, nil
+	}
+
+	p, err := os.FindProcess(pids[0])
+	if err != nil {
+		return 0, err
+	}
+	if err := p.Signal(os.Interrupt); err != nil {
+		klog.Infof("unable to interrupt %d, killing it and its mount process: %v", pids[0], err)
+		for _, pid := range pids {
+			if c, err := os.FindProcess(pid); err == nil && running(pid) {
+				if err := c.Kill(); err != nil {
+					return pids[0], errors.Wrapf(err, "killing %d", pid)
+				}
+			}
+		}
+		return pids[0], os.Remove(pidFile)
+	}
+	// the supervisor removes its pid file once its mount process has exited
+	for start := time.Now(); time.Since(start) < stopTimeout+5*time.Second; time.Sleep(500 * time.Millisecond) {
+		if !running(pids[0]) {
+			return pids[0], nil
+		}
+	}
+	klog.Warningf("%d did not exit, killing it", pids[0])
+	return pids[0], p.Kill()
+}
+
+// Supervise runs the mount processes returned by next, running the next one whenever the previous one exits, after a
+// delay which grows while they keep exiting quickly. It returns once next returns no process, or ctx is done, at which
+// point the running mount process is interrupted. pidFile records the pid of the supervisor and of its mount process.
+func Supervise(ctx context.Context, pidFile string, next func() (*exec.Cmd, error)) error {
+	if err := writePIDs(pidFile, os.Getpid()); err != nil {
+		klog.Warningf("unable to write %s: %v", pidFile, err)
+	}
+	defer os.Remove(pidFile)
+
+	var delay time.Duration
+	for {
+		start := time.Now()
+		cmd, err := next()
+		switch {
+		case err != nil:
+			out.WarningT("Unable to start the mount process: {{.error}}", out.V{"error": err})
+		case cmd == nil:
+			klog.Infof("the mount is no longer recorded, exiting")
+			return nil
+		default:
+			if err := cmd.Start(); err != nil {
+				out.WarningT("Unable to start the mount process: {{.error}}", out.V{"error": err})
+				break
+			}
+			if err := writePIDs(pidFile, os.Getpid(), cmd.Process.Pid); err != nil {
+				klog.Warningf("unable to write %s: %v", pidFile, err)
+			}
+			done := make(chan error, 1)
+			go func() {
+				done <- cmd.Wait()
+			}()
+			select {
+			case err := <-done:
+				if err != nil {
+					out.WarningT("The mount process {{.pid}} exited: {{.error}}", out.V{"pid": cmd.Process.Pid, "error": err})
+				} else {
+					out.WarningT("The mount process {{.pid}} exited", out.V{"pid": cmd.Process.Pid})
+				}
+			case <-ctx.Done():
+				return interrupt(cmd.Process, done)
+			}
+		}
+
+		delay = restartDelay(delay, time.Since(start))
+		klog.Infof("restarting the mount process in %s", delay)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(delay):
+		}
+	}
+}
+
+// restartDelay returns the delay before a mount process which exited after running for ran is restarted, given the previous delay
+func restartDelay(prev time.Duration, ran time.Duration) time.Duration {
+	if prev == 0 || ran >= healthyRun {
+		return minRestartDelay
+	}
+	if prev*2 > maxRestartDelay {
+		return maxRestartDelay
+	}
+	return prev * 2
+}
+
+// interrupt interrupts a process, whose exit is sent to done, and kills it if it cannot be interrupted or does not exit in time
+func interrupt(p *os.Process, done <-chan error) error {
+	if err := p.Signal(os.Interrupt); err != nil {
+		klog.Infof("unable to interrupt %d, killing it: %v", p.Pid, err)
+		return p.Kill()
+	}
+	select {
+	case <-done:
+		return nil
+	case <-time.After(stopTimeout):
+		klog.Warningf("%d did not exit within %s, killing it", p.Pid, stopTimeout)
+		return p.Kill()
+	}
+}
+
+// writePIDs writes pids to a pid file, one per line
+func writePIDs(pidFile string, pids ...int) error {
+	lines := []string{}
+	for _, pid := range pids {
+		lines = append(lines, strconv.Itoa(pid))
+	}
+	return lock.WriteFile(pidFile, []byte(strings.Join(lines, "\n")), 0o644)
+}
+
+// readPIDs reads the pids of a pid file, which may not exist
+func readPIDs(pidFile string) []int {
+	b, err := ioutil.ReadFile(pidFile)
+	if err != nil {
+		return nil
+	}
+	pids := []int{}
+	for _, line := range strings.Fields(string(b)) {
+		pid, err := strconv.Atoi(line)
+		if err != nil {
+			klog.Warningf("invalid pid in %s: %q", pidFile, line)
+			return nil
+		}
+		pids = append(pids, pid)
+	}
+	return pids
+}
+
+// running tells if a process is running minikube. The pids of pid files may have been reused by other processes since
+// they were written, such as after a reboot, which must not be mistaken for, or signalled as, mount processes.
+func running(pid int) bool {
+	// os.FindProcess does not check if pid is running
+	p, err := ps.FindProcess(pid)
+	if err != nil {
+		klog.Warningf("unable to find process %d: %v", pid, err)
+		return false
+	}
+	if p == nil {
+		return false
+	}
+	if !isMinikube(p.Executable()) {
+		klog.Infof("process %d runs %s, not minikube", pid, p.Executable())
+		return false
+	}
+	return true
+}
+
+// isMinikube tells if the name of the executable of a process is that of minikube: the name of this executable, or a
+// name containing minikube, such as minikube-linux-amd64, as the executable may have been replaced since
+func isMinikube(name string) bool {
+	if self, err := os.Executable(); err == nil && strings.EqualFold(filepath.Base(self), name) {
+		return true
+	}
+	return strings.Contains(strings.ToLower(name), "minikube")
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mount
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRestartDelay(t *testing.T) {
+	tests := []struct {
+		prev time.Duration
+		ran  time.Duration
+		want time.Duration
+	}{
+		{0, time.Second, minRestartDelay},
+		{minRestartDelay, time.Second, 2 * minRestartDelay},
+		{4 * time.Second, 0, 8 * time.Second},
+		{40 * time.Second, time.Second, maxRestartDelay},
+		{maxRestartDelay, time.Second, maxRestartDelay},
+		{maxRestartDelay, healthyRun, minRestartDelay},
+	}
+	for _, tc := range tests {
+		if got := restartDelay(tc.prev, tc.ran); got != tc.want {
+			t.Errorf("restartDelay(%s, %s) = %s, want %s", tc.prev, tc.ran, got, tc.want)
+		}
+	}
+}
+
+func TestPIDs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pids")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	pidFile := filepath.Join(dir, "mount.pid")
+
+	if pids := readPIDs(pidFile); len(pids) != 0 {
+		t.Errorf("readPIDs of a missing file = %v, want none", pids)
+	}
+	if err := writePIDs(pidFile, 42, 43); err != nil {
+		t.Fatalf("writePIDs: %v", err)
+	}
+	if pids := readPIDs(pidFile); !reflect.DeepEqual(pids, []int{42, 43}) {
+		t.Errorf("readPIDs = %v, want [42 43]", pids)
+	}
+	if err := ioutil.WriteFile(pidFile, []byte("garbage"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	if pids := readPIDs(pidFile); len(pids) != 0 {
+		t.Errorf("readPIDs of an invalid file = %v, want none", pids)
+	}
+	if !running(os.Getpid()) {
+		t.Errorf("the test process is not running")
+	}
+}
+
+func TestIsMinikube(t *testing.T) {
+	self, err := os.Executable()
+	if err != nil {
+		t.Fatalf("executable: %v", err)
+	}
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"minikube", true},
+		{"minikube.exe", true},
+		{"minikube-linux-amd64", true},
+		{filepath.Base(self), true},
+		{"systemd", false},
+		{"sshd", false},
+	}
+	for _, tc := range tests {
+		if got := isMinikube(tc.name); got != tc.want {
+			t.Errorf("isMinikube(%q) = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
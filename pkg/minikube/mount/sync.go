@@ -0,0 +1,424 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mount
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/out"
+)
+
+const (
+	// settleTime is how long the changes of a file are gathered before it is copied, so that a burst of writes is copied once
+	settleTime = 200 * time.Millisecond
+	// mkdirBatch is how many directories are created by each mkdir command
+	mkdirBatch = 100
+	// tempPrefix is the prefix of the temporary files of the files copied back to the host
+	tempPrefix = ".minikube-sync"
+)
+
+// Syncer copies a host directory to a directory of a node, and then the changes of the host directory as they happen.
+// Two-way syncers also copy the files changed on the node back to the host, but not their removal.
+type Syncer struct {
+	r      command.Runner
+	root   string
+	target string
+	cfg    *cluster.MountConfig
+	w      *fsnotify.Watcher
+	// stamp is the file of the node which is touched whenever the node is looked for changed files
+	stamp string
+	// synced are the hashes of the files as last copied in either direction, so that copies are not copied back
+	synced map[string]string
+}
+
+// NewSyncer prepares target, copies root to it, and starts watching root for changes
+func NewSyncer(r command.Runner, root string, target string, c *cluster.MountConfig) (*Syncer, error) {
+	if err := cluster.PrepareMount(r, target, c); err != nil {
+		return nil, err
+	}
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, errors.Wrap(err, "watcher")
+	}
+	s := &Syncer{
+		r:      r,
+		root:   filepath.Clean(root),
+		target: target,
+		cfg:    c,
+		w:      w,
+		stamp:  "/tmp/minikube-sync" + strings.ReplaceAll(target, "/", "-"),
+		synced: map[string]string{},
+	}
+	if err := s.addTree(s.root); err != nil {
+		w.Close()
+		return nil, err
+	}
+	if c.SyncDirection == cluster.SyncTwoWay {
+		if _, err := r.RunCmd(exec.Command("sudo", "touch", s.stamp)); err != nil {
+			w.Close()
+			return nil, errors.Wrap(err, "touch")
+		}
+	}
+	return s, nil
+}
+
+// Run copies changes until ctx is done
+func (s *Syncer) Run(ctx context.Context) error {
+	defer s.w.Close()
+
+	var poll <-chan time.Time
+	if s.cfg.SyncDirection == cluster.SyncTwoWay {
+		t := time.NewTicker(s.cfg.SyncInterval)
+		defer t.Stop()
+		poll = t.C
+	}
+
+	pending := map[string]bool{}
+	settled := time.NewTimer(settleTime)
+	settled.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case ev, ok := <-s.w.Events:
+			if !ok {
+				return nil
+			}
+			pending[ev.Name] = true
+			settled.Reset(settleTime)
+		case err, ok := <-s.w.Errors:
+			if !ok {
+				return nil
+			}
+			klog.Warningf("watch %s: %v", s.root, err)
+		case <-settled.C:
+			paths := []string{}
+			for p := range pending {
+				paths = append(paths, p)
+			}
+			sort.Strings(paths)
+			for _, p := range paths {
+				if err := s.sync(p); err != nil {
+					out.WarningT("Unable to sync {{.path}}: {{.error}}", out.V{"path": p, "error": err})
+				}
+			}
+			pending = map[string]bool{}
+		case <-poll:
+			if err := s.pull(); err != nil {
+				out.WarningT("Unable to sync the changes of {{.path}}: {{.error}}", out.V{"path": s.target, "error": err})
+			}
+		}
+	}
+}
+
+// rel returns the path of a host file relative to the root, with forward slashes
+func (s *Syncer) rel(p string) (string, error) {
+	rel, err := filepath.Rel(s.root, p)
+	if err != nil {
+		return "", err
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// ignored tells if a path relative to the root matches a pattern to ignore, either as a whole or by any of its names
+func (s *Syncer) ignored(rel string) bool {
+	if strings.HasPrefix(path.Base(rel), tempPrefix) {
+		return true
+	}
+	for _, pattern := range s.cfg.SyncIgnore {
+		if ok, _ := path.Match(pattern, rel); ok {
+			return true
+		}
+		for _, name := range strings.Split(rel, "/") {
+			if ok, _ := path.Match(pattern, name); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sync copies the current state of a host path to the node
+func (s *Syncer) sync(p string) error {
+	rel, err := s.rel(p)
+	if err != nil || s.ignored(rel) {
+		return err
+	}
+	fi, err := os.Lstat(p)
+	if os.IsNotExist(err) {
+		return s.remove(rel)
+	}
+	if err != nil {
+		return err
+	}
+	if fi.IsDir() {
+		return s.addTree(p)
+	}
+	return s.push(p, rel, fi)
+}
+
+// addTree watches a host directory and the directories below it, and copies them to the node
+func (s *Syncer) addTree(dir string) error {
+	var dirs []string
+	var files []string
+	err := filepath.Walk(dir, func(p string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := s.rel(p)
+		if err != nil {
+			return err
+		}
+		if s.ignored(rel) {
+			if fi.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if fi.IsDir() {
+			if err := s.w.Add(p); err != nil {
+				return errors.Wrapf(err, "watch %s", p)
+			}
+			dirs = append(dirs, path.Join(s.target, rel))
+			return nil
+		}
+		files = append(files, p)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for len(dirs) > 0 {
+		n := len(dirs)
+		if n > mkdirBatch {
+			n = mkdirBatch
+		}
+		if _, err := s.r.RunCmd(exec.Command("sudo", append([]string{"mkdir", "-p"}, dirs[:n]...)...)); err != nil {
+			return errors.Wrap(err, "mkdir")
+		}
+		dirs = dirs[n:]
+	}
+	for _, p := range files {
+		rel, err := s.rel(p)
+		if err != nil {
+			return err
+		}
+		fi, err := os.Lstat(p)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if err := s.push(p, rel, fi); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// push copies a host file to the node, unless it is unchanged since it was last copied
+func (s *Syncer) push(p string, rel string, fi os.FileInfo) error {
+	dst := path.Join(s.target, rel)
+	if fi.Mode()&os.ModeSymlink != 0 {
+		link, err := os.Readlink(p)
+		if err != nil {
+			return err
+		}
+		_, err = s.r.RunCmd(exec.Command("sudo", "ln", "-sfn", filepath.ToSlash(link), dst))
+		return err
+	}
+	if !fi.Mode().IsRegular() {
+		return nil
+	}
+
+	sum, err := hashFile(p)
+	if err != nil {
+		return err
+	}
+	if s.synced[rel] == sum {
+		return nil
+	}
+	f, err := assets.NewFileAsset(p, path.Dir(dst), path.Base(dst), fmt.Sprintf("%04o", fi.Mode().Perm()))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := s.r.Copy(f); err != nil {
+		return errors.Wrapf(err, "copy %s", rel)
+	}
+	klog.Infof("synced %s", rel)
+	s.synced[rel] = sum
+	return nil
+}
+
+// remove removes a path relative to the root from the node
+func (s *Syncer) remove(rel string) error {
+	if _, err := s.r.RunCmd(exec.Command("sudo", "rm", "-rf", path.Join(s.target, rel))); err != nil {
+		return err
+	}
+	for r := range s.synced {
+		if r == rel || strings.HasPrefix(r, rel+"/") {
+			delete(s.synced, r)
+		}
+	}
+	klog.Infof("removed %s", rel)
+	return nil
+}
+
+// pull copies the files changed on the node since the last pull back to the host
+func (s *Syncer) pull() error {
+	changed := fmt.Sprintf("sudo touch %[1]s.next && sudo find %[2]s -type f -newer %[1]s -exec sha256sum {} + ; sudo mv %[1]s.next %[1]s", s.stamp, s.target)
+	rr, err := s.r.RunCmd(exec.Command("/bin/bash", "-c", changed))
+	if err != nil {
+		return errors.Wrap(err, "find")
+	}
+
+	sc := bufio.NewScanner(&rr.Stdout)
+	for sc.Scan() {
+		// sha256sum escapes the names with special characters, which are not synced back
+		fields := strings.SplitN(sc.Text(), "  ", 2)
+		if len(fields) != 2 || strings.HasPrefix(fields[0], "\\") {
+			continue
+		}
+		sum, src := fields[0], fields[1]
+		rel := strings.TrimPrefix(src, s.target+"/")
+		if rel == src || s.ignored(rel) || s.synced[rel] == sum {
+			continue
+		}
+		if err := s.confined(rel); err != nil {
+			klog.Warningf("not syncing %s back: %v", rel, err)
+			continue
+		}
+		if err := s.fetch(src, rel, sum); err != nil {
+			return errors.Wrapf(err, "copy %s", rel)
+		}
+	}
+	return sc.Err()
+}
+
+// confined returns an error unless the host path of rel is within the root, without going through a symlink of the
+// host, which files copied back would be written through
+func (s *Syncer) confined(rel string) error {
+	cur := filepath.Clean(s.root)
+	for _, name := range strings.Split(rel, "/") {
+		if name == "" || name == "." || name == ".." {
+			return fmt.Errorf("%q is not a path within %s", rel, s.root)
+		}
+		cur = filepath.Join(cur, name)
+		fi, err := os.Lstat(cur)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if fi.Mode()&os.ModeSymlink != 0 {
+			return fmt.Errorf("%s is a symlink", cur)
+		}
+	}
+	return nil
+}
+
+// fetch copies a file of the node to the host, through a temporary file so that it is replaced at once
+func (s *Syncer) fetch(src string, rel string, sum string) error {
+	dst := filepath.Join(s.root, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(dst), tempPrefix)
+	if err != nil {
+		return err
+	}
+	tmp.Close()
+	defer os.Remove(tmp.Name())
+
+	if err := s.r.CopyFrom(src, tmp.Name()); err != nil {
+		return err
+	}
+	// temporary files are only readable by their owner: the file keeps its mode, or takes that of the node
+	mode, err := s.fileMode(dst, src)
+	if err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), mode); err != nil {
+		return err
+	}
+	// the hash is updated first, so that the change of the file is not copied back
+	s.synced[rel] = sum
+	if err := os.Rename(tmp.Name(), dst); err != nil {
+		return err
+	}
+	klog.Infof("synced %s back", rel)
+	return nil
+}
+
+// fileMode returns the permissions of the host file dst, or of the node file src if dst does not exist yet
+func (s *Syncer) fileMode(dst string, src string) (os.FileMode, error) {
+	fi, err := os.Stat(dst)
+	if err == nil {
+		return fi.Mode().Perm(), nil
+	}
+	if !os.IsNotExist(err) {
+		return 0, err
+	}
+	rr, err := s.r.RunCmd(exec.Command("sudo", "stat", "-c", "%a", src))
+	if err != nil {
+		return 0, errors.Wrap(err, "stat")
+	}
+	m, err := strconv.ParseUint(strings.TrimSpace(rr.Stdout.String()), 8, 32)
+	if err != nil {
+		return 0, errors.Wrapf(err, "mode of %s", src)
+	}
+	return os.FileMode(m).Perm(), nil
+}
+
+// hashFile returns the hex encoded SHA-256 of a file, as printed by sha256sum
+func hashFile(p string) (string, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,223 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mount
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/fsnotify/fsnotify"
+
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/command"
+)
+
+func newTestSyncer(t *testing.T, ignore ...string) (*Syncer, *command.FakeCommandRunner) {
+	root, err := ioutil.TempDir("", "sync")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(root) })
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		t.Fatalf("watcher: %v", err)
+	}
+	t.Cleanup(func() { w.Close() })
+
+	r := command.NewFakeCommandRunner()
+	return &Syncer{
+		r:      r,
+		root:   root,
+		target: "/target",
+		cfg:    &cluster.MountConfig{Type: cluster.MountSync, SyncDirection: cluster.SyncTwoWay, SyncIgnore: ignore},
+		w:      w,
+		stamp:  "/tmp/minikube-sync-target",
+		synced: map[string]string{},
+	}, r
+}
+
+func writeFile(t *testing.T, p string, contents string) {
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := ioutil.WriteFile(p, []byte(contents), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+}
+
+func TestSyncIgnored(t *testing.T) {
+	s, _ := newTestSyncer(t, ".git", "*.log", "build/out")
+	tests := []struct {
+		rel  string
+		want bool
+	}{
+		{"main.go", false},
+		{".git", true},
+		{".git/config", true},
+		{"sub/.git/HEAD", true},
+		{"debug.log", true},
+		{"logs/debug.log", true},
+		{"build/out", true},
+		{"build/src", false},
+		{".minikube-sync123", true},
+	}
+	for _, tc := range tests {
+		if got := s.ignored(tc.rel); got != tc.want {
+			t.Errorf("ignored(%q) = %v, want %v", tc.rel, got, tc.want)
+		}
+	}
+}
+
+func TestSyncPush(t *testing.T) {
+	s, r := newTestSyncer(t, "node_modules")
+	writeFile(t, filepath.Join(s.root, "app.js"), "console.log(1)")
+	writeFile(t, filepath.Join(s.root, "lib", "util.js"), "exports.x = 1")
+	writeFile(t, filepath.Join(s.root, "node_modules", "dep", "index.js"), "ignored")
+	r.SetCommandToOutput(map[string]string{"sudo mkdir -p /target /target/lib": ""})
+
+	if err := s.addTree(s.root); err != nil {
+		t.Fatalf("addTree: %v", err)
+	}
+	for _, rel := range []string{"app.js", "lib/util.js"} {
+		if _, err := r.GetFileToContents(filepath.Join(s.root, filepath.FromSlash(rel))); err != nil {
+			t.Errorf("%s was not copied: %v", rel, err)
+		}
+		if s.synced[rel] == "" {
+			t.Errorf("%s is not recorded as synced", rel)
+		}
+	}
+	if _, err := r.GetFileToContents(filepath.Join(s.root, "node_modules", "dep", "index.js")); err == nil {
+		t.Errorf("an ignored file was copied")
+	}
+
+	// unchanged files are not copied again
+	app := filepath.Join(s.root, "app.js")
+	r.SetFileToContents(map[string]string{app: "stale"})
+	if err := s.sync(app); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	if got, _ := r.GetFileToContents(app); got != "stale" {
+		t.Errorf("an unchanged file was copied again")
+	}
+	writeFile(t, app, "console.log(2)")
+	if err := s.sync(app); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	if got, _ := r.GetFileToContents(app); got != "console.log(2)" {
+		t.Errorf("contents = %q, want the changed file", got)
+	}
+
+	// removed files are removed from the node
+	r.SetCommandToOutput(map[string]string{"sudo rm -rf /target/lib": ""})
+	if err := os.RemoveAll(filepath.Join(s.root, "lib")); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	if err := s.sync(filepath.Join(s.root, "lib")); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	if _, ok := s.synced["lib/util.js"]; ok {
+		t.Errorf("lib/util.js is still recorded as synced")
+	}
+}
+
+func TestSyncPull(t *testing.T) {
+	s, r := newTestSyncer(t)
+	writeFile(t, filepath.Join(s.root, "pushed.txt"), "pushed")
+	s.synced["pushed.txt"] = "1111"
+	script := filepath.Join(s.root, "run.sh")
+	writeFile(t, script, "echo old")
+	if err := os.Chmod(script, 0o755); err != nil {
+		t.Fatalf("chmod: %v", err)
+	}
+
+	find := fmt.Sprintf("/bin/bash -c \"sudo touch %[1]s.next && sudo find /target -type f -newer %[1]s -exec sha256sum {} + ; sudo mv %[1]s.next %[1]s\"", s.stamp)
+	r.SetCommandToOutput(map[string]string{
+		find:                                  "1111  /target/pushed.txt\n2222  /target/gen/new.txt\n3333  /target/run.sh\n",
+		"sudo stat -c %a /target/gen/new.txt": "640\n",
+	})
+	r.SetFileToContents(map[string]string{
+		"/target/pushed.txt":  "changed on the node",
+		"/target/gen/new.txt": "generated",
+		"/target/run.sh":      "echo new",
+	})
+
+	if err := s.pull(); err != nil {
+		t.Fatalf("pull: %v", err)
+	}
+	b, err := ioutil.ReadFile(filepath.Join(s.root, "gen", "new.txt"))
+	if err != nil || string(b) != "generated" {
+		t.Errorf("gen/new.txt = %q (%v), want the file of the node", b, err)
+	}
+	if s.synced["gen/new.txt"] != "2222" {
+		t.Errorf("gen/new.txt is not recorded as synced")
+	}
+
+	// new files take the mode of the node, and replaced files keep theirs
+	modes := map[string]os.FileMode{"gen/new.txt": 0o640, "run.sh": 0o755}
+	for rel, want := range modes {
+		fi, err := os.Stat(filepath.Join(s.root, filepath.FromSlash(rel)))
+		if err != nil {
+			t.Fatalf("stat: %v", err)
+		}
+		if got := fi.Mode().Perm(); runtime.GOOS != "windows" && got != want {
+			t.Errorf("mode of %s = %o, want %o", rel, got, want)
+		}
+	}
+	if b, _ := ioutil.ReadFile(filepath.Join(s.root, "pushed.txt")); string(b) != "pushed" {
+		t.Errorf("a file which was pushed was copied back: %q", b)
+	}
+}
+
+func TestSyncPullThroughSymlink(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symlinks require privileges on windows")
+	}
+	s, r := newTestSyncer(t)
+	outside, err := ioutil.TempDir("", "outside")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(outside)
+	if err := os.Symlink(outside, filepath.Join(s.root, "out")); err != nil {
+		t.Fatalf("symlink: %v", err)
+	}
+
+	find := fmt.Sprintf("/bin/bash -c \"sudo touch %[1]s.next && sudo find /target -type f -newer %[1]s -exec sha256sum {} + ; sudo mv %[1]s.next %[1]s\"", s.stamp)
+	r.SetCommandToOutput(map[string]string{
+		find:                                  "1111  /target/out/evil.sh\n2222  /target/ok.txt\n",
+		"sudo stat -c %a /target/out/evil.sh": "755\n",
+		"sudo stat -c %a /target/ok.txt":      "644\n",
+	})
+	r.SetFileToContents(map[string]string{
+		"/target/out/evil.sh": "evil",
+		"/target/ok.txt":      "ok",
+	})
+
+	if err := s.pull(); err != nil {
+		t.Fatalf("pull: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(outside, "evil.sh")); err == nil {
+		t.Error("a file was synced back through a symlink of the host")
+	}
+	if b, err := ioutil.ReadFile(filepath.Join(s.root, "ok.txt")); err != nil || string(b) != "ok" {
+		t.Errorf("ok.txt = %q (%v), want the file of the node", b, err)
+	}
+}
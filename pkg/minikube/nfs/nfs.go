@@ -0,0 +1,1084 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfs
+
+import (
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// NFSv3 (RFC 1813) procedures
+const (
+	procNull        = 0
+	procGetattr     = 1
+	procSetattr     = 2
+	procLookup      = 3
+	procAccess      = 4
+	procReadlink    = 5
+	procRead        = 6
+	procWrite       = 7
+	procCreate      = 8
+	procMkdir       = 9
+	procSymlink     = 10
+	procMknod       = 11
+	procRemove      = 12
+	procRmdir       = 13
+	procRename      = 14
+	procLink        = 15
+	procReaddir     = 16
+	procReaddirplus = 17
+	procFsstat      = 18
+	procFsinfo      = 19
+	procPathconf    = 20
+	procCommit      = 21
+)
+
+// NFSv3 status codes
+const (
+	nfs3OK             = 0
+	nfs3ErrPerm        = 1
+	nfs3ErrNoEnt       = 2
+	nfs3ErrIO          = 5
+	nfs3ErrAcces       = 13
+	nfs3ErrExist       = 17
+	nfs3ErrXDev        = 18
+	nfs3ErrNotDir      = 20
+	nfs3ErrIsDir       = 21
+	nfs3ErrInval       = 22
+	nfs3ErrNoSpc       = 28
+	nfs3ErrROFS        = 30
+	nfs3ErrNameTooLong = 63
+	nfs3ErrNotEmpty    = 66
+	nfs3ErrStale       = 70
+	nfs3ErrBadHandle   = 10001
+	nfs3ErrBadCookie   = 10003
+	nfs3ErrNotSupp     = 10004
+	nfs3ErrTooSmall    = 10005
+)
+
+// File types
+const (
+	typeReg  = 1
+	typeDir  = 2
+	typeBlk  = 3
+	typeChr  = 4
+	typeLnk  = 5
+	typeSock = 6
+	typeFifo = 7
+)
+
+const (
+	// maxData is the maximum size of reads and writes
+	maxData = 1 << 20
+	// nameMax is the maximum length of file names
+	nameMax = 255
+
+	// createUnchecked, createGuarded and createExclusive are the modes of CREATE
+	createUnchecked = 0
+	createGuarded   = 1
+	createExclusive = 2
+
+	// setToServerTime and setToClientTime tell how SETATTR sets times
+	setToServerTime = 1
+	setToClientTime = 2
+
+	// fileSync tells clients that writes reached the host filesystem, which outlives the server
+	fileSync = 2
+
+	// attrSize is the size of encoded file attributes
+	attrSize = 84
+)
+
+var nfsProcedures = map[uint32]procedure{
+	procNull:        null,
+	procGetattr:     (*Server).getattr,
+	procSetattr:     (*Server).setattr,
+	procLookup:      (*Server).lookup,
+	procAccess:      (*Server).access,
+	procReadlink:    (*Server).readlink,
+	procRead:        (*Server).read,
+	procWrite:       (*Server).write,
+	procCreate:      (*Server).create,
+	procMkdir:       (*Server).mkdir,
+	procSymlink:     (*Server).symlink,
+	procMknod:       (*Server).mknod,
+	procRemove:      (*Server).remove,
+	procRmdir:       (*Server).rmdir,
+	procRename:      (*Server).rename,
+	procLink:        (*Server).link,
+	procReaddir:     (*Server).readdir,
+	procReaddirplus: (*Server).readdirplus,
+	procFsstat:      (*Server).fsstat,
+	procFsinfo:      (*Server).fsinfo,
+	procPathconf:    (*Server).pathconf,
+	procCommit:      (*Server).commit,
+}
+
+// status returns the NFS status of an error
+func status(err error) uint32 {
+	switch {
+	case err == nil:
+		return nfs3OK
+	case os.IsNotExist(err):
+		return nfs3ErrNoEnt
+	case os.IsExist(err):
+		return nfs3ErrExist
+	case os.IsPermission(err):
+		return nfs3ErrAcces
+	}
+	var errno syscall.Errno
+	if !errors.As(err, &errno) {
+		return nfs3ErrIO
+	}
+	switch errno {
+	case syscall.EPERM:
+		return nfs3ErrPerm
+	case syscall.ENOTEMPTY:
+		return nfs3ErrNotEmpty
+	case syscall.ENOTDIR:
+		return nfs3ErrNotDir
+	case syscall.EISDIR:
+		return nfs3ErrIsDir
+	case syscall.EINVAL:
+		return nfs3ErrInval
+	case syscall.ENAMETOOLONG:
+		return nfs3ErrNameTooLong
+	case syscall.ENOSPC:
+		return nfs3ErrNoSpc
+	case syscall.EROFS:
+		return nfs3ErrROFS
+	case syscall.EXDEV:
+		return nfs3ErrXDev
+	}
+	return nfs3ErrIO
+}
+
+// path returns the host path of a file ID
+func (s *Server) path(id uint64) (string, uint32) {
+	if id == 0 {
+		return "", nfs3ErrBadHandle
+	}
+	p, ok := s.handles.path(id)
+	if !ok {
+		return "", nfs3ErrStale
+	}
+	return p, nfs3OK
+}
+
+// isDir checks that p is a directory, and not a symlink to one
+func isDir(p string) uint32 {
+	fi, err := os.Lstat(p)
+	if err != nil {
+		return status(err)
+	}
+	if !fi.IsDir() {
+		return nfs3ErrNotDir
+	}
+	return nfs3OK
+}
+
+// child returns the path of the entry name of the directory dir. Since dir must not be a symlink, and name
+// can neither be . nor .., nor contain separators, paths never escape the exported directory.
+func (s *Server) child(dir string, name string) (string, uint32) {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, "/\x00") || strings.ContainsRune(name, filepath.Separator) {
+		return "", nfs3ErrInval
+	}
+	if len(name) > nameMax {
+		return "", nfs3ErrNameTooLong
+	}
+	if st := isDir(dir); st != nfs3OK {
+		return "", st
+	}
+	return filepath.Join(dir, name), nfs3OK
+}
+
+// parent returns the parent of a directory, which is the directory itself for the root
+func (s *Server) parent(dir string) string {
+	if dir == s.root {
+		return dir
+	}
+	return filepath.Dir(dir)
+}
+
+// ftype returns the NFS file type of a file mode
+func ftype(m os.FileMode) uint32 {
+	switch {
+	case m.IsDir():
+		return typeDir
+	case m&os.ModeSymlink != 0:
+		return typeLnk
+	case m&os.ModeDevice != 0 && m&os.ModeCharDevice != 0:
+		return typeChr
+	case m&os.ModeDevice != 0:
+		return typeBlk
+	case m&os.ModeSocket != 0:
+		return typeSock
+	case m&os.ModeNamedPipe != 0:
+		return typeFifo
+	}
+	return typeReg
+}
+
+// mode3 returns the NFS mode bits of a file mode
+func mode3(m os.FileMode) uint32 {
+	mode := uint32(m.Perm())
+	if m&os.ModeSetuid != 0 {
+		mode |= 0o4000
+	}
+	if m&os.ModeSetgid != 0 {
+		mode |= 0o2000
+	}
+	if m&os.ModeSticky != 0 {
+		mode |= 0o1000
+	}
+	return mode
+}
+
+// fileMode returns the file mode of NFS mode bits
+func fileMode(mode uint32) os.FileMode {
+	m := os.FileMode(mode & 0o777)
+	if mode&0o4000 != 0 {
+		m |= os.ModeSetuid
+	}
+	if mode&0o2000 != 0 {
+		m |= os.ModeSetgid
+	}
+	if mode&0o1000 != 0 {
+		m |= os.ModeSticky
+	}
+	return m
+}
+
+// fattr writes the attributes of a file. Only the modification time is portable, so it is used for every time.
+func (s *Server) fattr(w *xdrWriter, id uint64, fi os.FileInfo) {
+	nlink := uint32(1)
+	if fi.IsDir() {
+		nlink = 2
+	}
+	w.u32(ftype(fi.Mode()))
+	w.u32(mode3(fi.Mode()))
+	w.u32(nlink)
+	w.u32(s.uid)
+	w.u32(s.gid)
+	w.u64(uint64(fi.Size()))
+	w.u64(uint64(fi.Size()))
+	// rdev
+	w.u32(0)
+	w.u32(0)
+	// fsid
+	w.u64(1)
+	w.u64(id)
+	for i := 0; i < 3; i++ {
+		nfstime(w, fi.ModTime())
+	}
+}
+
+func nfstime(w *xdrWriter, t time.Time) {
+	w.u32(uint32(t.Unix()))
+	w.u32(uint32(t.Nanosecond()))
+}
+
+// postOpAttr writes the attributes of p, if available
+func (s *Server) postOpAttr(w *xdrWriter, p string) {
+	if p == "" {
+		w.boolean(false)
+		return
+	}
+	fi, err := os.Lstat(p)
+	if err != nil {
+		w.boolean(false)
+		return
+	}
+	w.boolean(true)
+	s.fattr(w, s.handles.id(p), fi)
+}
+
+// wccData writes the attributes of p after an operation. The attributes from before are not tracked.
+func (s *Server) wccData(w *xdrWriter, p string) {
+	w.boolean(false)
+	s.postOpAttr(w, p)
+}
+
+// sattr are the attributes to set on a file
+type sattr struct {
+	mode  *uint32
+	size  *uint64
+	atime *time.Time
+	mtime *time.Time
+}
+
+func readSattr(r *xdrReader) sattr {
+	var a sattr
+	if r.boolean() {
+		mode := r.u32()
+		a.mode = &mode
+	}
+	// the owner of files is not changed, as the users of the cluster are unknown to the host
+	if r.boolean() {
+		r.u32()
+	}
+	if r.boolean() {
+		r.u32()
+	}
+	if r.boolean() {
+		size := r.u64()
+		a.size = &size
+	}
+	a.atime = readSetTime(r)
+	a.mtime = readSetTime(r)
+	return a
+}
+
+func readSetTime(r *xdrReader) *time.Time {
+	var t time.Time
+	switch r.u32() {
+	case setToServerTime:
+		t = time.Now()
+	case setToClientTime:
+		sec := r.u32()
+		nsec := r.u32()
+		t = time.Unix(int64(sec), int64(nsec))
+	default:
+		return nil
+	}
+	return &t
+}
+
+// apply sets the attributes of p. The attributes of symlinks are left alone, as setting them would change their target.
+func (a sattr) apply(p string) error {
+	fi, err := os.Lstat(p)
+	if err != nil {
+		return err
+	}
+	if fi.Mode()&os.ModeSymlink != 0 {
+		return nil
+	}
+	if a.mode != nil {
+		if err := os.Chmod(p, fileMode(*a.mode)); err != nil {
+			return err
+		}
+	}
+	if a.size != nil {
+		if err := os.Truncate(p, int64(*a.size)); err != nil {
+			return err
+		}
+	}
+	if a.atime != nil || a.mtime != nil {
+		atime, mtime := time.Now(), fi.ModTime()
+		if a.atime != nil {
+			atime = *a.atime
+		}
+		if a.mtime != nil {
+			mtime = *a.mtime
+		}
+		if err := os.Chtimes(p, atime, mtime); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Server) getattr(args *xdrReader, res *xdrWriter) error {
+	id := args.handle()
+	if args.err != nil {
+		return args.err
+	}
+	p, st := s.path(id)
+	if st != nfs3OK {
+		res.u32(st)
+		return nil
+	}
+	fi, err := os.Lstat(p)
+	if err != nil {
+		res.u32(status(err))
+		return nil
+	}
+	res.u32(nfs3OK)
+	s.fattr(res, id, fi)
+	return nil
+}
+
+func (s *Server) setattr(args *xdrReader, res *xdrWriter) error {
+	id := args.handle()
+	a := readSattr(args)
+	// the ctime guard is not checked, as ctimes are not tracked
+	if args.boolean() {
+		args.u32()
+		args.u32()
+	}
+	if args.err != nil {
+		return args.err
+	}
+	p, st := s.path(id)
+	if st == nfs3OK {
+		st = status(a.apply(p))
+	}
+	res.u32(st)
+	s.wccData(res, p)
+	return nil
+}
+
+func (s *Server) lookup(args *xdrReader, res *xdrWriter) error {
+	dirID := args.handle()
+	name := args.str()
+	if args.err != nil {
+		return args.err
+	}
+	dir, st := s.path(dirID)
+	if st != nfs3OK {
+		res.u32(st)
+		s.postOpAttr(res, "")
+		return nil
+	}
+
+	var p string
+	switch name {
+	case ".":
+		p, st = dir, isDir(dir)
+	case "..":
+		p, st = s.parent(dir), isDir(dir)
+	default:
+		p, st = s.child(dir, name)
+	}
+	if st != nfs3OK {
+		res.u32(st)
+		s.postOpAttr(res, dir)
+		return nil
+	}
+	fi, err := os.Lstat(p)
+	if err != nil {
+		res.u32(status(err))
+		s.postOpAttr(res, dir)
+		return nil
+	}
+	id := s.handles.id(p)
+	res.u32(nfs3OK)
+	res.opaque(fileHandle(id))
+	res.boolean(true)
+	s.fattr(res, id, fi)
+	s.postOpAttr(res, dir)
+	return nil
+}
+
+// access grants every access which is asked for: the access to files is enforced by the host when they are used
+func (s *Server) access(args *xdrReader, res *xdrWriter) error {
+	id := args.handle()
+	mask := args.u32()
+	if args.err != nil {
+		return args.err
+	}
+	p, st := s.path(id)
+	res.u32(st)
+	s.postOpAttr(res, p)
+	if st == nfs3OK {
+		res.u32(mask)
+	}
+	return nil
+}
+
+func (s *Server) readlink(args *xdrReader, res *xdrWriter) error {
+	id := args.handle()
+	if args.err != nil {
+		return args.err
+	}
+	p, st := s.path(id)
+	if st != nfs3OK {
+		res.u32(st)
+		s.postOpAttr(res, "")
+		return nil
+	}
+	target, err := os.Readlink(p)
+	if err != nil {
+		res.u32(nfs3ErrInval)
+		s.postOpAttr(res, p)
+		return nil
+	}
+	res.u32(nfs3OK)
+	s.postOpAttr(res, p)
+	res.str(filepath.ToSlash(target))
+	return nil
+}
+
+// regular checks that p is a regular file, which may be opened without following symlinks
+func regular(p string) uint32 {
+	fi, err := os.Lstat(p)
+	switch {
+	case err != nil:
+		return status(err)
+	case fi.IsDir():
+		return nfs3ErrIsDir
+	case !fi.Mode().IsRegular():
+		return nfs3ErrInval
+	}
+	return nfs3OK
+}
+
+func (s *Server) read(args *xdrReader, res *xdrWriter) error {
+	id := args.handle()
+	offset := args.u64()
+	count := args.u32()
+	if args.err != nil {
+		return args.err
+	}
+	if count > maxData {
+		count = maxData
+	}
+	p, st := s.path(id)
+	if st == nfs3OK {
+		st = regular(p)
+	}
+	if st != nfs3OK {
+		res.u32(st)
+		s.postOpAttr(res, p)
+		return nil
+	}
+
+	data, eof, err := readAt(p, offset, count)
+	if err != nil {
+		res.u32(status(err))
+		s.postOpAttr(res, p)
+		return nil
+	}
+	res.u32(nfs3OK)
+	s.postOpAttr(res, p)
+	res.u32(uint32(len(data)))
+	res.boolean(eof)
+	res.opaque(data)
+	return nil
+}
+
+// readAt reads count bytes of p at offset, and tells if the end of the file was reached
+func readAt(p string, offset uint64, count uint32) ([]byte, bool, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return nil, false, err
+	}
+	defer f.Close()
+	if offset > math.MaxInt64 {
+		return nil, true, nil
+	}
+	data := make([]byte, count)
+	n, err := f.ReadAt(data, int64(offset))
+	if err == io.EOF {
+		return data[:n], true, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, false, err
+	}
+	return data[:n], int64(offset)+int64(n) >= fi.Size(), nil
+}
+
+func (s *Server) write(args *xdrReader, res *xdrWriter) error {
+	id := args.handle()
+	offset := args.u64()
+	count := args.u32()
+	// writes always reach the host filesystem, whatever the stability asked for
+	args.u32()
+	data := args.opaque()
+	if args.err != nil {
+		return args.err
+	}
+	if int(count) < len(data) {
+		data = data[:count]
+	}
+	p, st := s.path(id)
+	if st == nfs3OK {
+		st = regular(p)
+	}
+	if st == nfs3OK && offset > math.MaxInt64 {
+		st = nfs3ErrInval
+	}
+	if st != nfs3OK {
+		res.u32(st)
+		s.wccData(res, p)
+		return nil
+	}
+
+	n, err := writeAt(p, offset, data)
+	if err != nil {
+		res.u32(status(err))
+		s.wccData(res, p)
+		return nil
+	}
+	res.u32(nfs3OK)
+	s.wccData(res, p)
+	res.u32(uint32(n))
+	res.u32(fileSync)
+	res.fixed(s.verf[:])
+	return nil
+}
+
+func writeAt(p string, offset uint64, data []byte) (int, error) {
+	f, err := os.OpenFile(p, os.O_WRONLY, 0)
+	if err != nil {
+		return 0, err
+	}
+	n, err := f.WriteAt(data, int64(offset))
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return n, err
+}
+
+// created writes the result of the creation of p in dir
+func (s *Server) created(res *xdrWriter, dir string, p string, err error) {
+	if err != nil {
+		res.u32(status(err))
+		s.wccData(res, dir)
+		return
+	}
+	res.u32(nfs3OK)
+	res.boolean(true)
+	res.opaque(fileHandle(s.handles.id(p)))
+	s.postOpAttr(res, p)
+	s.wccData(res, dir)
+}
+
+// failed writes the result of a creation in dir which failed with st
+func (s *Server) failed(res *xdrWriter, dir string, st uint32) {
+	res.u32(st)
+	s.wccData(res, dir)
+}
+
+func (s *Server) create(args *xdrReader, res *xdrWriter) error {
+	dirID := args.handle()
+	name := args.str()
+	how := args.u32()
+	var a sattr
+	if how == createExclusive {
+		args.fixed(8)
+	} else {
+		a = readSattr(args)
+	}
+	if args.err != nil {
+		return args.err
+	}
+	dir, st := s.path(dirID)
+	if st != nfs3OK {
+		s.failed(res, "", st)
+		return nil
+	}
+	p, st := s.child(dir, name)
+	if st != nfs3OK {
+		s.failed(res, dir, st)
+		return nil
+	}
+
+	flags := os.O_WRONLY | os.O_CREATE
+	if how != createUnchecked {
+		flags |= os.O_EXCL
+	}
+	perm := os.FileMode(0o644)
+	if a.mode != nil {
+		perm = fileMode(*a.mode)
+	}
+	f, err := os.OpenFile(p, flags, perm)
+	if err == nil {
+		err = f.Close()
+	}
+	if err == nil {
+		a.mode = nil
+		err = a.apply(p)
+	}
+	s.created(res, dir, p, err)
+	return nil
+}
+
+func (s *Server) mkdir(args *xdrReader, res *xdrWriter) error {
+	dirID := args.handle()
+	name := args.str()
+	a := readSattr(args)
+	if args.err != nil {
+		return args.err
+	}
+	dir, st := s.path(dirID)
+	if st != nfs3OK {
+		s.failed(res, "", st)
+		return nil
+	}
+	p, st := s.child(dir, name)
+	if st != nfs3OK {
+		s.failed(res, dir, st)
+		return nil
+	}
+
+	perm := os.FileMode(0o755)
+	if a.mode != nil {
+		perm = fileMode(*a.mode)
+	}
+	err := os.Mkdir(p, perm)
+	if err == nil {
+		a.mode = nil
+		err = a.apply(p)
+	}
+	s.created(res, dir, p, err)
+	return nil
+}
+
+func (s *Server) symlink(args *xdrReader, res *xdrWriter) error {
+	dirID := args.handle()
+	name := args.str()
+	readSattr(args)
+	target := args.str()
+	if args.err != nil {
+		return args.err
+	}
+	dir, st := s.path(dirID)
+	if st != nfs3OK {
+		s.failed(res, "", st)
+		return nil
+	}
+	p, st := s.child(dir, name)
+	if st != nfs3OK {
+		s.failed(res, dir, st)
+		return nil
+	}
+	s.created(res, dir, p, os.Symlink(target, p))
+	return nil
+}
+
+// mknod is not supported: devices, sockets and pipes can not be shared with the host
+func (s *Server) mknod(args *xdrReader, res *xdrWriter) error {
+	s.failed(res, "", nfs3ErrNotSupp)
+	return nil
+}
+
+func (s *Server) remove(args *xdrReader, res *xdrWriter) error {
+	return s.unlink(args, res, false)
+}
+
+func (s *Server) rmdir(args *xdrReader, res *xdrWriter) error {
+	return s.unlink(args, res, true)
+}
+
+// unlink removes a file, or an empty directory if dir is set
+func (s *Server) unlink(args *xdrReader, res *xdrWriter, dir bool) error {
+	dirID := args.handle()
+	name := args.str()
+	if args.err != nil {
+		return args.err
+	}
+	parent, st := s.path(dirID)
+	if st != nfs3OK {
+		s.failed(res, "", st)
+		return nil
+	}
+	p, st := s.child(parent, name)
+	if st != nfs3OK {
+		s.failed(res, parent, st)
+		return nil
+	}
+
+	fi, err := os.Lstat(p)
+	switch {
+	case err != nil:
+		st = status(err)
+	case dir && !fi.IsDir():
+		st = nfs3ErrNotDir
+	case !dir && fi.IsDir():
+		st = nfs3ErrIsDir
+	default:
+		st = status(os.Remove(p))
+	}
+	if st == nfs3OK {
+		s.handles.remove(p)
+	}
+	s.failed(res, parent, st)
+	return nil
+}
+
+func (s *Server) rename(args *xdrReader, res *xdrWriter) error {
+	fromID := args.handle()
+	fromName := args.str()
+	toID := args.handle()
+	toName := args.str()
+	if args.err != nil {
+		return args.err
+	}
+	fromDir, st := s.path(fromID)
+	toDir, tst := s.path(toID)
+	var from, to string
+	if st == nfs3OK {
+		st = tst
+	}
+	if st == nfs3OK {
+		from, st = s.child(fromDir, fromName)
+	}
+	if st == nfs3OK {
+		to, st = s.child(toDir, toName)
+	}
+	if st == nfs3OK {
+		st = status(os.Rename(from, to))
+	}
+	if st == nfs3OK {
+		s.handles.rename(from, to)
+	}
+	res.u32(st)
+	s.wccData(res, fromDir)
+	s.wccData(res, toDir)
+	return nil
+}
+
+func (s *Server) link(args *xdrReader, res *xdrWriter) error {
+	id := args.handle()
+	dirID := args.handle()
+	name := args.str()
+	if args.err != nil {
+		return args.err
+	}
+	p, st := s.path(id)
+	dir, dst := s.path(dirID)
+	var np string
+	if st == nfs3OK {
+		st = dst
+	}
+	if st == nfs3OK {
+		np, st = s.child(dir, name)
+	}
+	if st == nfs3OK {
+		st = status(os.Link(p, np))
+	}
+	res.u32(st)
+	s.postOpAttr(res, p)
+	s.wccData(res, dir)
+	return nil
+}
+
+// dirEntry is an entry of a directory listing
+type dirEntry struct {
+	name string
+	path string
+}
+
+// list lists a directory, including . and .., in a stable order. The cookie of an entry is its index in the listing, plus one.
+func (s *Server) list(dir string) ([]dirEntry, error) {
+	fis, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	entries := []dirEntry{{name: ".", path: dir}, {name: "..", path: s.parent(dir)}}
+	for _, fi := range fis {
+		entries = append(entries, dirEntry{name: fi.Name(), path: filepath.Join(dir, fi.Name())})
+	}
+	return entries, nil
+}
+
+// xdrSize returns the encoded size of a string
+func xdrSize(s string) int {
+	return 4 + (len(s)+3)&^3
+}
+
+func (s *Server) readdir(args *xdrReader, res *xdrWriter) error {
+	id := args.handle()
+	cookie := args.u64()
+	args.fixed(8)
+	count := args.u32()
+	if args.err != nil {
+		return args.err
+	}
+	return s.readEntries(res, id, cookie, int(count), int(count), false)
+}
+
+func (s *Server) readdirplus(args *xdrReader, res *xdrWriter) error {
+	id := args.handle()
+	cookie := args.u64()
+	args.fixed(8)
+	dircount := args.u32()
+	maxcount := args.u32()
+	if args.err != nil {
+		return args.err
+	}
+	return s.readEntries(res, id, cookie, int(dircount), int(maxcount), true)
+}
+
+// readEntries writes the entries of a directory from cookie on, for READDIR, or READDIRPLUS if plus is set.
+// dircount bounds the size of the names and file IDs of the entries, and maxcount the size of the reply.
+func (s *Server) readEntries(res *xdrWriter, id uint64, cookie uint64, dircount int, maxcount int, plus bool) error {
+	dir, st := s.path(id)
+	if st == nfs3OK {
+		st = isDir(dir)
+	}
+	if st != nfs3OK {
+		res.u32(st)
+		s.postOpAttr(res, dir)
+		return nil
+	}
+	entries, err := s.list(dir)
+	if err != nil {
+		res.u32(status(err))
+		s.postOpAttr(res, dir)
+		return nil
+	}
+	if cookie > uint64(len(entries)) {
+		res.u32(nfs3ErrBadCookie)
+		s.postOpAttr(res, dir)
+		return nil
+	}
+
+	body := &xdrWriter{}
+	// status, directory attributes, verifier, end of the list and eof
+	size := 4 + 4 + attrSize + 8 + 4 + 4
+	names := 0
+	i := int(cookie)
+	for ; i < len(entries); i++ {
+		e := entries[i]
+		n := 8 + xdrSize(e.name) + 8
+		entrySize := 4 + n
+		if plus {
+			entrySize += 4 + attrSize + 4 + xdrSize("01234567")
+		}
+		if size+entrySize > maxcount || names+n > dircount {
+			break
+		}
+		size += entrySize
+		names += n
+
+		eid := s.handles.id(e.path)
+		body.boolean(true)
+		body.u64(eid)
+		body.str(e.name)
+		body.u64(uint64(i + 1))
+		if plus {
+			s.postOpAttr(body, e.path)
+			body.boolean(true)
+			body.opaque(fileHandle(eid))
+		}
+	}
+	if i == int(cookie) && i < len(entries) {
+		res.u32(nfs3ErrTooSmall)
+		s.postOpAttr(res, dir)
+		return nil
+	}
+
+	res.u32(nfs3OK)
+	s.postOpAttr(res, dir)
+	res.fixed(make([]byte, 8))
+	res.Write(body.Bytes())
+	res.boolean(false)
+	res.boolean(i == len(entries))
+	return nil
+}
+
+// fsstat reports a large free space: the usage of the host filesystem is not portable to query
+func (s *Server) fsstat(args *xdrReader, res *xdrWriter) error {
+	id := args.handle()
+	if args.err != nil {
+		return args.err
+	}
+	p, st := s.path(id)
+	res.u32(st)
+	s.postOpAttr(res, p)
+	if st != nfs3OK {
+		return nil
+	}
+	const bytes, files = 1 << 40, 1 << 24
+	res.u64(bytes)
+	res.u64(bytes)
+	res.u64(bytes)
+	res.u64(files)
+	res.u64(files)
+	res.u64(files)
+	res.u32(0)
+	return nil
+}
+
+func (s *Server) fsinfo(args *xdrReader, res *xdrWriter) error {
+	const (
+		fsfLink        = 0x1
+		fsfSymlink     = 0x2
+		fsfHomogeneous = 0x8
+		fsfCanSetTime  = 0x10
+	)
+	id := args.handle()
+	if args.err != nil {
+		return args.err
+	}
+	p, st := s.path(id)
+	res.u32(st)
+	s.postOpAttr(res, p)
+	if st != nfs3OK {
+		return nil
+	}
+	// rtmax, rtpref and rtmult, then the same for writes
+	for i := 0; i < 2; i++ {
+		res.u32(maxData)
+		res.u32(maxData)
+		res.u32(4096)
+	}
+	// dtpref
+	res.u32(64 << 10)
+	res.u64(math.MaxInt64)
+	// time_delta
+	res.u32(0)
+	res.u32(1)
+	res.u32(fsfLink | fsfSymlink | fsfHomogeneous | fsfCanSetTime)
+	return nil
+}
+
+func (s *Server) pathconf(args *xdrReader, res *xdrWriter) error {
+	id := args.handle()
+	if args.err != nil {
+		return args.err
+	}
+	p, st := s.path(id)
+	res.u32(st)
+	s.postOpAttr(res, p)
+	if st != nfs3OK {
+		return nil
+	}
+	// linkmax and name_max
+	res.u32(32000)
+	res.u32(nameMax)
+	// no_trunc and chown_restricted
+	res.boolean(true)
+	res.boolean(true)
+	// the default filesystems of macOS and Windows ignore case
+	res.boolean(runtime.GOOS == "darwin" || runtime.GOOS == "windows")
+	// case_preserving
+	res.boolean(true)
+	return nil
+}
+
+// commit has nothing to do, as every write reaches the host filesystem
+func (s *Server) commit(args *xdrReader, res *xdrWriter) error {
+	id := args.handle()
+	args.u64()
+	args.u32()
+	if args.err != nil {
+		return args.err
+	}
+	p, st := s.path(id)
+	res.u32(st)
+	s.wccData(res, p)
+	if st == nfs3OK {
+		res.fixed(s.verf[:])
+	}
+	return nil
+}
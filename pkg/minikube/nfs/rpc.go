@@ -0,0 +1,148 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfs
+
+import (
+	"encoding/binary"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// ONC RPC (RFC 5531) constants
+const (
+	rpcVersion = 2
+
+	msgCall  = 0
+	msgReply = 1
+
+	msgAccepted = 0
+	msgDenied   = 1
+
+	acceptSuccess      = 0
+	acceptProgUnavail  = 1
+	acceptProgMismatch = 2
+	acceptProcUnavail  = 3
+	acceptGarbageArgs  = 4
+
+	rejectRPCMismatch = 0
+
+	authNone = 0
+	authUnix = 1
+
+	// lastFragment marks the last fragment of a record, in the record marking of RPC over TCP
+	lastFragment = 1 << 31
+	// maxRecordSize bounds the size of the calls, which are at most a WRITE of maxData bytes
+	maxRecordSize = 2 * maxData
+)
+
+// call is an RPC call
+type call struct {
+	xid     uint32
+	rpcVers uint32
+	prog    uint32
+	vers    uint32
+	proc    uint32
+	// args are the arguments of the procedure
+	args *xdrReader
+}
+
+// readRecord reads a record, made of one or several fragments
+func readRecord(r io.Reader) ([]byte, error) {
+	var rec []byte
+	for {
+		var h [4]byte
+		if _, err := io.ReadFull(r, h[:]); err != nil {
+			return nil, err
+		}
+		n := binary.BigEndian.Uint32(h[:])
+		size := n &^ lastFragment
+		if uint64(len(rec))+uint64(size) > maxRecordSize {
+			return nil, errors.Errorf("record larger than %d bytes", maxRecordSize)
+		}
+		frag := make([]byte, size)
+		if _, err := io.ReadFull(r, frag); err != nil {
+			return nil, err
+		}
+		rec = append(rec, frag...)
+		if n&lastFragment != 0 {
+			return rec, nil
+		}
+	}
+}
+
+// writeRecord writes b as a single fragment record
+func writeRecord(w io.Writer, b []byte) error {
+	rec := make([]byte, 4+len(b))
+	binary.BigEndian.PutUint32(rec, uint32(len(b))|lastFragment)
+	copy(rec[4:], b)
+	_, err := w.Write(rec)
+	return err
+}
+
+// parseCall decodes the header of a call. The credentials are not checked: access to files is enforced by the host.
+func parseCall(rec []byte) (*call, error) {
+	r := &xdrReader{b: rec}
+	c := &call{xid: r.u32()}
+	if t := r.u32(); t != msgCall && r.err == nil {
+		return nil, errors.Errorf("message type %d is not a call", t)
+	}
+	c.rpcVers = r.u32()
+	c.prog = r.u32()
+	c.vers = r.u32()
+	c.proc = r.u32()
+	// credentials and verifier
+	r.u32()
+	r.opaque()
+	r.u32()
+	r.opaque()
+	if r.err != nil {
+		return nil, r.err
+	}
+	c.args = r
+	return c, nil
+}
+
+// acceptedReply starts the reply to call xid, with an accept status
+func acceptedReply(xid uint32, stat uint32) *xdrWriter {
+	w := &xdrWriter{}
+	w.u32(xid)
+	w.u32(msgReply)
+	w.u32(msgAccepted)
+	w.u32(authNone)
+	w.opaque(nil)
+	w.u32(stat)
+	return w
+}
+
+// mismatchReply replies to a call of an unsupported version of RPC or of a program
+func mismatchReply(c *call, version uint32) *xdrWriter {
+	if c.rpcVers != rpcVersion {
+		w := &xdrWriter{}
+		w.u32(c.xid)
+		w.u32(msgReply)
+		w.u32(msgDenied)
+		w.u32(rejectRPCMismatch)
+		w.u32(rpcVersion)
+		w.u32(rpcVersion)
+		return w
+	}
+	w := acceptedReply(c.xid, acceptProgMismatch)
+	w.u32(version)
+	w.u32(version)
+	return w
+}
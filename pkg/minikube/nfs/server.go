@@ -0,0 +1,367 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package nfs is a userspace NFSv3 server, which exports a host directory to the cluster.
+// The mount protocol is served on the same port as NFS, so that clients can mount
+// with port=<port>,mountport=<port>,nolock without the portmapper nor the lock manager.
+package nfs
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"io"
+	"net"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+)
+
+// RPC programs served
+const (
+	mountProgram = 100005
+	nfsProgram   = 100003
+	version3     = 3
+)
+
+// Procedures of the mount protocol
+const (
+	mountProcNull    = 0
+	mountProcMnt     = 1
+	mountProcDump    = 2
+	mountProcUmnt    = 3
+	mountProcUmntAll = 4
+	mountProcExport  = 5
+
+	mnt3OK    = 0
+	mnt3NoEnt = 2
+)
+
+// Server serves a host directory over NFSv3
+type Server struct {
+	root string
+	// uid and gid are reported as the owner of every file, as the host users are unknown to the cluster
+	uid uint32
+	gid uint32
+
+	handles *handleTable
+	// verf is the write verifier, which changes when the server restarts
+	verf [8]byte
+
+	mu sync.Mutex
+	// allowed, unless empty, are the only addresses connections are accepted from
+	allowed []net.IP
+}
+
+// NewServer returns a server of the directory root, whose files are reported as owned by uid and gid
+func NewServer(root string, uid, gid uint32) *Server {
+	s := &Server{root: filepath.Clean(root), uid: uid, gid: gid}
+	s.handles = newHandleTable(s.root)
+	if _, err := rand.Read(s.verf[:]); err != nil {
+		klog.Warningf("unable to generate a write verifier: %v", err)
+	}
+	return s
+}
+
+// Serve serves the connections accepted by l, until it is closed
+func (s *Server) Serve(l net.Listener) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return errors.Wrap(err, "accept")
+		}
+		if !s.accepts(conn.RemoteAddr()) {
+			klog.Warningf("nfs: refused connection from %s", conn.RemoteAddr())
+			conn.Close()
+			continue
+		}
+		go s.serveConn(conn)
+	}
+}
+
+// SetAllowedHosts restricts the connections to the ones from hosts, or lets any host connect if hosts is empty.
+// It may be called while the server runs.
+func (s *Server) SetAllowedHosts(hosts []net.IP) {
+	s.mu.Lock()
+	s.allowed = hosts
+	s.mu.Unlock()
+}
+
+// accepts tells if a connection from addr is accepted
+func (s *Server) accepts(addr net.Addr) bool {
+	s.mu.Lock()
+	hosts := s.allowed
+	s.mu.Unlock()
+	if len(hosts) == 0 {
+		return true
+	}
+	a, ok := addr.(*net.TCPAddr)
+	if !ok {
+		return false
+	}
+	for _, h := range hosts {
+		if h.Equal(a.IP) {
+			return true
+		}
+	}
+	return false
+}
+
+// serveConn serves the calls of a connection. Clients send calls without waiting for the replies, so calls are served concurrently.
+func (s *Server) serveConn(conn net.Conn) {
+	defer conn.Close()
+	klog.Infof("nfs: serving %s", conn.RemoteAddr())
+
+	var mu sync.Mutex
+	for {
+		rec, err := readRecord(conn)
+		if err != nil {
+			if err != io.EOF {
+				klog.Warningf("nfs: read from %s: %v", conn.RemoteAddr(), err)
+			}
+			return
+		}
+		go func() {
+			reply := s.handle(rec)
+			if reply == nil {
+				return
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if err := writeRecord(conn, reply); err != nil {
+				klog.Warningf("nfs: write to %s: %v", conn.RemoteAddr(), err)
+			}
+		}()
+	}
+}
+
+// procedure serves a call, and writes its results to res. An error is returned if the arguments can not be decoded.
+type procedure func(s *Server, args *xdrReader, res *xdrWriter) error
+
+var mountProcedures = map[uint32]procedure{
+	mountProcNull:    null,
+	mountProcMnt:     (*Server).mnt,
+	mountProcDump:    (*Server).dump,
+	mountProcUmnt:    (*Server).umnt,
+	mountProcUmntAll: null,
+	mountProcExport:  (*Server).export,
+}
+
+// handle serves a record, and returns the reply
+func (s *Server) handle(rec []byte) []byte {
+	c, err := parseCall(rec)
+	if err != nil {
+		klog.Warningf("nfs: %v", err)
+		return nil
+	}
+	if c.rpcVers != rpcVersion {
+		return mismatchReply(c, version3).Bytes()
+	}
+
+	var procs map[uint32]procedure
+	switch c.prog {
+	case mountProgram:
+		procs = mountProcedures
+	case nfsProgram:
+		procs = nfsProcedures
+	default:
+		return acceptedReply(c.xid, acceptProgUnavail).Bytes()
+	}
+	if c.vers != version3 {
+		return mismatchReply(c, version3).Bytes()
+	}
+	p, ok := procs[c.proc]
+	if !ok {
+		return acceptedReply(c.xid, acceptProcUnavail).Bytes()
+	}
+
+	res := &xdrWriter{}
+	if err := p(s, c.args, res); err != nil {
+		return acceptedReply(c.xid, acceptGarbageArgs).Bytes()
+	}
+	reply := acceptedReply(c.xid, acceptSuccess)
+	reply.Write(res.Bytes())
+	return reply.Bytes()
+}
+
+func null(s *Server, args *xdrReader, res *xdrWriter) error {
+	return nil
+}
+
+// mnt returns the handle of an exported directory: the root, or any directory below it
+func (s *Server) mnt(args *xdrReader, res *xdrWriter) error {
+	dir := args.str()
+	if args.err != nil {
+		return args.err
+	}
+
+	id, st := s.lookupPath(dir)
+	if st != nfs3OK {
+		klog.Warningf("nfs: mount of %q refused: error %d", dir, st)
+		res.u32(mnt3NoEnt)
+		return nil
+	}
+	klog.Infof("nfs: mounted %q", dir)
+	res.u32(mnt3OK)
+	res.opaque(fileHandle(id))
+	res.u32(1)
+	res.u32(authUnix)
+	return nil
+}
+
+// lookupPath returns the handle of a directory, given as a path relative to the root
+func (s *Server) lookupPath(dir string) (uint64, uint32) {
+	id := rootID
+	for _, name := range strings.Split(path.Clean("/"+dir), "/") {
+		if name == "" {
+			continue
+		}
+		p, st := s.path(id)
+		if st != nfs3OK {
+			return 0, st
+		}
+		c, st := s.child(p, name)
+		if st != nfs3OK {
+			return 0, st
+		}
+		id = s.handles.id(c)
+	}
+	p, st := s.path(id)
+	if st != nfs3OK {
+		return 0, st
+	}
+	return id, isDir(p)
+}
+
+// dump lists the clients which mounted the export, which are not tracked
+func (s *Server) dump(args *xdrReader, res *xdrWriter) error {
+	res.boolean(false)
+	return nil
+}
+
+func (s *Server) umnt(args *xdrReader, res *xdrWriter) error {
+	dir := args.str()
+	if args.err != nil {
+		return args.err
+	}
+	klog.Infof("nfs: unmounted %q", dir)
+	return nil
+}
+
+// export lists the exported directory, which is available to every client
+func (s *Server) export(args *xdrReader, res *xdrWriter) error {
+	res.boolean(true)
+	res.str("/")
+	res.boolean(false)
+	res.boolean(false)
+	return nil
+}
+
+// rootID is the file ID of the exported directory
+const rootID uint64 = 1
+
+// handleTable maps the file handles given to clients to host paths. Handles are file IDs, which are assigned on lookup, and only valid while the server runs.
+type handleTable struct {
+	mu    sync.Mutex
+	next  uint64
+	paths map[uint64]string
+	ids   map[string]uint64
+}
+
+func newHandleTable(root string) *handleTable {
+	return &handleTable{
+		next:  rootID + 1,
+		paths: map[uint64]string{rootID: root},
+		ids:   map[string]uint64{root: rootID},
+	}
+}
+
+// id returns the file ID of a path, which is assigned if needed
+func (t *handleTable) id(p string) uint64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if id, ok := t.ids[p]; ok {
+		return id
+	}
+	id := t.next
+	t.next++
+	t.paths[id] = p
+	t.ids[p] = id
+	return id
+}
+
+// path returns the path of a file ID
+func (t *handleTable) path(id uint64) (string, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	p, ok := t.paths[id]
+	return p, ok
+}
+
+// rename moves the file IDs of from, and of everything below it, to to. The file IDs of to are dropped, as it is replaced.
+func (t *handleTable) rename(from, to string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.drop(to)
+	moved := map[string]uint64{}
+	for p, id := range t.ids {
+		if p == from || strings.HasPrefix(p, from+string(filepath.Separator)) {
+			moved[p] = id
+		}
+	}
+	for p, id := range moved {
+		np := to + strings.TrimPrefix(p, from)
+		delete(t.ids, p)
+		t.ids[np] = id
+		t.paths[id] = np
+	}
+}
+
+// remove drops the file IDs of a removed path
+func (t *handleTable) remove(p string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.drop(p)
+}
+
+// drop drops the file IDs of p and of everything below it, with the lock held
+func (t *handleTable) drop(p string) {
+	for q, id := range t.ids {
+		if q == p || strings.HasPrefix(q, p+string(filepath.Separator)) {
+			delete(t.ids, q)
+			delete(t.paths, id)
+		}
+	}
+}
+
+// fileHandle encodes a file ID as a file handle
+func fileHandle(id uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, id)
+	return b
+}
+
+// handle reads a file handle, and returns its file ID, which is 0 for malformed handles
+func (r *xdrReader) handle() uint64 {
+	b := r.opaque()
+	if len(b) != 8 {
+		return 0
+	}
+	return binary.BigEndian.Uint64(b)
+}
@@ -0,0 +1,440 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfs
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// client calls a server over an in-memory connection
+type client struct {
+	t    *testing.T
+	conn net.Conn
+	xid  uint32
+}
+
+func newClient(t *testing.T, root string) *client {
+	s := NewServer(root, 1000, 1001)
+	c, sc := net.Pipe()
+	go s.serveConn(sc)
+	t.Cleanup(func() { c.Close() })
+	return &client{t: t, conn: c}
+}
+
+// call calls a procedure, and returns its results
+func (c *client) call(prog, proc uint32, args func(w *xdrWriter)) *xdrReader {
+	c.t.Helper()
+	c.xid++
+	w := &xdrWriter{}
+	w.u32(c.xid)
+	w.u32(msgCall)
+	w.u32(rpcVersion)
+	w.u32(prog)
+	w.u32(version3)
+	w.u32(proc)
+	w.u32(authUnix)
+	w.opaque([]byte("credentials"))
+	w.u32(authNone)
+	w.opaque(nil)
+	if args != nil {
+		args(w)
+	}
+	if err := writeRecord(c.conn, w.Bytes()); err != nil {
+		c.t.Fatalf("write: %v", err)
+	}
+	rec, err := readRecord(c.conn)
+	if err != nil {
+		c.t.Fatalf("read: %v", err)
+	}
+	r := &xdrReader{b: rec}
+	if xid := r.u32(); xid != c.xid {
+		c.t.Fatalf("reply to %d, want %d", xid, c.xid)
+	}
+	r.u32()
+	if stat := r.u32(); stat != msgAccepted {
+		c.t.Fatalf("call denied: %d", stat)
+	}
+	r.u32()
+	r.opaque()
+	if stat := r.u32(); stat != acceptSuccess {
+		c.t.Fatalf("call not accepted: %d", stat)
+	}
+	return r
+}
+
+// mount returns the handle of the root
+func (c *client) mount() uint64 {
+	c.t.Helper()
+	r := c.call(mountProgram, mountProcMnt, func(w *xdrWriter) { w.str("/") })
+	if st := r.u32(); st != mnt3OK {
+		c.t.Fatalf("mount: %d", st)
+	}
+	return r.handle()
+}
+
+// lookup returns the status of a lookup, and the handle found
+func (c *client) lookup(dir uint64, name string) (uint32, uint64) {
+	c.t.Helper()
+	r := c.call(nfsProgram, procLookup, func(w *xdrWriter) {
+		w.opaque(fileHandle(dir))
+		w.str(name)
+	})
+	if st := r.u32(); st != nfs3OK {
+		return st, 0
+	}
+	return nfs3OK, r.handle()
+}
+
+// getattr returns the status of a getattr, and the type and size of the file
+func (c *client) getattr(id uint64) (uint32, uint32, uint64) {
+	c.t.Helper()
+	r := c.call(nfsProgram, procGetattr, func(w *xdrWriter) { w.opaque(fileHandle(id)) })
+	if st := r.u32(); st != nfs3OK {
+		return st, 0, 0
+	}
+	typ := r.u32()
+	r.u32()
+	r.u32()
+	if uid, gid := r.u32(), r.u32(); uid != 1000 || gid != 1001 {
+		c.t.Errorf("owner = %d:%d, want 1000:1001", uid, gid)
+	}
+	return nfs3OK, typ, r.u64()
+}
+
+func (c *client) read(id uint64, offset uint64, count uint32) (string, bool) {
+	c.t.Helper()
+	r := c.call(nfsProgram, procRead, func(w *xdrWriter) {
+		w.opaque(fileHandle(id))
+		w.u64(offset)
+		w.u32(count)
+	})
+	if st := r.u32(); st != nfs3OK {
+		c.t.Fatalf("read: %d", st)
+	}
+	if r.boolean() {
+		r.fixed(attrSize)
+	}
+	r.u32()
+	eof := r.boolean()
+	return string(r.opaque()), eof
+}
+
+// readdir returns the names listed from cookie on, the cookie of the last one, and whether the listing ended
+func (c *client) readdir(dir uint64, cookie uint64, count uint32) ([]string, uint64, bool) {
+	c.t.Helper()
+	r := c.call(nfsProgram, procReaddir, func(w *xdrWriter) {
+		w.opaque(fileHandle(dir))
+		w.u64(cookie)
+		w.fixed(make([]byte, 8))
+		w.u32(count)
+	})
+	if st := r.u32(); st != nfs3OK {
+		c.t.Fatalf("readdir: %d", st)
+	}
+	if r.boolean() {
+		r.fixed(attrSize)
+	}
+	r.fixed(8)
+	var names []string
+	for r.boolean() {
+		r.u64()
+		names = append(names, r.str())
+		cookie = r.u64()
+	}
+	return names, cookie, r.boolean()
+}
+
+func tempRoot(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "nfs")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	if err := os.Mkdir(filepath.Join(dir, "sub"), 0o755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "hello.txt"), []byte("hello world"), 0o644); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	return dir
+}
+
+func TestMount(t *testing.T) {
+	c := newClient(t, tempRoot(t))
+	if root := c.mount(); root != rootID {
+		t.Errorf("root handle = %d, want %d", root, rootID)
+	}
+
+	r := c.call(mountProgram, mountProcMnt, func(w *xdrWriter) { w.str("/sub") })
+	if st := r.u32(); st != mnt3OK {
+		t.Errorf("mount of /sub: %d", st)
+	}
+	for _, dir := range []string{"/missing", "/hello.txt"} {
+		r = c.call(mountProgram, mountProcMnt, func(w *xdrWriter) { w.str(dir) })
+		if st := r.u32(); st != mnt3NoEnt {
+			t.Errorf("mount of %s = %d, want %d", dir, st, mnt3NoEnt)
+		}
+	}
+}
+
+func TestLookupReadWrite(t *testing.T) {
+	root := tempRoot(t)
+	c := newClient(t, root)
+	dir := c.mount()
+
+	st, id := c.lookup(dir, "hello.txt")
+	if st != nfs3OK {
+		t.Fatalf("lookup: %d", st)
+	}
+	if st, typ, size := c.getattr(id); st != nfs3OK || typ != typeReg || size != 11 {
+		t.Errorf("getattr = %d, type %d, size %d, want regular file of 11 bytes", st, typ, size)
+	}
+	if data, eof := c.read(id, 6, 100); data != "world" || !eof {
+		t.Errorf("read = %q (eof %v), want \"world\" (eof true)", data, eof)
+	}
+	if data, eof := c.read(id, 0, 5); data != "hello" || eof {
+		t.Errorf("read = %q (eof %v), want \"hello\" (eof false)", data, eof)
+	}
+
+	r := c.call(nfsProgram, procWrite, func(w *xdrWriter) {
+		w.opaque(fileHandle(id))
+		w.u64(6)
+		w.u32(5)
+		w.u32(0)
+		w.opaque([]byte("there"))
+	})
+	if st := r.u32(); st != nfs3OK {
+		t.Fatalf("write: %d", st)
+	}
+	b, err := ioutil.ReadFile(filepath.Join(root, "hello.txt"))
+	if err != nil || string(b) != "hello there" {
+		t.Errorf("file = %q (%v), want \"hello there\"", b, err)
+	}
+
+	if st, _ := c.lookup(dir, "missing"); st != nfs3ErrNoEnt {
+		t.Errorf("lookup of a missing file = %d, want %d", st, nfs3ErrNoEnt)
+	}
+}
+
+func TestLookupEscape(t *testing.T) {
+	root := tempRoot(t)
+	if err := os.Symlink(os.TempDir(), filepath.Join(root, "link")); err != nil {
+		t.Skipf("symlink: %v", err)
+	}
+	c := newClient(t, root)
+	dir := c.mount()
+
+	if st, id := c.lookup(dir, ".."); st != nfs3OK || id != rootID {
+		t.Errorf("lookup of .. in the root = %d, handle %d, want the root", st, id)
+	}
+	if st, _ := c.lookup(dir, "sub/../.."); st != nfs3ErrInval {
+		t.Errorf("lookup of a path = %d, want %d", st, nfs3ErrInval)
+	}
+	st, link := c.lookup(dir, "link")
+	if st != nfs3OK {
+		t.Fatalf("lookup of link: %d", st)
+	}
+	if st, typ, _ := c.getattr(link); st != nfs3OK || typ != typeLnk {
+		t.Errorf("getattr of link = %d, type %d, want a symlink", st, typ)
+	}
+	if st, _ := c.lookup(link, "hello.txt"); st != nfs3ErrNotDir {
+		t.Errorf("lookup through a symlink = %d, want %d", st, nfs3ErrNotDir)
+	}
+}
+
+func TestCreateRenameRemove(t *testing.T) {
+	root := tempRoot(t)
+	c := newClient(t, root)
+	dir := c.mount()
+
+	r := c.call(nfsProgram, procCreate, func(w *xdrWriter) {
+		w.opaque(fileHandle(dir))
+		w.str("new.txt")
+		w.u32(createGuarded)
+		// mode 0600, then no owner, size nor times
+		w.boolean(true)
+		w.u32(0o600)
+		for i := 0; i < 3; i++ {
+			w.boolean(false)
+		}
+		w.u32(0)
+		w.u32(0)
+	})
+	if st := r.u32(); st != nfs3OK {
+		t.Fatalf("create: %d", st)
+	}
+	r.boolean()
+	id := r.handle()
+	fi, err := os.Stat(filepath.Join(root, "new.txt"))
+	if err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+	if fi.Mode().Perm() != 0o600 {
+		t.Errorf("mode = %o, want 600", fi.Mode().Perm())
+	}
+
+	r = c.call(nfsProgram, procRename, func(w *xdrWriter) {
+		w.opaque(fileHandle(dir))
+		w.str("new.txt")
+		w.opaque(fileHandle(dir))
+		w.str("renamed.txt")
+	})
+	if st := r.u32(); st != nfs3OK {
+		t.Fatalf("rename: %d", st)
+	}
+	if st, typ, _ := c.getattr(id); st != nfs3OK || typ != typeReg {
+		t.Errorf("getattr after rename = %d, type %d, want the renamed file", st, typ)
+	}
+	if st, rid := c.lookup(dir, "renamed.txt"); st != nfs3OK || rid != id {
+		t.Errorf("lookup of the renamed file = %d, handle %d, want handle %d", st, rid, id)
+	}
+
+	r = c.call(nfsProgram, procRemove, func(w *xdrWriter) {
+		w.opaque(fileHandle(dir))
+		w.str("renamed.txt")
+	})
+	if st := r.u32(); st != nfs3OK {
+		t.Fatalf("remove: %d", st)
+	}
+	if _, err := os.Stat(filepath.Join(root, "renamed.txt")); !os.IsNotExist(err) {
+		t.Errorf("renamed.txt was not removed: %v", err)
+	}
+	if st, _, _ := c.getattr(id); st != nfs3ErrStale {
+		t.Errorf("getattr after remove = %d, want %d", st, nfs3ErrStale)
+	}
+
+	r = c.call(nfsProgram, procRemove, func(w *xdrWriter) {
+		w.opaque(fileHandle(dir))
+		w.str("sub")
+	})
+	if st := r.u32(); st != nfs3ErrIsDir {
+		t.Errorf("remove of a directory = %d, want %d", st, nfs3ErrIsDir)
+	}
+}
+
+func TestReaddir(t *testing.T) {
+	c := newClient(t, tempRoot(t))
+	dir := c.mount()
+
+	names, _, eof := c.readdir(dir, 0, 4096)
+	sort.Strings(names)
+	if diff := cmp.Diff([]string{".", "..", "hello.txt", "sub"}, names); diff != "" || !eof {
+		t.Errorf("readdir (-want +got): %s, eof %v", diff, eof)
+	}
+
+	// a small count takes several calls
+	var all []string
+	cookie := uint64(0)
+	for i := 0; i < 10; i++ {
+		var names []string
+		names, cookie, eof = c.readdir(dir, cookie, 150)
+		all = append(all, names...)
+		if eof {
+			break
+		}
+	}
+	sort.Strings(all)
+	if diff := cmp.Diff([]string{".", "..", "hello.txt", "sub"}, all); diff != "" || !eof {
+		t.Errorf("paged readdir (-want +got): %s, eof %v", diff, eof)
+	}
+}
+
+func TestBadCalls(t *testing.T) {
+	c := newClient(t, tempRoot(t))
+
+	if st, _, _ := c.getattr(0); st != nfs3ErrBadHandle {
+		t.Errorf("getattr of a malformed handle = %d, want %d", st, nfs3ErrBadHandle)
+	}
+	if st, _, _ := c.getattr(12345); st != nfs3ErrStale {
+		t.Errorf("getattr of an unknown handle = %d, want %d", st, nfs3ErrStale)
+	}
+
+	w := &xdrWriter{}
+	for _, v := range []uint32{1, msgCall, rpcVersion, nfsProgram, version3, procGetattr, authNone, 0, authNone, 0} {
+		w.u32(v)
+	}
+	if err := writeRecord(c.conn, w.Bytes()); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+	rec, err := readRecord(c.conn)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	r := &xdrReader{b: rec[12:]}
+	r.u32()
+	r.opaque()
+	if stat := r.u32(); stat != acceptGarbageArgs {
+		t.Errorf("accept status of a call without arguments = %d, want %d", stat, acceptGarbageArgs)
+	}
+}
+
+func TestServerAccepts(t *testing.T) {
+	s := NewServer("/export", 1000, 1001)
+	if !s.accepts(&net.TCPAddr{IP: net.ParseIP("10.0.0.1")}) {
+		t.Error("a server without allowed hosts refused a connection")
+	}
+
+	s.SetAllowedHosts([]net.IP{net.ParseIP("192.168.49.2"), net.ParseIP("fd00::2")})
+	tests := []struct {
+		addr net.Addr
+		want bool
+	}{
+		{&net.TCPAddr{IP: net.ParseIP("192.168.49.2"), Port: 1234}, true},
+		{&net.TCPAddr{IP: net.ParseIP("::ffff:192.168.49.2"), Port: 1234}, true},
+		{&net.TCPAddr{IP: net.ParseIP("fd00::2"), Port: 1234}, true},
+		{&net.TCPAddr{IP: net.ParseIP("192.168.49.3"), Port: 1234}, false},
+		{&net.UnixAddr{Name: "/tmp/sock", Net: "unix"}, false},
+	}
+	for _, tc := range tests {
+		if got := s.accepts(tc.addr); got != tc.want {
+			t.Errorf("accepts(%v) = %v, want %v", tc.addr, got, tc.want)
+		}
+	}
+}
+
+func TestServeRefusesOtherHosts(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer l.Close()
+	s := NewServer("/export", 1000, 1001)
+	s.SetAllowedHosts([]net.IP{net.ParseIP("192.168.49.2")})
+	go s.Serve(l)
+
+	conn, err := net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+	w := &xdrWriter{}
+	for _, v := range []uint32{1, msgCall, rpcVersion, mountProgram, version3, mountProcNull, authNone, 0, authNone, 0} {
+		w.u32(v)
+	}
+	// the server closes the connections of other hosts, whose calls are never replied to
+	writeRecord(conn, w.Bytes())
+	if _, err := readRecord(conn); err == nil {
+		t.Error("a host which is not allowed got a reply")
+	}
+}
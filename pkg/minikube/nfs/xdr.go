@@ -0,0 +1,117 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package nfs
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/pkg/errors"
+)
+
+// errGarbage is returned when the arguments of a call can not be decoded
+var errGarbage = errors.New("garbage arguments")
+
+// xdrReader decodes XDR (RFC 4506) data. Once data is missing, every read returns zero and err is set.
+type xdrReader struct {
+	b   []byte
+	err error
+}
+
+func (r *xdrReader) u32() uint32 {
+	b := r.fixed(4)
+	if b == nil {
+		return 0
+	}
+	return binary.BigEndian.Uint32(b)
+}
+
+func (r *xdrReader) u64() uint64 {
+	hi := r.u32()
+	lo := r.u32()
+	return uint64(hi)<<32 | uint64(lo)
+}
+
+func (r *xdrReader) boolean() bool {
+	return r.u32() != 0
+}
+
+// fixed reads n bytes of fixed-length opaque data, padded to a multiple of 4 bytes
+func (r *xdrReader) fixed(n uint32) []byte {
+	if r.err != nil {
+		return nil
+	}
+	padded := (uint64(n) + 3) &^ 3
+	if uint64(len(r.b)) < padded {
+		r.err = errGarbage
+		return nil
+	}
+	b := r.b[:n]
+	r.b = r.b[padded:]
+	return b
+}
+
+// opaque reads variable-length opaque data
+func (r *xdrReader) opaque() []byte {
+	return r.fixed(r.u32())
+}
+
+func (r *xdrReader) str() string {
+	return string(r.opaque())
+}
+
+// xdrWriter encodes XDR data
+type xdrWriter struct {
+	bytes.Buffer
+}
+
+func (w *xdrWriter) u32(v uint32) {
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], v)
+	w.Write(b[:])
+}
+
+func (w *xdrWriter) u64(v uint64) {
+	w.u32(uint32(v >> 32))
+	w.u32(uint32(v))
+}
+
+func (w *xdrWriter) boolean(v bool) {
+	if v {
+		w.u32(1)
+		return
+	}
+	w.u32(0)
+}
+
+// fixed writes fixed-length opaque data, padded to a multiple of 4 bytes
+func (w *xdrWriter) fixed(b []byte) {
+	w.Write(b)
+	if pad := len(b) % 4; pad != 0 {
+		w.Write(make([]byte, 4-pad))
+	}
+}
+
+// opaque writes variable-length opaque data
+func (w *xdrWriter) opaque(b []byte) {
+	w.u32(uint32(len(b)))
+	w.fixed(b)
+}
+
+func (w *xdrWriter) str(s string) {
+	w.opaque([]byte(s))
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"os/exec"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+
+	"k8s.io/minikube/pkg/drivers/kic/oci"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/driver"
+)
+
+// nodeNetwork returns the IPv4 subnet the nodes of a cluster are attached to, and the addresses of the containers
+// attached to it if known: the docker or podman network of the cluster, or the /24 network of ip for the other drivers
+func nodeNetwork(cc config.ClusterConfig, ip string) (*net.IPNet, []net.IP, error) {
+	if driver.IsKIC(cc.Driver) {
+		subnet, used, err := oci.NetworkAddresses(cc.Driver, driver.NetworkName(cc))
+		if err == nil {
+			return subnet, used, nil
+		}
+		klog.Warningf("unable to get the subnet of network %s, assuming the /24 network of %s: %v", driver.NetworkName(cc), ip, err)
+	}
+	subnet, err := oci.ParseSubnet(ip)
+	return subnet, nil, err
+}
+
+// chooseHAVIP returns the virtual IP for the control planes of an HA cluster, which is checked to be free: neither a
+// node, nor a container of the docker network of the cluster, nor a host which answers a ping from the primary control
+// plane cp may use it.
+func chooseHAVIP(cc config.ClusterConfig, cp config.Node, r command.Runner) (string, error) {
+	subnet, used, err := nodeNetwork(cc, cp.IP)
+	if err != nil {
+		return "", errors.Wrap(err, "subnet of the control planes")
+	}
+	for _, n := range cc.Nodes {
+		used = append(used, net.ParseIP(n.IP))
+	}
+	inUse := func(ip net.IP) bool {
+		for _, u := range used {
+			if u.Equal(ip) {
+				return true
+			}
+		}
+		// nodes without ping, such as the kicbase image, rely on the addresses of the network
+		if _, err := r.RunCmd(exec.Command("ping", "-c", "1", "-W", "1", ip.String())); err == nil {
+			klog.Warningf("%s answers pings, so is not used as virtual IP", ip)
+			return true
+		}
+		return false
+	}
+	return haVIP(cp.IP, subnet, inUse)
+}
+
+// haVIP returns the virtual IP for the control planes of an HA cluster: the last usable address of the subnet of the
+// primary control plane which is not in use, going down towards the gateway
+func haVIP(cpIP string, subnet *net.IPNet, inUse func(net.IP) bool) (string, error) {
+	ip := net.ParseIP(cpIP).To4()
+	if ip == nil {
+		return "", fmt.Errorf("failed to parse IPv4 address %q", cpIP)
+	}
+	if !subnet.Contains(ip) {
+		return "", fmt.Errorf("%s is not within the subnet %s", ip, subnet)
+	}
+	first, vip := oci.UsableRange(subnet)
+	for ; bytes.Compare(vip, first) >= 0; vip = oci.AddToIP(vip, -1) {
+		if !vip.Equal(ip) && !inUse(vip) {
+			return vip.String(), nil
+		}
+	}
+	return "", fmt.Errorf("no free address left in the subnet %s for the virtual IP", subnet)
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"net"
+	"testing"
+)
+
+func TestHAVIP(t *testing.T) {
+	tests := []struct {
+		cpIP      string
+		subnet    string
+		used      []string
+		expected  string
+		shouldErr bool
+	}{
+		{"192.168.49.2", "192.168.49.0/24", nil, "192.168.49.254", false},
+		{"192.168.49.254", "192.168.49.0/24", nil, "192.168.49.253", false},
+		{"192.168.49.2", "192.168.49.0/24", []string{"192.168.49.254", "192.168.49.253"}, "192.168.49.252", false},
+		{"192.168.49.18", "192.168.49.16/28", nil, "192.168.49.30", false},
+		{"10.10.3.2", "10.10.0.0/16", nil, "10.10.255.254", false},
+		{"192.168.49.250", "192.168.49.248/29", []string{"192.168.49.254", "192.168.49.253", "192.168.49.251"}, "192.168.49.252", false},
+		{"192.168.49.250", "192.168.49.248/29", []string{"192.168.49.254", "192.168.49.253", "192.168.49.252", "192.168.49.251"}, "", true},
+		{"192.168.50.2", "192.168.49.0/24", nil, "", true},
+		{"fd00::2", "192.168.49.0/24", nil, "", true},
+	}
+	for _, tc := range tests {
+		_, subnet, err := net.ParseCIDR(tc.subnet)
+		if err != nil {
+			t.Fatalf("parse %s: %v", tc.subnet, err)
+		}
+		inUse := func(ip net.IP) bool {
+			for _, u := range tc.used {
+				if ip.Equal(net.ParseIP(u)) {
+					return true
+				}
+			}
+			return false
+		}
+		got, err := haVIP(tc.cpIP, subnet, inUse)
+		if err != nil && !tc.shouldErr {
+			t.Errorf("haVIP(%s, %s) returned unexpected error: %v", tc.cpIP, tc.subnet, err)
+		}
+		if err == nil && tc.shouldErr {
+			t.Errorf("haVIP(%s, %s) should have returned an error", tc.cpIP, tc.subnet)
+		}
+		if err == nil && got != tc.expected {
+			t.Errorf("haVIP(%s, %s) = %s, expected %s", tc.cpIP, tc.subnet, got, tc.expected)
+		}
+	}
+}
@@ -25,6 +25,7 @@ import (
 
 	"k8s.io/klog/v2"
 	"k8s.io/minikube/pkg/kapi"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/driver"
 	"k8s.io/minikube/pkg/minikube/machine"
@@ -67,6 +68,14 @@ func Delete(cc config.ClusterConfig, name string) (*config.Node, error) {
 		return n, errors.Wrap(err, "retrieve")
 	}
 
+	primary, err := config.PrimaryControlPlane(&cc)
+	if err != nil {
+		return n, errors.Wrap(err, "get primary control plane")
+	}
+	if n.Name == primary.Name {
+		return n, errors.New("the primary control plane cannot be deleted")
+	}
+
 	m := driver.MachineName(cc, *n)
 	api, err := machine.NewAPIClient()
 	if err != nil {
@@ -93,6 +102,13 @@ func Delete(cc config.ClusterConfig, name string) (*config.Node, error) {
 		klog.Infof("successfully scaled coredns replicas to 1")
 	}
 
+	// control planes of HA clusters run a stacked etcd member, which would otherwise break quorum once gone
+	if n.ControlPlane {
+		if err := bsutil.RemoveEtcdMember(runner, cc, primary, *n); err != nil {
+			klog.Warningf("unable to remove etcd member: %v", err)
+		}
+	}
+
 	// kubectl delete
 	client, err := kapi.Client(cc.Name)
 	if err != nil {
@@ -38,6 +38,7 @@ import (
 	"k8s.io/minikube/pkg/drivers/kic/oci"
 	"k8s.io/minikube/pkg/kapi"
 	"k8s.io/minikube/pkg/minikube/bootstrapper"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil"
 	"k8s.io/minikube/pkg/minikube/bootstrapper/images"
 	"k8s.io/minikube/pkg/minikube/cluster"
 	"k8s.io/minikube/pkg/minikube/cni"
@@ -57,6 +58,7 @@ import (
 	"k8s.io/minikube/pkg/minikube/proxy"
 	"k8s.io/minikube/pkg/minikube/reason"
 	"k8s.io/minikube/pkg/minikube/style"
+	"k8s.io/minikube/pkg/minikube/sysinit"
 	"k8s.io/minikube/pkg/util"
 	"k8s.io/minikube/pkg/util/retry"
 )
@@ -94,16 +96,33 @@ func Start(starter Starter, apiServer bool) (*kubeconfig.Settings, error) {
 	showVersionInfo(starter.Node.KubernetesVersion, cr)
 
 	// Add "host.minikube.internal" DNS alias (intentionally non-fatal)
-	hostIP, err := cluster.HostIP(starter.Host, starter.Cfg.Name)
+	hostIP, err := cluster.HostIP(starter.Host, driver.NetworkName(*starter.Cfg))
 	if err != nil {
 		klog.Errorf("Unable to get host IP: %v", err)
 	} else if err := machine.AddHostAlias(starter.Runner, constants.HostAlias, hostIP); err != nil {
 		klog.Errorf("Unable to add host alias: %v", err)
 	}
 
+	// Add "registry.minikube" DNS alias, served on every node by the registry proxy of the registry addon
+	if starter.Cfg.Registry {
+		if err := machine.AddHostAlias(starter.Runner, constants.RegistryAlias, net.ParseIP(starter.Node.IP)); err != nil {
+			klog.Errorf("Unable to add registry alias: %v", err)
+		}
+	}
+
 	var bs bootstrapper.Bootstrapper
 	var kcs *kubeconfig.Settings
 	if apiServer {
+		// The virtual IP is needed by the kubeconfig, certs and kube-vip, so it must be chosen first
+		if starter.Cfg.HA && starter.Cfg.KubernetesConfig.APIServerHAVIP == "" {
+			vip, err := chooseHAVIP(*starter.Cfg, *starter.Node, starter.Runner)
+			if err != nil {
+				return nil, errors.Wrap(err, "choosing virtual IP")
+			}
+			klog.Infof("using virtual IP %s for the control planes of %s", vip, starter.Cfg.Name)
+			starter.Cfg.KubernetesConfig.APIServerHAVIP = vip
+		}
+
 		// Must be written before bootstrap, otherwise health checks may flake due to stale IP
 		kcs = setupKubeconfig(starter.Host, starter.Cfg, starter.Node, starter.Cfg.Name)
 		if err != nil {
@@ -176,13 +195,8 @@ func Start(starter Starter, apiServer bool) (*kubeconfig.Settings, error) {
 			return nil, errors.Wrap(err, "getting control plane bootstrapper")
 		}
 
-		joinCmd, err := cpBs.GenerateToken(*starter.Cfg)
-		if err != nil {
-			return nil, errors.Wrap(err, "generating join token")
-		}
-
-		if err = bs.JoinCluster(*starter.Cfg, *starter.Node, joinCmd); err != nil {
-			return nil, errors.Wrap(err, "joining cluster")
+		if err := joinCluster(starter, cpBs, bs, cpr); err != nil {
+			return nil, err
 		}
 
 		cnm, err := cni.New(*starter.Cfg)
@@ -207,6 +221,36 @@ func Start(starter Starter, apiServer bool) (*kubeconfig.Settings, error) {
 	return kcs, config.Write(viper.GetString(config.ProfileName), starter.Cfg)
 }
 
+// joinCluster joins a node to the cluster, either as a worker or as an additional control plane
+func joinCluster(starter Starter, cpBs bootstrapper.Bootstrapper, bs bootstrapper.Bootstrapper, cpr command.Runner) error {
+	if starter.Node.ControlPlane {
+		// control planes keep their etcd membership across restarts, resetting them would lose it
+		if starter.PreExists {
+			klog.Infof("%s is an existing control plane, restarting kubelet rather than rejoining", starter.Node.Name)
+			return sysinit.New(starter.Runner).Restart("kubelet")
+		}
+
+		// remove any member left behind by a previous failed join, as it would prevent etcd from joining again
+		cp, err := config.PrimaryControlPlane(starter.Cfg)
+		if err != nil {
+			return errors.Wrap(err, "getting primary control plane")
+		}
+		if err := bsutil.RemoveEtcdMember(cpr, *starter.Cfg, cp, *starter.Node); err != nil {
+			klog.Warningf("unable to remove stale etcd member: %v", err)
+		}
+	}
+
+	joinCmd, err := cpBs.GenerateToken(*starter.Cfg, *starter.Node)
+	if err != nil {
+		return errors.Wrap(err, "generating join token")
+	}
+
+	if err = bs.JoinCluster(*starter.Cfg, *starter.Node, joinCmd); err != nil {
+		return errors.Wrap(err, "joining cluster")
+	}
+	return nil
+}
+
 // Provision provisions the machine/container for the node
 func Provision(cc *config.ClusterConfig, n *config.Node, apiServer bool, delOnFail bool) (command.Runner, bool, libmachine.API, *host.Host, error) {
 	register.Reg.SetStep(register.StartingNode)
@@ -244,6 +288,7 @@ func configureRuntimes(runner cruntime.CommandRunner, cc config.ClusterConfig, k
 		Runner:            runner,
 		ImageRepository:   cc.KubernetesConfig.ImageRepository,
 		KubernetesVersion: kv,
+		InsecureRegistry:  cc.InsecureRegistry,
 	}
 	cr, err := cruntime.New(co)
 	if err != nil {
@@ -336,6 +381,10 @@ func apiServerURL(h host.Host, cc config.ClusterConfig, n config.Node) (string,
 	if err != nil {
 		return "", err
 	}
+	// HA clusters are reached through their virtual IP, unless the driver requires port forwarding
+	if cc.KubernetesConfig.APIServerHAVIP != "" && !driver.NeedsPortForward(h.DriverName) {
+		hostname = cc.KubernetesConfig.APIServerHAVIP
+	}
 	return fmt.Sprintf("https://" + net.JoinHostPort(hostname, strconv.Itoa(port))), nil
 }
 
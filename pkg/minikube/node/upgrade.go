@@ -0,0 +1,161 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"os/exec"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	"k8s.io/klog/v2"
+
+	cmdcfg "k8s.io/minikube/cmd/minikube/cmd/config"
+	"k8s.io/minikube/pkg/kapi"
+	"k8s.io/minikube/pkg/minikube/cluster"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/out"
+	"k8s.io/minikube/pkg/minikube/out/register"
+	"k8s.io/minikube/pkg/minikube/style"
+	"k8s.io/minikube/pkg/util"
+)
+
+// Upgrade upgrades a running cluster to a newer Kubernetes version: the control planes first, then each worker in turn,
+// which is drained beforehand and uncordoned afterwards. The version of each node of cc is updated once it is upgraded,
+// so that an upgrade which failed part way may be retried, but cc is not saved.
+func Upgrade(api libmachine.API, cc *config.ClusterConfig, version string) error {
+	old := cc.KubernetesConfig.KubernetesVersion
+	cc.KubernetesConfig.KubernetesVersion = version
+
+	primary, err := config.PrimaryControlPlane(cc)
+	if err != nil {
+		return errors.Wrap(err, "primary control plane")
+	}
+	cpr, err := runner(api, *cc, primary)
+	if err != nil {
+		return err
+	}
+
+	register.Reg.SetStep(register.UpgradingControlPlane)
+	for _, i := range upgradeOrder(*cc, primary, old, version) {
+		n := cc.Nodes[i]
+		if !n.ControlPlane {
+			register.Reg.SetStep(register.UpgradingWorkers)
+		}
+		// the primary control plane is not drained, as the others are drained through it
+		r := cpr
+		if n.Name == primary.Name {
+			r = nil
+		}
+		if err := upgradeNode(api, cc, i, r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// upgradeOrder returns the indexes of the nodes of cc which an upgrade from old to version upgrades, in order: the
+// primary control plane first, as kubeadm upgrade apply upgrades the configuration of the whole cluster, then the other
+// control planes, then the workers. Nodes already at version, from an upgrade which failed part way, are skipped.
+func upgradeOrder(cc config.ClusterConfig, primary config.Node, old string, version string) []int {
+	var cps, workers []int
+	for i, n := range cc.Nodes {
+		if n.KubernetesVersion == version {
+			continue
+		}
+		switch {
+		case n.Name == primary.Name:
+			cps = append([]int{i}, cps...)
+		case n.ControlPlane:
+			cps = append(cps, i)
+		case n.KubernetesVersion != old && config.ValidateNodeVersion(n.KubernetesVersion, version) == nil:
+			// workers added with their own --kubernetes-version keep it, for as long as the control plane supports it
+			klog.Infof("keeping %s at Kubernetes %s", n.Name, n.KubernetesVersion)
+		default:
+			workers = append(workers, i)
+		}
+	}
+	return append(cps, workers...)
+}
+
+// upgradeNode upgrades the i-th node of a cluster. Unless cpr is nil, the node is drained beforehand, and uncordoned afterwards, through the control plane runner cpr.
+func upgradeNode(api libmachine.API, cc *config.ClusterConfig, i int, cpr command.Runner) error {
+	// the node is only recorded at the new version once it is upgraded
+	n := cc.Nodes[i]
+	n.KubernetesVersion = cc.KubernetesConfig.KubernetesVersion
+	name := driver.MachineName(*cc, n)
+	out.Step(style.Waiting, "Upgrading {{.name}} to Kubernetes {{.version}} ...", out.V{"name": name, "version": n.KubernetesVersion})
+
+	r, err := runner(api, *cc, n)
+	if err != nil {
+		return err
+	}
+	sv, err := util.ParseKubernetesVersion(n.KubernetesVersion)
+	if err != nil {
+		return errors.Wrap(err, "parsing Kubernetes version")
+	}
+	cr, err := cruntime.New(cruntime.Config{
+		Type:              cc.KubernetesConfig.ContainerRuntime,
+		Runner:            r,
+		ImageRepository:   cc.KubernetesConfig.ImageRepository,
+		KubernetesVersion: sv,
+		InsecureRegistry:  cc.InsecureRegistry,
+	})
+	if err != nil {
+		return errors.Wrap(err, "runtime")
+	}
+	bs, err := cluster.Bootstrapper(api, viper.GetString(cmdcfg.Bootstrapper), *cc, r)
+	if err != nil {
+		return errors.Wrap(err, "bootstrapper")
+	}
+
+	if cpr != nil {
+		if err := kubectl(cpr, *cc, "drain", name, "--ignore-daemonsets", "--delete-local-data", "--force"); err != nil {
+			return errors.Wrapf(err, "drain %s", name)
+		}
+	}
+	if err := bs.UpgradeNode(*cc, n, cr); err != nil {
+		return errors.Wrapf(err, "upgrade %s", name)
+	}
+	cc.Nodes[i] = n
+	if cpr != nil {
+		if err := kubectl(cpr, *cc, "uncordon", name); err != nil {
+			return errors.Wrapf(err, "uncordon %s", name)
+		}
+	}
+	return nil
+}
+
+// runner returns a command runner for a node of a cluster
+func runner(api libmachine.API, cc config.ClusterConfig, n config.Node) (command.Runner, error) {
+	h, err := machine.LoadHost(api, driver.MachineName(cc, n))
+	if err != nil {
+		return nil, errors.Wrap(err, "load host")
+	}
+	return machine.CommandRunner(h)
+}
+
+// kubectl runs a kubectl command against the cluster through a control plane runner
+func kubectl(cpr command.Runner, cc config.ClusterConfig, args ...string) error {
+	args = append([]string{"KUBECONFIG=/var/lib/minikube/kubeconfig", kapi.KubectlBinaryPath(cc.KubernetesConfig.KubernetesVersion)}, args...)
+	_, err := cpr.RunCmd(exec.Command("sudo", args...))
+	return err
+}
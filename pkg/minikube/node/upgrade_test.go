@@ -0,0 +1,102 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package node
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+func TestUpgradeOrder(t *testing.T) {
+	const old, version = "v1.19.4", "v1.20.0"
+	tests := []struct {
+		description string
+		nodes       []config.Node
+		want        []string
+	}{
+		{
+			description: "single node",
+			nodes:       []config.Node{{Name: "", ControlPlane: true, Worker: true, KubernetesVersion: old}},
+			want:        []string{""},
+		},
+		{
+			description: "control planes before workers",
+			nodes: []config.Node{
+				{Name: "", ControlPlane: true, KubernetesVersion: old},
+				{Name: "m02", KubernetesVersion: old},
+				{Name: "m03", ControlPlane: true, KubernetesVersion: old},
+				{Name: "m04", KubernetesVersion: old},
+			},
+			want: []string{"", "m03", "m02", "m04"},
+		},
+		{
+			description: "primary first",
+			nodes: []config.Node{
+				{Name: "m02", KubernetesVersion: old},
+				{Name: "m03", ControlPlane: true, KubernetesVersion: old},
+				{Name: "m04", ControlPlane: true, KubernetesVersion: old},
+			},
+			want: []string{"m03", "m04", "m02"},
+		},
+		{
+			description: "workers at their own version are kept",
+			nodes: []config.Node{
+				{Name: "", ControlPlane: true, KubernetesVersion: old},
+				{Name: "m02", KubernetesVersion: "v1.19.0"},
+				{Name: "m03", KubernetesVersion: old},
+			},
+			want: []string{"", "m03"},
+		},
+		{
+			description: "workers too old for the new version are upgraded",
+			nodes: []config.Node{
+				{Name: "", ControlPlane: true, KubernetesVersion: old},
+				{Name: "m02", KubernetesVersion: "v1.17.0"},
+			},
+			want: []string{"", "m02"},
+		},
+		{
+			description: "upgraded nodes are skipped when retrying",
+			nodes: []config.Node{
+				{Name: "", ControlPlane: true, KubernetesVersion: version},
+				{Name: "m02", ControlPlane: true, KubernetesVersion: old},
+				{Name: "m03", KubernetesVersion: version},
+				{Name: "m04", KubernetesVersion: old},
+			},
+			want: []string{"m02", "m04"},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.description, func(t *testing.T) {
+			cc := config.ClusterConfig{Nodes: tc.nodes}
+			primary, err := config.PrimaryControlPlane(&cc)
+			if err != nil {
+				t.Fatalf("primary control plane: %v", err)
+			}
+			var got []string
+			for _, i := range upgradeOrder(cc, primary, old, version) {
+				got = append(got, cc.Nodes[i].Name)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("upgradeOrder() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
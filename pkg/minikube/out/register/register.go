@@ -46,6 +46,10 @@ const (
 	Deleting  RegStep = "Deleting"
 	Pausing   RegStep = "Pausing"
 	Unpausing RegStep = "Unpausing"
+
+	Upgrading             RegStep = "Upgrading"
+	UpgradingControlPlane RegStep = "Upgrading Control Plane"
+	UpgradingWorkers      RegStep = "Upgrading Workers"
 )
 
 // RegStep is a type representing a distinct step of `minikube start`
@@ -86,6 +90,7 @@ func init() {
 			Stopping:  {Stopping, PowerOff, Done},
 			Pausing:   {Pausing, Done},
 			Unpausing: {Unpausing, Done},
+			Upgrading: {Upgrading, UpgradingControlPlane, UpgradingWorkers, Done},
 			Deleting:  {Deleting, Stopping, Deleting, Done},
 		},
 	}
@@ -0,0 +1,87 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package portforward forwards TCP connections from a host port into the cluster
+package portforward
+
+import (
+	"context"
+	"io"
+	"net"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+)
+
+// dialTimeout is how long to wait for the target to accept a forwarded connection
+const dialTimeout = 10 * time.Second
+
+// Forward listens on addr, and forwards every connection to target until ctx is done
+func Forward(ctx context.Context, addr string, target string) error {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return errors.Wrapf(err, "listen on %s", addr)
+	}
+	return Serve(ctx, l, target)
+}
+
+// Serve forwards every connection accepted by l to target until ctx is done, and then closes l
+func Serve(ctx context.Context, l net.Listener, target string) error {
+	go func() {
+		<-ctx.Done()
+		l.Close()
+	}()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return errors.Wrap(err, "accept")
+		}
+		go forward(conn, target)
+	}
+}
+
+// forward copies data between conn and a new connection to target, until both sides are done
+func forward(conn net.Conn, target string) {
+	defer conn.Close()
+
+	upstream, err := net.DialTimeout("tcp", target, dialTimeout)
+	if err != nil {
+		klog.Warningf("unable to forward %s to %s: %v", conn.RemoteAddr(), target, err)
+		return
+	}
+	defer upstream.Close()
+
+	done := make(chan struct{}, 2)
+	pipe := func(dst net.Conn, src net.Conn) {
+		if _, err := io.Copy(dst, src); err != nil {
+			klog.Infof("forwarding %s to %s: %v", src.RemoteAddr(), dst.RemoteAddr(), err)
+		}
+		// let the other side know that no more data is coming, while still reading its response
+		if c, ok := dst.(*net.TCPConn); ok {
+			c.CloseWrite()
+		}
+		done <- struct{}{}
+	}
+	go pipe(upstream, conn)
+	go pipe(conn, upstream)
+	<-done
+	<-done
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package portforward
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net"
+	"testing"
+)
+
+func TestServe(t *testing.T) {
+	// the target upper-cases whatever it is sent, until its client stops writing
+	target, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer target.Close()
+	go func() {
+		for {
+			conn, err := target.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				b, _ := ioutil.ReadAll(conn)
+				for i := range b {
+					if b[i] >= 'a' && b[i] <= 'z' {
+						b[i] -= 'a' - 'A'
+					}
+				}
+				conn.Write(b)
+			}()
+		}
+	}()
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	errc := make(chan error)
+	go func() { errc <- Serve(ctx, l, target.Addr().String()) }()
+
+	tests := []struct {
+		msg  string
+		want string
+	}{
+		{"hello", "HELLO"},
+		{"localhost:5000/app", "LOCALHOST:5000/APP"},
+	}
+	for _, tc := range tests {
+		conn, err := net.Dial("tcp", l.Addr().String())
+		if err != nil {
+			t.Fatalf("dial: %v", err)
+		}
+		if _, err := io.WriteString(conn, tc.msg); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+		conn.(*net.TCPConn).CloseWrite()
+		got, err := ioutil.ReadAll(conn)
+		conn.Close()
+		if err != nil {
+			t.Fatalf("read: %v", err)
+		}
+		if string(got) != tc.want {
+			t.Errorf("forwarded %q = %q, want %q", tc.msg, got, tc.want)
+		}
+	}
+
+	cancel()
+	if err := <-errc; err != nil {
+		t.Errorf("Serve() = %v, want nil once the context is done", err)
+	}
+}
@@ -0,0 +1,137 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reason
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+var (
+	// customIssues are the known issues loaded at runtime, which are matched before the built-in ones
+	customIssues   []match
+	customIssuesMu sync.RWMutex
+)
+
+// CustomIssue describes a known issue loaded at runtime, such as a failure specific to an environment
+type CustomIssue struct {
+	ID string `yaml:"id"`
+	// Regexp is the regular expression which errors are matched against
+	Regexp string `yaml:"regexp"`
+	// GOOS are the operating systems this issue is specific to
+	GOOS     []string `yaml:"goos,omitempty"`
+	Advice   string   `yaml:"advice,omitempty"`
+	URL      string   `yaml:"url,omitempty"`
+	ExitCode int      `yaml:"exitCode,omitempty"`
+	// Issues are related minikube issue numbers
+	Issues []int `yaml:"issues,omitempty"`
+	// IssueLinks are links to related issues in other trackers
+	IssueLinks []string `yaml:"issueLinks,omitempty"`
+}
+
+// ParseKnownIssues parses and validates a YAML list of known issues
+func ParseKnownIssues(data []byte) ([]CustomIssue, error) {
+	var cis []CustomIssue
+	if err := yaml.UnmarshalStrict(data, &cis); err != nil {
+		return nil, errors.Wrap(err, "unmarshal")
+	}
+	for _, ci := range cis {
+		if ci.ID == "" {
+			return nil, fmt.Errorf("known issue matching %q has no id", ci.Regexp)
+		}
+		if ci.Regexp == "" {
+			return nil, fmt.Errorf("known issue %s has no regexp", ci.ID)
+		}
+		if _, err := regexp.Compile(ci.Regexp); err != nil {
+			return nil, errors.Wrapf(err, "known issue %s", ci.ID)
+		}
+		if ci.ExitCode < 0 || ci.ExitCode > 255 {
+			return nil, fmt.Errorf("known issue %s has an invalid exit code %d", ci.ID, ci.ExitCode)
+		}
+	}
+	return cis, nil
+}
+
+// LoadKnownIssues loads the known issues of the *.yaml files within dir, replacing those loaded before.
+// Files which fail to parse are skipped, and reported in the returned error.
+func LoadKnownIssues(dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.yaml"))
+	if err != nil {
+		return errors.Wrap(err, "glob")
+	}
+	sort.Strings(paths)
+
+	var ms []match
+	var failed []string
+	for _, p := range paths {
+		data, err := ioutil.ReadFile(p)
+		if err == nil {
+			var cis []CustomIssue
+			cis, err = ParseKnownIssues(data)
+			for _, ci := range cis {
+				ms = append(ms, ci.match())
+			}
+		}
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", p, err))
+		}
+	}
+
+	customIssuesMu.Lock()
+	customIssues = ms
+	customIssuesMu.Unlock()
+
+	if len(failed) > 0 {
+		return errors.New(strings.Join(failed, "; "))
+	}
+	return nil
+}
+
+// match converts a known issue loaded at runtime to a match
+func (ci CustomIssue) match() match {
+	exitCode := ci.ExitCode
+	if exitCode == 0 {
+		exitCode = ExProgramError
+	}
+	return match{
+		Kind: Kind{
+			ID:         ci.ID,
+			ExitCode:   exitCode,
+			Advice:     ci.Advice,
+			URL:        ci.URL,
+			Issues:     ci.Issues,
+			IssueLinks: ci.IssueLinks,
+		},
+		Regexp: regexp.MustCompile(ci.Regexp),
+		GOOS:   ci.GOOS,
+	}
+}
+
+// knownIssuesLoaded returns the known issues loaded at runtime
+func knownIssuesLoaded() []match {
+	customIssuesMu.RLock()
+	defer customIssuesMu.RUnlock()
+	return customIssues
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reason
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadKnownIssues(t *testing.T) {
+	dir, err := ioutil.TempDir("", "known_issues")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	defer func() {
+		customIssues = nil
+	}()
+
+	files := map[string]string{
+		"proxy.yaml": `
+- id: CORP_PROXY_CERT
+  regexp: 'x509: certificate signed by unknown authority'
+  advice: Install the corporate root certificate into ~/.minikube/certs
+  url: https://wiki.example.com/minikube
+  exitCode: 61
+  issueLinks: [https://tracker.example.com/IT-42]
+- id: CORP_AV_LOCK
+  regexp: 'quarantined by endpoint protection'
+  goos: [windows]
+`,
+		"broken.yaml": `
+- id: BROKEN
+  regexp: '('
+`,
+		"ignored.txt": `not yaml`,
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+
+	if err := LoadKnownIssues(dir); err == nil {
+		t.Errorf("LoadKnownIssues(%s) returned no error for broken.yaml", dir)
+	}
+
+	got := MatchKnownIssue(Kind{}, fmt.Errorf("pulling image: Get https://k8s.gcr.io/v2/: x509: certificate signed by unknown authority"), "linux")
+	if got == nil || got.ID != "CORP_PROXY_CERT" {
+		t.Fatalf("MatchKnownIssue()=%+v, want CORP_PROXY_CERT", got)
+	}
+	if got.ExitCode != 61 || got.URL == "" || got.Advice == "" {
+		t.Errorf("MatchKnownIssue()=%+v, want exit code, URL and advice from proxy.yaml", got)
+	}
+	if urls := got.IssueURLs(); len(urls) != 1 || urls[0] != "https://tracker.example.com/IT-42" {
+		t.Errorf("IssueURLs()=%v, want the issue link from proxy.yaml", urls)
+	}
+
+	log := "I1017 starting\nE1017 copy failed: minikube.exe was quarantined by endpoint protection\nI1017 done"
+	if ds := Diagnose(log, "linux"); len(ds) != 0 {
+		t.Errorf("Diagnose(linux)=%+v, want no windows issues", ds)
+	}
+	ds := Diagnose(log, "windows")
+	if len(ds) != 1 || ds[0].ID != "CORP_AV_LOCK" {
+		t.Fatalf("Diagnose(windows)=%+v, want CORP_AV_LOCK", ds)
+	}
+	if want := "E1017 copy failed: minikube.exe was quarantined by endpoint protection"; ds[0].Match != want {
+		t.Errorf("Diagnose(windows) match=%q, want %q", ds[0].Match, want)
+	}
+}
+
+func TestParseKnownIssues(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"no id", "- regexp: foo"},
+		{"no regexp", "- id: FOO"},
+		{"invalid exit code", "- id: FOO\n  regexp: foo\n  exitCode: 300"},
+		{"unknown field", "- id: FOO\n  regexp: foo\n  regex: bar"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := ParseKnownIssues([]byte(tc.data)); err == nil {
+				t.Errorf("ParseKnownIssues(%q) returned no error", tc.data)
+			}
+		})
+	}
+}
@@ -18,6 +18,7 @@ package reason
 
 import (
 	"regexp"
+	"strings"
 
 	"k8s.io/klog/v2"
 )
@@ -35,6 +36,8 @@ type match struct {
 
 func knownIssues() []match {
 	ps := []match{}
+	// Issues loaded at runtime are more specific to the environment than the built-in ones
+	ps = append(ps, knownIssuesLoaded()...)
 	// This is intentionally in dependency order
 	ps = append(ps, programIssues...)
 	ps = append(ps, resourceIssues...)
@@ -85,3 +88,48 @@ func MatchKnownIssue(r Kind, err error, goos string) *Kind {
 
 	return genericMatch
 }
+
+// Diagnosis is a known issue found within a log
+type Diagnosis struct {
+	Kind
+	// Match is the first line of the log which matched the issue
+	Match string
+}
+
+// Diagnose returns every known issue found within a log, such as the output of minikube logs, on an OS
+func Diagnose(log string, goos string) []Diagnosis {
+	var ds []Diagnosis
+	seen := map[string]bool{}
+	for _, ki := range knownIssues() {
+		if ki.Regexp == nil || seen[ki.ID] {
+			continue
+		}
+		if len(ki.GOOS) > 0 {
+			found := false
+			for _, o := range ki.GOOS {
+				if o == goos {
+					found = true
+				}
+			}
+			if !found {
+				continue
+			}
+		}
+
+		loc := ki.Regexp.FindStringIndex(log)
+		if loc == nil {
+			continue
+		}
+		// report the whole line on which the match begins
+		start := strings.LastIndex(log[:loc[0]], "\n") + 1
+		end := strings.Index(log[loc[0]:], "\n")
+		if end < 0 {
+			end = len(log)
+		} else {
+			end += loc[0]
+		}
+		seen[ki.ID] = true
+		ds = append(ds, Diagnosis{Kind: ki.Kind, Match: strings.TrimSpace(log[start:end])})
+	}
+	return ds
+}
@@ -55,6 +55,8 @@ type Kind struct {
 	URL string
 	// Issues are a list of related issues to this issue
 	Issues []int
+	// IssueLinks are links to related issues outside of the minikube issue tracker
+	IssueLinks []string
 	// Show the new issue link
 	NewIssueLink bool
 	// Do not attempt to match this reason to a specific known issue
@@ -67,7 +69,7 @@ func (k *Kind) IssueURLs() []string {
 	for _, i := range k.Issues {
 		is = append(is, fmt.Sprintf("%s/%d", issueBase, i))
 	}
-	return is
+	return append(is, k.IssueLinks...)
 }
 
 // Sections are ordered roughly by stack dependencies
@@ -208,11 +210,16 @@ var (
 	HostKubeconfigUpdate    = Kind{ID: "HOST_KUBECONFIG_UPDATE", ExitCode: ExHostConfig}
 	HostKubeconfigDeleteCtx = Kind{ID: "HOST_KUBECONFIG_DELETE_CTX", ExitCode: ExHostConfig}
 	HostKubectlProxy        = Kind{ID: "HOST_KUBECTL_PROXY", ExitCode: ExHostError}
+	HostLogsBundle          = Kind{ID: "HOST_LOGS_BUNDLE", ExitCode: ExHostError}
+	HostMetricsListen       = Kind{ID: "HOST_METRICS_LISTEN", ExitCode: ExHostError, Advice: "Ensure that nothing else listens on the --metrics-addr port, or choose another port"}
 	HostMountPid            = Kind{ID: "HOST_MOUNT_PID", ExitCode: ExHostError}
 	HostPathMissing         = Kind{ID: "HOST_PATH_MISSING", ExitCode: ExHostNotFound}
 	HostPathStat            = Kind{ID: "HOST_PATH_STAT", ExitCode: ExHostError}
 	HostPurge               = Kind{ID: "HOST_PURGE", ExitCode: ExHostError}
+	HostRegistryForward     = Kind{ID: "HOST_REGISTRY_FORWARD", ExitCode: ExHostError, Advice: "Ensure that nothing else listens on the port, or recreate the cluster with another --registry-port"}
 	HostSaveProfile         = Kind{ID: "HOST_SAVE_PROFILE", ExitCode: ExHostConfig}
+	HostSnapshotDelete      = Kind{ID: "HOST_SNAPSHOT_DELETE", ExitCode: ExHostError}
+	HostSnapshotList        = Kind{ID: "HOST_SNAPSHOT_LIST", ExitCode: ExHostConfig}
 
 	ProviderNotFound    = Kind{ID: "PROVIDER_NOT_FOUND", ExitCode: ExProviderNotFound}
 	ProviderUnavailable = Kind{ID: "PROVIDER_UNAVAILABLE", ExitCode: ExProviderNotFound, Style: style.Shrug}
@@ -237,7 +244,15 @@ var (
 	GuestCacheLoad        = Kind{ID: "GUEST_CACHE_LOAD", ExitCode: ExGuestError}
 	GuestCert             = Kind{ID: "GUEST_CERT", ExitCode: ExGuestError}
 	GuestCpConfig         = Kind{ID: "GUEST_CP_CONFIG", ExitCode: ExGuestConfig}
+	GuestCopy             = Kind{ID: "GUEST_COPY", ExitCode: ExGuestError}
 	GuestDeletion         = Kind{ID: "GUEST_DELETION", ExitCode: ExGuestError}
+	GuestImageBuild       = Kind{ID: "GUEST_IMAGE_BUILD", ExitCode: ExGuestError}
+	GuestImageList        = Kind{ID: "GUEST_IMAGE_LIST", ExitCode: ExGuestError}
+	GuestImageLoad        = Kind{ID: "GUEST_IMAGE_LOAD", ExitCode: ExGuestError}
+	GuestImagePull        = Kind{ID: "GUEST_IMAGE_PULL", ExitCode: ExGuestError}
+	GuestImagePush        = Kind{ID: "GUEST_IMAGE_PUSH", ExitCode: ExGuestError}
+	GuestImageRemove      = Kind{ID: "GUEST_IMAGE_REMOVE", ExitCode: ExGuestError}
+	GuestImageSave        = Kind{ID: "GUEST_IMAGE_SAVE", ExitCode: ExGuestError}
 	GuestLoadHost         = Kind{ID: "GUEST_LOAD_HOST", ExitCode: ExGuestError}
 	GuestMount            = Kind{ID: "GUEST_MOUNT", ExitCode: ExGuestError}
 	GuestMountConflict    = Kind{ID: "GUEST_MOUNT_CONFLICT", ExitCode: ExGuestConflict}
@@ -249,6 +264,8 @@ var (
 	GuestPause            = Kind{ID: "GUEST_PAUSE", ExitCode: ExGuestError}
 	GuestProfileDeletion  = Kind{ID: "GUEST_PROFILE_DELETION", ExitCode: ExGuestError}
 	GuestProvision        = Kind{ID: "GUEST_PROVISION", ExitCode: ExGuestError}
+	GuestSnapshotRestore  = Kind{ID: "GUEST_SNAPSHOT_RESTORE", ExitCode: ExGuestError}
+	GuestSnapshotSave     = Kind{ID: "GUEST_SNAPSHOT_SAVE", ExitCode: ExGuestError}
 	GuestStart            = Kind{ID: "GUEST_START", ExitCode: ExGuestError}
 	GuestStatus           = Kind{ID: "GUEST_STATUS", ExitCode: ExGuestError}
 	GuestStopTimeout      = Kind{ID: "GUEST_STOP_TIMEOUT", ExitCode: ExGuestTimeout}
@@ -279,6 +296,7 @@ var (
 	SvcList         = Kind{ID: "SVC_LIST", ExitCode: ExSvcError}
 	SvcTunnelStart  = Kind{ID: "SVC_TUNNEL_START", ExitCode: ExSvcError}
 	SvcTunnelStop   = Kind{ID: "SVC_TUNNEL_STOP", ExitCode: ExSvcError}
+	SvcTunnelStatus = Kind{ID: "SVC_TUNNEL_STATUS", ExitCode: ExSvcError}
 	SvcURLTimeout   = Kind{ID: "SVC_URL_TIMEOUT", ExitCode: ExSvcTimeout}
 	SvcNotFound     = Kind{ID: "SVC_NOT_FOUND", ExitCode: ExSvcNotFound}
 
@@ -289,8 +307,10 @@ var (
 
 	AddonUnsupported = Kind{ID: "SVC_ADDON_UNSUPPORTED", ExitCode: ExSvcUnsupported}
 	AddonNotEnabled  = Kind{ID: "SVC_ADDON_NOT_ENABLED", ExitCode: ExProgramConflict}
+	AddonInstall     = Kind{ID: "SVC_ADDON_INSTALL", ExitCode: ExSvcConfig}
 
 	KubernetesInstallFailed = Kind{ID: "K8S_INSTALL_FAILED", ExitCode: ExControlPlaneError}
+	KubernetesUpgrade       = Kind{ID: "K8S_UPGRADE_FAILED", ExitCode: ExControlPlaneError}
 	KubernetesTooOld        = Kind{ID: "K8S_OLD_UNSUPPORTED", ExitCode: ExControlPlaneUnsupported}
 	KubernetesDowngrade     = Kind{
 		ID:       "K8S_DOWNGRADE_UNSUPPORTED",
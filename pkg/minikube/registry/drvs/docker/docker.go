@@ -31,6 +31,7 @@ import (
 	"k8s.io/minikube/pkg/drivers/kic"
 	"k8s.io/minikube/pkg/drivers/kic/oci"
 	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/minikube/driver"
 	"k8s.io/minikube/pkg/minikube/localpath"
 	"k8s.io/minikube/pkg/minikube/registry"
@@ -66,6 +67,11 @@ func configure(cc config.ClusterConfig, n config.Node) (interface{}, error) {
 		extraArgs = append(extraArgs, "-p", port)
 	}
 
+	// the local registry is published on a fixed host port by the primary control plane
+	if cp, err := config.PrimaryControlPlane(&cc); err == nil && cc.Registry && cp.Name == n.Name {
+		extraArgs = append(extraArgs, "-p", fmt.Sprintf("%s:%d:%d", oci.DefaultBindIPV4, cc.RegistryHostPort, constants.RegistryAddonPort))
+	}
+
 	return kic.NewDriver(kic.Config{
 		ClusterName:       cc.Name,
 		MachineName:       driver.MachineName(cc, n),
@@ -79,6 +85,10 @@ func configure(cc config.ClusterConfig, n config.Node) (interface{}, error) {
 		KubernetesVersion: cc.KubernetesConfig.KubernetesVersion,
 		ContainerRuntime:  cc.KubernetesConfig.ContainerRuntime,
 		ExtraArgs:         extraArgs,
+		Network:           cc.Network,
+		Subnet:            cc.Subnet,
+		StaticIP:          cc.StaticIP,
+		IPv6:              cc.KubernetesConfig.IPFamily == constants.IPv6Family || cc.KubernetesConfig.IPFamily == constants.DualStackFamily,
 	}), nil
 }
 
@@ -32,6 +32,7 @@ import (
 	"k8s.io/minikube/pkg/drivers/kic"
 	"k8s.io/minikube/pkg/drivers/kic/oci"
 	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/constants"
 	"k8s.io/minikube/pkg/minikube/driver"
 	"k8s.io/minikube/pkg/minikube/localpath"
 	"k8s.io/minikube/pkg/minikube/out"
@@ -75,6 +76,11 @@ func configure(cc config.ClusterConfig, n config.Node) (interface{}, error) {
 		extraArgs = append(extraArgs, "-p", port)
 	}
 
+	// the local registry is published on a fixed host port by the primary control plane
+	if cp, err := config.PrimaryControlPlane(&cc); err == nil && cc.Registry && cp.Name == n.Name {
+		extraArgs = append(extraArgs, "-p", fmt.Sprintf("%s:%d:%d", oci.DefaultBindIPV4, cc.RegistryHostPort, constants.RegistryAddonPort))
+	}
+
 	return kic.NewDriver(kic.Config{
 		ClusterName:       cc.Name,
 		MachineName:       driver.MachineName(cc, n),
@@ -88,6 +94,9 @@ func configure(cc config.ClusterConfig, n config.Node) (interface{}, error) {
 		KubernetesVersion: cc.KubernetesConfig.KubernetesVersion,
 		ContainerRuntime:  cc.KubernetesConfig.ContainerRuntime,
 		ExtraArgs:         extraArgs,
+		Network:           cc.Network,
+		Subnet:            cc.Subnet,
+		StaticIP:          cc.StaticIP,
 	}), nil
 }
 
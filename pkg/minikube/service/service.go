@@ -241,6 +241,17 @@ func PrintServiceList(writer io.Writer, data [][]string) {
 	table.Render()
 }
 
+// PrintForwardedServiceList prints a list of forwarded services as a table which has
+// "Namespace", "Name", "Target Port", "URL" and "Status" columns to a writer
+func PrintForwardedServiceList(writer io.Writer, data [][]string) {
+	table := tablewriter.NewWriter(writer)
+	table.SetHeader([]string{"Namespace", "Name", "Target Port", "URL", "Status"})
+	table.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+	table.SetCenterSeparator("|")
+	table.AppendBulk(data)
+	table.Render()
+}
+
 // SVCNotFoundError error type handles 'service not found' scenarios
 type SVCNotFoundError struct {
 	Err error
@@ -0,0 +1,135 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shell
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// Outputs are the machine readable formats which environment variables can be written in, instead of a shell script
+var Outputs = []string{"json", "yaml", "dotenv", "github-actions", "direnv"}
+
+// githubDelimiter delimits multi-line values in the github-actions format
+const githubDelimiter = "_MINIKUBE_EOF_"
+
+// ValidOutput returns whether an output format is supported
+func ValidOutput(format string) bool {
+	for _, o := range Outputs {
+		if o == format {
+			return true
+		}
+	}
+	return false
+}
+
+// SetOutput writes environment variables to set in a machine readable format
+func SetOutput(w io.Writer, format string, vars map[string]string) error {
+	return writeOutput(w, format, vars, nil)
+}
+
+// UnsetOutput writes environment variables to unset in a machine readable format.
+// Variables which had a value before minikube set them are restored to it.
+func UnsetOutput(w io.Writer, format string, vars []string) error {
+	restore, unset := unsetVars(vars)
+	return writeOutput(w, format, restore, unset)
+}
+
+// writeOutput writes environment variables to set and to unset in a machine readable format
+func writeOutput(w io.Writer, format string, set map[string]string, unset []string) error {
+	switch format {
+	case "json", "yaml":
+		vars := map[string]interface{}{}
+		for k, v := range set {
+			vars[k] = v
+		}
+		// unset variables are null
+		for _, k := range unset {
+			vars[k] = nil
+		}
+		var data []byte
+		var err error
+		if format == "json" {
+			data, err = json.MarshalIndent(vars, "", "  ")
+			data = append(data, '\n')
+		} else {
+			data, err = yaml.Marshal(vars)
+		}
+		if err != nil {
+			return errors.Wrapf(err, "marshal %s", format)
+		}
+		_, err = w.Write(data)
+		return err
+	case "dotenv", "github-actions", "direnv":
+		var b strings.Builder
+		for _, k := range sortedKeys(set) {
+			b.WriteString(setLine(format, k, set[k]))
+		}
+		for _, k := range unset {
+			b.WriteString(unsetLine(format, k))
+		}
+		_, err := io.WriteString(w, b.String())
+		return err
+	default:
+		return fmt.Errorf("unsupported output %q, expected one of: %s", format, strings.Join(Outputs, ", "))
+	}
+}
+
+// setLine returns the line setting a variable in a line based format
+func setLine(format, k, v string) string {
+	switch format {
+	case "github-actions":
+		if strings.Contains(v, "\n") {
+			return fmt.Sprintf("%s<<%s\n%s\n%s\n", k, githubDelimiter, v, githubDelimiter)
+		}
+		return fmt.Sprintf("%s=%s\n", k, v)
+	case "direnv":
+		return fmt.Sprintf("export %s=%s\n", k, singleQuote(v))
+	default:
+		return fmt.Sprintf("%s=%s\n", k, strconv.Quote(v))
+	}
+}
+
+// unsetLine returns the line unsetting a variable in a line based format, which is an empty value unless the format can unset variables
+func unsetLine(format, k string) string {
+	if format == "direnv" {
+		return fmt.Sprintf("unset %s\n", k)
+	}
+	return fmt.Sprintf("%s=\n", k)
+}
+
+// singleQuote quotes a value for a POSIX shell
+func singleQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
+
+// sortedKeys returns the keys of a map in order
+func sortedKeys(m map[string]string) []string {
+	keys := []string{}
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
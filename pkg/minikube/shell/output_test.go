@@ -0,0 +1,120 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package shell
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/constants"
+)
+
+func TestSetOutput(t *testing.T) {
+	vars := map[string]string{
+		"DOCKER_HOST":        "tcp://192.168.49.2:2376",
+		"DOCKER_CERT_PATH":   "/home/it's me/.minikube/certs",
+		"MINIKUBE_MULTILINE": "a\nb",
+	}
+	var testCases = []struct {
+		format   string
+		expected string
+	}{
+		{"json", `{
+  "DOCKER_CERT_PATH": "/home/it's me/.minikube/certs",
+  "DOCKER_HOST": "tcp://192.168.49.2:2376",
+  "MINIKUBE_MULTILINE": "a\nb"
+}
+`},
+		{"yaml", `DOCKER_CERT_PATH: /home/it's me/.minikube/certs
+DOCKER_HOST: tcp://192.168.49.2:2376
+MINIKUBE_MULTILINE: |-
+  a
+  b
+`},
+		{"dotenv", `DOCKER_CERT_PATH="/home/it's me/.minikube/certs"
+DOCKER_HOST="tcp://192.168.49.2:2376"
+MINIKUBE_MULTILINE="a\nb"
+`},
+		{"github-actions", `DOCKER_CERT_PATH=/home/it's me/.minikube/certs
+DOCKER_HOST=tcp://192.168.49.2:2376
+MINIKUBE_MULTILINE<<_MINIKUBE_EOF_
+a
+b
+_MINIKUBE_EOF_
+`},
+		{"direnv", `export DOCKER_CERT_PATH='/home/it'\''s me/.minikube/certs'
+export DOCKER_HOST='tcp://192.168.49.2:2376'
+export MINIKUBE_MULTILINE='a
+b'
+`},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.format, func(t *testing.T) {
+			var b bytes.Buffer
+			if err := SetOutput(&b, tc.format, vars); err != nil {
+				t.Fatalf("SetOutput(%s): %v", tc.format, err)
+			}
+			if b.String() != tc.expected {
+				t.Errorf("SetOutput(%s) = %q, want %q", tc.format, b.String(), tc.expected)
+			}
+		})
+	}
+
+	if err := SetOutput(&bytes.Buffer{}, "xml", vars); err == nil {
+		t.Errorf("SetOutput(xml) returned no error")
+	}
+}
+
+func TestUnsetOutput(t *testing.T) {
+	exEnv := constants.MinikubeExistingPrefix + "DOCKER_HOST"
+	defer os.Setenv(exEnv, os.Getenv(exEnv))
+	os.Setenv(exEnv, "unix:///var/run/docker.sock")
+
+	var testCases = []struct {
+		format   string
+		expected string
+	}{
+		{"json", `{
+  "DOCKER_HOST": "unix:///var/run/docker.sock",
+  "MINIKUBE_ACTIVE_DOCKERD": null,
+  "MINIKUBE_EXISTING_DOCKER_HOST": null
+}
+`},
+		{"dotenv", `DOCKER_HOST="unix:///var/run/docker.sock"
+MINIKUBE_ACTIVE_DOCKERD=
+MINIKUBE_EXISTING_DOCKER_HOST=
+`},
+		{"direnv", `export DOCKER_HOST='unix:///var/run/docker.sock'
+unset MINIKUBE_ACTIVE_DOCKERD
+unset MINIKUBE_EXISTING_DOCKER_HOST
+`},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.format, func(t *testing.T) {
+			var b bytes.Buffer
+			if err := UnsetOutput(&b, tc.format, []string{"DOCKER_HOST", "MINIKUBE_ACTIVE_DOCKERD"}); err != nil {
+				t.Fatalf("UnsetOutput(%s): %v", tc.format, err)
+			}
+			if b.String() != tc.expected {
+				t.Errorf("UnsetOutput(%s) = %q, want %q", tc.format, b.String(), tc.expected)
+			}
+		})
+	}
+}
@@ -122,6 +122,36 @@ REM @FOR /f "tokens=*" %%i IN ('%s') DO @%%i
 			return fmt.Sprintf(`
 # %s
 # eval $(%s)
+`, s...)
+		},
+	},
+	"nushell": nushell,
+	"nu":      nushell,
+	"xonsh": {
+		prefix:         "$",
+		suffix:         "\"\n",
+		delimiter:      " = \"",
+		unsetPrefix:    "del $",
+		unsetSuffix:    "\n",
+		unsetDelimiter: "",
+		usageHint: func(s ...interface{}) string {
+			return fmt.Sprintf(`
+# %s
+# execx($(%s --shell xonsh))
+`, s...)
+		},
+	},
+	"elvish": {
+		prefix:         "set-env ",
+		suffix:         "\"\n",
+		delimiter:      " \"",
+		unsetPrefix:    "unset-env ",
+		unsetSuffix:    "\n",
+		unsetDelimiter: "",
+		usageHint: func(s ...interface{}) string {
+			return fmt.Sprintf(`
+# %s
+# eval (%s --shell elvish | slurp)
 `, s...)
 		},
 	},
@@ -138,6 +168,22 @@ REM @FOR /f "tokens=*" %%i IN ('%s') DO @%%i
 	},
 }
 
+// nushell cannot evaluate a script generated at runtime, so its usage hint loads the variables from --output json instead
+var nushell = shellData{
+	prefix:         "$env.",
+	suffix:         "\"\n",
+	delimiter:      " = \"",
+	unsetPrefix:    "hide-env ",
+	unsetSuffix:    "\n",
+	unsetDelimiter: "",
+	usageHint: func(s ...interface{}) string {
+		return fmt.Sprintf(`
+# %s
+# %s --output json | from json | load-env
+`, s...)
+	},
+}
+
 var defaultSh = "bash"
 var defaultShell shellData = shellConfigMap[defaultSh]
 
@@ -215,21 +261,34 @@ func UnsetScript(ec EnvConfig, w io.Writer, vars []string) error {
 		UnsetDelimiter: shellCfg.unsetDelimiter,
 		UnsetSuffix:    shellCfg.unsetSuffix,
 	}
-	var tempUnset []string
+	restore, unset := unsetVars(vars)
 	for _, env := range vars {
-		exEnv := constants.MinikubeExistingPrefix + env
-		if v := os.Getenv(exEnv); v == "" {
-			cfg.Unset = append(cfg.Unset, env)
-		} else {
+		if v, ok := restore[env]; ok {
 			cfg.Set = append(cfg.Set, unsetConfigItem{
 				Env:   env,
 				Value: v,
 			})
-			tempUnset = append(tempUnset, exEnv)
 		}
 	}
-	cfg.Unset = append(cfg.Unset, tempUnset...)
+	cfg.Unset = unset
 
 	tmpl := template.Must(template.New("unsetEnv").Parse(unsetEnvTmpl))
 	return tmpl.Execute(w, &cfg)
 }
+
+// unsetVars returns the variables to restore to the values they had before minikube set them, and the variables to unset
+func unsetVars(vars []string) (map[string]string, []string) {
+	restore := map[string]string{}
+	unset := []string{}
+	var tempUnset []string
+	for _, env := range vars {
+		exEnv := constants.MinikubeExistingPrefix + env
+		if v := os.Getenv(exEnv); v == "" {
+			unset = append(unset, env)
+		} else {
+			restore[env] = v
+			tempUnset = append(tempUnset, exEnv)
+		}
+	}
+	return restore, append(unset, tempUnset...)
+}
@@ -40,6 +40,12 @@ func TestGenerateUsageHint(t *testing.T) {
 ;; (with-temp-buffer (shell-command "bar" (current-buffer)) (eval-buffer))`},
 		{EnvConfig{"fish"}, `# foo
 # bar | source`},
+		{EnvConfig{"nushell"}, `# foo
+# bar --output json | from json | load-env`},
+		{EnvConfig{"xonsh"}, `# foo
+# execx($(bar --shell xonsh))`},
+		{EnvConfig{"elvish"}, `# foo
+# eval (bar --shell elvish | slurp)`},
 		{EnvConfig{"none"}, ``},
 	}
 	for _, tc := range testCases {
@@ -67,6 +73,9 @@ func TestCfgSet(t *testing.T) {
 		{"", "eval", EnvConfig{"emacs"}, `")`},
 		{"", "eval", EnvConfig{"none"}, ``},
 		{"", "eval", EnvConfig{"fish"}, `";`},
+		{"", "eval", EnvConfig{"nu"}, `"`},
+		{"", "eval", EnvConfig{"xonsh"}, `"`},
+		{"", "eval", EnvConfig{"elvish"}, `"`},
 	}
 	for _, tc := range testCases {
 		tc := tc
@@ -99,6 +108,12 @@ SET bar=`},
 set -e bar;`},
 		{[]string{"baz", "bar"}, EnvConfig{"emacs"}, `(setenv "baz" nil)
 (setenv "bar" nil)`},
+		{[]string{"baz", "bar"}, EnvConfig{"nushell"}, `hide-env baz
+hide-env bar`},
+		{[]string{"baz", "bar"}, EnvConfig{"xonsh"}, `del $baz
+del $bar`},
+		{[]string{"baz", "bar"}, EnvConfig{"elvish"}, `unset-env baz
+unset-env bar`},
 		{[]string{"baz", "bar"}, EnvConfig{"none"}, "baz\nbar"},
 	}
 	for _, tc := range testCases {
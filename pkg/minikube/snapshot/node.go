@@ -0,0 +1,379 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package snapshot
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/docker/machine/libmachine"
+	"github.com/docker/machine/libmachine/state"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/bootstrapper/bsutil"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/cruntime"
+	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/machine"
+	"k8s.io/minikube/pkg/minikube/sysinit"
+	"k8s.io/minikube/pkg/minikube/vmpath"
+	"k8s.io/minikube/pkg/util/retry"
+)
+
+// guestArchive is where the archive of a node is staged within the guest
+var guestArchive = path.Join(vmpath.GuestEphemeralDir, "snapshot.tar.gz")
+
+var (
+	// etcdDataDir holds the etcd data of control planes
+	etcdDataDir = path.Join(vmpath.GuestPersistentDir, "etcd")
+	// serviceAccountKeys signed the service account tokens stored within etcd, so must be kept alongside it
+	serviceAccountKeys = []string{path.Join(vmpath.GuestKubernetesCertsDir, "sa.key"), path.Join(vmpath.GuestKubernetesCertsDir, "sa.pub")}
+	// hostpathDirs are where the storage provisioner and hostpath persistent volumes keep their data
+	hostpathDirs = []string{"/tmp/hostpath-provisioner", "/tmp/hostpath_pv"}
+)
+
+// runtimeStore describes where a container runtime keeps its images, and which services must be stopped to change them
+type runtimeStore struct {
+	dir      string
+	services []string
+}
+
+// runtimeStores are the image stores of each container runtime
+var runtimeStores = map[string]runtimeStore{
+	"docker":     {dir: "/var/lib/docker", services: []string{"docker.socket", "docker"}},
+	"containerd": {dir: "/var/lib/containerd", services: []string{"containerd"}},
+	"crio":       {dir: "/var/lib/containers", services: []string{"crio"}},
+	"cri-o":      {dir: "/var/lib/containers", services: []string{"crio"}},
+}
+
+// dataDirs returns the directories which a snapshot replaces wholesale for the given runtime
+func dataDirs(rs runtimeStore) []string {
+	return append([]string{etcdDataDir, rs.dir}, hostpathDirs...)
+}
+
+// Save saves a snapshot of every node of a running cluster. Workloads are restarted while the snapshot is taken.
+func Save(api libmachine.API, cc config.ClusterConfig, name string) (*Snapshot, error) {
+	if err := checkName(name); err != nil {
+		return nil, err
+	}
+	if driver.BareMetal(cc.Driver) {
+		return nil, fmt.Errorf("the %s driver does not support snapshots", cc.Driver)
+	}
+	rs, ok := runtimeStores[cc.KubernetesConfig.ContainerRuntime]
+	if !ok {
+		return nil, fmt.Errorf("snapshots are not supported for the %q container runtime", cc.KubernetesConfig.ContainerRuntime)
+	}
+
+	dst := Dir(cc.Name, name)
+	if _, err := os.Stat(dst); err == nil {
+		return nil, fmt.Errorf("snapshot %q of %q already exists", name, cc.Name)
+	}
+
+	// snapshots are saved into a temporary directory, so that a failed save does not leave a partial snapshot behind
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return nil, errors.Wrap(err, "mkdir")
+	}
+	tmp, err := ioutil.TempDir(filepath.Dir(dst), "."+name)
+	if err != nil {
+		return nil, errors.Wrap(err, "tempdir")
+	}
+	defer os.RemoveAll(tmp)
+
+	runners, err := nodeRunners(api, cc)
+	if err != nil {
+		return nil, err
+	}
+	archives, err := saveNodes(cc, runners, rs, tmp)
+	if err != nil {
+		return nil, err
+	}
+	s := &Snapshot{
+		Name:     name,
+		Created:  time.Now(),
+		Config:   cc,
+		Archives: archives,
+	}
+	if err := s.save(tmp); err != nil {
+		return nil, errors.Wrap(err, "save metadata")
+	}
+	if err := os.Rename(tmp, dst); err != nil {
+		return nil, errors.Wrap(err, "rename")
+	}
+	return s, nil
+}
+
+// Restore restores a snapshot into the running cluster it was saved from.
+// Certificates and static pod manifests are kept, so that they match the current IPs of the nodes.
+func Restore(api libmachine.API, cc *config.ClusterConfig, name string) error {
+	s, err := Load(cc.Name, name)
+	if err != nil {
+		return err
+	}
+	if err := compatible(s.Config, *cc); err != nil {
+		return errors.Wrapf(err, "snapshot %q cannot be restored into %q", name, cc.Name)
+	}
+	rs := runtimeStores[cc.KubernetesConfig.ContainerRuntime]
+
+	runners, err := nodeRunners(api, *cc)
+	if err != nil {
+		return err
+	}
+	if err := restoreNodes(*cc, runners, rs, Dir(cc.Name, name), s.Archives); err != nil {
+		return err
+	}
+
+	// the etcd members of the snapshot still advertise the IPs the nodes had when it was saved
+	cp, err := config.PrimaryControlPlane(cc)
+	if err != nil {
+		return errors.Wrap(err, "primary control plane")
+	}
+	for _, n := range config.ControlPlanes(*cc) {
+		old, _ := nodeByName(s.Config, n.Name)
+		if old.IP == n.IP {
+			continue
+		}
+		klog.Infof("IP of %s changed from %s to %s since the snapshot was saved", n.Name, old.IP, n.IP)
+		n := n
+		update := func() error {
+			return bsutil.UpdateEtcdMemberPeerURL(runners[cp.Name], *cc, cp, n)
+		}
+		if err := retry.Expo(update, 2*time.Second, 2*time.Minute); err != nil {
+			return errors.Wrapf(err, "updating etcd member of %s", n.Name)
+		}
+	}
+
+	cc.Addons = s.Config.Addons
+	return config.SaveProfile(cc.Name, cc)
+}
+
+// compatible returns an error if a snapshot saved from old cannot be restored into cc
+func compatible(old config.ClusterConfig, cc config.ClusterConfig) error {
+	if old.Driver != cc.Driver {
+		return fmt.Errorf("it was saved with the %s driver, not %s", old.Driver, cc.Driver)
+	}
+	if old.KubernetesConfig.ContainerRuntime != cc.KubernetesConfig.ContainerRuntime {
+		return fmt.Errorf("it was saved with the %s container runtime, not %s", old.KubernetesConfig.ContainerRuntime, cc.KubernetesConfig.ContainerRuntime)
+	}
+	if old.KubernetesConfig.KubernetesVersion != cc.KubernetesConfig.KubernetesVersion {
+		return fmt.Errorf("it was saved with Kubernetes %s, not %s", old.KubernetesConfig.KubernetesVersion, cc.KubernetesConfig.KubernetesVersion)
+	}
+	if len(old.Nodes) != len(cc.Nodes) {
+		return fmt.Errorf("it was saved with %d nodes, not %d", len(old.Nodes), len(cc.Nodes))
+	}
+	for _, n := range cc.Nodes {
+		o, ok := nodeByName(old, n.Name)
+		if !ok {
+			return fmt.Errorf("it has no node named %s", n.Name)
+		}
+		if o.ControlPlane != n.ControlPlane {
+			return fmt.Errorf("node %s has a different role", n.Name)
+		}
+	}
+	return nil
+}
+
+// nodeByName finds the named node of a cluster
+func nodeByName(cc config.ClusterConfig, name string) (config.Node, bool) {
+	for _, n := range cc.Nodes {
+		if n.Name == name {
+			return n, true
+		}
+	}
+	return config.Node{}, false
+}
+
+// nodeRunners returns a command runner for every node of a cluster, by name
+func nodeRunners(api libmachine.API, cc config.ClusterConfig) (map[string]command.Runner, error) {
+	runners := map[string]command.Runner{}
+	for _, n := range cc.Nodes {
+		r, err := runner(api, cc, n)
+		if err != nil {
+			return nil, err
+		}
+		runners[n.Name] = r
+	}
+	return runners, nil
+}
+
+// runner returns a command runner for a node, which must be running
+func runner(api libmachine.API, cc config.ClusterConfig, n config.Node) (command.Runner, error) {
+	m := driver.MachineName(cc, n)
+	st, err := machine.Status(api, m)
+	if err != nil {
+		return nil, errors.Wrapf(err, "status %s", m)
+	}
+	if st != state.Running.String() {
+		return nil, fmt.Errorf("node %s is %s, it must be running", n.Name, st)
+	}
+	h, err := machine.LoadHost(api, m)
+	if err != nil {
+		return nil, errors.Wrapf(err, "load host %s", m)
+	}
+	return machine.CommandRunner(h)
+}
+
+// saveNodes archives the state of every node into dir, and returns the names of the archives by node. Every node
+// is stopped before any is archived, so that the etcd members of the control planes are saved at the same revision.
+func saveNodes(cc config.ClusterConfig, runners map[string]command.Runner, rs runtimeStore, dir string) (map[string]string, error) {
+	defer startNodes(cc, runners, rs)
+	for _, n := range cc.Nodes {
+		if err := stopNode(cc, runners[n.Name], rs); err != nil {
+			return nil, errors.Wrapf(err, "stopping node %s", n.Name)
+		}
+	}
+
+	archives := map[string]string{}
+	for _, n := range cc.Nodes {
+		archive := driver.MachineName(cc, n) + ".tar.gz"
+		if err := archiveNode(runners[n.Name], rs, filepath.Join(dir, archive)); err != nil {
+			return nil, errors.Wrapf(err, "saving node %s", n.Name)
+		}
+		archives[n.Name] = archive
+	}
+	return archives, nil
+}
+
+// archiveNode archives the state of a stopped node to dst on the host
+func archiveNode(r command.Runner, rs runtimeStore, dst string) error {
+	paths := append(dataDirs(rs), serviceAccountKeys...)
+	for i, p := range paths {
+		paths[i] = strings.TrimPrefix(p, "/")
+	}
+	// worker nodes lack etcd and the service account keys, which tar is told to skip
+	tar := fmt.Sprintf("sudo mkdir -p %s && sudo tar --ignore-failed-read -czf %s -C / %s", vmpath.GuestEphemeralDir, guestArchive, strings.Join(paths, " "))
+	if _, err := r.RunCmd(exec.Command("/bin/bash", "-c", tar)); err != nil {
+		return errors.Wrap(err, "tar")
+	}
+	defer func() {
+		if _, err := r.RunCmd(exec.Command("sudo", "rm", "-f", guestArchive)); err != nil {
+			klog.Warningf("unable to remove %s: %v", guestArchive, err)
+		}
+	}()
+
+	if _, err := r.RunCmd(exec.Command("sudo", "chmod", "0644", guestArchive)); err != nil {
+		return errors.Wrap(err, "chmod")
+	}
+	return r.CopyFrom(guestArchive, dst)
+}
+
+// restoreNodes replaces the state of every node with its archive within dir. Every node is stopped before any is
+// restored, so that the etcd members of the control planes do not replicate the state the snapshot replaces.
+func restoreNodes(cc config.ClusterConfig, runners map[string]command.Runner, rs runtimeStore, dir string, archives map[string]string) error {
+	for _, n := range cc.Nodes {
+		if err := stageArchive(runners[n.Name], filepath.Join(dir, archives[n.Name])); err != nil {
+			return errors.Wrapf(err, "copying archive of node %s", n.Name)
+		}
+	}
+	defer func() {
+		for _, n := range cc.Nodes {
+			if _, err := runners[n.Name].RunCmd(exec.Command("sudo", "rm", "-f", guestArchive)); err != nil {
+				klog.Warningf("unable to remove %s: %v", guestArchive, err)
+			}
+		}
+	}()
+
+	defer startNodes(cc, runners, rs)
+	for _, n := range cc.Nodes {
+		if err := stopNode(cc, runners[n.Name], rs); err != nil {
+			return errors.Wrapf(err, "stopping node %s", n.Name)
+		}
+	}
+	for _, n := range cc.Nodes {
+		if err := extractArchive(runners[n.Name], rs); err != nil {
+			return errors.Wrapf(err, "restoring node %s", n.Name)
+		}
+	}
+	return nil
+}
+
+// stageArchive copies the archive at src on the host into the node
+func stageArchive(r command.Runner, src string) error {
+	f, err := assets.NewFileAsset(src, path.Dir(guestArchive), path.Base(guestArchive), "0644")
+	if err != nil {
+		return errors.Wrap(err, "archive")
+	}
+	return r.Copy(f)
+}
+
+// extractArchive replaces the state of a stopped node with the archive staged by stageArchive
+func extractArchive(r command.Runner, rs runtimeStore) error {
+	// the directories may be mount points, so only their contents are removed
+	clear := fmt.Sprintf("for d in %s; do [ -d $d ] && sudo find $d -mindepth 1 -delete; done; true", strings.Join(dataDirs(rs), " "))
+	if _, err := r.RunCmd(exec.Command("/bin/bash", "-c", clear)); err != nil {
+		return errors.Wrap(err, "clear")
+	}
+	if _, err := r.RunCmd(exec.Command("sudo", "tar", "-xzf", guestArchive, "-C", "/")); err != nil {
+		return errors.Wrap(err, "extract")
+	}
+	return nil
+}
+
+// stopNode stops the kubelet, every Kubernetes container and the container runtime, so that their state stops changing
+func stopNode(cc config.ClusterConfig, r command.Runner, rs runtimeStore) error {
+	sm := sysinit.New(r)
+	if err := sm.Stop("kubelet"); err != nil {
+		return errors.Wrap(err, "stop kubelet")
+	}
+
+	cr, err := cruntime.New(cruntime.Config{Type: cc.KubernetesConfig.ContainerRuntime, Socket: cc.KubernetesConfig.CRISocket, Runner: r})
+	if err != nil {
+		return errors.Wrap(err, "runtime")
+	}
+	ids, err := cr.ListContainers(cruntime.ListOptions{State: cruntime.Running})
+	if err != nil {
+		return errors.Wrap(err, "list containers")
+	}
+	if err := cr.StopContainers(ids); err != nil {
+		return errors.Wrap(err, "stop containers")
+	}
+
+	for _, svc := range rs.services {
+		if err := sm.Stop(svc); err != nil {
+			return errors.Wrapf(err, "stop %s", svc)
+		}
+	}
+	return nil
+}
+
+// startNodes starts every node of a cluster stopped by stopNode, logging the nodes which fail to start
+func startNodes(cc config.ClusterConfig, runners map[string]command.Runner, rs runtimeStore) {
+	for _, n := range cc.Nodes {
+		if err := startNode(runners[n.Name], rs); err != nil {
+			klog.Errorf("unable to start node %s after snapshot: %v", n.Name, err)
+		}
+	}
+}
+
+// startNode starts the container runtime and kubelet stopped by stopNode
+func startNode(r command.Runner, rs runtimeStore) error {
+	sm := sysinit.New(r)
+	svcs := append([]string{}, rs.services...)
+	for _, svc := range append(svcs, "kubelet") {
+		if err := sm.Start(svc); err != nil {
+			return errors.Wrapf(err, "start %s", svc)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package snapshot
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"k8s.io/minikube/pkg/minikube/assets"
+	"k8s.io/minikube/pkg/minikube/command"
+	"k8s.io/minikube/pkg/minikube/config"
+)
+
+// recordingRunner runs every command successfully, and records it in a log shared by the nodes of a cluster
+type recordingRunner struct {
+	node string
+	log  *[]string
+}
+
+func (r *recordingRunner) record(s string) {
+	*r.log = append(*r.log, r.node+": "+s)
+}
+
+func (r *recordingRunner) RunCmd(cmd *exec.Cmd) (*command.RunResult, error) {
+	rr := &command.RunResult{Args: cmd.Args}
+	r.record(rr.Command())
+	return rr, nil
+}
+
+func (r *recordingRunner) Copy(f assets.CopyableFile) error {
+	r.record("copy " + f.GetSourcePath())
+	return nil
+}
+
+func (r *recordingRunner) CopyFrom(src string, dst string) error {
+	r.record("copy from " + src)
+	return ioutil.WriteFile(dst, []byte("archive"), 0644)
+}
+
+func (r *recordingRunner) Remove(f assets.CopyableFile) error {
+	r.record("remove " + f.GetTargetName())
+	return nil
+}
+
+// firstAndLast returns the indexes of the first and last entries of log which contain s
+func firstAndLast(log []string, s string) (int, int) {
+	first, last := -1, -1
+	for i, l := range log {
+		if strings.Contains(l, s) {
+			if first == -1 {
+				first = i
+			}
+			last = i
+		}
+	}
+	return first, last
+}
+
+func TestNodesStoppedTogether(t *testing.T) {
+	cc := config.ClusterConfig{
+		Name:             "ha",
+		KubernetesConfig: config.KubernetesConfig{ContainerRuntime: "docker"},
+		Nodes: []config.Node{
+			{Name: "", ControlPlane: true},
+			{Name: "m02", ControlPlane: true},
+			{Name: "m03", ControlPlane: true},
+		},
+	}
+	rs := runtimeStores["docker"]
+
+	tests := []struct {
+		name string
+		run  func(runners map[string]command.Runner, dir string) error
+		// work is done on each node once every node is stopped
+		work string
+	}{
+		{
+			name: "save",
+			run: func(runners map[string]command.Runner, dir string) error {
+				_, err := saveNodes(cc, runners, rs, dir)
+				return err
+			},
+			work: "tar --ignore-failed-read",
+		},
+		{
+			name: "restore",
+			run: func(runners map[string]command.Runner, dir string) error {
+				archives := map[string]string{}
+				for _, n := range cc.Nodes {
+					archives[n.Name] = n.Name + ".tar.gz"
+					if err := ioutil.WriteFile(filepath.Join(dir, archives[n.Name]), []byte("archive"), 0644); err != nil {
+						t.Fatalf("write: %v", err)
+					}
+				}
+				return restoreNodes(cc, runners, rs, dir, archives)
+			},
+			work: "tar -xzf",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "snapshot")
+			if err != nil {
+				t.Fatalf("tempdir: %v", err)
+			}
+			defer os.RemoveAll(dir)
+
+			var log []string
+			runners := map[string]command.Runner{}
+			for _, n := range cc.Nodes {
+				runners[n.Name] = &recordingRunner{node: "node" + n.Name, log: &log}
+			}
+			if err := tc.run(runners, dir); err != nil {
+				t.Fatalf("%s: %v", tc.name, err)
+			}
+
+			_, lastStop := firstAndLast(log, "systemctl stop kubelet")
+			firstWork, lastWork := firstAndLast(log, tc.work)
+			firstStart, _ := firstAndLast(log, "systemctl start kubelet")
+			if lastStop == -1 || firstWork == -1 || firstStart == -1 {
+				t.Fatalf("missing commands:\n%s", strings.Join(log, "\n"))
+			}
+			if lastStop > firstWork || lastWork > firstStart {
+				t.Errorf("a node was not stopped before the others were worked on, or started before they were done:\n%s", strings.Join(log, "\n"))
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package snapshot saves the state of every node of a cluster to the host, and restores it later
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/localpath"
+)
+
+// metadataFile is the file within a snapshot directory which describes the snapshot
+const metadataFile = "snapshot.json"
+
+// validName restricts snapshot names to something which is safe to use as a directory name
+var validName = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]*$`)
+
+// Snapshot describes a saved snapshot of a cluster
+type Snapshot struct {
+	Name    string
+	Created time.Time
+	// Config is the cluster config at the time the snapshot was saved
+	Config config.ClusterConfig
+	// Archives maps node names to the archive of their state, relative to the snapshot directory
+	Archives map[string]string
+}
+
+// checkName returns an error unless name is a valid snapshot name, which cannot escape the snapshot directory
+func checkName(name string) error {
+	if !validName.MatchString(name) {
+		return fmt.Errorf("invalid snapshot name %q: may only contain letters, digits, '.', '_' and '-'", name)
+	}
+	return nil
+}
+
+// Dir returns the directory which holds the named snapshot of a profile
+func Dir(profile string, name string) string {
+	return localpath.MakeMiniPath("snapshots", profile, name)
+}
+
+// Load loads the named snapshot of a profile
+func Load(profile string, name string) (*Snapshot, error) {
+	if err := checkName(name); err != nil {
+		return nil, err
+	}
+	b, err := ioutil.ReadFile(filepath.Join(Dir(profile, name), metadataFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("snapshot %q of %q does not exist", name, profile)
+		}
+		return nil, errors.Wrap(err, "read")
+	}
+
+	s := &Snapshot{}
+	if err := json.Unmarshal(b, s); err != nil {
+		return nil, errors.Wrapf(err, "unmarshal %s", metadataFile)
+	}
+	return s, nil
+}
+
+// List returns every snapshot of a profile, oldest first
+func List(profile string) ([]*Snapshot, error) {
+	entries, err := ioutil.ReadDir(localpath.MakeMiniPath("snapshots", profile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "read dir")
+	}
+
+	snaps := []*Snapshot{}
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		s, err := Load(profile, e.Name())
+		if err != nil {
+			// incomplete snapshots are left behind by failed saves, and are not worth failing over
+			continue
+		}
+		snaps = append(snaps, s)
+	}
+	sort.Slice(snaps, func(i, j int) bool { return snaps[i].Created.Before(snaps[j].Created) })
+	return snaps, nil
+}
+
+// Delete deletes the named snapshot of a profile
+func Delete(profile string, name string) error {
+	if err := checkName(name); err != nil {
+		return err
+	}
+	if _, err := Load(profile, name); err != nil {
+		return err
+	}
+	return os.RemoveAll(Dir(profile, name))
+}
+
+// Size returns the total size of the archives of a snapshot
+func Size(profile string, s *Snapshot) int64 {
+	var size int64
+	for _, a := range s.Archives {
+		if fi, err := os.Stat(filepath.Join(Dir(profile, s.Name), a)); err == nil {
+			size += fi.Size()
+		}
+	}
+	return size
+}
+
+// save writes the snapshot metadata within dir
+func (s *Snapshot) save(dir string) error {
+	b, err := json.MarshalIndent(s, "", "    ")
+	if err != nil {
+		return errors.Wrap(err, "marshal")
+	}
+	return ioutil.WriteFile(filepath.Join(dir, metadataFile), b, 0644)
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package snapshot
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"k8s.io/minikube/pkg/minikube/config"
+	"k8s.io/minikube/pkg/minikube/localpath"
+)
+
+func setMinikubeHome(t *testing.T) {
+	t.Helper()
+	td, err := ioutil.TempDir("", "snapshot")
+	if err != nil {
+		t.Fatalf("tempdir: %v", err)
+	}
+	old := os.Getenv(localpath.MinikubeHome)
+	os.Setenv(localpath.MinikubeHome, td)
+	t.Cleanup(func() {
+		os.Setenv(localpath.MinikubeHome, old)
+		os.RemoveAll(td)
+	})
+}
+
+func writeSnapshot(t *testing.T, profile string, s *Snapshot) {
+	t.Helper()
+	dir := Dir(profile, s.Name)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := s.save(dir); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	for _, a := range s.Archives {
+		if err := ioutil.WriteFile(filepath.Join(dir, a), []byte("archive"), 0644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+}
+
+func TestListLoadDelete(t *testing.T) {
+	setMinikubeHome(t)
+	now := time.Now()
+	writeSnapshot(t, "p1", &Snapshot{Name: "newer", Created: now, Archives: map[string]string{"m01": "p1.tar.gz"}})
+	writeSnapshot(t, "p1", &Snapshot{Name: "older", Created: now.Add(-time.Hour), Archives: map[string]string{"m01": "p1.tar.gz", "m02": "p1-m02.tar.gz"}})
+	writeSnapshot(t, "p2", &Snapshot{Name: "other", Created: now})
+	// left behind by a failed save
+	if err := os.MkdirAll(Dir("p1", ".partial123"), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+
+	snaps, err := List("p1")
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(snaps) != 2 || snaps[0].Name != "older" || snaps[1].Name != "newer" {
+		t.Fatalf("List() = %+v, want [older newer]", snaps)
+	}
+	if got := Size("p1", snaps[0]); got != 2*int64(len("archive")) {
+		t.Errorf("Size() = %d, want %d", got, 2*len("archive"))
+	}
+
+	if err := Delete("p1", "older"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := Load("p1", "older"); err == nil {
+		t.Errorf("Load succeeded after Delete")
+	}
+	if err := Delete("p1", "missing"); err == nil {
+		t.Errorf("Delete succeeded for a missing snapshot")
+	}
+
+	snaps, err = List("missing-profile")
+	if err != nil || len(snaps) != 0 {
+		t.Errorf("List() of a profile without snapshots = %v, %v, want none", snaps, err)
+	}
+}
+
+func TestCompatible(t *testing.T) {
+	base := config.ClusterConfig{
+		Driver:           "docker",
+		KubernetesConfig: config.KubernetesConfig{KubernetesVersion: "v1.20.0", ContainerRuntime: "docker"},
+		Nodes: []config.Node{
+			{Name: "", IP: "192.168.49.2", ControlPlane: true},
+			{Name: "m02", IP: "192.168.49.3"},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(cc *config.ClusterConfig)
+		wantErr bool
+	}{
+		{"same", func(cc *config.ClusterConfig) {}, false},
+		{"changed IPs", func(cc *config.ClusterConfig) { cc.Nodes[0].IP = "192.168.49.5" }, false},
+		{"driver", func(cc *config.ClusterConfig) { cc.Driver = "kvm2" }, true},
+		{"runtime", func(cc *config.ClusterConfig) { cc.KubernetesConfig.ContainerRuntime = "containerd" }, true},
+		{"version", func(cc *config.ClusterConfig) { cc.KubernetesConfig.KubernetesVersion = "v1.19.0" }, true},
+		{"node count", func(cc *config.ClusterConfig) { cc.Nodes = cc.Nodes[:1] }, true},
+		{"node name", func(cc *config.ClusterConfig) { cc.Nodes[1].Name = "m03" }, true},
+		{"node role", func(cc *config.ClusterConfig) { cc.Nodes[1].ControlPlane = true }, true},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cc := base
+			cc.Nodes = append([]config.Node{}, base.Nodes...)
+			tc.modify(&cc)
+			if err := compatible(base, cc); (err != nil) != tc.wantErr {
+				t.Errorf("compatible() error = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestEscapingNames(t *testing.T) {
+	setMinikubeHome(t)
+	writeSnapshot(t, "p1", &Snapshot{Name: "mine", Created: time.Now()})
+	writeSnapshot(t, "p2", &Snapshot{Name: "victim", Created: time.Now()})
+
+	for _, name := range []string{"../p2/victim", "..", "mine/../../p2/victim", "/tmp"} {
+		if _, err := Load("p1", name); err == nil {
+			t.Errorf("Load(%q) succeeded", name)
+		}
+		if err := Delete("p1", name); err == nil {
+			t.Errorf("Delete(%q) succeeded", name)
+		}
+		if _, err := Save(nil, config.ClusterConfig{Name: "p1"}, name); err == nil {
+			t.Errorf("Save(%q) succeeded", name)
+		}
+	}
+	if _, err := Load("p2", "victim"); err != nil {
+		t.Errorf("the snapshot of another profile is gone: %v", err)
+	}
+}
@@ -19,6 +19,7 @@ package tunnel
 import (
 	"fmt"
 	"net"
+	"strings"
 
 	"github.com/docker/machine/libmachine"
 	"github.com/docker/machine/libmachine/host"
@@ -86,7 +87,9 @@ func getRoute(host *host.Host, clusterConfig config.ClusterConfig) (*Route, erro
 		return nil, errors.Wrapf(err, "error getting host IP for %s", host.Name)
 	}
 
-	_, ipNet, err := net.ParseCIDR(clusterConfig.KubernetesConfig.ServiceCIDR)
+	// dual-stack clusters are routed through the CIDR of their primary IP family
+	serviceCIDR := strings.Split(clusterConfig.KubernetesConfig.ServiceCIDR, ",")[0]
+	_, ipNet, err := net.ParseCIDR(serviceCIDR)
 	if err != nil {
 		return nil, fmt.Errorf("error parsing service CIDR: %s", err)
 	}
@@ -94,6 +97,16 @@ func getRoute(host *host.Host, clusterConfig config.ClusterConfig) (*Route, erro
 	if ip == nil {
 		return nil, fmt.Errorf("invalid IP for host %s", hostDriverIP)
 	}
+	// the drivers only report the IPv4 address of the nodes of IPv6 clusters
+	if ipNet.IP.To4() == nil && ip.To4() != nil {
+		cp, err := config.PrimaryControlPlane(&clusterConfig)
+		if err != nil {
+			return nil, errors.Wrap(err, "getting control plane")
+		}
+		if ip = net.ParseIP(cp.IP); ip == nil || ip.To4() != nil {
+			return nil, fmt.Errorf("no IPv6 address for host %s", host.Name)
+		}
+	}
 	dnsIP, err := util.GetDNSIP(ipNet.String())
 	if err != nil {
 		return nil, err
@@ -152,3 +152,39 @@ func TestRouteIPDetection(t *testing.T) {
 	}
 
 }
+
+func TestRouteIPv6Detection(t *testing.T) {
+	tcs := []struct {
+		description string
+		serviceCIDR string
+		expectedDst string
+		expectedGw  string
+		expectedDNS string
+	}{
+		{"dual-stack", "10.96.0.0/12,fd00:10:96::/112", "10.96.0.0/12", "192.168.49.2", "10.96.0.10"},
+		{"ipv6", "fd00:10:96::/112", "fd00:10:96::/112", "fd00:192:168:49::2", "fd00:10:96::a"},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.description, func(t *testing.T) {
+			cfg := config.ClusterConfig{
+				KubernetesConfig: config.KubernetesConfig{ServiceCIDR: tc.serviceCIDR},
+				Nodes:            []config.Node{{IP: "fd00:192:168:49::2", ControlPlane: true}},
+			}
+			h := &host.Host{Driver: &tests.MockDriver{IP: "192.168.49.2"}}
+
+			r, err := getRoute(h, cfg)
+			if err != nil {
+				t.Fatalf("getRoute: %v", err)
+			}
+			if r.DestCIDR.String() != tc.expectedDst {
+				t.Errorf("expected destination %s, got %s", tc.expectedDst, r.DestCIDR)
+			}
+			if r.Gateway.String() != tc.expectedGw {
+				t.Errorf("expected gateway %s, got %s", tc.expectedGw, r.Gateway)
+			}
+			if r.ClusterDNSIP.String() != tc.expectedDNS {
+				t.Errorf("expected cluster DNS %s, got %s", tc.expectedDNS, r.ClusterDNSIP)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+// +build !windows
+
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"io"
+	"os"
+
+	"github.com/VividCortex/godaemon"
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+)
+
+// Daemonize runs the tunnel in the background, and writes its output to logFile. As the process runs all over again,
+// this should be called before the tunnel starts. It only returns in the background process, with true.
+func Daemonize(logFile string) (bool, error) {
+	stdout, stderr, err := godaemon.MakeDaemon(&godaemon.DaemonAttr{CaptureOutput: true})
+	if err != nil {
+		return false, err
+	}
+	f, err := os.OpenFile(logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return true, errors.Wrapf(err, "opening %s", logFile)
+	}
+	for _, r := range []io.Reader{stdout, stderr} {
+		go func(r io.Reader) {
+			if _, err := io.Copy(f, r); err != nil {
+				klog.Errorf("error writing tunnel output to %s: %v", logFile, err)
+			}
+		}(r)
+	}
+	return true, nil
+}
@@ -0,0 +1,54 @@
+// +build windows
+
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+
+	"github.com/pkg/errors"
+)
+
+// daemonEnv is set in the environment of the background process
+const daemonEnv = "MINIKUBE_TUNNEL_DAEMON"
+
+// detachedProcess starts a process without a console
+const detachedProcess = 0x00000008
+
+// Daemonize runs the tunnel in the background, and writes its output to logFile. minikube is run again in the
+// background with the same arguments, so this should be called before the tunnel starts. It returns true in the
+// background process, and false in the process which started it.
+func Daemonize(logFile string) (bool, error) {
+	if os.Getenv(daemonEnv) != "" {
+		return true, nil
+	}
+	f, err := os.OpenFile(logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return false, errors.Wrapf(err, "opening %s", logFile)
+	}
+	defer f.Close()
+
+	cmd := exec.Command(os.Args[0], os.Args[1:]...)
+	cmd.Env = append(os.Environ(), daemonEnv+"=1")
+	cmd.Stdout = f
+	cmd.Stderr = f
+	cmd.SysProcAttr = &syscall.SysProcAttr{CreationFlags: detachedProcess | syscall.CREATE_NEW_PROCESS_GROUP}
+	return false, cmd.Start()
+}
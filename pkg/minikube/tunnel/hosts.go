@@ -0,0 +1,121 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/klog/v2"
+)
+
+// HostsFile returns the path to the hosts file of the host
+func HostsFile() string {
+	if runtime.GOOS == "windows" {
+		return filepath.Join(os.Getenv("SystemRoot"), "System32", "drivers", "etc", "hosts")
+	}
+	return "/etc/hosts"
+}
+
+// hostsBlock replaces the block of hosts managed by the tunnel of a cluster in the content of a hosts file.
+// The block is removed if there are no hosts.
+func hostsBlock(content string, name string, hosts map[string]string) string {
+	begin := fmt.Sprintf("# BEGIN minikube tunnel %s", name)
+	end := fmt.Sprintf("# END minikube tunnel %s", name)
+
+	var lines []string
+	inBlock := false
+	for _, l := range strings.SplitAfter(content, "\n") {
+		switch strings.TrimSpace(l) {
+		case begin:
+			inBlock = true
+			continue
+		case end:
+			inBlock = false
+			continue
+		}
+		if !inBlock && l != "" {
+			lines = append(lines, l)
+		}
+	}
+	if len(lines) > 0 && !strings.HasSuffix(lines[len(lines)-1], "\n") {
+		lines[len(lines)-1] += "\n"
+	}
+	if len(hosts) == 0 {
+		return strings.Join(lines, "")
+	}
+
+	names := make([]string, 0, len(hosts))
+	for h := range hosts {
+		names = append(names, h)
+	}
+	sort.Strings(names)
+	lines = append(lines, begin+"\n")
+	for _, h := range names {
+		lines = append(lines, fmt.Sprintf("%s\t%s\n", hosts[h], h))
+	}
+	lines = append(lines, end+"\n")
+	return strings.Join(lines, "")
+}
+
+// writeHostsFile writes the hosts managed by the tunnel of a cluster to a hosts file, if they changed
+func writeHostsFile(path string, name string, hosts map[string]string) error {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return errors.Wrap(err, "read")
+	}
+	content := hostsBlock(string(b), name, hosts)
+	if content == string(b) {
+		return nil
+	}
+	klog.Infof("updating the hosts of %s in %q:\n%s", name, path, content)
+
+	// the tunnel runs elevated on Windows, and is allowed to write the hosts file directly
+	if runtime.GOOS == "windows" {
+		return ioutil.WriteFile(path, []byte(content), 0644)
+	}
+
+	// write the hosts into tf, then copy it over the hosts file, which keeps its owner and mode
+	tf, err := ioutil.TempFile("", "minikube-tunnel-hosts-")
+	if err != nil {
+		return errors.Wrap(err, "tempfile")
+	}
+	defer os.Remove(tf.Name())
+
+	if _, err = tf.WriteString(content); err != nil {
+		return errors.Wrap(err, "write")
+	}
+	if err = tf.Close(); err != nil {
+		return errors.Wrap(err, "close")
+	}
+
+	cmd := exec.Command("sudo", "cp", tf.Name(), path)
+	if _, err = cmd.Output(); err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return fmt.Errorf("%q failed: %v: %q", strings.Join(cmd.Args, " "), exitErr, exitErr.Stderr)
+		}
+		return errors.Wrap(err, "copy")
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"testing"
+)
+
+func TestHostsBlock(t *testing.T) {
+	const base = "127.0.0.1\tlocalhost\n::1\tlocalhost\n"
+	const block = "# BEGIN minikube tunnel minikube\n10.96.0.3\tapp.test\n192.168.39.2\tweb.test\n# END minikube tunnel minikube\n"
+	hosts := map[string]string{"web.test": "192.168.39.2", "app.test": "10.96.0.3"}
+
+	tests := []struct {
+		name    string
+		content string
+		hosts   map[string]string
+		want    string
+	}{
+		{"add", base, hosts, base + block},
+		{"add without trailing newline", "127.0.0.1\tlocalhost", hosts, "127.0.0.1\tlocalhost\n" + block},
+		{"replace", base + "# BEGIN minikube tunnel minikube\n10.0.0.1\told.test\n# END minikube tunnel minikube\n", hosts, base + block},
+		{"remove", base + block, nil, base},
+		{"unchanged", base + block, hosts, base + block},
+		{"other cluster", base + "# BEGIN minikube tunnel p2\n10.0.0.1\tp2.test\n# END minikube tunnel p2\n", nil, base + "# BEGIN minikube tunnel p2\n10.0.0.1\tp2.test\n# END minikube tunnel p2\n"},
+		{"empty", "", nil, ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := hostsBlock(tc.content, "minikube", tc.hosts)
+			if got != tc.want {
+				t.Errorf("hostsBlock() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	core "k8s.io/api/core/v1"
+	networking "k8s.io/api/networking/v1beta1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	typed_core "k8s.io/client-go/kubernetes/typed/core/v1"
+	typed_networking "k8s.io/client-go/kubernetes/typed/networking/v1beta1"
+	"k8s.io/klog/v2"
+)
+
+// ingressControllerSelector selects the services of ingress controllers, such as ingress-nginx
+const ingressControllerSelector = "app.kubernetes.io/component=controller"
+
+// IngressEmulator sets the address of Ingress resources to the ingress controller, which is reachable through the tunnel route,
+// and maps their hosts to it in the hosts file of the host
+type IngressEmulator struct {
+	name           string
+	coreV1Client   typed_core.CoreV1Interface
+	netV1Client    typed_networking.NetworkingV1beta1Interface
+	hostsFile      string
+	requestSender  requestSender
+	patchConverter patchConverter
+	hostsWriter    func(path string, name string, hosts map[string]string) error
+	// patched are the ingresses patched so far, by namespace/name
+	patched map[string]networking.Ingress
+	// hosts are the hosts written to the hosts file, or nil until it is first written
+	hosts map[string]string
+}
+
+// NewIngressEmulator creates a new IngressEmulator for the tunnel of a cluster. Hosts are not written if hostsFile is empty.
+func NewIngressEmulator(name string, coreV1Client typed_core.CoreV1Interface, netV1Client typed_networking.NetworkingV1beta1Interface, hostsFile string) *IngressEmulator {
+	return &IngressEmulator{
+		name:           name,
+		coreV1Client:   coreV1Client,
+		netV1Client:    netV1Client,
+		hostsFile:      hostsFile,
+		requestSender:  &defaultRequestSender{},
+		patchConverter: &defaultPatchConverter{},
+		hostsWriter:    writeHostsFile,
+		patched:        make(map[string]networking.Ingress),
+	}
+}
+
+// PatchIngresses sets the address of all ingresses to the ingress controller, and writes their hosts to the hosts file.
+// The controller is reached at the ClusterIP of its LoadBalancer service if it has one, or else on its host ports at gateway.
+func (e *IngressEmulator) PatchIngresses(gateway net.IP) ([]string, error) {
+	ingresses, err := e.netV1Client.Ingresses("").List(meta.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "list ingresses")
+	}
+	ip := e.controllerIP(gateway)
+	if ip == "" {
+		return nil, nil
+	}
+
+	var names []string
+	hosts := map[string]string{}
+	for _, ing := range ingresses.Items {
+		name := fmt.Sprintf("%s/%s", ing.Namespace, ing.Name)
+		names = append(names, name)
+		for _, h := range ingressHosts(ing) {
+			hosts[h] = ip
+		}
+		if addrs := ing.Status.LoadBalancer.Ingress; len(addrs) == 1 && addrs[0].IP == ip {
+			continue
+		}
+		jsonPatch := fmt.Sprintf(`[{"op": "add", "path": "/status/loadBalancer/ingress", "value":  [ { "ip": "%s" } ] }]`, ip)
+		if err := e.patch(ing, jsonPatch); err != nil {
+			klog.Errorf("error patching ingress %s with IP %s: %v", name, ip, err)
+			continue
+		}
+		klog.Infof("Patched ingress %s with IP %s", name, ip)
+		e.patched[name] = ing
+	}
+
+	if err := e.writeHosts(hosts); err != nil {
+		return names, err
+	}
+	return names, nil
+}
+
+// Cleanup unsets the address of the ingresses patched so far, and removes their hosts from the hosts file
+func (e *IngressEmulator) Cleanup() ([]string, error) {
+	var names []string
+	for name, ing := range e.patched {
+		if err := e.patch(ing, `[{"op": "remove", "path": "/status/loadBalancer/ingress" }]`); err != nil {
+			klog.Errorf("error cleaning up ingress %s: %v", name, err)
+			continue
+		}
+		klog.Infof("Removed load balancer ingress from ingress %s.", name)
+		names = append(names, name)
+		delete(e.patched, name)
+	}
+	return names, e.writeHosts(map[string]string{})
+}
+
+func (e *IngressEmulator) patch(ing networking.Ingress, jsonPatch string) error {
+	patch := &Patch{
+		Type:         types.JSONPatchType,
+		ResourceName: ing.Name,
+		NameSpaceSet: true,
+		NameSpace:    ing.Namespace,
+		Subresource:  "status",
+		Resource:     "ingresses",
+		BodyContent:  jsonPatch,
+	}
+	request := e.patchConverter.convert(e.netV1Client.RESTClient(), patch)
+	result, err := e.requestSender.send(request)
+	if err != nil {
+		klog.Errorf("%s", result)
+	}
+	return err
+}
+
+// controllerIP returns the IP the ingress controller is reachable at through the tunnel route
+func (e *IngressEmulator) controllerIP(gateway net.IP) string {
+	services, err := e.coreV1Client.Services("").List(meta.ListOptions{LabelSelector: ingressControllerSelector})
+	if err != nil {
+		klog.Warningf("error listing ingress controller services: %v", err)
+	} else {
+		for _, svc := range services.Items {
+			if svc.Spec.Type == core.ServiceTypeLoadBalancer && svc.Spec.ClusterIP != "" {
+				return svc.Spec.ClusterIP
+			}
+		}
+	}
+	if gateway == nil {
+		return ""
+	}
+	return gateway.String()
+}
+
+// writeHosts writes hosts to the hosts file, if they changed. The hosts file is always written the first time,
+// to remove the hosts left by a tunnel which did not exit cleanly.
+func (e *IngressEmulator) writeHosts(hosts map[string]string) error {
+	if e.hostsFile == "" || (e.hosts != nil && equalHosts(hosts, e.hosts)) {
+		return nil
+	}
+	if err := e.hostsWriter(e.hostsFile, e.name, hosts); err != nil {
+		return errors.Wrap(err, "hosts file")
+	}
+	e.hosts = hosts
+	return nil
+}
+
+// ingressHosts returns the sorted hosts of the rules of an ingress. Wildcard hosts cannot be written to a hosts file, and are skipped.
+func ingressHosts(ing networking.Ingress) []string {
+	var hosts []string
+	for _, r := range ing.Spec.Rules {
+		if r.Host == "" || strings.HasPrefix(r.Host, "*") {
+			continue
+		}
+		hosts = append(hosts, r.Host)
+	}
+	sort.Strings(hosts)
+	return hosts
+}
+
+func equalHosts(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for h, ip := range a {
+		if b[h] != ip {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"net"
+	"reflect"
+	"testing"
+
+	core "k8s.io/api/core/v1"
+	networking "k8s.io/api/networking/v1beta1"
+	meta "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func ingress(name string, ip string, hosts ...string) *networking.Ingress {
+	ing := &networking.Ingress{ObjectMeta: meta.ObjectMeta{Name: name, Namespace: "default"}}
+	for _, h := range hosts {
+		ing.Spec.Rules = append(ing.Spec.Rules, networking.IngressRule{Host: h})
+	}
+	if ip != "" {
+		ing.Status.LoadBalancer.Ingress = []core.LoadBalancerIngress{{IP: ip}}
+	}
+	return ing
+}
+
+func TestPatchIngresses(t *testing.T) {
+	gateway := net.ParseIP("192.168.39.2")
+	lb := &core.Service{
+		ObjectMeta: meta.ObjectMeta{Name: "ingress-nginx-controller", Namespace: "ingress-nginx", Labels: map[string]string{"app.kubernetes.io/component": "controller"}},
+		Spec:       core.ServiceSpec{Type: core.ServiceTypeLoadBalancer, ClusterIP: "10.96.0.80"},
+	}
+
+	tests := []struct {
+		name        string
+		objects     []runtime.Object
+		wantPatches []string
+		wantHosts   string
+	}{
+		{
+			name:        "host ports",
+			objects:     []runtime.Object{ingress("web", "", "web.test", "*.web.test"), ingress("api", "192.168.39.2", "api.test")},
+			wantPatches: []string{"web"},
+			wantHosts:   "# BEGIN minikube tunnel minikube\n192.168.39.2\tapi.test\n192.168.39.2\tweb.test\n# END minikube tunnel minikube\n",
+		},
+		{
+			name:        "load balancer",
+			objects:     []runtime.Object{lb, ingress("web", "192.168.39.2", "web.test")},
+			wantPatches: []string{"web"},
+			wantHosts:   "# BEGIN minikube tunnel minikube\n10.96.0.80\tweb.test\n# END minikube tunnel minikube\n",
+		},
+		{
+			name: "no ingresses",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			client := fake.NewSimpleClientset(tc.objects...)
+			e := NewIngressEmulator("minikube", client.CoreV1(), client.NetworkingV1beta1(), "hosts")
+			converter := &recordingPatchConverter{}
+			e.requestSender = &countingRequestSender{}
+			e.patchConverter = converter
+			hosts := ""
+			e.hostsWriter = func(path string, name string, h map[string]string) error {
+				hosts = hostsBlock(hosts, name, h)
+				return nil
+			}
+
+			if _, err := e.PatchIngresses(gateway); err != nil {
+				t.Fatalf("PatchIngresses: %v", err)
+			}
+			var patched []string
+			for _, p := range converter.patches {
+				patched = append(patched, p.ResourceName)
+			}
+			if !reflect.DeepEqual(patched, tc.wantPatches) {
+				t.Errorf("patched %v, want %v", patched, tc.wantPatches)
+			}
+			if hosts != tc.wantHosts {
+				t.Errorf("hosts = %q, want %q", hosts, tc.wantHosts)
+			}
+
+			if _, err := e.Cleanup(); err != nil {
+				t.Fatalf("Cleanup: %v", err)
+			}
+			if len(converter.patches) != 2*len(tc.wantPatches) {
+				t.Errorf("got %d patches after cleanup, want %d", len(converter.patches), 2*len(tc.wantPatches))
+			}
+			if hosts != "" {
+				t.Errorf("hosts = %q after cleanup, want none", hosts)
+			}
+		})
+	}
+}
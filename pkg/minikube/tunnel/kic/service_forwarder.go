@@ -0,0 +1,273 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kic
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/phayes/freeport"
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	typed_core "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	"k8s.io/klog/v2"
+)
+
+// Statuses of a forwarded service
+const (
+	ForwardRunning      = "Forwarding"
+	ForwardNoEndpoints  = "No endpoints"
+	ForwardReconnecting = "Reconnecting"
+	ForwardNotFound     = "Not found"
+	ForwardError        = "Error"
+)
+
+// ServiceForwarder forwards services to stable ports on the host. The ssh connection of a service is
+// reconnected, to the same ports, when it drops or when the service or its endpoints change.
+type ServiceForwarder struct {
+	sshPort   string
+	sshKey    string
+	v1Core    typed_core.CoreV1Interface
+	namespace string
+	services  []string
+	// nextPort is the next port to forward to, or 0 to forward to free ports
+	nextPort int
+	// available tells if nextPort can be forwarded to
+	available func(port int) error
+	ports     map[string]int
+	fwds      map[string]*forward
+	report    func([][]string)
+	rows      [][]string
+}
+
+// forward is the ssh connection forwarding a service
+type forward struct {
+	conn   *sshConn
+	key    string
+	exited chan struct{}
+}
+
+// NewServiceForwarder returns a forwarder for services of a namespace. Their ports are forwarded to consecutive
+// ports from localPort, or to free ports if localPort is 0. report is called with a row per port whenever they change.
+func NewServiceForwarder(sshPort, sshKey string, v1Core typed_core.CoreV1Interface, namespace string, services []string, localPort int, report func([][]string)) *ServiceForwarder {
+	return &ServiceForwarder{
+		sshPort:   sshPort,
+		sshKey:    sshKey,
+		v1Core:    v1Core,
+		namespace: namespace,
+		services:  services,
+		nextPort:  localPort,
+		available: portAvailable,
+		ports:     make(map[string]int),
+		fwds:      make(map[string]*forward),
+		report:    report,
+	}
+}
+
+// Start forwards the services until ctx is done, or until their ports cannot be allocated
+func (f *ServiceForwarder) Start(ctx context.Context) error {
+	defer func() {
+		for name := range f.fwds {
+			f.stop(name)
+		}
+	}()
+
+	for {
+		var rows [][]string
+		for _, name := range f.services {
+			r, err := f.reconcile(name)
+			if err != nil {
+				return err
+			}
+			rows = append(rows, r...)
+		}
+		if !reflect.DeepEqual(rows, f.rows) {
+			f.rows = rows
+			f.report(rows)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// reconcile (re)connects the forward of a service if needed, and returns its rows
+func (f *ServiceForwarder) reconcile(name string) ([][]string, error) {
+	svc, err := f.v1Core.Services(f.namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		// the forward is kept while the apiserver is unavailable, as it may be restarting
+		status := ForwardError
+		if apierrors.IsNotFound(err) {
+			f.stop(name)
+			status = ForwardNotFound
+		} else {
+			klog.Warningf("error getting service %s: %v", name, err)
+		}
+		return [][]string{{f.namespace, name, "", "", status}}, nil
+	}
+
+	ports, err := f.localPorts(svc)
+	if err != nil {
+		return nil, errors.Wrapf(err, "allocating ports for service %s", name)
+	}
+
+	endpoints := f.endpoints(svc)
+	key := sshConnUniqName(*svc) + "-" + strings.Join(endpoints, ",")
+	status := ForwardRunning
+	fwd, ok := f.fwds[name]
+	switch {
+	case !ok:
+		err = f.connect(svc, key, ports)
+	case fwd.key != key:
+		klog.Infof("service %s changed, reconnecting", name)
+		f.stop(name)
+		err = f.connect(svc, key, ports)
+	default:
+		select {
+		case <-fwd.exited:
+			klog.Infof("ssh connection of service %s dropped, reconnecting", name)
+			status = ForwardReconnecting
+			delete(f.fwds, name)
+			err = f.connect(svc, key, ports)
+		default:
+		}
+	}
+	if err != nil {
+		klog.Warningf("error connecting service %s: %v", name, err)
+		status = ForwardReconnecting
+	} else if len(endpoints) == 0 && status == ForwardRunning {
+		status = ForwardNoEndpoints
+	}
+
+	var rows [][]string
+	for i, port := range svc.Spec.Ports {
+		target := strconv.Itoa(int(port.Port))
+		if port.Name != "" {
+			target = fmt.Sprintf("%s/%d", port.Name, port.Port)
+		}
+		rows = append(rows, []string{f.namespace, name, target, fmt.Sprintf("http://127.0.0.1:%d", ports[i]), status})
+	}
+	return rows, nil
+}
+
+// connect starts an ssh connection forwarding a service to ports
+func (f *ServiceForwarder) connect(svc *v1.Service, key string, ports []int) error {
+	conn := createSSHConnWithPorts(svc.Name, f.sshPort, f.sshKey, svc, ports)
+	if err := conn.start(); err != nil {
+		return errors.Wrap(err, "starting ssh")
+	}
+	fwd := &forward{conn: conn, key: key, exited: make(chan struct{})}
+	f.fwds[svc.Name] = fwd
+	go func() {
+		// the error is ignored, as ssh exits with an error when it is stopped too
+		_ = conn.cmd.Wait()
+		close(fwd.exited)
+	}()
+	return nil
+}
+
+// stop stops the forward of a service, if any
+func (f *ServiceForwarder) stop(name string) {
+	fwd, ok := f.fwds[name]
+	if !ok {
+		return
+	}
+	delete(f.fwds, name)
+	select {
+	case <-fwd.exited:
+		return
+	default:
+	}
+	if err := fwd.conn.stop(); err != nil {
+		klog.Errorf("error stopping ssh tunnel: %v", err)
+		return
+	}
+	<-fwd.exited
+}
+
+// endpoints returns the sorted addresses of the ready endpoints of a service
+func (f *ServiceForwarder) endpoints(svc *v1.Service) []string {
+	ep, err := f.v1Core.Endpoints(svc.Namespace).Get(svc.Name, metav1.GetOptions{})
+	if err != nil {
+		klog.Infof("error getting endpoints of service %s: %v", svc.Name, err)
+		return nil
+	}
+	var addrs []string
+	for _, s := range ep.Subsets {
+		for _, a := range s.Addresses {
+			addrs = append(addrs, a.IP)
+		}
+	}
+	sort.Strings(addrs)
+	return addrs
+}
+
+// localPorts returns the host ports the ports of a service are forwarded to. Once allocated, a port
+// is kept for as long as the forwarder runs, so that the URLs of a service survive reconnections.
+func (f *ServiceForwarder) localPorts(svc *v1.Service) ([]int, error) {
+	ports := make([]int, 0, len(svc.Spec.Ports))
+	for _, port := range svc.Spec.Ports {
+		key := fmt.Sprintf("%s/%d", svc.Name, port.Port)
+		p, ok := f.ports[key]
+		if !ok {
+			var err error
+			if p, err = f.allocatePort(); err != nil {
+				return nil, err
+			}
+			f.ports[key] = p
+		}
+		ports = append(ports, p)
+	}
+	return ports, nil
+}
+
+// allocatePort returns the next port to forward to, which must be free
+func (f *ServiceForwarder) allocatePort() (int, error) {
+	if f.nextPort == 0 {
+		return freeport.GetFreePort()
+	}
+	p := f.nextPort
+	if err := f.available(p); err != nil {
+		return 0, errors.Wrapf(err, "port %d is not available", p)
+	}
+	f.nextPort++
+	return p, nil
+}
+
+// portAvailable returns an error unless a port of the host can be listened on
+func portAvailable(port int) error {
+	l, err := net.Listen("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(port)))
+	if err != nil {
+		return err
+	}
+	if err := l.Close(); err != nil {
+		klog.Warningf("error closing listener on port %d: %v", port, err)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2020 The Kubernetes Authors All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kic
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func service(name string, ports ...int32) *v1.Service {
+	svc := &v1.Service{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}}
+	for _, p := range ports {
+		svc.Spec.Ports = append(svc.Spec.Ports, v1.ServicePort{Port: p})
+	}
+	return svc
+}
+
+func TestLocalPorts(t *testing.T) {
+	base := 30000
+	f := NewServiceForwarder("22", "id_rsa", fake.NewSimpleClientset().CoreV1(), "default", []string{"web", "db"}, base, nil)
+	// the ports in use on the host do not matter
+	f.available = func(int) error { return nil }
+
+	tests := []struct {
+		svc  *v1.Service
+		want []int
+	}{
+		{service("web", 80, 443), []int{base, base + 1}},
+		{service("db", 5432), []int{base + 2}},
+		// ports are kept across calls, and new ports of a service get the next port
+		{service("web", 443, 8080, 80), []int{base + 1, base + 3, base}},
+	}
+	for _, tc := range tests {
+		got, err := f.localPorts(tc.svc)
+		if err != nil {
+			t.Fatalf("localPorts(%s): %v", tc.svc.Name, err)
+		}
+		if len(got) != len(tc.want) {
+			t.Fatalf("localPorts(%s) = %v, want %v", tc.svc.Name, got, tc.want)
+		}
+		for i := range got {
+			if got[i] != tc.want[i] {
+				t.Errorf("localPorts(%s) = %v, want %v", tc.svc.Name, got, tc.want)
+				break
+			}
+		}
+	}
+}
+
+func TestLocalPortsInUse(t *testing.T) {
+	f := NewServiceForwarder("22", "id_rsa", fake.NewSimpleClientset().CoreV1(), "default", []string{"web"}, 30000, nil)
+	f.available = func(p int) error {
+		if p == 30001 {
+			return errors.New("address already in use")
+		}
+		return nil
+	}
+	if _, err := f.localPorts(service("web", 80, 443)); err == nil {
+		t.Error("localPorts succeeded with a port in use")
+	}
+}
+
+func TestReconcileNotFound(t *testing.T) {
+	f := NewServiceForwarder("22", "id_rsa", fake.NewSimpleClientset().CoreV1(), "default", []string{"missing"}, 0, nil)
+	rows, err := f.reconcile("missing")
+	if err != nil {
+		t.Fatalf("reconcile: %v", err)
+	}
+	if len(rows) != 1 || rows[0][4] != ForwardNotFound {
+		t.Errorf("reconcile = %v, want a single %q row", rows, ForwardNotFound)
+	}
+}
@@ -89,34 +89,43 @@ func createSSHConn(name, sshPort, sshKey string, svc *v1.Service) *sshConn {
 }
 
 func createSSHConnWithRandomPorts(name, sshPort, sshKey string, svc *v1.Service) (*sshConn, error) {
-	// extract sshArgs
+	usedPorts := make([]int, 0, len(svc.Spec.Ports))
+
+	for range svc.Spec.Ports {
+		freeport, err := freeport.GetFreePort()
+		if err != nil {
+			return nil, err
+		}
+		usedPorts = append(usedPorts, freeport)
+	}
+
+	return createSSHConnWithPorts(name, sshPort, sshKey, svc, usedPorts), nil
+}
+
+// createSSHConnWithPorts creates an ssh connection forwarding the i-th port of a service to the i-th of ports.
+// ssh exits if the forwards cannot be set up or the connection drops, so that it can be reconnected.
+func createSSHConnWithPorts(name, sshPort, sshKey string, svc *v1.Service, ports []int) *sshConn {
 	sshArgs := []string{
-		// TODO: document the options here
 		"-o", "UserKnownHostsFile=/dev/null",
 		"-o", "StrictHostKeyChecking=no",
+		"-o", "ExitOnForwardFailure=yes",
+		"-o", "ServerAliveInterval=5",
+		"-o", "ServerAliveCountMax=3",
 		"-N",
 		"docker@127.0.0.1",
 		"-p", sshPort,
 		"-i", sshKey,
 	}
 
-	usedPorts := make([]int, 0, len(svc.Spec.Ports))
-
-	for _, port := range svc.Spec.Ports {
-		freeport, err := freeport.GetFreePort()
-		if err != nil {
-			return nil, err
-		}
-
+	for i, port := range svc.Spec.Ports {
 		arg := fmt.Sprintf(
 			"-L %d:%s:%d",
-			freeport,
+			ports[i],
 			svc.Spec.ClusterIP,
 			port.Port,
 		)
 
 		sshArgs = append(sshArgs, arg)
-		usedPorts = append(usedPorts, freeport)
 	}
 
 	cmd := exec.Command("ssh", sshArgs...)
@@ -125,14 +134,18 @@ func createSSHConnWithRandomPorts(name, sshPort, sshKey string, svc *v1.Service)
 		name:    name,
 		service: svc.Name,
 		cmd:     cmd,
-		ports:   usedPorts,
-	}, nil
+		ports:   ports,
+	}
 }
 
-func (c *sshConn) startAndWait() error {
+func (c *sshConn) start() error {
 	out.Step(style.Running, "Starting tunnel for service {{.service}}.", out.V{"service": c.service})
 
-	err := c.cmd.Start()
+	return c.cmd.Start()
+}
+
+func (c *sshConn) startAndWait() error {
+	err := c.start()
 	if err != nil {
 		return err
 	}
@@ -19,6 +19,7 @@ package kic
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"strings"
 	"time"
 
@@ -39,10 +40,12 @@ type SSHTunnel struct {
 	LoadBalancerEmulator tunnel.LoadBalancerEmulator
 	conns                map[string]*sshConn
 	connsToStop          map[string]*sshConn
+	report               func(services []string)
+	services             []string
 }
 
-// NewSSHTunnel ...
-func NewSSHTunnel(ctx context.Context, sshPort, sshKey string, v1Core typed_core.CoreV1Interface) *SSHTunnel {
+// NewSSHTunnel ... report is called with the names of the tunneled services whenever they change, unless it is nil.
+func NewSSHTunnel(ctx context.Context, sshPort, sshKey string, v1Core typed_core.CoreV1Interface, report func(services []string)) *SSHTunnel {
 	return &SSHTunnel{
 		ctx:                  ctx,
 		sshPort:              sshPort,
@@ -51,6 +54,7 @@ func NewSSHTunnel(ctx context.Context, sshPort, sshKey string, v1Core typed_core
 		LoadBalancerEmulator: tunnel.NewLoadBalancerEmulator(v1Core),
 		conns:                make(map[string]*sshConn),
 		connsToStop:          make(map[string]*sshConn),
+		report:               report,
 	}
 }
 
@@ -59,6 +63,8 @@ func (t *SSHTunnel) Start() error {
 	for {
 		select {
 		case <-t.ctx.Done():
+			t.markConnectionsToBeStopped()
+			t.stopMarkedConnections()
 			_, err := t.LoadBalancerEmulator.Cleanup()
 			if err != nil {
 				klog.Errorf("error cleaning up: %v", err)
@@ -70,18 +76,27 @@ func (t *SSHTunnel) Start() error {
 		services, err := t.v1Core.Services("").List(metav1.ListOptions{})
 		if err != nil {
 			klog.Errorf("error listing services: %v", err)
+			time.Sleep(1 * time.Second)
+			continue
 		}
 
 		t.markConnectionsToBeStopped()
 
+		var names []string
 		for _, svc := range services.Items {
 			if svc.Spec.Type == v1.ServiceTypeLoadBalancer {
 				t.startConnection(svc)
+				names = append(names, svc.Name)
 			}
 		}
 
 		t.stopMarkedConnections()
 
+		if t.report != nil && !reflect.DeepEqual(names, t.services) {
+			t.report(names)
+		}
+		t.services = names
+
 		// TODO: which time to use?
 		time.Sleep(1 * time.Second)
 	}
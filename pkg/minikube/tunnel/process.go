@@ -21,6 +21,9 @@ import (
 	"os"
 	"runtime"
 	"syscall"
+	"time"
+
+	"k8s.io/klog/v2"
 )
 
 var checkIfRunning func(pid int) (bool, error)
@@ -31,6 +34,29 @@ func init() {
 	getPid = osGetPid
 }
 
+// stopProcess interrupts a process, or kills it if it cannot be interrupted, as on Windows, or does not exit within timeout
+func stopProcess(pid int, timeout time.Duration) error {
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return fmt.Errorf("error finding process %d: %s", pid, err)
+	}
+	if err := p.Signal(os.Interrupt); err != nil {
+		klog.Infof("unable to interrupt %d, killing it: %v", pid, err)
+		return p.Kill()
+	}
+	for start := time.Now(); time.Since(start) < timeout; time.Sleep(500 * time.Millisecond) {
+		isRunning, err := checkIfRunning(pid)
+		if err != nil {
+			return err
+		}
+		if !isRunning {
+			return nil
+		}
+	}
+	klog.Warningf("%d did not exit within %s, killing it", pid, timeout)
+	return p.Kill()
+}
+
 func osGetPid() int {
 	return os.Getpid()
 }
@@ -32,11 +32,14 @@ import (
 
 // ID represents a registry ID
 type ID struct {
-	// Route is the key
+	// Route is the key, or MachineName for tunnels without routes, such as the ssh tunnels of the kic drivers
 	Route *Route
 	// the rest is metadata
 	MachineName string
 	Pid         int
+	// Services and Ingresses are the resources patched by the tunnel
+	Services  []string `json:",omitempty"`
+	Ingresses []string `json:",omitempty"`
 }
 
 // Equal checks if two ID are equal
@@ -50,6 +53,14 @@ func (t *ID) String() string {
 	return fmt.Sprintf("ID { Route: %v, machineName: %s, Pid: %d }", t.Route, t.MachineName, t.Pid)
 }
 
+// sameTunnel checks if two IDs have the same key
+func (t *ID) sameTunnel(other *ID) bool {
+	if t.Route == nil || other.Route == nil {
+		return t.Route == nil && other.Route == nil && t.MachineName == other.MachineName
+	}
+	return t.Route.Equal(other.Route)
+}
+
 type persistentRegistry struct {
 	path string
 }
@@ -61,7 +72,7 @@ func (r *persistentRegistry) IsAlreadyDefinedAndRunning(tunnel *ID) (*ID, error)
 	}
 
 	for _, t := range tunnels {
-		if t.Route.Equal(tunnel.Route) {
+		if t.sameTunnel(tunnel) {
 			isRunning, err := checkIfRunning(t.Pid)
 			if err != nil {
 				return nil, fmt.Errorf("error checking whether conflicting tunnel (%v) is running: %s", t, err)
@@ -76,8 +87,8 @@ func (r *persistentRegistry) IsAlreadyDefinedAndRunning(tunnel *ID) (*ID, error)
 
 func (r *persistentRegistry) Register(tunnel *ID) (rerr error) {
 	klog.V(3).Infof("registering tunnel: %s", tunnel)
-	if tunnel.Route == nil {
-		return errors.New("tunnel.Route should not be nil")
+	if tunnel.Route == nil && tunnel.MachineName == "" {
+		return errors.New("tunnel.Route or tunnel.MachineName should be set")
 	}
 
 	tunnels, err := r.List()
@@ -87,12 +98,13 @@ func (r *persistentRegistry) Register(tunnel *ID) (rerr error) {
 
 	alreadyExists := false
 	for i, t := range tunnels {
-		if t.Route.Equal(tunnel.Route) {
+		if t.sameTunnel(tunnel) {
 			isRunning, err := checkIfRunning(t.Pid)
 			if err != nil {
 				return fmt.Errorf("error checking whether conflicting tunnel (%v) is running: %s", t, err)
 			}
-			if isRunning {
+			// a running tunnel may update its own metadata
+			if isRunning && t.Pid != tunnel.Pid {
 				return errorTunnelAlreadyExists(t)
 			}
 			tunnels[i] = tunnel
@@ -137,21 +149,31 @@ func (r *persistentRegistry) Register(tunnel *ID) (rerr error) {
 	return nil
 }
 
-func (r *persistentRegistry) Remove(route *Route) (rerr error) {
+func (r *persistentRegistry) Remove(route *Route) error {
 	klog.V(3).Infof("removing tunnel from registry: %s", route)
+	return r.remove(func(t *ID) bool { return t.Route.Equal(route) }, fmt.Sprintf("route: %s", route))
+}
+
+// RemoveID removes a tunnel, which may have no route, from the registry
+func (r *persistentRegistry) RemoveID(tunnel *ID) error {
+	klog.V(3).Infof("removing tunnel from registry: %s", tunnel)
+	return r.remove(tunnel.sameTunnel, fmt.Sprintf("tunnel: %s", tunnel))
+}
+
+func (r *persistentRegistry) remove(match func(*ID) bool, desc string) (rerr error) {
 	tunnels, err := r.List()
 	if err != nil {
 		return err
 	}
 	idx := -1
 	for i := range tunnels {
-		if tunnels[i].Route.Equal(route) {
+		if match(tunnels[i]) {
 			idx = i
 			break
 		}
 	}
 	if idx == -1 {
-		return fmt.Errorf("can't remove route: %s not found in tunnel registry", route)
+		return fmt.Errorf("can't remove %s not found in tunnel registry", desc)
 	}
 	tunnels = append(tunnels[:idx], tunnels[idx+1:]...)
 	klog.V(4).Infof("tunnels after remove: %s", tunnels)
@@ -266,3 +266,46 @@ func createTestRegistry(t *testing.T) (reg *persistentRegistry, cleanup func())
 	}
 	return registry, func() { os.Remove(f.Name()) }
 }
+
+func TestRegisterWithoutRoute(t *testing.T) {
+	file := tmpFile(t)
+	reg := &persistentRegistry{
+		path: file,
+	}
+	defer os.Remove(file)
+
+	id := &ID{MachineName: "testmachine", Pid: os.Getpid()}
+	if err := reg.Register(id); err != nil {
+		t.Fatalf("failed to register: expected no error, got %s", err)
+	}
+	// the tunnel updates its own metadata
+	id.Services = []string{"svc1"}
+	if err := reg.Register(id); err != nil {
+		t.Fatalf("failed to update: expected no error, got %s", err)
+	}
+	if err := reg.Register(&ID{MachineName: "testmachine", Pid: 5678}); err == nil {
+		t.Error("expected error on duplicate machine, got nil")
+	}
+	if err := reg.Register(&ID{MachineName: "othermachine", Pid: 5678}); err != nil {
+		t.Errorf("failed to register: expected no error, got %s", err)
+	}
+
+	tunnels, err := reg.List()
+	if err != nil {
+		t.Fatalf("failed to list: expected no error, got %s", err)
+	}
+	if len(tunnels) != 2 || !reflect.DeepEqual(tunnels[0], id) {
+		t.Errorf("expected %+v first of 2 tunnels, got %+v", id, tunnels)
+	}
+
+	if err := reg.RemoveID(id); err != nil {
+		t.Fatalf("failed to remove: expected no error, got %s", err)
+	}
+	tunnels, err = reg.List()
+	if err != nil {
+		t.Fatalf("failed to list: expected no error, got %s", err)
+	}
+	if len(tunnels) != 1 || tunnels[0].MachineName != "othermachine" {
+		t.Errorf("expected only othermachine, got %+v", tunnels)
+	}
+}
@@ -61,11 +61,22 @@ func (r *simpleReporter) Report(tunnelState *Status) {
 		routerError = tunnelState.RouteError.Error()
 	}
 
+	// ingresses are only reported by tunnels which emulate them
+	ingresses := ""
+	ingressError := ""
+	if tunnelState.PatchedIngresses != nil || tunnelState.IngressEmulatorError != nil {
+		ingresses = fmt.Sprintf("\tingresses: [%s]\n", strings.Join(tunnelState.PatchedIngresses, ", "))
+		ingressError = fmt.Sprintf("\t\tingress emulator: %s\n", noErrors)
+		if tunnelState.IngressEmulatorError != nil {
+			ingressError = fmt.Sprintf("\t\tingress emulator: %s\n", tunnelState.IngressEmulatorError)
+		}
+	}
+
 	errors := fmt.Sprintf(`    errors: 
 		minikube: %s
 		router: %s
 		loadbalancer emulator: %s
-`, minikubeError, routerError, lbError)
+%s`, minikubeError, routerError, lbError, ingressError)
 
 	_, err := r.out.Write([]byte(fmt.Sprintf(
 		`Status:	
@@ -74,11 +85,12 @@ func (r *simpleReporter) Report(tunnelState *Status) {
 	route: %s
 	minikube: %s
 	services: %s
-%s`, tunnelState.TunnelID.MachineName,
+%s%s`, tunnelState.TunnelID.MachineName,
 		tunnelState.TunnelID.Pid,
 		tunnelState.TunnelID.Route,
 		minikubeState,
 		managedServices,
+		ingresses,
 		errors)))
 	if err != nil {
 		klog.Errorf("failed to report state %s", err)
@@ -83,6 +83,34 @@ func TestReporter(t *testing.T) {
 		minikube: minikubeerror
 		router: route error
 		loadbalancer emulator: lberror
+`,
+		},
+		{
+			name: "ingresses",
+			tunnelState: &Status{
+				TunnelID: ID{
+					Route:       unsafeParseRoute("1.2.3.4", "10.96.0.0/12"),
+					MachineName: "testmachine",
+					Pid:         1234,
+				},
+				MinikubeState: Running,
+
+				PatchedServices:      []string{"svc1"},
+				PatchedIngresses:     []string{"default/web"},
+				IngressEmulatorError: errors.New("ingresserror"),
+			},
+			expectedOutput: `Status:	
+	machine: testmachine
+	pid: 1234
+	route: 10.96.0.0/12 -> 1.2.3.4
+	minikube: Running
+	services: [svc1]
+	ingresses: [default/web]
+    errors: 
+		minikube: no errors
+		router: no errors
+		loadbalancer emulator: no errors
+		ingress emulator: ingresserror
 `,
 		},
 	}
@@ -48,6 +48,9 @@ func (router *osRouter) EnsureRouteIsAdded(route *Route) error {
 
 	klog.Infof("Adding route for CIDR %s to gateway %s", serviceCIDR, gatewayIP)
 	command := exec.Command("sudo", "route", "-n", "add", serviceCIDR, gatewayIP)
+	if route.DestCIDR.IP.To4() == nil {
+		command = exec.Command("sudo", "route", "-n", "add", "-inet6", serviceCIDR, gatewayIP)
+	}
 	klog.Infof("About to run command: %s", command.Args)
 	stdInAndOut, err := command.CombinedOutput()
 	message := fmt.Sprintf("%s", stdInAndOut)
@@ -61,7 +64,11 @@ func (router *osRouter) EnsureRouteIsAdded(route *Route) error {
 }
 
 func (router *osRouter) Inspect(route *Route) (exists bool, conflict string, overlaps []string, err error) {
-	cmd := exec.Command("netstat", "-nr", "-f", "inet")
+	family := "inet"
+	if route.DestCIDR.IP.To4() == nil {
+		family = "inet6"
+	}
+	cmd := exec.Command("netstat", "-nr", "-f", family)
 	cmd.Env = append(cmd.Env, "LC_ALL=C")
 	stdInAndOut, err := cmd.CombinedOutput()
 	if err != nil {
@@ -119,6 +126,10 @@ func (router *osRouter) parseTable(table []byte) routingTable {
 }
 
 func (router *osRouter) padCIDR(origCIDR string) string {
+	// IPv6 destinations are listed in full
+	if strings.Contains(origCIDR, ":") {
+		return origCIDR
+	}
 	s := ""
 	dots := 0
 	slash := false
@@ -160,13 +171,17 @@ func (router *osRouter) Cleanup(route *Route) error {
 		return nil
 	}
 	cmd := exec.Command("sudo", "route", "-n", "delete", route.DestCIDR.String())
+	if route.DestCIDR.IP.To4() == nil {
+		cmd = exec.Command("sudo", "route", "-n", "delete", "-inet6", route.DestCIDR.String())
+	}
 	stdInAndOut, err := cmd.CombinedOutput()
 	if err != nil {
 		return err
 	}
 	msg := fmt.Sprintf("%s", stdInAndOut)
 	klog.V(4).Infof("%s", msg)
-	re := regexp.MustCompile("^delete net ([^:]*)$")
+	// errors follow the destination after a colon, which IPv6 destinations contain too
+	re := regexp.MustCompile(`^delete net ([^:]*|[0-9a-fA-F:]+/\d+)\s*$`)
 	if !re.MatchString(msg) {
 		return fmt.Errorf("error deleting route: %s, %d", msg, len(strings.Split(msg, "\n")))
 	}
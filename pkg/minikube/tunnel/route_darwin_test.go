@@ -136,6 +136,7 @@ func TestCIDRPadding(t *testing.T) {
 		{inputCIDR: "192.168.43", paddedCIDR: "192.168.43.0/24"},
 		{inputCIDR: "192.168.43.1/32", paddedCIDR: "192.168.43.1/32"},
 		{inputCIDR: "127.0.0.1", paddedCIDR: "127.0.0.1/32"},
+		{inputCIDR: "fd00:10:96::/112", paddedCIDR: "fd00:10:96::/112"},
 	}
 
 	for _, test := range testCases {
@@ -18,13 +18,19 @@ package tunnel
 
 import (
 	"fmt"
+	"io/ioutil"
 	"net"
+	"os"
 	"os/exec"
 	"strings"
 
+	"github.com/pkg/errors"
 	"k8s.io/klog/v2"
 )
 
+// dnsmasqDir is where the dnsmasq instance of NetworkManager reads additional configuration from
+const dnsmasqDir = "/etc/NetworkManager/dnsmasq.d"
+
 func (router *osRouter) EnsureRouteIsAdded(route *Route) error {
 	exists, err := isValidToAddOrDelete(router, route)
 	if err != nil {
@@ -50,11 +56,18 @@ func (router *osRouter) EnsureRouteIsAdded(route *Route) error {
 		klog.Errorf("error adding Route: %s, %d", message, len(strings.Split(message, "\n")))
 		return err
 	}
+	if err := writeResolverFile(route); err != nil {
+		klog.Errorf("DNS forwarding unavailable: %v", err)
+	}
 	return nil
 }
 
 func (router *osRouter) Inspect(route *Route) (exists bool, conflict string, overlaps []string, err error) {
 	cmd := exec.Command("ip", "r")
+	// IPv6 routes are listed separately
+	if route.DestCIDR.IP.To4() == nil {
+		cmd = exec.Command("ip", "-6", "r")
+	}
 	cmd.Env = append(cmd.Env, "LC_ALL=C")
 	stdInAndOut, err := cmd.CombinedOutput()
 	if err != nil {
@@ -87,13 +100,17 @@ func (router *osRouter) parseTable(table []byte) routingTable {
 			gatewayIPString := fields[2]
 			gatewayIP := net.ParseIP(gatewayIPString)
 
-			// if not via format, then gateway is assumed to be 0.0.0.0
+			_, ipNet, err := net.ParseCIDR(dstCIDRString)
+
+			// if not via format, then gateway is assumed to be 0.0.0.0, or :: for IPv6 routes
 			// "1.2.3.0/24 dev eno1 proto kernel scope link src 1.2.3.54 metric 100"
 			if fields[1] != "via" {
 				gatewayIP = net.ParseIP("0.0.0.0")
+				if err == nil && ipNet.IP.To4() == nil {
+					gatewayIP = net.IPv6zero
+				}
 			}
 
-			_, ipNet, err := net.ParseCIDR(dstCIDRString)
 			if err != nil {
 				klog.V(4).Infof("skipping line: can't parse CIDR from routing table: %s", dstCIDRString)
 			} else if gatewayIP == nil {
@@ -126,6 +143,11 @@ func (router *osRouter) Cleanup(route *Route) error {
 	serviceCIDR := route.DestCIDR.String()
 	gatewayIP := route.Gateway.String()
 
+	// idempotent removal of cluster domain dns, while the interface of the route can still be looked up
+	if err := removeResolverFile(route); err != nil {
+		klog.Errorf("error removing DNS forwarding: %v", err)
+	}
+
 	klog.Infof("Cleaning up route for CIDR %s to gateway %s\n", serviceCIDR, gatewayIP)
 	command := exec.Command("sudo", "ip", "route", "delete", serviceCIDR)
 	stdInAndOut, err := command.CombinedOutput()
@@ -136,3 +158,125 @@ func (router *osRouter) Cleanup(route *Route) error {
 	}
 	return nil
 }
+
+// writeResolverFile forwards the DNS queries for the cluster domain to the cluster DNS, through the route.
+// systemd-resolved is configured if it is running, or else the dnsmasq instance of NetworkManager.
+func writeResolverFile(route *Route) error {
+	if route.ClusterDomain == "" || route.ClusterDNSIP == nil {
+		return nil
+	}
+
+	if resolvedActive() {
+		iface, err := routeInterface(route.Gateway)
+		if err != nil {
+			return err
+		}
+		klog.Infof("configuring systemd-resolved to forward %s to %s on %s", route.ClusterDomain, route.ClusterDNSIP, iface)
+		for _, args := range [][]string{
+			{"resolvectl", "dns", iface, route.ClusterDNSIP.String()},
+			{"resolvectl", "domain", iface, "~" + route.ClusterDomain},
+		} {
+			if err := runSudo(args...); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if _, err := os.Stat(dnsmasqDir); err != nil {
+		return errors.New("neither systemd-resolved nor the dnsmasq of NetworkManager is available")
+	}
+	resolverFile := dnsmasqFile(route)
+	content := fmt.Sprintf("server=/%s/%s\n", route.ClusterDomain, route.ClusterDNSIP)
+	klog.Infof("preparing DNS forwarding config in %q:\n%s", resolverFile, content)
+
+	tf, err := ioutil.TempFile("", "minikube-tunnel-resolver-")
+	if err != nil {
+		return errors.Wrap(err, "tempfile")
+	}
+	defer os.Remove(tf.Name())
+
+	if _, err = tf.WriteString(content); err != nil {
+		return errors.Wrap(err, "write")
+	}
+	if err = tf.Close(); err != nil {
+		return errors.Wrap(err, "close")
+	}
+	if err = os.Chmod(tf.Name(), 0644); err != nil {
+		return errors.Wrap(err, "chmod")
+	}
+	if err := runSudo("cp", "-fp", tf.Name(), resolverFile); err != nil {
+		return err
+	}
+	if err := runSudo("systemctl", "reload", "NetworkManager"); err != nil {
+		return err
+	}
+	klog.Infof("DNS forwarding now configured in %q", resolverFile)
+	return nil
+}
+
+// removeResolverFile removes the DNS forwarding configured by writeResolverFile, if any
+func removeResolverFile(route *Route) error {
+	if route.ClusterDomain == "" || route.ClusterDNSIP == nil {
+		return nil
+	}
+
+	if resolvedActive() {
+		iface, err := routeInterface(route.Gateway)
+		if err != nil {
+			return err
+		}
+		return runSudo("resolvectl", "revert", iface)
+	}
+
+	resolverFile := dnsmasqFile(route)
+	if _, err := os.Stat(resolverFile); err != nil {
+		return nil
+	}
+	if err := runSudo("rm", "-f", resolverFile); err != nil {
+		return err
+	}
+	return runSudo("systemctl", "reload", "NetworkManager")
+}
+
+func dnsmasqFile(route *Route) string {
+	return fmt.Sprintf("%s/minikube-%s.conf", dnsmasqDir, route.ClusterDomain)
+}
+
+// resolvedActive returns whether systemd-resolved manages the DNS of the host
+func resolvedActive() bool {
+	if _, err := exec.LookPath("resolvectl"); err != nil {
+		return false
+	}
+	return exec.Command("systemctl", "is-active", "--quiet", "systemd-resolved").Run() == nil
+}
+
+// routeInterface returns the network interface packets to gateway are sent through
+func routeInterface(gateway net.IP) (string, error) {
+	cmd := exec.Command("ip", "route", "get", gateway.String())
+	cmd.Env = append(cmd.Env, "LC_ALL=C")
+	stdout, err := cmd.Output()
+	if err != nil {
+		return "", errors.Wrapf(err, "running %v", cmd.Args)
+	}
+	// "192.168.39.47 dev virbr1 src 192.168.39.1 uid 1000"
+	fields := strings.Fields(string(stdout))
+	for i, f := range fields {
+		if f == "dev" && i+1 < len(fields) {
+			return fields[i+1], nil
+		}
+	}
+	return "", fmt.Errorf("no interface found in %q", stdout)
+}
+
+func runSudo(args ...string) error {
+	cmd := exec.Command("sudo", args...)
+	klog.Infof("About to run command: %s", cmd.Args)
+	if _, err := cmd.Output(); err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return fmt.Errorf("%q failed: %v: %q", strings.Join(cmd.Args, " "), exitErr, exitErr.Stderr)
+		}
+		return errors.Wrapf(err, "running %v", cmd.Args)
+	}
+	return nil
+}
@@ -121,6 +121,34 @@ func TestParseTable(t *testing.T) {
 	}
 }
 
+func TestParseTableIPv6(t *testing.T) {
+
+	const table = `fd00:10:96::/112 via fd00:192:168:49::2 dev br-8c6ea1c3c2a3 metric 1024 pref medium
+fd00:192:168:49::/64 dev br-8c6ea1c3c2a3 proto kernel metric 256 pref medium
+fe80::/64 dev eno1 proto kernel metric 256 pref medium
+default via fe80::1 dev eno1 proto ra metric 100 pref medium`
+
+	rt := (&osRouter{}).parseTable([]byte(table))
+
+	expectedRt := routingTable{
+		routingTableLine{
+			route: unsafeParseRoute("fd00:192:168:49::2", "fd00:10:96::/112"),
+			line:  "fd00:10:96::/112 via fd00:192:168:49::2 dev br-8c6ea1c3c2a3 metric 1024 pref medium",
+		},
+		routingTableLine{
+			route: unsafeParseRoute("::", "fd00:192:168:49::/64"),
+			line:  "fd00:192:168:49::/64 dev br-8c6ea1c3c2a3 proto kernel metric 256 pref medium",
+		},
+		routingTableLine{
+			route: unsafeParseRoute("::", "fe80::/64"),
+			line:  "fe80::/64 dev eno1 proto kernel metric 256 pref medium",
+		},
+	}
+	if !expectedRt.Equal(&rt) {
+		t.Errorf("expected:\n %s\ngot\n %s", expectedRt.String(), rt.String())
+	}
+}
+
 func addRoute(t *testing.T, cidr string, gw string) {
 	command := exec.Command("sudo", "ip", "route", "add", cidr, "via", gw)
 	sout, err := command.CombinedOutput()
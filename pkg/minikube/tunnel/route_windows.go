@@ -33,6 +33,9 @@ func (router *osRouter) EnsureRouteIsAdded(route *Route) error {
 	if exists {
 		return nil
 	}
+	if route.DestCIDR.IP.To4() == nil {
+		return fmt.Errorf("IPv6 routes are not supported on Windows: %s", route.DestCIDR)
+	}
 
 	serviceCIDR := route.DestCIDR.String()
 	destinationIP := route.DestCIDR.IP.String()
@@ -21,6 +21,7 @@ import (
 	"os"
 
 	"os/exec"
+	"reflect"
 	"regexp"
 
 	"github.com/docker/machine/libmachine"
@@ -30,6 +31,7 @@ import (
 	"k8s.io/klog/v2"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/driver"
+	"k8s.io/minikube/pkg/minikube/metrics"
 )
 
 // tunnel represents the basic API for a tunnel: periodically the state of the tunnel
@@ -89,6 +91,7 @@ type tunnel struct {
 	clusterInspector     *clusterInspector
 	router               router
 	LoadBalancerEmulator LoadBalancerEmulator
+	ingressEmulator      *IngressEmulator
 	reporter             reporter
 	registry             *persistentRegistry
 
@@ -102,6 +105,7 @@ func (t *tunnel) cleanup() *Status {
 		t.status.RouteError = errors.Errorf("error cleaning up route: %v", err)
 		klog.V(3).Infof(t.status.RouteError.Error())
 	} else {
+		metrics.TunnelRoutesRemoved.Inc()
 		err = t.registry.Remove(t.status.TunnelID.Route)
 		if err != nil {
 			klog.V(3).Infof("error removing route from registry: %v", err)
@@ -109,6 +113,11 @@ func (t *tunnel) cleanup() *Status {
 	}
 	if t.status.MinikubeState == Running {
 		t.status.PatchedServices, t.status.LoadBalancerEmulatorError = t.LoadBalancerEmulator.Cleanup()
+		metrics.TunnelPatchedServices.Set(0)
+	}
+	// the hosts file is cleaned up even if the cluster is not running
+	if t.ingressEmulator != nil {
+		t.status.PatchedIngresses, t.status.IngressEmulatorError = t.ingressEmulator.Cleanup()
 	}
 	return t.status
 }
@@ -122,7 +131,15 @@ func (t *tunnel) update() *Status {
 		klog.V(3).Infof("minikube is running, trying to add route%s", t.status.TunnelID.Route)
 		setupRoute(t, h)
 		if t.status.RouteError == nil {
+			services, ingresses := t.status.PatchedServices, t.status.PatchedIngresses
 			t.status.PatchedServices, t.status.LoadBalancerEmulatorError = t.LoadBalancerEmulator.PatchServices()
+			metrics.TunnelPatchedServices.Set(float64(len(t.status.PatchedServices)))
+			if t.ingressEmulator != nil {
+				t.status.PatchedIngresses, t.status.IngressEmulatorError = t.ingressEmulator.PatchIngresses(t.status.TunnelID.Route.Gateway)
+			}
+			if !reflect.DeepEqual(services, t.status.PatchedServices) || !reflect.DeepEqual(ingresses, t.status.PatchedIngresses) {
+				registerPatched(t)
+			}
 		}
 	}
 	klog.V(3).Infof("sending report %s", t.status)
@@ -130,6 +147,16 @@ func (t *tunnel) update() *Status {
 	return t.status
 }
 
+// registerPatched records the services and ingresses patched by the tunnel in the registry, for minikube tunnel status
+func registerPatched(t *tunnel) {
+	id := t.status.TunnelID
+	id.Services = t.status.PatchedServices
+	id.Ingresses = t.status.PatchedIngresses
+	if err := t.registry.Register(&id); err != nil {
+		klog.Warningf("failed to update tunnel in registry: %v", err)
+	}
+}
+
 func setupRoute(t *tunnel, h *host.Host) {
 	exists, conflict, _, err := t.router.Inspect(t.status.TunnelID.Route)
 	if err != nil {
@@ -142,6 +169,7 @@ func setupRoute(t *tunnel, h *host.Host) {
 		if t.status.RouteError != nil {
 			return
 		}
+		metrics.TunnelRoutesAdded.Inc()
 		// the route was added successfully, we need to make sure the registry has it too
 		// this might fail in race conditions, when another process created this tunnel
 		if err := t.registry.Register(&t.status.TunnelID); err != nil {
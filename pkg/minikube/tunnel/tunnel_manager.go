@@ -24,10 +24,12 @@ import (
 	"fmt"
 
 	"github.com/docker/machine/libmachine"
+	"github.com/pkg/errors"
 	typed_core "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/klog/v2"
 	"k8s.io/minikube/pkg/minikube/config"
 	"k8s.io/minikube/pkg/minikube/localpath"
+	"k8s.io/minikube/pkg/minikube/metrics"
 )
 
 // Manager can create, start and cleanup a tunnel
@@ -58,12 +60,13 @@ func NewManager() *Manager {
 	}
 }
 
-// StartTunnel starts the tunnel
-func (mgr *Manager) StartTunnel(ctx context.Context, machineName string, machineAPI libmachine.API, configLoader config.Loader, v1Core typed_core.CoreV1Interface) (done chan bool, err error) {
+// StartTunnel starts the tunnel. Ingresses are only emulated if ingress is not nil.
+func (mgr *Manager) StartTunnel(ctx context.Context, machineName string, machineAPI libmachine.API, configLoader config.Loader, v1Core typed_core.CoreV1Interface, ingress *IngressEmulator) (done chan bool, err error) {
 	tunnel, err := newTunnel(machineName, machineAPI, configLoader, v1Core, mgr.registry, mgr.router)
 	if err != nil {
 		return nil, fmt.Errorf("error creating tunnel: %s", err)
 	}
+	tunnel.ingressEmulator = ingress
 	return mgr.startTunnel(ctx, tunnel)
 
 }
@@ -140,10 +143,17 @@ func (mgr *Manager) CleanupNotRunningTunnels() error {
 			return fmt.Errorf("error checking if tunnel is running: %s", err)
 		}
 		if !isRunning {
+			if tunnel.Route == nil {
+				if err := mgr.registry.RemoveID(tunnel); err != nil {
+					return err
+				}
+				continue
+			}
 			err = mgr.router.Cleanup(tunnel.Route)
 			if err != nil {
 				return err
 			}
+			metrics.TunnelRoutesRemoved.Inc()
 			err = mgr.registry.Remove(tunnel.Route)
 			if err != nil {
 				return err
@@ -152,3 +162,54 @@ func (mgr *Manager) CleanupNotRunningTunnels() error {
 	}
 	return nil
 }
+
+// List returns the running tunnels
+func (mgr *Manager) List() ([]*ID, error) {
+	tunnels, err := mgr.registry.List()
+	if err != nil {
+		return nil, fmt.Errorf("error listing tunnels from registry: %s", err)
+	}
+	var running []*ID
+	for _, t := range tunnels {
+		isRunning, err := checkIfRunning(t.Pid)
+		if err != nil {
+			return nil, fmt.Errorf("error checking if tunnel is running: %s", err)
+		}
+		if isRunning {
+			running = append(running, t)
+		}
+	}
+	return running, nil
+}
+
+// Register registers a tunnel which is not started by the manager, such as the ssh tunnel of the kic drivers, or updates it
+func (mgr *Manager) Register(id *ID) error {
+	return mgr.registry.Register(id)
+}
+
+// Unregister removes a tunnel which is not started by the manager from the registry
+func (mgr *Manager) Unregister(id *ID) error {
+	return mgr.registry.RemoveID(id)
+}
+
+// StopTunnels stops the running tunnels of a machine, and returns them. The tunnels are interrupted, so that they
+// clean up after themselves, and are killed if they cannot be interrupted or do not exit within timeout.
+func (mgr *Manager) StopTunnels(machineName string, timeout time.Duration) ([]*ID, error) {
+	tunnels, err := mgr.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var stopped []*ID
+	for _, t := range tunnels {
+		if t.MachineName != machineName {
+			continue
+		}
+		if err := stopProcess(t.Pid, timeout); err != nil {
+			return stopped, errors.Wrapf(err, "stopping tunnel %d", t.Pid)
+		}
+		stopped = append(stopped, t)
+	}
+	// the routes of killed tunnels are left behind
+	return stopped, mgr.CleanupNotRunningTunnels()
+}
@@ -34,6 +34,9 @@ type Status struct {
 
 	PatchedServices           []string
 	LoadBalancerEmulatorError error
+
+	PatchedIngresses     []string
+	IngressEmulatorError error
 }
 
 // Clone clones an existing Status
@@ -45,18 +48,22 @@ func (t *Status) Clone() *Status {
 		RouteError:                t.RouteError,
 		PatchedServices:           t.PatchedServices,
 		LoadBalancerEmulatorError: t.LoadBalancerEmulatorError,
+		PatchedIngresses:          t.PatchedIngresses,
+		IngressEmulatorError:      t.IngressEmulatorError,
 	}
 }
 
 func (t *Status) String() string {
-	return fmt.Sprintf("id(%v), minikube(%s, e:%s), route(%s, e:%s), services(%s, e:%s)",
+	return fmt.Sprintf("id(%v), minikube(%s, e:%s), route(%s, e:%s), services(%s, e:%s), ingresses(%s, e:%s)",
 		t.TunnelID,
 		t.MinikubeState,
 		t.MinikubeError,
 		t.TunnelID.Route,
 		t.RouteError,
 		t.PatchedServices,
-		t.LoadBalancerEmulatorError)
+		t.LoadBalancerEmulatorError,
+		t.PatchedIngresses,
+		t.IngressEmulatorError)
 }
 
 // Route represents a route
@@ -73,7 +80,7 @@ func (r *Route) String() string {
 
 // Equal checks if two routes are equal
 func (r *Route) Equal(other *Route) bool {
-	return other != nil && r.DestCIDR.IP.Equal(other.DestCIDR.IP) &&
+	return r != nil && other != nil && r.DestCIDR.IP.Equal(other.DestCIDR.IP) &&
 		r.DestCIDR.Mask.String() == other.DestCIDR.Mask.String() &&
 		r.Gateway.Equal(other.Gateway)
 }
@@ -18,6 +18,7 @@ package util
 
 import (
 	"net"
+	"strings"
 
 	"github.com/pkg/errors"
 )
@@ -44,25 +45,35 @@ var DefaultV114AdmissionControllers = []string{
 // DefaultLegacyAdmissionControllers are admission controllers we include with Kubernetes <1.14.0
 var DefaultLegacyAdmissionControllers = append([]string{"Initializers"}, DefaultV114AdmissionControllers...)
 
-// GetServiceClusterIP returns the first IP of the ServiceCIDR
+// GetServiceClusterIP returns the first IP of the ServiceCIDR, or of its first CIDR for dual-stack clusters
 func GetServiceClusterIP(serviceCIDR string) (net.IP, error) {
-	ip, _, err := net.ParseCIDR(serviceCIDR)
+	ip, err := serviceCIDRIP(serviceCIDR)
 	if err != nil {
-		return nil, errors.Wrap(err, "parsing default service cidr")
+		return nil, err
 	}
-	ip = ip.To4()
-	ip[3]++
+	ip[len(ip)-1]++
 	return ip, nil
 }
 
-// GetDNSIP returns x.x.x.10 of the service CIDR
+// GetDNSIP returns x.x.x.10 of the service CIDR, or of its first CIDR for dual-stack clusters
 func GetDNSIP(serviceCIDR string) (net.IP, error) {
-	ip, _, err := net.ParseCIDR(serviceCIDR)
+	ip, err := serviceCIDRIP(serviceCIDR)
+	if err != nil {
+		return nil, err
+	}
+	ip[len(ip)-1] = 10
+	return ip, nil
+}
+
+// serviceCIDRIP returns the network IP of the primary CIDR of a comma separated service CIDR
+func serviceCIDRIP(serviceCIDR string) (net.IP, error) {
+	ip, _, err := net.ParseCIDR(strings.TrimSpace(strings.Split(serviceCIDR, ",")[0]))
 	if err != nil {
 		return nil, errors.Wrap(err, "parsing default service cidr")
 	}
-	ip = ip.To4()
-	ip[3] = 10
+	if ip4 := ip.To4(); ip4 != nil {
+		return ip4, nil
+	}
 	return ip, nil
 }
 
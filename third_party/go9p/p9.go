@@ -109,6 +109,7 @@ const (
 	EEXIST  = 17
 	ENOTDIR = 20
 	EINVAL  = 22
+	EROFS   = 30
 )
 
 // Error represents a 9P2000 (and 9P2000.u) error
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"log"
 	"net"
+	"time"
 )
 
 func (srv *Srv) NewConn(c net.Conn) {
@@ -122,6 +123,7 @@ func (conn *Conn) recv() {
 
 			req.Conn = conn
 			req.Tc = fc
+			req.start = time.Now()
 			//			req.Rc = rc
 			if conn.Debuglevel > 0 {
 				conn.logFcall(req.Tc)
@@ -141,6 +143,12 @@ func (conn *Conn) recv() {
 			if conn.npend > conn.maxpend {
 				conn.maxpend = conn.npend
 			}
+			switch fc.Type {
+			case Tread:
+				conn.nreads++
+			case Twrite:
+				conn.nwrites++
+			}
 
 			req.next = conn.reqs[tag]
 			conn.reqs[tag] = req
@@ -180,6 +188,9 @@ func (conn *Conn) send() {
 			conn.rsz += uint64(req.Rc.Size)
 			conn.npend--
 			conn.Unlock()
+			if conn.Srv.Observer != nil {
+				conn.Srv.Observer.ObserveRequest(opName(req.Tc.Type), req.Tc.Size, req.Rc.Size, time.Since(req.start))
+			}
 			if conn.Debuglevel > 0 {
 				conn.logFcall(req.Rc)
 				if conn.Debuglevel&DbgPrintPackets != 0 {
@@ -255,6 +266,12 @@ func (srv *Srv) StartListener(l net.Listener) error {
 			return &Error{err.Error(), EIO}
 		}
 
+		if !srv.Accepts(c.RemoteAddr()) {
+			log.Println("refused connection from", c.RemoteAddr())
+			c.Close()
+			continue
+		}
+
 		srv.NewConn(c)
 	}
 }
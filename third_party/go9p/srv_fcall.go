@@ -111,6 +111,7 @@ func (srv *Srv) attach(req *SrvReq) {
 		req.Afid = conn.FidGet(tc.Afid)
 		if req.Afid == nil {
 			req.RespondError(Eunknownfid)
+			return
 		}
 	}
 
@@ -10,6 +10,7 @@ import (
 	"net"
 	"runtime"
 	"sync"
+	"time"
 )
 
 type reqStatus int
@@ -33,6 +34,7 @@ var Ebadoffset error = &Error{"bad offset in directory read", EINVAL}
 var Edirchange error = &Error{"cannot convert between files and directories", EINVAL}
 var Enouser error = &Error{"unknown user", EINVAL}
 var Enotimpl error = &Error{"not implemented", EINVAL}
+var Erofs error = &Error{"read-only file system", EROFS}
 
 // Authentication operations. The file server should implement them if
 // it requires user authentication. The authentication in 9P2000 is
@@ -123,6 +125,9 @@ type Srv struct {
 	Upool      Users  // Interface for finding users and groups known to the file server
 	Maxpend    int    // Maximum pending outgoing requests
 	Log        *Logger
+	Observer   StatsObserver // If set, notified of each request served
+	// If not empty, only the connections from these addresses are accepted
+	AllowedHosts []net.IP
 
 	ops   interface{}     // operations
 	conns map[*Conn]*Conn // List of connections
@@ -189,6 +194,7 @@ type SrvReq struct {
 	Conn   *Conn   // Connection that the request belongs to
 
 	status     reqStatus
+	start      time.Time
 	flushreq   *SrvReq
 	prev, next *SrvReq
 }
@@ -224,6 +230,43 @@ func (srv *Srv) Start(ops interface{}) bool {
 	return true
 }
 
+// Accepts tells if a connection from addr is accepted: its IP is one of
+// AllowedHosts, or AllowedHosts is empty
+func (srv *Srv) Accepts(addr net.Addr) bool {
+	srv.Lock()
+	hosts := srv.AllowedHosts
+	srv.Unlock()
+	if len(hosts) == 0 {
+		return true
+	}
+
+	var ip net.IP
+	switch a := addr.(type) {
+	case *net.TCPAddr:
+		ip = a.IP
+	case *net.UDPAddr:
+		ip = a.IP
+	case *net.IPAddr:
+		ip = a.IP
+	default:
+		return false
+	}
+
+	for _, h := range hosts {
+		if h.Equal(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// SetAllowedHosts replaces AllowedHosts while the server runs
+func (srv *Srv) SetAllowedHosts(hosts []net.IP) {
+	srv.Lock()
+	srv.AllowedHosts = hosts
+	srv.Unlock()
+}
+
 func (srv *Srv) String() string {
 	return srv.Id
 }
@@ -1,6 +1,37 @@
 package go9p
 
+import "time"
+
 type StatsOps interface {
 	statsRegister()
 	statsUnregister()
 }
+
+// StatsObserver is notified of each request served by a Srv, e.g. to export
+// metrics for it. Op is the name of the request, such as "read" for Tread.
+type StatsObserver interface {
+	ObserveRequest(op string, tsize, rsize uint32, latency time.Duration)
+}
+
+var opNames = map[uint8]string{
+	Tversion: "version",
+	Tauth:    "auth",
+	Tattach:  "attach",
+	Tflush:   "flush",
+	Twalk:    "walk",
+	Topen:    "open",
+	Tcreate:  "create",
+	Tread:    "read",
+	Twrite:   "write",
+	Tclunk:   "clunk",
+	Tremove:  "remove",
+	Tstat:    "stat",
+	Twstat:   "wstat",
+}
+
+func opName(t uint8) string {
+	if name, ok := opNames[t]; ok {
+		return name
+	}
+	return "unknown"
+}
@@ -12,6 +12,7 @@ import (
 	"path"
 	"sort"
 	"strconv"
+	"strings"
 	"syscall"
 )
 
@@ -28,6 +29,25 @@ type ufsFid struct {
 type Ufs struct {
 	Srv
 	Root string
+	// ReadOnly refuses the requests which modify files
+	ReadOnly bool
+	// Allow lists the paths, relative to Root, which are exported along with
+	// the directories leading to them. All of Root is exported if it is empty.
+	Allow []string
+	// Owner, unless nil, is reported as the owner of every file, and is the
+	// only owner files may be given. Files keep the owner of the server.
+	Owner *Owner
+	// Umask clears permission bits of the files reported, created and chmoded
+	Umask uint32
+	// Token, unless empty, must be written to an authentication fid, or
+	// start the aname, for clients to attach
+	Token string
+}
+
+// Owner is a numeric user and group
+type Owner struct {
+	Uid uint32
+	Gid uint32
 }
 
 func toError(err error) *Error {
@@ -130,6 +150,208 @@ func dir2Npmode(d os.FileInfo, dotu bool) uint32 {
 	return ret
 }
 
+// rel returns the path of p relative to the root, and whether p is within the root
+func (ufs *Ufs) rel(p string) (string, bool) {
+	root := path.Clean(ufs.Root)
+	p = path.Clean(p)
+	if p == root {
+		return "", true
+	}
+	prefix := strings.TrimSuffix(root, "/") + "/"
+	if !strings.HasPrefix(p, prefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(p, prefix), true
+}
+
+// exported tells if p is within an allowed path
+func (ufs *Ufs) exported(p string) bool {
+	rel, ok := ufs.rel(p)
+	if !ok {
+		return false
+	}
+	if len(ufs.Allow) == 0 {
+		return true
+	}
+	for _, a := range ufs.Allow {
+		a = strings.Trim(path.Clean("/"+a), "/")
+		if a == "" || rel == a || strings.HasPrefix(rel, a+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// visible tells if p may be walked to: it is exported, or leads to an
+// allowed path
+func (ufs *Ufs) visible(p string) bool {
+	if ufs.exported(p) {
+		return true
+	}
+	rel, ok := ufs.rel(p)
+	if !ok {
+		return false
+	}
+	for _, a := range ufs.Allow {
+		a = strings.Trim(path.Clean("/"+a), "/")
+		if rel == "" || strings.HasPrefix(a, rel+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// writable returns the error which refuses changes to p, if any
+func (ufs *Ufs) writable(p string) error {
+	if ufs.ReadOnly {
+		return Erofs
+	}
+	if !ufs.exported(p) {
+		return Eperm
+	}
+	return nil
+}
+
+// walkName returns the path which a walk from dir, whose stat is st, to
+// name leads to, and whether it may be walked to
+func (ufs *Ufs) walkName(dir string, st os.FileInfo, name string) (string, bool) {
+	// walking through a symlink would follow it, possibly out of the root
+	if !st.IsDir() {
+		return "", false
+	}
+	var p string
+	switch {
+	case name == ".":
+		p = dir
+	case name == "..":
+		// the parent of the root is the root itself
+		if rel, _ := ufs.rel(dir); rel == "" {
+			p = dir
+		} else {
+			p = path.Dir(dir)
+		}
+	case !validName(name):
+		return "", false
+	default:
+		p = dir + "/" + name
+	}
+	return p, ufs.visible(p)
+}
+
+// resolve walks from the root to p, relative to it, one name at a time,
+// and returns the path it leads to and its stat. Like walks, it never goes
+// through symlinks.
+func (ufs *Ufs) resolve(p string) (string, os.FileInfo, *Error) {
+	dir := path.Clean(ufs.Root)
+	st, err := os.Stat(dir)
+	if err != nil {
+		return "", nil, toError(err)
+	}
+	for _, name := range strings.Split(p, "/") {
+		if name == "" {
+			continue
+		}
+		next, ok := ufs.walkName(dir, st, name)
+		if !ok {
+			return "", nil, Enoent
+		}
+		if st, err = os.Lstat(next); err != nil {
+			return "", nil, toError(err)
+		}
+		dir = next
+	}
+	return dir, st, nil
+}
+
+// validName tells if name is the name of a file of a directory
+func validName(name string) bool {
+	if name == "" || name == "." || name == ".." {
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		if os.IsPathSeparator(name[i]) || name[i] == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// mapDir maps the owner and the permissions of a stat
+func (ufs *Ufs) mapDir(d *Dir) {
+	d.Mode &^= ufs.Umask & 0777
+	if ufs.Owner != nil {
+		d.Uidnum = ufs.Owner.Uid
+		d.Gidnum = ufs.Owner.Gid
+		d.Uid = strconv.FormatUint(uint64(ufs.Owner.Uid), 10)
+		d.Gid = strconv.FormatUint(uint64(ufs.Owner.Gid), 10)
+	}
+}
+
+// wstatAllowed checks a wstat request against the export options, and
+// adjusts it to them. If it is refused, it responds with an error, and
+// returns false.
+func (ufs *Ufs) wstatAllowed(req *SrvReq, fid *ufsFid) bool {
+	dir := &req.Tc.Dir
+	if err := ufs.writable(fid.path); err != nil {
+		req.RespondError(err)
+		return false
+	}
+
+	// chmod, truncate and chtimes follow symlinks, possibly out of the root
+	if fid.st.Mode()&os.ModeSymlink != 0 && (dir.Mode != 0xFFFFFFFF || dir.Length != 0xFFFFFFFFFFFFFFFF || dir.Mtime != ^uint32(0) || dir.Atime != ^uint32(0)) {
+		req.RespondError(Eperm)
+		return false
+	}
+	if dir.Mode != 0xFFFFFFFF {
+		dir.Mode &^= ufs.Umask & 0777
+	}
+
+	if ufs.Owner != nil {
+		uid, gid := strconv.FormatUint(uint64(ufs.Owner.Uid), 10), strconv.FormatUint(uint64(ufs.Owner.Gid), 10)
+		if req.Conn.Dotu && ((dir.Uidnum != NOUID && dir.Uidnum != ufs.Owner.Uid) || (dir.Gidnum != NOUID && dir.Gidnum != ufs.Owner.Gid)) ||
+			!req.Conn.Dotu && ((dir.Uid != "" && dir.Uid != uid) || (dir.Gid != "" && dir.Gid != gid)) {
+			req.RespondError(Eperm)
+			return false
+		}
+		dir.Uidnum, dir.Gidnum = NOUID, NOUID
+		dir.Uid, dir.Gid = "", ""
+	}
+
+	if dir.Name != "" {
+		// as computed by Wstat, whose destination directory must not be
+		// reached through symlinks
+		var dest string
+		destdir, name := path.Split(dir.Name)
+		if !validName(name) {
+			req.RespondError(Eperm)
+			return false
+		}
+		if dir.Name[0] == '/' {
+			p, st, err := ufs.resolve(destdir)
+			if err != nil {
+				req.RespondError(err)
+				return false
+			}
+			if !st.IsDir() {
+				req.RespondError(Enotdir)
+				return false
+			}
+			dest = p + "/" + name
+		} else if destdir != "" {
+			req.RespondError(Eperm)
+			return false
+		} else {
+			fiddir, _ := path.Split(fid.path)
+			dest = path.Join(fiddir, dir.Name)
+		}
+		if err := ufs.writable(dest); err != nil {
+			req.RespondError(err)
+			return false
+		}
+	}
+	return true
+}
+
 // Dir is an instantiation of the p.Dir structure
 // that can act as a receiver for local methods.
 type ufsDir struct {
@@ -149,33 +371,45 @@ func (*Ufs) ConnClosed(conn *Conn) {
 }
 
 func (*Ufs) FidDestroy(sfid *SrvFid) {
-	var fid *ufsFid
-
-	if sfid.Aux == nil {
+	// authentication fids have no file
+	fid, ok := sfid.Aux.(*ufsFid)
+	if !ok {
 		return
 	}
 
-	fid = sfid.Aux.(*ufsFid)
 	if fid.file != nil {
 		fid.file.Close()
 	}
 }
 
 func (ufs *Ufs) Attach(req *SrvReq) {
-	if req.Afid != nil {
+	if req.Afid != nil && ufs.Token == "" {
 		req.RespondError(Enoauth)
 		return
 	}
 
 	tc := req.Tc
+	aname := tc.Aname
+	if p, ok := ufs.anamePath(aname); ok {
+		aname = p
+	}
 	fid := new(ufsFid)
 	// You can think of the ufs.Root as a 'chroot' of a sort.
 	// clients attach are not allowed to go outside the
 	// directory represented by ufs.Root
-	fid.path = path.Join(ufs.Root, tc.Aname)
+	p, st, err := ufs.resolve(aname)
+	if err != nil {
+		req.RespondError(err)
+		return
+	}
+	if !st.IsDir() {
+		req.RespondError(Enotdir)
+		return
+	}
+	fid.path = p
 
 	req.Fid.Aux = fid
-	err := fid.stat()
+	err = fid.stat()
 	if err != nil {
 		req.RespondError(err)
 		return
@@ -187,7 +421,7 @@ func (ufs *Ufs) Attach(req *SrvReq) {
 
 func (*Ufs) Flush(req *SrvReq) {}
 
-func (*Ufs) Walk(req *SrvReq) {
+func (ufs *Ufs) Walk(req *SrvReq) {
 	fid := req.Fid.Aux.(*ufsFid)
 	tc := req.Tc
 
@@ -204,11 +438,15 @@ func (*Ufs) Walk(req *SrvReq) {
 	nfid := req.Newfid.Aux.(*ufsFid)
 	wqids := make([]Qid, len(tc.Wname))
 	path := fid.path
+	st := fid.st
 	i := 0
 	for ; i < len(tc.Wname); i++ {
-		p := path + "/" + tc.Wname[i]
-		st, err := os.Lstat(p)
-		if err != nil {
+		p, ok := ufs.walkName(path, st, tc.Wname[i])
+		var err error
+		if ok {
+			st, err = os.Lstat(p)
+		}
+		if !ok || err != nil {
 			if i == 0 {
 				req.RespondError(Enoent)
 				return
@@ -225,7 +463,7 @@ func (*Ufs) Walk(req *SrvReq) {
 	req.RespondRwalk(wqids[0:i])
 }
 
-func (*Ufs) Open(req *SrvReq) {
+func (ufs *Ufs) Open(req *SrvReq) {
 	fid := req.Fid.Aux.(*ufsFid)
 	tc := req.Tc
 	err := fid.stat()
@@ -234,8 +472,20 @@ func (*Ufs) Open(req *SrvReq) {
 		return
 	}
 
+	// opening a symlink would follow it, possibly out of the root
+	if fid.st.Mode()&os.ModeSymlink != 0 {
+		req.RespondError(Eperm)
+		return
+	}
+	if tc.Mode&3 == OWRITE || tc.Mode&3 == ORDWR || tc.Mode&(OTRUNC|ORCLOSE) != 0 {
+		if e := ufs.writable(fid.path); e != nil {
+			req.RespondError(e)
+			return
+		}
+	}
+
 	var e error
-	fid.file, e = os.OpenFile(fid.path, omode2uflags(tc.Mode), 0)
+	fid.file, e = os.OpenFile(fid.path, omode2uflags(tc.Mode)|oNoFollow, 0)
 	if e != nil {
 		req.RespondError(toError(e))
 		return
@@ -244,7 +494,7 @@ func (*Ufs) Open(req *SrvReq) {
 	req.RespondRopen(dir2Qid(fid.st), 0)
 }
 
-func (*Ufs) Create(req *SrvReq) {
+func (ufs *Ufs) Create(req *SrvReq) {
 	fid := req.Fid.Aux.(*ufsFid)
 	tc := req.Tc
 	err := fid.stat()
@@ -252,13 +502,22 @@ func (*Ufs) Create(req *SrvReq) {
 		req.RespondError(err)
 		return
 	}
+	if !fid.st.IsDir() || !validName(tc.Name) {
+		req.RespondError(Eperm)
+		return
+	}
 
 	path := fid.path + "/" + tc.Name
+	if e := ufs.writable(path); e != nil {
+		req.RespondError(e)
+		return
+	}
+	perm := tc.Perm &^ (ufs.Umask & 0777)
 	var e error = nil
 	var file *os.File = nil
 	switch {
 	case tc.Perm&DMDIR != 0:
-		e = os.Mkdir(path, os.FileMode(tc.Perm&0777))
+		e = os.Mkdir(path, os.FileMode(perm&0777))
 
 	case tc.Perm&DMSYMLINK != 0:
 		e = os.Symlink(tc.Ext, path)
@@ -284,7 +543,7 @@ func (*Ufs) Create(req *SrvReq) {
 		return
 
 	default:
-		var mode uint32 = tc.Perm & 0777
+		var mode uint32 = perm & 0777
 		if req.Conn.Dotu {
 			if tc.Perm&DMSETUID > 0 {
 				mode |= syscall.S_ISUID
@@ -293,11 +552,17 @@ func (*Ufs) Create(req *SrvReq) {
 				mode |= syscall.S_ISGID
 			}
 		}
-		file, e = os.OpenFile(path, omode2uflags(tc.Mode)|os.O_CREATE, os.FileMode(mode))
+		// a file, or a symlink, of the same name is not opened, so that it
+		// is not truncated, or followed out of the root
+		file, e = os.OpenFile(path, omode2uflags(tc.Mode)|os.O_CREATE|os.O_EXCL|oNoFollow, os.FileMode(mode))
 	}
 
+	// symlinks, and hard links to symlinks, are not followed
 	if file == nil && e == nil {
-		file, e = os.OpenFile(path, omode2uflags(tc.Mode), 0)
+		var st os.FileInfo
+		if st, e = os.Lstat(path); e == nil && st.Mode()&os.ModeSymlink == 0 {
+			file, e = os.OpenFile(path, omode2uflags(tc.Mode)|oNoFollow, 0)
+		}
 	}
 
 	if e != nil {
@@ -316,7 +581,7 @@ func (*Ufs) Create(req *SrvReq) {
 	req.RespondRcreate(dir2Qid(fid.st), 0)
 }
 
-func (*Ufs) Read(req *SrvReq) {
+func (ufs *Ufs) Read(req *SrvReq) {
 	fid := req.Fid.Aux.(*ufsFid)
 	tc := req.Tc
 	rc := req.Rc
@@ -349,10 +614,14 @@ func (*Ufs) Read(req *SrvReq) {
 			fid.direntends = nil
 			for i := 0; i < len(fid.dirs); i++ {
 				path := fid.path + "/" + fid.dirs[i].Name()
+				if !ufs.visible(path) {
+					continue
+				}
 				st, _ := dir2Dir(path, fid.dirs[i], req.Conn.Dotu, req.Conn.Srv.Upool)
 				if st == nil {
 					continue
 				}
+				ufs.mapDir(st)
 				b := PackDir(st, req.Conn.Dotu)
 				fid.dirents = append(fid.dirents, b...)
 				count += len(b)
@@ -401,7 +670,7 @@ func (*Ufs) Read(req *SrvReq) {
 	req.Respond()
 }
 
-func (*Ufs) Write(req *SrvReq) {
+func (ufs *Ufs) Write(req *SrvReq) {
 	fid := req.Fid.Aux.(*ufsFid)
 	tc := req.Tc
 	err := fid.stat()
@@ -409,6 +678,10 @@ func (*Ufs) Write(req *SrvReq) {
 		req.RespondError(err)
 		return
 	}
+	if e := ufs.writable(fid.path); e != nil {
+		req.RespondError(e)
+		return
+	}
 
 	n, e := fid.file.WriteAt(tc.Data, int64(tc.Offset))
 	if e != nil {
@@ -421,13 +694,17 @@ func (*Ufs) Write(req *SrvReq) {
 
 func (*Ufs) Clunk(req *SrvReq) { req.RespondRclunk() }
 
-func (*Ufs) Remove(req *SrvReq) {
+func (ufs *Ufs) Remove(req *SrvReq) {
 	fid := req.Fid.Aux.(*ufsFid)
 	err := fid.stat()
 	if err != nil {
 		req.RespondError(err)
 		return
 	}
+	if e := ufs.writable(fid.path); e != nil {
+		req.RespondError(e)
+		return
+	}
 
 	e := os.Remove(fid.path)
 	if e != nil {
@@ -438,7 +715,7 @@ func (*Ufs) Remove(req *SrvReq) {
 	req.RespondRremove()
 }
 
-func (*Ufs) Stat(req *SrvReq) {
+func (ufs *Ufs) Stat(req *SrvReq) {
 	fid := req.Fid.Aux.(*ufsFid)
 	err := fid.stat()
 	if err != nil {
@@ -451,6 +728,7 @@ func (*Ufs) Stat(req *SrvReq) {
 		req.RespondError(derr)
 		return
 	}
+	ufs.mapDir(st)
 
 	req.RespondRstat(st)
 }
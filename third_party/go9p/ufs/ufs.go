@@ -7,17 +7,50 @@ package ufs
 import (
 	"fmt"
 	"log"
+	"net"
 
 	"k8s.io/minikube/third_party/go9p"
 )
 
-func StartServer(addrVal string, debugVal int, rootVal string) {
+// Options restrict what a server exports, and how
+type Options struct {
+	// ReadOnly refuses the requests which modify files
+	ReadOnly bool
+	// Allow lists the paths, relative to the root, which are exported. All of the root is exported if it is empty.
+	Allow []string
+	// Owner, unless nil, is reported as the owner of every file
+	Owner *go9p.Owner
+	// Umask clears permission bits of the files reported, created and chmoded
+	Umask uint32
+	// AllowedHosts, unless empty, are the only addresses connections are accepted from
+	AllowedHosts []net.IP
+	// HostUpdates, unless nil, replace AllowedHosts with each list received
+	HostUpdates <-chan []net.IP
+	// Token, unless empty, is required to attach, as the start of the aname or through authentication
+	Token string
+}
+
+func StartServer(addrVal string, debugVal int, rootVal string, observer go9p.StatsObserver, opts Options) {
 	ufs := new(go9p.Ufs)
 	ufs.Dotu = true
 	ufs.Id = "ufs"
 	ufs.Root = rootVal
+	ufs.ReadOnly = opts.ReadOnly
+	ufs.Allow = opts.Allow
+	ufs.Owner = opts.Owner
+	ufs.Umask = opts.Umask
+	ufs.AllowedHosts = opts.AllowedHosts
+	ufs.Token = opts.Token
 	ufs.Debuglevel = debugVal
+	ufs.Observer = observer
 	ufs.Start(ufs)
+	if opts.HostUpdates != nil {
+		go func() {
+			for hosts := range opts.HostUpdates {
+				ufs.SetAllowedHosts(hosts)
+			}
+		}()
+	}
 
 	fmt.Print("ufs starting\n")
 	// determined by build tags
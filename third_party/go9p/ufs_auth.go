@@ -0,0 +1,88 @@
+// Copyright 2009 The Go9p Authors.  All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package go9p
+
+import (
+	"crypto/subtle"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// ufsAuth is the state of an authentication fid: the token written to it
+type ufsAuth struct {
+	sync.Mutex
+	token []byte
+}
+
+var authPath uint64
+
+// AuthInit creates an authentication fid, to which the client writes the
+// token. It refuses to if the server does not require a token.
+func (ufs *Ufs) AuthInit(afid *SrvFid, aname string) (*Qid, error) {
+	if ufs.Token == "" {
+		return nil, Enoauth
+	}
+	afid.Aux = new(ufsAuth)
+	return &Qid{Type: QTAUTH, Path: atomic.AddUint64(&authPath, 1)}, nil
+}
+
+func (*Ufs) AuthDestroy(afid *SrvFid) {}
+
+// AuthCheck accepts an attach if the server does not require a token, if
+// the token was written to afid, or if aname starts with the token
+func (ufs *Ufs) AuthCheck(fid *SrvFid, afid *SrvFid, aname string) error {
+	if ufs.Token == "" {
+		return nil
+	}
+	if afid != nil {
+		if a, ok := afid.Aux.(*ufsAuth); ok {
+			a.Lock()
+			defer a.Unlock()
+			if ufs.validToken(string(a.token)) {
+				return nil
+			}
+		}
+	}
+	if _, ok := ufs.anamePath(aname); ok {
+		return nil
+	}
+	return Eperm
+}
+
+func (*Ufs) AuthRead(afid *SrvFid, offset uint64, data []byte) (int, error) {
+	return 0, nil
+}
+
+func (*Ufs) AuthWrite(afid *SrvFid, offset uint64, data []byte) (int, error) {
+	a, ok := afid.Aux.(*ufsAuth)
+	if !ok {
+		return 0, Ebaduse
+	}
+	a.Lock()
+	defer a.Unlock()
+	// tokens are much shorter
+	if len(a.token)+len(data) > 1024 {
+		return 0, Eperm
+	}
+	a.token = append(a.token, data...)
+	return len(data), nil
+}
+
+// anamePath returns the path which an aname of the form token[/path]
+// attaches to, and whether the aname starts with the token. Clients which
+// cannot authenticate, such as the Linux kernel, pass the token this way.
+func (ufs *Ufs) anamePath(aname string) (string, bool) {
+	token, p := aname, ""
+	if i := strings.Index(aname, "/"); i >= 0 {
+		token, p = aname[:i], aname[i:]
+	}
+	return p, ufs.validToken(token)
+}
+
+// validToken compares a token to the token of the server in constant time
+func (ufs *Ufs) validToken(token string) bool {
+	return ufs.Token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(ufs.Token)) == 1
+}
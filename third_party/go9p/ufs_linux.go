@@ -11,6 +11,9 @@ import (
 	"time"
 )
 
+// oNoFollow makes opens fail on symlinks
+const oNoFollow = syscall.O_NOFOLLOW
+
 func atime(stat *syscall.Stat_t) time.Time {
 	return time.Unix(stat.Atim.Unix())
 }
@@ -124,6 +127,9 @@ func (u *Ufs) Wstat(req *SrvReq) {
 		req.RespondError(err)
 		return
 	}
+	if !u.wstatAllowed(req, fid) {
+		return
+	}
 
 	dir := &req.Tc.Dir
 	if dir.Mode != 0xFFFFFFFF {
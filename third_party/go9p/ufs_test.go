@@ -0,0 +1,368 @@
+// +build !windows
+
+package go9p
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// serveUfs serves a directory with the given export options, and returns
+// the address of the server
+func serveUfs(t *testing.T, ufs *Ufs) string {
+	ufs.Dotu = true
+	ufs.Id = "ufs"
+	if !ufs.Start(ufs) {
+		t.Fatal("failed to start the server")
+	}
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+	go ufs.StartListener(l)
+	return l.Addr().String()
+}
+
+// startUfs serves a directory with the given export options, and returns a
+// client attached to it
+func startUfs(t *testing.T, ufs *Ufs) *Clnt {
+	clnt, err := Mount("tcp", serveUfs(t, ufs), "", 8192, OsUsers.Uid2User(os.Getuid()))
+	if err != nil {
+		t.Fatalf("mount: %v", err)
+	}
+	t.Cleanup(clnt.Unmount)
+	return clnt
+}
+
+// exportedTree creates a directory with files in and out of an allowed
+// subdirectory, and a symlink pointing out of it
+func exportedTree(t *testing.T) (string, string) {
+	tmp, err := ioutil.TempDir("", "ufs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(tmp) })
+	root := filepath.Join(tmp, "root")
+	secret := filepath.Join(tmp, "secret")
+	for _, d := range []string{filepath.Join(root, "src"), filepath.Join(root, "private"), secret} {
+		if err := os.MkdirAll(d, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, f := range []string{filepath.Join(root, "src", "main.go"), filepath.Join(root, "private", "key"), filepath.Join(secret, "key")} {
+		if err := ioutil.WriteFile(f, []byte("data"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Symlink(secret, filepath.Join(root, "src", "escape")); err != nil {
+		t.Fatal(err)
+	}
+	return root, secret
+}
+
+// nullDir returns a wstat which changes nothing
+func nullDir() *Dir {
+	return &Dir{
+		Type:    ^uint16(0),
+		Dev:     ^uint32(0),
+		Qid:     Qid{Type: ^uint8(0), Version: ^uint32(0), Path: ^uint64(0)},
+		Mode:    ^uint32(0),
+		Atime:   ^uint32(0),
+		Mtime:   ^uint32(0),
+		Length:  ^uint64(0),
+		Uidnum:  NOUID,
+		Gidnum:  NOUID,
+		Muidnum: NOUID,
+	}
+}
+
+func TestUfsReadOnly(t *testing.T) {
+	root, _ := exportedTree(t)
+	clnt := startUfs(t, &Ufs{Root: root, ReadOnly: true})
+
+	f, err := clnt.FOpen("/src/main.go", OREAD)
+	if err != nil {
+		t.Fatalf("open for reading: %v", err)
+	}
+	f.Close()
+
+	if _, err := clnt.FOpen("/src/main.go", OWRITE); err == nil {
+		t.Error("open for writing succeeded on a read-only export")
+	}
+	if _, err := clnt.FCreate("/src/new.go", 0644, OWRITE); err == nil {
+		t.Error("create succeeded on a read-only export")
+	}
+	if err := clnt.FRemove("/src/main.go"); err == nil {
+		t.Error("remove succeeded on a read-only export")
+	}
+	if _, err := os.Stat(filepath.Join(root, "src", "main.go")); err != nil {
+		t.Errorf("stat: %v", err)
+	}
+}
+
+func TestUfsAllow(t *testing.T) {
+	root, _ := exportedTree(t)
+	clnt := startUfs(t, &Ufs{Root: root, Allow: []string{"src"}})
+
+	if _, err := clnt.FStat("/src/main.go"); err != nil {
+		t.Errorf("stat of an allowed file: %v", err)
+	}
+	if _, err := clnt.FStat("/private/key"); err == nil {
+		t.Error("stat of a file which is not allowed succeeded")
+	}
+	if _, err := clnt.FStat("/src/../private/key"); err == nil {
+		t.Error("stat through .. of a file which is not allowed succeeded")
+	}
+	if _, err := clnt.FCreate("/new", 0644, OWRITE); err == nil {
+		t.Error("create out of the allowed paths succeeded")
+	}
+
+	f, err := clnt.FOpen("/", OREAD)
+	if err != nil {
+		t.Fatalf("open root: %v", err)
+	}
+	defer f.Close()
+	dirs, err := f.Readdir(0)
+	if err != nil {
+		t.Fatalf("readdir: %v", err)
+	}
+	if len(dirs) != 1 || dirs[0].Name != "src" {
+		var names []string
+		for _, d := range dirs {
+			names = append(names, d.Name)
+		}
+		t.Errorf("root lists %v, want [src]", names)
+	}
+}
+
+func TestUfsSymlinkEscape(t *testing.T) {
+	root, secret := exportedTree(t)
+	clnt := startUfs(t, &Ufs{Root: root})
+
+	if _, err := clnt.FStat("/src/escape"); err != nil {
+		t.Errorf("stat of the symlink: %v", err)
+	}
+	if _, err := clnt.FStat("/src/escape/key"); err == nil {
+		t.Error("walk through a symlink out of the root succeeded")
+	}
+	if _, err := clnt.FOpen("/src/escape", OREAD); err == nil {
+		t.Error("open of a symlink out of the root succeeded")
+	}
+	if _, err := clnt.FStat("/../secret/key"); err == nil {
+		t.Error("walk through .. out of the root succeeded")
+	}
+
+	fid, err := clnt.FWalk("/src/escape")
+	if err != nil {
+		t.Fatalf("walk: %v", err)
+	}
+	d := nullDir()
+	d.Mode = 0777
+	if err := clnt.Wstat(fid, d); err == nil {
+		t.Error("chmod through a symlink out of the root succeeded")
+	}
+	st, err := os.Stat(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.Mode().Perm() != 0755 {
+		t.Errorf("mode of %s = %o, want 755", secret, st.Mode().Perm())
+	}
+
+	fid, err = clnt.FWalk("/src/main.go")
+	if err != nil {
+		t.Fatalf("walk: %v", err)
+	}
+	for _, name := range []string{"/src/escape/stolen", "escape/stolen", "../stolen"} {
+		d = nullDir()
+		d.Name = name
+		if err := clnt.Wstat(fid, d); err == nil {
+			t.Errorf("rename to %s succeeded", name)
+		}
+	}
+	if _, err := os.Lstat(filepath.Join(secret, "stolen")); err == nil {
+		t.Error("a file was renamed out of the root")
+	}
+}
+
+func TestUfsAnameSymlink(t *testing.T) {
+	root, secret := exportedTree(t)
+	if err := os.Symlink(filepath.Dir(secret), filepath.Join(root, "up")); err != nil {
+		t.Fatal(err)
+	}
+	user := OsUsers.Uid2User(os.Getuid())
+	addr := serveUfs(t, &Ufs{Root: root, Allow: []string{"src", "up"}})
+
+	for _, aname := range []string{"up/secret", "src/escape", "src/../private", "../secret"} {
+		if clnt, err := Mount("tcp", addr, aname, 8192, user); err == nil {
+			clnt.Unmount()
+			t.Errorf("attach to %q succeeded", aname)
+		}
+	}
+	clnt, err := Mount("tcp", addr, "src", 8192, user)
+	if err != nil {
+		t.Fatalf("attach to src: %v", err)
+	}
+	clnt.Unmount()
+}
+
+func TestUfsCreateSymlink(t *testing.T) {
+	root, secret := exportedTree(t)
+	key := filepath.Join(secret, "key")
+	clnt := startUfs(t, &Ufs{Root: root})
+
+	fid, err := clnt.FWalk("/src")
+	if err != nil {
+		t.Fatalf("walk: %v", err)
+	}
+	// the link is created, but must not be opened
+	if err := clnt.Create(fid, "link", DMSYMLINK|0777, OWRITE|OTRUNC, key); err == nil {
+		fid.Iounit = 8192
+		if _, err := clnt.Write(fid, []byte("pwned"), 0); err == nil {
+			t.Error("write through a created symlink succeeded")
+		}
+	}
+	if b, err := ioutil.ReadFile(key); err != nil || string(b) != "data" {
+		t.Errorf("%s = %q, %v, want %q", key, b, err, "data")
+	}
+}
+
+func TestUfsCreateOverSymlink(t *testing.T) {
+	root, secret := exportedTree(t)
+	key := filepath.Join(secret, "key")
+	if err := os.Symlink(key, filepath.Join(root, "src", "keylink")); err != nil {
+		t.Fatal(err)
+	}
+	clnt := startUfs(t, &Ufs{Root: root})
+
+	if f, err := clnt.FCreate("/src/keylink", 0644, OWRITE|OTRUNC); err == nil {
+		f.Write([]byte("pwned"))
+		f.Close()
+		t.Error("create over a symlink succeeded")
+	}
+	if b, err := ioutil.ReadFile(key); err != nil || string(b) != "data" {
+		t.Errorf("%s = %q, %v, want %q", key, b, err, "data")
+	}
+}
+
+func TestUfsOwner(t *testing.T) {
+	root, _ := exportedTree(t)
+	owner := &Owner{Uid: 1000, Gid: 999}
+	clnt := startUfs(t, &Ufs{Root: root, Owner: owner, Umask: 022})
+
+	d, err := clnt.FStat("/src/main.go")
+	if err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+	if d.Uidnum != owner.Uid || d.Gidnum != owner.Gid {
+		t.Errorf("owner = %d:%d, want %d:%d", d.Uidnum, d.Gidnum, owner.Uid, owner.Gid)
+	}
+
+	f, err := clnt.FCreate("/src/new.go", 0666, OWRITE)
+	if err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	f.Close()
+	st, err := os.Stat(filepath.Join(root, "src", "new.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.Mode().Perm()&022 != 0 {
+		t.Errorf("mode of the created file = %o, want the umask 022 cleared", st.Mode().Perm())
+	}
+
+	fid, err := clnt.FWalk("/src/main.go")
+	if err != nil {
+		t.Fatalf("walk: %v", err)
+	}
+	d = nullDir()
+	d.Uidnum = 0
+	if err := clnt.Wstat(fid, d); err == nil {
+		t.Error("chown to another user succeeded")
+	}
+	d.Uidnum = owner.Uid
+	if err := clnt.Wstat(fid, d); err != nil {
+		t.Errorf("chown to the owner: %v", err)
+	}
+}
+
+func TestSrvAccepts(t *testing.T) {
+	srv := &Srv{}
+	if !srv.Accepts(&net.TCPAddr{IP: net.ParseIP("10.0.0.1")}) {
+		t.Error("a server without allowed hosts refused a connection")
+	}
+
+	srv.AllowedHosts = []net.IP{net.ParseIP("192.168.49.2"), net.ParseIP("fd00::2")}
+	tests := []struct {
+		addr net.Addr
+		want bool
+	}{
+		{&net.TCPAddr{IP: net.ParseIP("192.168.49.2"), Port: 1234}, true},
+		{&net.TCPAddr{IP: net.ParseIP("::ffff:192.168.49.2"), Port: 1234}, true},
+		{&net.TCPAddr{IP: net.ParseIP("fd00::2"), Port: 1234}, true},
+		{&net.TCPAddr{IP: net.ParseIP("192.168.49.3"), Port: 1234}, false},
+		{&net.UnixAddr{Name: "/tmp/sock", Net: "unix"}, false},
+	}
+	for _, tc := range tests {
+		if got := srv.Accepts(tc.addr); got != tc.want {
+			t.Errorf("Accepts(%v) = %v, want %v", tc.addr, got, tc.want)
+		}
+	}
+
+	srv.SetAllowedHosts([]net.IP{net.ParseIP("192.168.49.3")})
+	if srv.Accepts(&net.TCPAddr{IP: net.ParseIP("192.168.49.2")}) || !srv.Accepts(&net.TCPAddr{IP: net.ParseIP("192.168.49.3")}) {
+		t.Error("the allowed hosts were not replaced")
+	}
+
+	root, _ := exportedTree(t)
+	addr := serveUfs(t, &Ufs{Srv: Srv{AllowedHosts: []net.IP{net.ParseIP("192.168.49.2")}}, Root: root})
+	if _, err := Mount("tcp", addr, "", 8192, OsUsers.Uid2User(os.Getuid())); err == nil {
+		t.Error("mount from a host which is not allowed succeeded")
+	}
+}
+
+func TestUfsToken(t *testing.T) {
+	root, _ := exportedTree(t)
+	user := OsUsers.Uid2User(os.Getuid())
+	addr := serveUfs(t, &Ufs{Root: root, Token: "s3cr3t"})
+
+	for _, aname := range []string{"", "/src", "wrong", "wrong/src", "s3cr3tx"} {
+		if _, err := Mount("tcp", addr, aname, 8192, user); err == nil {
+			t.Errorf("mount with aname %q succeeded without the token", aname)
+		}
+	}
+
+	clnt, err := Mount("tcp", addr, "s3cr3t/src", 8192, user)
+	if err != nil {
+		t.Fatalf("mount with the token in the aname: %v", err)
+	}
+	defer clnt.Unmount()
+	if _, err := clnt.FStat("/main.go"); err != nil {
+		t.Errorf("stat below the aname path: %v", err)
+	}
+
+	c, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	aclnt, err := Connect(c, 8192+IOHDRSZ, true)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer aclnt.Unmount()
+	afid, err := aclnt.Auth(user, "")
+	if err != nil {
+		t.Fatalf("auth: %v", err)
+	}
+	afid.Iounit = 8192
+	if _, err := aclnt.Write(afid, []byte("s3cr3t"), 0); err != nil {
+		t.Fatalf("writing the token: %v", err)
+	}
+	if _, err := aclnt.Attach(afid, user, "/src"); err != nil {
+		t.Errorf("attach with an authentication fid: %v", err)
+	}
+}
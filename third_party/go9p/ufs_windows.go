@@ -13,6 +13,10 @@ import (
 	"time"
 )
 
+// oNoFollow makes opens fail on symlinks. Windows has none, so that creates
+// only rely on O_EXCL, which fails on existing symlinks.
+const oNoFollow = 0
+
 func atime(fi os.FileInfo) time.Time {
 	return time.Unix(0, fi.Sys().(*syscall.Win32FileAttributeData).LastAccessTime.Nanoseconds())
 }
@@ -140,6 +144,9 @@ func (u *Ufs) Wstat(req *SrvReq) {
 		req.RespondError(err)
 		return
 	}
+	if !u.wstatAllowed(req, fid) {
+		return
+	}
 
 	dir := &req.Tc.Dir
 	if dir.Mode != 0xFFFFFFFF {